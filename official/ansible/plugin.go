@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -54,31 +56,150 @@ func (p *AnsiblePlugin) GetActions() map[string]ActionSpec {
 		"playbook": {
 			Description: "Run Ansible playbook",
 			Inputs: map[string]IOSpec{
-				"playbook":  {Type: "string", Required: true, Description: "Playbook YAML content or file path"},
-				"inventory": {Type: "string", Required: false, Description: "Inventory content or file path"},
-				"vars":      {Type: "object", Required: false, Description: "Extra variables"},
-				"limit":     {Type: "string", Required: false, Description: "Limit to specific hosts"},
-				"tags":      {Type: "string", Required: false, Description: "Run specific tags"},
+				"playbook":         {Type: "string", Required: true, Description: "Playbook YAML content or file path"},
+				"inventory":        {Type: "string", Required: false, Description: "Inventory content or file path"},
+				"inventory_script": {Type: "string", Required: false, Description: "Path to an executable dynamic inventory script (overrides inventory)"},
+				"vars":             {Type: "object", Required: false, Description: "Extra variables"},
+				"limit":            {Type: "string", Required: false, Description: "Limit to specific hosts"},
+				"tags":             {Type: "string", Required: false, Description: "Run specific tags"},
+				"json_output":      {Type: "boolean", Required: false, Default: false, Description: "Use the json stdout callback and return structured results"},
+				"become":           {Type: "boolean", Required: false, Default: false, Description: "Run operations with privilege escalation (--become)"},
+				"become_password":  {Type: "string", Required: false, Description: "Privilege escalation password, passed via the environment"},
+				"private_key_file": {Type: "string", Required: false, Description: "SSH private key file (--private-key)"},
+				"user":             {Type: "string", Required: false, Description: "Remote user to connect as (-u)"},
+				"connection":       {Type: "string", Required: false, Description: "Connection type, e.g. ssh, local, winrm (-c)"},
 			},
 			Outputs: map[string]IOSpec{
 				"success": {Type: "boolean", Description: "Operation success"},
 				"output":  {Type: "string", Description: "Command output"},
-				"stats":   {Type: "object", Description: "Ansible execution statistics"},
+				"stats":   {Type: "object", Description: "Ansible execution statistics (numeric per host with json_output)"},
+				"plays":   {Type: "array", Description: "Plays with their tasks and per-host results (json_output only)"},
+				"tasks":   {Type: "array", Description: "Flat list of per-host task results (json_output only)"},
 			},
 		},
 		"ad_hoc": {
 			Description: "Run ad-hoc command",
 			Inputs: map[string]IOSpec{
-				"hosts":     {Type: "string", Required: true, Description: "Target hosts"},
-				"module":    {Type: "string", Required: true, Description: "Ansible module"},
-				"args":      {Type: "string", Required: false, Description: "Module arguments"},
-				"inventory": {Type: "string", Required: false, Description: "Inventory file"},
+				"hosts":            {Type: "string", Required: true, Description: "Target hosts"},
+				"module":           {Type: "string", Required: true, Description: "Ansible module"},
+				"args":             {Type: "string", Required: false, Description: "Module arguments"},
+				"inventory":        {Type: "string", Required: false, Description: "Inventory file"},
+				"become":           {Type: "boolean", Required: false, Default: false, Description: "Run operations with privilege escalation (--become)"},
+				"become_password":  {Type: "string", Required: false, Description: "Privilege escalation password, passed via the environment"},
+				"private_key_file": {Type: "string", Required: false, Description: "SSH private key file (--private-key)"},
+				"user":             {Type: "string", Required: false, Description: "Remote user to connect as (-u)"},
+				"connection":       {Type: "string", Required: false, Description: "Connection type, e.g. ssh, local, winrm (-c)"},
 			},
 			Outputs: map[string]IOSpec{
 				"success": {Type: "boolean", Description: "Operation success"},
 				"output":  {Type: "string", Description: "Command output"},
 			},
 		},
+		"list_inventory": {
+			Description: "List inventory hosts and groups",
+			Inputs: map[string]IOSpec{
+				"inventory":        {Type: "string", Required: false, Description: "Inventory content or file path"},
+				"inventory_script": {Type: "string", Required: false, Description: "Path to an executable dynamic inventory script (overrides inventory)"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":  {Type: "boolean", Description: "Operation success"},
+				"hosts":    {Type: "array", Description: "All hosts, sorted"},
+				"groups":   {Type: "object", Description: "Group name to its direct hosts and child groups"},
+				"hostvars": {Type: "object", Description: "Variables per host"},
+			},
+		},
+		"galaxy_install": {
+			Description: "Install Ansible Galaxy roles or collections",
+			Inputs: map[string]IOSpec{
+				"type":              {Type: "string", Required: false, Default: "role", Description: "What to install: role or collection"},
+				"name":              {Type: "string", Required: false, Description: "Role or collection name"},
+				"requirements_file": {Type: "string", Required: false, Description: "Path to requirements.yml (alternative to name)"},
+				"force":             {Type: "boolean", Required: false, Default: false, Description: "Reinstall even if already present"},
+				"install_path":      {Type: "string", Required: false, Description: "Directory to install into (defaults to the Ansible configured path)"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":      {Type: "boolean", Description: "Operation success"},
+				"installed":    {Type: "array", Description: "Installed items"},
+				"skipped":      {Type: "array", Description: "Items that were already present"},
+				"install_path": {Type: "string", Description: "Directory the items were installed into"},
+				"output":       {Type: "string", Description: "Command output"},
+			},
+		},
+		"galaxy_install_collection": {
+			Description: "Install Ansible Galaxy collections",
+			Inputs: map[string]IOSpec{
+				"name":              {Type: "string", Required: false, Description: "Collection name (namespace.collection)"},
+				"requirements_file": {Type: "string", Required: false, Description: "Path to requirements.yml (alternative to name)"},
+				"force":             {Type: "boolean", Required: false, Default: false, Description: "Reinstall even if already present"},
+				"install_path":      {Type: "string", Required: false, Description: "Directory to install into (defaults to the Ansible configured path)"},
+				"upgrade":           {Type: "boolean", Required: false, Default: false, Description: "Upgrade to the latest matching version"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":      {Type: "boolean", Description: "Operation success"},
+				"installed":    {Type: "array", Description: "Installed collections"},
+				"skipped":      {Type: "array", Description: "Collections that were already present"},
+				"install_path": {Type: "string", Description: "Directory the collections were installed into"},
+				"output":       {Type: "string", Description: "Command output"},
+			},
+		},
+		"galaxy_install_role": {
+			Description: "Install Ansible Galaxy roles",
+			Inputs: map[string]IOSpec{
+				"name":              {Type: "string", Required: false, Description: "Role name (namespace.role)"},
+				"requirements_file": {Type: "string", Required: false, Description: "Path to requirements.yml (alternative to name)"},
+				"force":             {Type: "boolean", Required: false, Default: false, Description: "Reinstall even if already present"},
+				"install_path":      {Type: "string", Required: false, Description: "Directory to install into (defaults to the Ansible configured path)"},
+				"upgrade":           {Type: "boolean", Required: false, Default: false, Description: "Ignored for roles; use force to reinstall"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":      {Type: "boolean", Description: "Operation success"},
+				"installed":    {Type: "array", Description: "Installed roles"},
+				"skipped":      {Type: "array", Description: "Roles that were already present"},
+				"install_path": {Type: "string", Description: "Directory the roles were installed into"},
+				"output":       {Type: "string", Description: "Command output"},
+			},
+		},
+		"vault_encrypt": {
+			Description: "Encrypt a string or file with Ansible Vault",
+			Inputs: map[string]IOSpec{
+				"content":        {Type: "string", Required: false, Description: "Plaintext to encrypt (either content or file is required)"},
+				"file":           {Type: "string", Required: false, Description: "File to encrypt instead of content"},
+				"vault_password": {Type: "string", Required: true, Description: "Vault password"},
+				"name":           {Type: "string", Required: false, Description: "Variable name to prefix the encrypted block with"},
+				"output_file":    {Type: "string", Required: false, Description: "Write the encrypted block or file here"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":   {Type: "boolean", Description: "Operation success"},
+				"encrypted": {Type: "string", Description: "Encrypted !vault block (content only)"},
+				"file":      {Type: "string", Description: "Path of the encrypted file (file only)"},
+			},
+		},
+		"vault_encrypt_file": {
+			Description: "Encrypt a file with Ansible Vault",
+			Inputs: map[string]IOSpec{
+				"file":           {Type: "string", Required: true, Description: "File to encrypt"},
+				"vault_password": {Type: "string", Required: true, Description: "Vault password"},
+				"output_file":    {Type: "string", Required: false, Description: "Write the encrypted file here instead of encrypting in place"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Operation success"},
+				"file":    {Type: "string", Description: "Path of the encrypted file"},
+				"output":  {Type: "string", Description: "Command output"},
+			},
+		},
+		"vault_rekey": {
+			Description: "Change the password of an Ansible Vault file",
+			Inputs: map[string]IOSpec{
+				"file":         {Type: "string", Required: true, Description: "Encrypted file to rekey"},
+				"old_password": {Type: "string", Required: true, Description: "Current vault password"},
+				"new_password": {Type: "string", Required: true, Description: "New vault password"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Operation success"},
+				"file":    {Type: "string", Description: "Path of the rekeyed file"},
+				"output":  {Type: "string", Description: "Command output"},
+			},
+		},
 	}
 }
 
@@ -88,6 +209,24 @@ func (p *AnsiblePlugin) Execute(action string, params map[string]interface{}) (m
 		return p.runPlaybook(params)
 	case "ad_hoc":
 		return p.runAdHoc(params)
+	case "list_inventory":
+		return p.listInventory(params)
+	case "galaxy_install":
+		kind := getStringParam(params, "type", "role")
+		if kind != "role" && kind != "collection" {
+			return map[string]interface{}{"error": fmt.Sprintf("unsupported type: %s (expected role or collection)", kind)}, nil
+		}
+		return p.galaxyInstall(kind, params)
+	case "galaxy_install_collection":
+		return p.galaxyInstall("collection", params)
+	case "galaxy_install_role":
+		return p.galaxyInstall("role", params)
+	case "vault_encrypt":
+		return p.vaultEncrypt(params)
+	case "vault_encrypt_file":
+		return p.vaultEncryptFile(params)
+	case "vault_rekey":
+		return p.vaultRekey(params)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
@@ -120,19 +259,9 @@ func (p *AnsiblePlugin) runPlaybook(params map[string]interface{}) (map[string]i
 	}
 
 	// Handle inventory
-	inventoryFile := "localhost,"
-	if inventory, ok := params["inventory"].(string); ok && inventory != "" {
-		if _, err := os.Stat(inventory); err == nil {
-			// It's an existing file path
-			inventoryFile = inventory
-		} else {
-			// It's inventory content, write to temp file
-			invFile := filepath.Join(tmpDir, "inventory")
-			if err := ioutil.WriteFile(invFile, []byte(inventory), 0644); err != nil {
-				return map[string]interface{}{"error": fmt.Sprintf("failed to write inventory: %v", err)}, nil
-			}
-			inventoryFile = invFile
-		}
+	inventoryFile, err := resolveInventory(params, tmpDir)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
 	// Build ansible-playbook command
@@ -156,10 +285,18 @@ func (p *AnsiblePlugin) runPlaybook(params map[string]interface{}) (map[string]i
 		args = append(args, "--tags", tags)
 	}
 
+	args = append(args, connectionArgs(params)...)
+
 	// Execute command
-	cmd := exec.Command("bash", "-c", strings.Join(args, " "))
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = connectionEnv(params)
+
+	if getBoolParam(params, "json_output", false) {
+		return p.runPlaybookJSON(cmd)
+	}
+
 	output, err := cmd.CombinedOutput()
-	
+
 	success := err == nil
 	outputStr := string(output)
 	stats := p.parseAnsibleStats(outputStr)
@@ -171,6 +308,210 @@ func (p *AnsiblePlugin) runPlaybook(params map[string]interface{}) (map[string]i
 	}, nil
 }
 
+// runPlaybookJSON runs the playbook with the json stdout callback. Stdout then holds a
+// single JSON document, so stderr (warnings, deprecations) is kept separately.
+func (p *AnsiblePlugin) runPlaybookJSON(cmd *exec.Cmd) (map[string]interface{}, error) {
+	cmd.Env = append(cmd.Env, "ANSIBLE_STDOUT_CALLBACK=json")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	result := map[string]interface{}{
+		"success": runErr == nil,
+		"output":  stderr.String(),
+	}
+
+	plays, tasks, stats, err := parseJSONCallback(stdout.Bytes())
+	if err != nil {
+		result["success"] = false
+		result["output"] = stdout.String() + stderr.String()
+		result["error"] = err.Error()
+		return result, nil
+	}
+
+	result["plays"] = plays
+	result["tasks"] = tasks
+	result["stats"] = stats
+	return result, nil
+}
+
+type jsonCallbackOutput struct {
+	Plays []struct {
+		Play struct {
+			Name string `json:"name"`
+		} `json:"play"`
+		Tasks []struct {
+			Task struct {
+				Name string `json:"name"`
+			} `json:"task"`
+			Hosts map[string]map[string]interface{} `json:"hosts"`
+		} `json:"tasks"`
+	} `json:"plays"`
+	Stats map[string]map[string]float64 `json:"stats"`
+}
+
+// parseJSONCallback converts json callback output into plays, a flat task list and
+// numeric per-host stats keyed like the text recap (ok, changed, failed, ...).
+func parseJSONCallback(data []byte) ([]interface{}, []interface{}, map[string]interface{}, error) {
+	// Anything printed before the document (e.g. by a plugin) is not part of it
+	start := bytes.IndexByte(data, '{')
+	if start < 0 {
+		return nil, nil, nil, fmt.Errorf("no JSON output from ansible-playbook")
+	}
+
+	var parsed jsonCallbackOutput
+	if err := json.Unmarshal(data[start:], &parsed); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse JSON callback output: %v", err)
+	}
+
+	plays := []interface{}{}
+	tasks := []interface{}{}
+	for _, play := range parsed.Plays {
+		playTasks := []interface{}{}
+		for _, task := range play.Tasks {
+			hosts := make([]string, 0, len(task.Hosts))
+			for host := range task.Hosts {
+				hosts = append(hosts, host)
+			}
+			sort.Strings(hosts)
+
+			hostResults := map[string]interface{}{}
+			for _, host := range hosts {
+				hostResult := taskHostResult(task.Hosts[host])
+				hostResults[host] = hostResult
+
+				entry := map[string]interface{}{
+					"play": play.Play.Name,
+					"task": task.Task.Name,
+					"host": host,
+				}
+				for key, value := range hostResult {
+					entry[key] = value
+				}
+				tasks = append(tasks, entry)
+			}
+
+			playTasks = append(playTasks, map[string]interface{}{
+				"name":  task.Task.Name,
+				"hosts": hostResults,
+			})
+		}
+
+		plays = append(plays, map[string]interface{}{
+			"name":  play.Play.Name,
+			"tasks": playTasks,
+		})
+	}
+
+	stats := map[string]interface{}{}
+	for host, hostStats := range parsed.Stats {
+		typed := map[string]interface{}{}
+		for key, value := range hostStats {
+			// The callback says "failures" where the recap says "failed"
+			if key == "failures" {
+				key = "failed"
+			}
+			typed[key] = int(value)
+		}
+		stats[host] = typed
+	}
+
+	return plays, tasks, stats, nil
+}
+
+// taskHostResult keeps the fields a workflow branches on from one host's task result
+func taskHostResult(raw map[string]interface{}) map[string]interface{} {
+	flag := func(key string) bool {
+		value, _ := raw[key].(bool)
+		return value
+	}
+
+	result := map[string]interface{}{
+		"changed":     flag("changed"),
+		"failed":      flag("failed"),
+		"skipped":     flag("skipped"),
+		"unreachable": flag("unreachable"),
+	}
+	if action, ok := raw["action"]; ok {
+		result["action"] = action
+	}
+	if msg, ok := raw["msg"]; ok {
+		result["msg"] = msg
+	}
+	return result
+}
+
+func (p *AnsiblePlugin) listInventory(params map[string]interface{}) (map[string]interface{}, error) {
+	tmpDir, err := ioutil.TempDir("", "ansible-")
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create temp dir: %v", err)}, nil
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inventoryFile, err := resolveInventory(params, tmpDir)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	cmd := exec.Command("ansible-inventory", "-i", inventoryFile, "--list")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("ansible-inventory failed: %v: %s", err, strings.TrimSpace(stderr.String())),
+		}, nil
+	}
+
+	var inventory map[string]interface{}
+	if err := json.Unmarshal(output, &inventory); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to parse inventory: %v", err)}, nil
+	}
+
+	hostvars := map[string]interface{}{}
+	if meta, ok := inventory["_meta"].(map[string]interface{}); ok {
+		if vars, ok := meta["hostvars"].(map[string]interface{}); ok {
+			hostvars = vars
+		}
+	}
+
+	groups := map[string]interface{}{}
+	hostSet := map[string]bool{}
+	for name, raw := range inventory {
+		group, ok := raw.(map[string]interface{})
+		if !ok || name == "_meta" {
+			continue
+		}
+		hosts := toStringSlice(group["hosts"])
+		for _, host := range hosts {
+			hostSet[host] = true
+		}
+		groups[name] = map[string]interface{}{
+			"hosts":    hosts,
+			"children": toStringSlice(group["children"]),
+		}
+	}
+	for host := range hostvars {
+		hostSet[host] = true
+	}
+
+	hosts := make([]string, 0, len(hostSet))
+	for host := range hostSet {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	return map[string]interface{}{
+		"success":  true,
+		"hosts":    hosts,
+		"groups":   groups,
+		"hostvars": hostvars,
+	}, nil
+}
+
 func (p *AnsiblePlugin) runAdHoc(params map[string]interface{}) (map[string]interface{}, error) {
 	hosts, ok := params["hosts"].(string)
 	if !ok || hosts == "" {
@@ -197,10 +538,13 @@ func (p *AnsiblePlugin) runAdHoc(params map[string]interface{}) (map[string]inte
 		args = append(args, "-a", moduleArgs)
 	}
 
+	args = append(args, connectionArgs(params)...)
+
 	// Execute command
-	cmd := exec.Command("bash", "-c", strings.Join(args, " "))
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = connectionEnv(params)
 	output, err := cmd.CombinedOutput()
-	
+
 	success := err == nil
 	outputStr := string(output)
 
@@ -210,19 +554,312 @@ func (p *AnsiblePlugin) runAdHoc(params map[string]interface{}) (map[string]inte
 	}, nil
 }
 
+// galaxyInstall runs ansible-galaxy <kind> install for a single name or a requirements
+// file. A requirements file is installed by one ansible-galaxy invocation, which
+// resolves all entries before installing any of them.
+func (p *AnsiblePlugin) galaxyInstall(kind string, params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	requirementsFile := getStringParam(params, "requirements_file", "")
+	if name == "" && requirementsFile == "" {
+		return map[string]interface{}{"error": "name or requirements_file is required"}, nil
+	}
+	if name != "" && requirementsFile != "" {
+		return map[string]interface{}{"error": "name and requirements_file are mutually exclusive"}, nil
+	}
+
+	args := []string{kind, "install"}
+	if requirementsFile != "" {
+		if _, err := os.Stat(requirementsFile); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("requirements file not found: %v", err)}, nil
+		}
+		args = append(args, "-r", requirementsFile)
+	} else {
+		args = append(args, name)
+	}
+	if getBoolParam(params, "force", false) {
+		args = append(args, "--force")
+	}
+	if kind == "collection" && getBoolParam(params, "upgrade", false) {
+		args = append(args, "--upgrade")
+	}
+	installPath := getStringParam(params, "install_path", "")
+	if installPath != "" {
+		args = append(args, "-p", installPath)
+	}
+
+	output, err := exec.Command("ansible-galaxy", args...).CombinedOutput()
+	outputStr := string(output)
+	installed, skipped := parseGalaxyOutput(outputStr)
+	if installPath == "" {
+		installPath = parseGalaxyInstallPath(kind, outputStr)
+	}
+
+	// ansible-galaxy only prints a summary when every requested collection is present
+	if strings.Contains(outputStr, "Nothing to do") && len(skipped) == 0 && name != "" {
+		skipped = append(skipped, name)
+	}
+
+	result := map[string]interface{}{
+		"success":      err == nil,
+		"installed":    installed,
+		"skipped":      skipped,
+		"install_path": installPath,
+		"output":       outputStr,
+	}
+	if err != nil {
+		result["error"] = fmt.Sprintf("ansible-galaxy %s install failed: %v", kind, err)
+	}
+	return result, nil
+}
+
+// parseGalaxyOutput extracts installed and already-present items from ansible-galaxy
+// output, e.g. "community.general:8.0.0 was installed successfully" or
+// "- geerlingguy.nginx (3.1.4) is already installed, skipping."
+func parseGalaxyOutput(output string) ([]string, []string) {
+	installed := []string{}
+	skipped := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "[WARNING]:"))
+		line = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+
+		if idx := strings.Index(line, " was installed successfully"); idx > 0 {
+			installed = append(installed, strings.Trim(line[:idx], "'\""))
+			continue
+		}
+		if strings.HasPrefix(line, "Skipping '") {
+			if end := strings.Index(line[len("Skipping '"):], "'"); end > 0 {
+				skipped = append(skipped, line[len("Skipping '"):len("Skipping '")+end])
+			}
+			continue
+		}
+		if idx := strings.Index(line, " is already installed"); idx > 0 {
+			skipped = append(skipped, strings.Trim(line[:idx], "'\""))
+		}
+	}
+	return installed, skipped
+}
+
+var (
+	// Installing 'community.general:8.0.0' to '/root/.ansible/collections/ansible_collections/community/general'
+	collectionPathPattern = regexp.MustCompile(`Installing '[^']+' to '([^']+)'`)
+	// - extracting nginx to /root/.ansible/roles/geerlingguy.nginx
+	rolePathPattern = regexp.MustCompile(`extracting \S+ to (\S+)`)
+)
+
+// parseGalaxyInstallPath derives the base install directory from the first item
+// ansible-galaxy reports installing, or returns "" if nothing was installed
+func parseGalaxyInstallPath(kind, output string) string {
+	if kind == "collection" {
+		if match := collectionPathPattern.FindStringSubmatch(output); match != nil {
+			// Strip the trailing <namespace>/<name> to get the collections root
+			return filepath.Dir(filepath.Dir(match[1]))
+		}
+		return ""
+	}
+	if match := rolePathPattern.FindStringSubmatch(output); match != nil {
+		return filepath.Dir(match[1])
+	}
+	return ""
+}
+
+func (p *AnsiblePlugin) vaultEncrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	if getStringParam(params, "file", "") != "" {
+		return p.vaultEncryptFile(params)
+	}
+
+	content, ok := params["content"].(string)
+	if !ok || content == "" {
+		return map[string]interface{}{"error": "content or file is required"}, nil
+	}
+
+	password, ok := params["vault_password"].(string)
+	if !ok || password == "" {
+		return map[string]interface{}{"error": "vault_password is required"}, nil
+	}
+
+	passwordFile, err := writeVaultPasswordFile(password)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer os.Remove(passwordFile)
+
+	// The plaintext is passed on stdin so it never appears in the process list
+	args := []string{"encrypt_string", "--vault-password-file", passwordFile}
+	if name := getStringParam(params, "name", ""); name != "" {
+		args = append(args, "--name", name)
+	}
+
+	cmd := exec.Command("ansible-vault", args...)
+	cmd.Stdin = strings.NewReader(content)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("ansible-vault encrypt_string failed: %v: %s", err, strings.TrimSpace(stderr.String())),
+		}, nil
+	}
+
+	encrypted := strings.TrimSpace(string(output))
+	if outputFile := getStringParam(params, "output_file", ""); outputFile != "" {
+		if err := ioutil.WriteFile(outputFile, []byte(encrypted+"\n"), 0600); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to write output file: %v", err)}, nil
+		}
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"encrypted": encrypted,
+	}, nil
+}
+
+func (p *AnsiblePlugin) vaultEncryptFile(params map[string]interface{}) (map[string]interface{}, error) {
+	file, ok := params["file"].(string)
+	if !ok || file == "" {
+		return map[string]interface{}{"error": "file is required"}, nil
+	}
+
+	password, ok := params["vault_password"].(string)
+	if !ok || password == "" {
+		return map[string]interface{}{"error": "vault_password is required"}, nil
+	}
+
+	passwordFile, err := writeVaultPasswordFile(password)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer os.Remove(passwordFile)
+
+	args := []string{"encrypt", "--vault-password-file", passwordFile}
+	target := file
+	if outputFile := getStringParam(params, "output_file", ""); outputFile != "" {
+		args = append(args, "--output", outputFile)
+		target = outputFile
+	}
+	args = append(args, file)
+
+	output, err := exec.Command("ansible-vault", args...).CombinedOutput()
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"output":  string(output),
+			"error":   fmt.Sprintf("ansible-vault encrypt failed: %v", err),
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"file":    target,
+		"output":  string(output),
+	}, nil
+}
+
+func (p *AnsiblePlugin) vaultRekey(params map[string]interface{}) (map[string]interface{}, error) {
+	file := getStringParam(params, "file", "")
+	if file == "" {
+		return map[string]interface{}{"error": "file is required"}, nil
+	}
+
+	oldPassword := getStringParam(params, "old_password", "")
+	newPassword := getStringParam(params, "new_password", "")
+	if oldPassword == "" || newPassword == "" {
+		return map[string]interface{}{"error": "old_password and new_password are required"}, nil
+	}
+
+	oldPasswordFile, err := writeVaultPasswordFile(oldPassword)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer os.Remove(oldPasswordFile)
+
+	newPasswordFile, err := writeVaultPasswordFile(newPassword)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer os.Remove(newPasswordFile)
+
+	output, err := exec.Command("ansible-vault", "rekey",
+		"--vault-password-file", oldPasswordFile,
+		"--new-vault-password-file", newPasswordFile,
+		file).CombinedOutput()
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"output":  string(output),
+			"error":   fmt.Sprintf("ansible-vault rekey failed: %v", err),
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"file":    file,
+		"output":  string(output),
+	}, nil
+}
+
+// writeVaultPasswordFile stores the vault password in a private temp file for
+// --vault-password-file; callers must remove it when done
+func writeVaultPasswordFile(password string) (string, error) {
+	f, err := ioutil.TempFile("", "ansible-vault-pass-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create password file: %v", err)
+	}
+	defer f.Close()
+
+	if err := f.Chmod(0600); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to secure password file: %v", err)
+	}
+	if _, err := f.WriteString(password); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write password file: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// connectionArgs maps the become and SSH connection inputs to ansible command line flags
+func connectionArgs(params map[string]interface{}) []string {
+	var args []string
+	if getBoolParam(params, "become", false) {
+		args = append(args, "--become")
+	}
+	if keyFile := getStringParam(params, "private_key_file", ""); keyFile != "" {
+		args = append(args, "--private-key", keyFile)
+	}
+	if user := getStringParam(params, "user", ""); user != "" {
+		args = append(args, "-u", user)
+	}
+	if connection := getStringParam(params, "connection", ""); connection != "" {
+		args = append(args, "-c", connection)
+	}
+	return args
+}
+
+// connectionEnv returns the command environment. The become password goes in
+// ANSIBLE_BECOME_PASS rather than on the command line, where any local user could
+// read it from the process list.
+func connectionEnv(params map[string]interface{}) []string {
+	env := os.Environ()
+	if password := getStringParam(params, "become_password", ""); password != "" {
+		env = append(env, "ANSIBLE_BECOME_PASS="+password)
+	}
+	return env
+}
+
 func (p *AnsiblePlugin) parseAnsibleStats(output string) map[string]interface{} {
 	stats := make(map[string]interface{})
-	
+
 	// Look for PLAY RECAP section
 	lines := strings.Split(output, "\n")
 	inRecap := false
-	
+
 	for _, line := range lines {
 		if strings.Contains(line, "PLAY RECAP") {
 			inRecap = true
 			continue
 		}
-		
+
 		if inRecap && strings.TrimSpace(line) != "" {
 			// Parse stats lines like: "localhost : ok=2 changed=0 unreachable=0 failed=0"
 			if strings.Contains(line, ":") {
@@ -230,13 +867,13 @@ func (p *AnsiblePlugin) parseAnsibleStats(output string) map[string]interface{}
 				if len(parts) == 2 {
 					host := strings.TrimSpace(parts[0])
 					statsStr := strings.TrimSpace(parts[1])
-					
+
 					hostStats := make(map[string]interface{})
-					
+
 					// Parse individual stats using regex
 					re := regexp.MustCompile(`(\w+)=(\d+)`)
 					matches := re.FindAllStringSubmatch(statsStr, -1)
-					
+
 					for _, match := range matches {
 						if len(match) == 3 {
 							key := match[1]
@@ -244,7 +881,7 @@ func (p *AnsiblePlugin) parseAnsibleStats(output string) map[string]interface{}
 							hostStats[key] = value
 						}
 					}
-					
+
 					if len(hostStats) > 0 {
 						stats[host] = hostStats
 					}
@@ -252,10 +889,58 @@ func (p *AnsiblePlugin) parseAnsibleStats(output string) map[string]interface{}
 			}
 		}
 	}
-	
+
 	return stats
 }
 
+// resolveInventory returns the inventory argument for -i. An inventory_script is
+// made executable and used as a dynamic inventory; otherwise inventory may be a
+// file path or inline content, which is written into tmpDir.
+func resolveInventory(params map[string]interface{}, tmpDir string) (string, error) {
+	if script := getStringParam(params, "inventory_script", ""); script != "" {
+		info, err := os.Stat(script)
+		if err != nil {
+			return "", fmt.Errorf("inventory script not found: %v", err)
+		}
+		if info.IsDir() {
+			return "", fmt.Errorf("inventory script %s is a directory", script)
+		}
+		if info.Mode()&0100 == 0 {
+			if err := os.Chmod(script, info.Mode()|0100); err != nil {
+				return "", fmt.Errorf("failed to make inventory script executable: %v", err)
+			}
+		}
+		return filepath.Abs(script)
+	}
+
+	inventory := getStringParam(params, "inventory", "")
+	if inventory == "" {
+		return "localhost,", nil
+	}
+	if _, err := os.Stat(inventory); err == nil {
+		// It's an existing file path
+		return inventory, nil
+	}
+
+	// It's inventory content, write to temp file
+	invFile := filepath.Join(tmpDir, "inventory")
+	if err := ioutil.WriteFile(invFile, []byte(inventory), 0644); err != nil {
+		return "", fmt.Errorf("failed to write inventory: %v", err)
+	}
+	return invFile, nil
+}
+
+func toStringSlice(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
 // Helper function to get string parameter
 func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
 	if val, ok := params[key].(string); ok {
@@ -311,4 +996,4 @@ func main() {
 	}
 
 	json.NewEncoder(os.Stdout).Encode(result)
-}
\ No newline at end of file
+}
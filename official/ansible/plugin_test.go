@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeAnsible puts an executable with the given name first on PATH. It records each
+// argument it receives on its own line in the returned file.
+func fakeAnsible(t *testing.T, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	argvFile := filepath.Join(dir, "argv")
+	script := "#!/bin/sh\nfor arg in \"$@\"; do printf '%s\\n' \"$arg\"; done > \"" + argvFile + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake %s: %v", name, err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argvFile
+}
+
+func TestAdHocArgsAreNotShellInterpreted(t *testing.T) {
+	argvFile := fakeAnsible(t, "ansible")
+	marker := filepath.Join(t.TempDir(), "injected")
+	moduleArgs := "echo hello world; touch " + marker
+
+	result, err := NewAnsiblePlugin().Execute("ad_hoc", map[string]interface{}{
+		"hosts":     "web servers",
+		"module":    "shell",
+		"args":      moduleArgs,
+		"inventory": "localhost,",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if result["success"] != true {
+		t.Fatalf("expected success, got %v", result)
+	}
+
+	data, err := os.ReadFile(argvFile)
+	if err != nil {
+		t.Fatalf("fake ansible was not run: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	want := []string{"web servers", "-i", "localhost,", "-m", "shell", "-a", moduleArgs}
+	if strings.Join(got, "\x00") != strings.Join(want, "\x00") {
+		t.Fatalf("argv = %q, want %q", got, want)
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("the command after ';' was executed by a shell")
+	}
+}
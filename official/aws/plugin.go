@@ -6,7 +6,9 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Metadata struct {
@@ -51,12 +53,14 @@ func (p *AWSPlugin) GetActions() map[string]ActionSpec {
 		"ec2_list": {
 			Description: "List EC2 instances with filters",
 			Inputs: map[string]IOSpec{
-				"region":  {Type: "string", Required: false, Description: "AWS region"},
-				"filters": {Type: "object", Required: false, Description: "Instance filters"},
-				"state":   {Type: "string", Required: false, Description: "Instance state filter"},
+				"region":    {Type: "string", Required: false, Description: "AWS region"},
+				"filters":   {Type: "object", Required: false, Description: "Instance filters"},
+				"state":     {Type: "string", Required: false, Description: "Instance state filter"},
+				"max_items": {Type: "number", Required: false, Description: "Maximum number of instances to return (all pages are read by default)"},
 			},
 			Outputs: map[string]IOSpec{
 				"instances": {Type: "array", Description: "EC2 instances"},
+				"truncated": {Type: "boolean", Description: "True when max_items cut off further results"},
 			},
 		},
 		"ec2_launch": {
@@ -85,14 +89,19 @@ func (p *AWSPlugin) GetActions() map[string]ActionSpec {
 				"success": {Type: "boolean", Description: "Termination success"},
 			},
 		},
+		"ec2_stop":   ec2StateChangeSpec("Stop EC2 instances", "stopped"),
+		"ec2_start":  ec2StateChangeSpec("Start stopped EC2 instances", "running"),
+		"ec2_reboot": ec2StateChangeSpec("Reboot EC2 instances", "running"),
 		"s3_list": {
 			Description: "List S3 buckets and objects",
 			Inputs: map[string]IOSpec{
-				"bucket": {Type: "string", Required: false, Description: "Bucket name (list objects) or empty (list buckets)"},
-				"prefix": {Type: "string", Required: false, Description: "Object prefix filter"},
+				"bucket":    {Type: "string", Required: false, Description: "Bucket name (list objects) or empty (list buckets)"},
+				"prefix":    {Type: "string", Required: false, Description: "Object prefix filter"},
+				"max_items": {Type: "number", Required: false, Description: "Maximum number of buckets or objects to return (all pages are read by default)"},
 			},
 			Outputs: map[string]IOSpec{
-				"items": {Type: "array", Description: "Buckets or objects"},
+				"items":     {Type: "array", Description: "Buckets or objects"},
+				"truncated": {Type: "boolean", Description: "True when max_items cut off further results"},
 			},
 		},
 		"s3_upload": {
@@ -135,11 +144,13 @@ func (p *AWSPlugin) GetActions() map[string]ActionSpec {
 		"lambda_list": {
 			Description: "List Lambda functions",
 			Inputs: map[string]IOSpec{
-				"prefix": {Type: "string", Required: false, Description: "Function name prefix"},
-				"region": {Type: "string", Required: false, Description: "AWS region"},
+				"prefix":    {Type: "string", Required: false, Description: "Function name prefix"},
+				"region":    {Type: "string", Required: false, Description: "AWS region"},
+				"max_items": {Type: "number", Required: false, Description: "Maximum number of functions to return (all pages are read by default)"},
 			},
 			Outputs: map[string]IOSpec{
 				"functions": {Type: "array", Description: "Lambda functions"},
+				"truncated": {Type: "boolean", Description: "True when max_items cut off further results"},
 			},
 		},
 	}
@@ -153,6 +164,12 @@ func (p *AWSPlugin) Execute(action string, params map[string]interface{}) (map[s
 		return p.ec2Launch(params)
 	case "ec2_terminate":
 		return p.ec2Terminate(params)
+	case "ec2_stop":
+		return p.ec2StateChange(params, "stop-instances", "stopped")
+	case "ec2_start":
+		return p.ec2StateChange(params, "start-instances", "running")
+	case "ec2_reboot":
+		return p.ec2StateChange(params, "reboot-instances", "running")
 	case "s3_list":
 		return p.s3List(params)
 	case "s3_upload":
@@ -179,6 +196,9 @@ func (p *AWSPlugin) ec2List(params map[string]interface{}) (map[string]interface
 		args = append(args, "--filters", fmt.Sprintf("Name=instance-state-name,Values=%s", state))
 	}
 	
+	maxItems := getMaxItems(params)
+	args = append(args, paginationArgs(maxItems)...)
+	
 	output, err := exec.Command("aws", args...).Output()
 	if err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("aws command failed: %v", err)}, nil
@@ -204,7 +224,14 @@ func (p *AWSPlugin) ec2List(params map[string]interface{}) (map[string]interface
 		}
 	}
 	
-	return map[string]interface{}{"instances": instances}, nil
+	// max_items counts reservations, which can hold several instances each
+	truncated := result["NextToken"] != nil
+	if maxItems > 0 && len(instances) > maxItems {
+		instances = instances[:maxItems]
+		truncated = true
+	}
+	
+	return map[string]interface{}{"instances": instances, "truncated": truncated}, nil
 }
 
 func (p *AWSPlugin) ec2Launch(params map[string]interface{}) (map[string]interface{}, error) {
@@ -284,8 +311,161 @@ func (p *AWSPlugin) ec2Terminate(params map[string]interface{}) (map[string]inte
 	return map[string]interface{}{"success": true}, nil
 }
 
+func ec2StateChangeSpec(description, targetState string) ActionSpec {
+	return ActionSpec{
+		Description: description,
+		Inputs: map[string]IOSpec{
+			"instance_ids":  {Type: "array", Required: true, Description: "Instance IDs"},
+			"region":        {Type: "string", Required: false, Description: "AWS region"},
+			"wait":          {Type: "boolean", Required: false, Default: false, Description: "Wait until all instances are " + targetState},
+			"timeout":       {Type: "number", Required: false, Default: 600, Description: "Maximum seconds to wait"},
+			"poll_interval": {Type: "number", Required: false, Default: 5, Description: "Seconds between state checks while waiting"},
+		},
+		Outputs: map[string]IOSpec{
+			"success":   {Type: "boolean", Description: "Whether the request succeeded (and instances reached the target state when waiting)"},
+			"instances": {Type: "array", Description: "Per-instance instance_id, previous_state and current_state"},
+			"states":    {Type: "object", Description: "Final state by instance ID when waiting"},
+		},
+	}
+}
+
+// ec2StateChange runs stop-instances, start-instances or reboot-instances and
+// optionally polls describe-instances until every instance is in targetState
+func (p *AWSPlugin) ec2StateChange(params map[string]interface{}, command, targetState string) (map[string]interface{}, error) {
+	instanceIds, ok := params["instance_ids"].([]interface{})
+	if !ok || len(instanceIds) == 0 {
+		return map[string]interface{}{"error": "instance_ids is required"}, nil
+	}
+
+	ids := make([]string, len(instanceIds))
+	for i, id := range instanceIds {
+		if idStr, ok := id.(string); ok && idStr != "" {
+			ids[i] = idStr
+		} else {
+			return map[string]interface{}{"error": "invalid instance ID format"}, nil
+		}
+	}
+
+	region, _ := params["region"].(string)
+
+	args := []string{"ec2", command, "--instance-ids"}
+	args = append(args, ids...)
+	args = append(args, "--output", "json")
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	output, err := exec.Command("aws", args...).Output()
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("aws command failed: %v", err), "success": false}, nil
+	}
+
+	// reboot-instances prints nothing; stop and start report each state transition
+	instances := []map[string]interface{}{}
+	if len(strings.TrimSpace(string(output))) > 0 {
+		var result map[string]interface{}
+		if err := json.Unmarshal(output, &result); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}, nil
+		}
+		for _, key := range []string{"StoppingInstances", "StartingInstances"} {
+			changes, _ := result[key].([]interface{})
+			for _, change := range changes {
+				changeMap, ok := change.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				instances = append(instances, map[string]interface{}{
+					"instance_id":    changeMap["InstanceId"],
+					"previous_state": stateName(changeMap["PreviousState"]),
+					"current_state":  stateName(changeMap["CurrentState"]),
+				})
+			}
+		}
+	}
+
+	response := map[string]interface{}{"success": true, "instances": instances}
+
+	if wait, _ := params["wait"].(bool); !wait {
+		return response, nil
+	}
+
+	timeout := 600 * time.Second
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = time.Duration(val * float64(time.Second))
+	}
+	interval := 5 * time.Second
+	if val, ok := params["poll_interval"].(float64); ok && val > 0 {
+		interval = time.Duration(val * float64(time.Second))
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		states, err := describeInstanceStates(ids, region)
+		if err != nil {
+			response["success"] = false
+			response["error"] = err.Error()
+			return response, nil
+		}
+		response["states"] = states
+
+		done := true
+		for _, id := range ids {
+			if states[id] != targetState {
+				done = false
+				break
+			}
+		}
+		if done {
+			return response, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			response["success"] = false
+			response["error"] = fmt.Sprintf("timed out after %s waiting for instances to be %s", timeout, targetState)
+			return response, nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// describeInstanceStates returns the current state name of each instance
+func describeInstanceStates(ids []string, region string) (map[string]interface{}, error) {
+	args := []string{"ec2", "describe-instances", "--instance-ids"}
+	args = append(args, ids...)
+	args = append(args, "--query", "Reservations[].Instances[].{id: InstanceId, state: State.Name}", "--output", "json")
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	output, err := exec.Command("aws", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("aws command failed: %v", err)
+	}
+
+	var instances []map[string]interface{}
+	if err := json.Unmarshal(output, &instances); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	states := map[string]interface{}{}
+	for _, instance := range instances {
+		if id, ok := instance["id"].(string); ok {
+			states[id] = instance["state"]
+		}
+	}
+	return states, nil
+}
+
+func stateName(state interface{}) interface{} {
+	if stateMap, ok := state.(map[string]interface{}); ok {
+		return stateMap["Name"]
+	}
+	return nil
+}
+
 func (p *AWSPlugin) s3List(params map[string]interface{}) (map[string]interface{}, error) {
 	bucket, hasBucket := params["bucket"].(string)
+	maxItems := getMaxItems(params)
 	
 	if !hasBucket || bucket == "" {
 		// List buckets
@@ -299,7 +479,13 @@ func (p *AWSPlugin) s3List(params map[string]interface{}) (map[string]interface{
 			return map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}, nil
 		}
 		
-		return map[string]interface{}{"items": result["Buckets"]}, nil
+		buckets, _ := result["Buckets"].([]interface{})
+		if buckets == nil {
+			buckets = []interface{}{}
+		}
+		buckets, truncated := capItems(buckets, maxItems)
+		
+		return map[string]interface{}{"items": buckets, "truncated": truncated}, nil
 	} else {
 		// List objects in bucket
 		args := []string{"s3api", "list-objects-v2", "--bucket", bucket, "--output", "json"}
@@ -307,6 +493,7 @@ func (p *AWSPlugin) s3List(params map[string]interface{}) (map[string]interface{
 		if prefix, ok := params["prefix"].(string); ok && prefix != "" {
 			args = append(args, "--prefix", prefix)
 		}
+		args = append(args, paginationArgs(maxItems)...)
 		
 		output, err := exec.Command("aws", args...).Output()
 		if err != nil {
@@ -318,12 +505,12 @@ func (p *AWSPlugin) s3List(params map[string]interface{}) (map[string]interface{
 			return map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}, nil
 		}
 		
-		contents := result["Contents"]
+		contents, _ := result["Contents"].([]interface{})
 		if contents == nil {
 			contents = []interface{}{}
 		}
 		
-		return map[string]interface{}{"items": contents}, nil
+		return map[string]interface{}{"items": contents, "truncated": result["NextToken"] != nil}, nil
 	}
 }
 
@@ -438,6 +625,15 @@ func (p *AWSPlugin) lambdaList(params map[string]interface{}) (map[string]interf
 		args = append(args, "--region", region)
 	}
 	
+	// The prefix is matched locally, so the cap can only be applied to the CLI when there is none
+	prefix, _ := params["prefix"].(string)
+	maxItems := getMaxItems(params)
+	if prefix == "" {
+		args = append(args, paginationArgs(maxItems)...)
+	} else {
+		args = append(args, paginationArgs(0)...)
+	}
+	
 	output, err := exec.Command("aws", args...).Output()
 	if err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("aws command failed: %v", err)}, nil
@@ -448,27 +644,55 @@ func (p *AWSPlugin) lambdaList(params map[string]interface{}) (map[string]interf
 		return map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}, nil
 	}
 	
-	functions := result["Functions"]
+	functions, _ := result["Functions"].([]interface{})
 	if functions == nil {
 		functions = []interface{}{}
 	}
+	truncated := result["NextToken"] != nil
 	
 	// Filter by prefix if provided
-	if prefix, ok := params["prefix"].(string); ok && prefix != "" {
-		if functionsList, ok := functions.([]interface{}); ok {
-			filtered := []interface{}{}
-			for _, fn := range functionsList {
-				if fnMap, ok := fn.(map[string]interface{}); ok {
-					if name, ok := fnMap["FunctionName"].(string); ok && strings.HasPrefix(name, prefix) {
-						filtered = append(filtered, fn)
-					}
+	if prefix != "" {
+		filtered := []interface{}{}
+		for _, fn := range functions {
+			if fnMap, ok := fn.(map[string]interface{}); ok {
+				if name, ok := fnMap["FunctionName"].(string); ok && strings.HasPrefix(name, prefix) {
+					filtered = append(filtered, fn)
 				}
 			}
-			functions = filtered
 		}
+		functions, truncated = capItems(filtered, maxItems)
 	}
 	
-	return map[string]interface{}{"functions": functions}, nil
+	return map[string]interface{}{"functions": functions, "truncated": truncated}, nil
+}
+
+// listPageSize is the number of results requested per API call while the CLI pages
+// through a listing; larger pages mean fewer round trips on big accounts
+const listPageSize = 1000
+
+func getMaxItems(params map[string]interface{}) int {
+	if val, ok := params["max_items"].(float64); ok && val > 0 {
+		return int(val)
+	}
+	return 0
+}
+
+// paginationArgs makes the CLI follow every page of a listing, stopping after maxItems
+// results when set. The CLI then adds a NextToken to its output if results were left out.
+func paginationArgs(maxItems int) []string {
+	args := []string{"--page-size", strconv.Itoa(listPageSize)}
+	if maxItems > 0 {
+		args = append(args, "--max-items", strconv.Itoa(maxItems))
+	}
+	return args
+}
+
+// capItems limits items to maxItems (0 means no limit) and reports whether any were dropped
+func capItems(items []interface{}, maxItems int) ([]interface{}, bool) {
+	if maxItems > 0 && len(items) > maxItems {
+		return items[:maxItems], true
+	}
+	return items, false
 }
 
 func main() {
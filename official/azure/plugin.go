@@ -0,0 +1,576 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type AzurePlugin struct{}
+
+func NewAzurePlugin() *AzurePlugin {
+	return &AzurePlugin{}
+}
+
+func (p *AzurePlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "azure",
+		Version:     "1.0.0",
+		Description: "Azure virtual machines, storage, AKS and resource groups via the az CLI",
+		Author:      "Corynth Team",
+		Tags:        []string{"azure", "cloud", "vm", "storage", "aks", "cloud-native"},
+	}
+}
+
+func (p *AzurePlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"vm_list": {
+			Description: "List virtual machines with their power state and IP addresses",
+			Inputs: withSubscription(map[string]IOSpec{
+				"resource_group": {Type: "string", Required: false, Description: "Only list VMs in this resource group"},
+			}),
+			Outputs: map[string]IOSpec{
+				"vms": {Type: "array", Description: "VMs as {name, resource_group, location, size, os_type, power_state, public_ips, private_ips, id}"},
+			},
+		},
+		"vm_start":   vmPowerActionSpec("Start a virtual machine", false),
+		"vm_stop":    vmPowerActionSpec("Stop a virtual machine", true),
+		"vm_restart": vmPowerActionSpec("Restart a virtual machine", false),
+		"vm_create": {
+			Description: "Create a Linux virtual machine with SSH authentication",
+			Inputs: withSubscription(map[string]IOSpec{
+				"resource_group": {Type: "string", Required: true, Description: "Resource group"},
+				"name":           {Type: "string", Required: true, Description: "VM name"},
+				"image":          {Type: "string", Required: true, Description: "Image alias or URN, e.g. Ubuntu2204"},
+				"size":           {Type: "string", Required: false, Default: "Standard_B1s", Description: "VM size"},
+				"admin_username": {Type: "string", Required: false, Default: "azureuser", Description: "Administrator user name"},
+				"ssh_key_value":  {Type: "string", Required: false, Description: "SSH public key, or a path to one (generates keys when omitted)"},
+				"location":       {Type: "string", Required: false, Description: "Region (defaults to the resource group's)"},
+				"public_ip":      {Type: "boolean", Required: false, Default: true, Description: "Attach a public IP address"},
+			}),
+			Outputs: map[string]IOSpec{
+				"id":          {Type: "string", Description: "VM resource ID"},
+				"power_state": {Type: "string", Description: "Power state after creation"},
+				"public_ip":   {Type: "string", Description: "Public IP address"},
+				"private_ip":  {Type: "string", Description: "Private IP address"},
+				"fqdns":       {Type: "string", Description: "Fully qualified domain names"},
+			},
+		},
+		"storage_account_list": {
+			Description: "List storage accounts",
+			Inputs: withSubscription(map[string]IOSpec{
+				"resource_group": {Type: "string", Required: false, Description: "Only list accounts in this resource group"},
+			}),
+			Outputs: map[string]IOSpec{
+				"accounts": {Type: "array", Description: "Accounts as {name, resource_group, location, kind, sku, blob_endpoint, id}"},
+			},
+		},
+		"storage_blob_upload": {
+			Description: "Upload a file to a blob container",
+			Inputs: withSubscription(map[string]IOSpec{
+				"account_name": {Type: "string", Required: true, Description: "Storage account name"},
+				"container":    {Type: "string", Required: true, Description: "Container name"},
+				"file_path":    {Type: "string", Required: true, Description: "Local file to upload"},
+				"blob_name":    {Type: "string", Required: false, Description: "Blob name (defaults to the file name)"},
+				"overwrite":    {Type: "boolean", Required: false, Default: false, Description: "Replace an existing blob"},
+				"auth_mode":    {Type: "string", Required: false, Default: "login", Description: "login (Azure AD) or key (account key)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Upload success"},
+				"url":     {Type: "string", Description: "Blob URL"},
+				"etag":    {Type: "string", Description: "Blob ETag"},
+			},
+		},
+		"aks_get_credentials": {
+			Description: "Merge an AKS cluster's credentials into a kubeconfig",
+			Inputs: withSubscription(map[string]IOSpec{
+				"resource_group": {Type: "string", Required: true, Description: "Resource group"},
+				"cluster_name":   {Type: "string", Required: true, Description: "AKS cluster name"},
+				"admin":          {Type: "boolean", Required: false, Default: false, Description: "Get cluster administrator credentials"},
+				"file":           {Type: "string", Required: false, Description: "Kubeconfig file to update (defaults to ~/.kube/config)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether credentials were merged"},
+				"context": {Type: "string", Description: "kubectl context now current"},
+			},
+		},
+		"resource_group_create": {
+			Description: "Create or update a resource group",
+			Inputs: withSubscription(map[string]IOSpec{
+				"name":     {Type: "string", Required: true, Description: "Resource group name"},
+				"location": {Type: "string", Required: true, Description: "Region, e.g. westeurope"},
+				"tags":     {Type: "object", Required: false, Description: "Tags as key/value pairs"},
+			}),
+			Outputs: map[string]IOSpec{
+				"id":                 {Type: "string", Description: "Resource group ID"},
+				"provisioning_state": {Type: "string", Description: "Provisioning state"},
+			},
+		},
+		"resource_group_delete": {
+			Description: "Delete a resource group and everything in it",
+			Inputs: withSubscription(map[string]IOSpec{
+				"name": {Type: "string", Required: true, Description: "Resource group name"},
+				"wait": {Type: "boolean", Required: false, Default: true, Description: "Wait for the deletion to finish"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the deletion finished, or was accepted when not waiting"},
+			},
+		},
+	}
+}
+
+// withSubscription adds the subscription input shared by every action
+func withSubscription(inputs map[string]IOSpec) map[string]IOSpec {
+	inputs["subscription_id"] = IOSpec{
+		Type:        "string",
+		Required:    false,
+		Description: "Subscription ID (defaults to AZURE_SUBSCRIPTION_ID, then the az CLI default)",
+	}
+	return inputs
+}
+
+func vmPowerActionSpec(description string, deallocate bool) ActionSpec {
+	inputs := map[string]IOSpec{
+		"resource_group": {Type: "string", Required: true, Description: "Resource group"},
+		"name":           {Type: "string", Required: true, Description: "VM name"},
+	}
+	if deallocate {
+		inputs["deallocate"] = IOSpec{Type: "boolean", Required: false, Default: false, Description: "Deallocate the VM so compute is no longer billed"}
+	}
+	return ActionSpec{
+		Description: description,
+		Inputs:      withSubscription(inputs),
+		Outputs: map[string]IOSpec{
+			"success":     {Type: "boolean", Description: "Operation success"},
+			"power_state": {Type: "string", Description: "Power state afterwards, e.g. VM running"},
+		},
+	}
+}
+
+func (p *AzurePlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(map[string]interface{}) (map[string]interface{}, error)
+	switch action {
+	case "vm_list":
+		run = p.vmList
+	case "vm_start", "vm_stop", "vm_restart":
+		command := strings.TrimPrefix(action, "vm_")
+		run = func(params map[string]interface{}) (map[string]interface{}, error) {
+			return p.vmPower(params, command)
+		}
+	case "vm_create":
+		run = p.vmCreate
+	case "storage_account_list":
+		run = p.storageAccountList
+	case "storage_blob_upload":
+		run = p.storageBlobUpload
+	case "aks_get_credentials":
+		run = p.aksGetCredentials
+	case "resource_group_create":
+		run = p.resourceGroupCreate
+	case "resource_group_delete":
+		run = p.resourceGroupDelete
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	result, err := run(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+// runAz runs an az command for the selected subscription and returns its JSON output
+func runAz(params map[string]interface{}, args ...string) ([]byte, error) {
+	if subscription := getStringParam(params, "subscription_id", os.Getenv("AZURE_SUBSCRIPTION_ID")); subscription != "" {
+		args = append(args, "--subscription", subscription)
+	}
+	args = append(args, "--only-show-errors", "--output", "json")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("az", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("az %s failed: %s", strings.Join(args[:2], " "), strings.TrimPrefix(message, "ERROR: "))
+		}
+		return nil, fmt.Errorf("az %s failed: %v", strings.Join(args[:2], " "), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func runAzJSON(params map[string]interface{}, result interface{}, args ...string) error {
+	output, err := runAz(params, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(output, result); err != nil {
+		return fmt.Errorf("failed to parse az output: %v", err)
+	}
+	return nil
+}
+
+func requireStrings(params map[string]interface{}, keys ...string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = getStringParam(params, key, "")
+		if values[i] == "" {
+			return nil, fmt.Errorf("%s is required", key)
+		}
+	}
+	return values, nil
+}
+
+func (p *AzurePlugin) vmList(params map[string]interface{}) (map[string]interface{}, error) {
+	args := []string{"vm", "list", "--show-details"}
+	if group := getStringParam(params, "resource_group", ""); group != "" {
+		args = append(args, "--resource-group", group)
+	}
+
+	var vms []map[string]interface{}
+	if err := runAzJSON(params, &vms, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(vms))
+	for _, vm := range vms {
+		result = append(result, map[string]interface{}{
+			"name":           vm["name"],
+			"resource_group": vm["resourceGroup"],
+			"location":       vm["location"],
+			"size":           nested(vm, "hardwareProfile", "vmSize"),
+			"os_type":        nested(vm, "storageProfile", "osDisk", "osType"),
+			"power_state":    vm["powerState"],
+			"public_ips":     splitIPs(vm["publicIps"]),
+			"private_ips":    splitIPs(vm["privateIps"]),
+			"id":             vm["id"],
+		})
+	}
+
+	return map[string]interface{}{"vms": result}, nil
+}
+
+func (p *AzurePlugin) vmPower(params map[string]interface{}, command string) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "resource_group", "name")
+	if err != nil {
+		return nil, err
+	}
+	group, name := values[0], values[1]
+
+	if command == "stop" && getBoolParam(params, "deallocate", false) {
+		command = "deallocate"
+	}
+	if _, err := runAz(params, "vm", command, "--resource-group", group, "--name", name); err != nil {
+		return nil, err
+	}
+
+	// The power commands print nothing, so read the state back
+	var powerState string
+	if err := runAzJSON(params, &powerState, "vm", "show", "--show-details", "--resource-group", group, "--name", name, "--query", "powerState"); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"success":     true,
+		"power_state": powerState,
+	}, nil
+}
+
+func (p *AzurePlugin) vmCreate(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "resource_group", "name", "image")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"vm", "create",
+		"--resource-group", values[0],
+		"--name", values[1],
+		"--image", values[2],
+		"--size", getStringParam(params, "size", "Standard_B1s"),
+		"--admin-username", getStringParam(params, "admin_username", "azureuser"),
+		"--authentication-type", "ssh",
+	}
+	if key := getStringParam(params, "ssh_key_value", ""); key != "" {
+		args = append(args, "--ssh-key-values", key)
+	} else {
+		args = append(args, "--generate-ssh-keys")
+	}
+	if location := getStringParam(params, "location", ""); location != "" {
+		args = append(args, "--location", location)
+	}
+	if !getBoolParam(params, "public_ip", true) {
+		args = append(args, "--public-ip-address", "")
+	}
+
+	var vm map[string]interface{}
+	if err := runAzJSON(params, &vm, args...); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":          vm["id"],
+		"power_state": vm["powerState"],
+		"public_ip":   vm["publicIpAddress"],
+		"private_ip":  vm["privateIpAddress"],
+		"fqdns":       vm["fqdns"],
+	}, nil
+}
+
+func (p *AzurePlugin) storageAccountList(params map[string]interface{}) (map[string]interface{}, error) {
+	args := []string{"storage", "account", "list"}
+	if group := getStringParam(params, "resource_group", ""); group != "" {
+		args = append(args, "--resource-group", group)
+	}
+
+	var accounts []map[string]interface{}
+	if err := runAzJSON(params, &accounts, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, map[string]interface{}{
+			"name":           account["name"],
+			"resource_group": account["resourceGroup"],
+			"location":       account["location"],
+			"kind":           account["kind"],
+			"sku":            nested(account, "sku", "name"),
+			"blob_endpoint":  nested(account, "primaryEndpoints", "blob"),
+			"id":             account["id"],
+		})
+	}
+
+	return map[string]interface{}{"accounts": result}, nil
+}
+
+func (p *AzurePlugin) storageBlobUpload(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "account_name", "container", "file_path")
+	if err != nil {
+		return nil, err
+	}
+	account, container, filePath := values[0], values[1], values[2]
+
+	if info, err := os.Stat(filePath); err != nil {
+		return nil, fmt.Errorf("cannot read file_path: %v", err)
+	} else if info.IsDir() {
+		return nil, errors.New("file_path must be a file, not a directory")
+	}
+
+	authMode := getStringParam(params, "auth_mode", "login")
+	if authMode != "login" && authMode != "key" {
+		return nil, fmt.Errorf("unsupported auth_mode: %s (use login or key)", authMode)
+	}
+
+	blobName := getStringParam(params, "blob_name", filepath.Base(filePath))
+	args := []string{"storage", "blob", "upload",
+		"--account-name", account,
+		"--container-name", container,
+		"--file", filePath,
+		"--name", blobName,
+		"--auth-mode", authMode,
+	}
+	if getBoolParam(params, "overwrite", false) {
+		args = append(args, "--overwrite")
+	}
+
+	var upload map[string]interface{}
+	if err := runAzJSON(params, &upload, args...); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"url":     fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", account, container, blobName),
+		"etag":    strings.Trim(fmt.Sprint(upload["etag"]), `"`),
+	}, nil
+}
+
+func (p *AzurePlugin) aksGetCredentials(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "resource_group", "cluster_name")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"aks", "get-credentials",
+		"--resource-group", values[0],
+		"--name", values[1],
+		"--overwrite-existing",
+	}
+	if getBoolParam(params, "admin", false) {
+		args = append(args, "--admin")
+	}
+	if file := getStringParam(params, "file", ""); file != "" {
+		args = append(args, "--file", file)
+	}
+
+	if _, err := runAz(params, args...); err != nil {
+		return nil, err
+	}
+
+	// az names the context after the cluster, with an -admin suffix for administrator credentials
+	context := values[1]
+	if getBoolParam(params, "admin", false) {
+		context += "-admin"
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"context": context,
+	}, nil
+}
+
+func (p *AzurePlugin) resourceGroupCreate(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "name", "location")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"group", "create", "--name", values[0], "--location", values[1]}
+	if tags, ok := params["tags"].(map[string]interface{}); ok && len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		args = append(args, "--tags")
+		for _, key := range keys {
+			args = append(args, fmt.Sprintf("%s=%v", key, tags[key]))
+		}
+	}
+
+	var group map[string]interface{}
+	if err := runAzJSON(params, &group, args...); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":                 group["id"],
+		"provisioning_state": nested(group, "properties", "provisioningState"),
+	}, nil
+}
+
+func (p *AzurePlugin) resourceGroupDelete(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"group", "delete", "--name", values[0], "--yes"}
+	if !getBoolParam(params, "wait", true) {
+		args = append(args, "--no-wait")
+	}
+	if _, err := runAz(params, args...); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"success": true}, nil
+}
+
+// nested walks a path of object keys, returning nil when any level is missing
+func nested(obj map[string]interface{}, keys ...string) interface{} {
+	var current interface{} = obj
+	for _, key := range keys {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// splitIPs turns the comma-separated address list from vm list --show-details into an array
+func splitIPs(value interface{}) []string {
+	ips := []string{}
+	s, _ := value.(string)
+	for _, ip := range strings.Split(s, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewAzurePlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"io"
 	"math"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Metadata struct {
@@ -35,6 +37,66 @@ type ActionSpec struct {
 
 type CalculatorPlugin struct{}
 
+// evaluator holds the per-call settings used while walking an expression's AST
+type evaluator struct {
+	// integer makes division truncate toward zero
+	integer bool
+	// variables binds identifiers to values; they take precedence over pi and e
+	variables map[string]float64
+	// resolved records the variables the expression actually referenced
+	resolved map[string]float64
+}
+
+// mathFunction describes a function callable from expressions; maxArgs < 0 means variadic
+type mathFunction struct {
+	minArgs int
+	maxArgs int
+	call    func(args []float64) float64
+}
+
+// mathFunctions is the whitelist of callable functions; any other name is rejected
+var mathFunctions = map[string]mathFunction{
+	"sqrt":  {1, 1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, 1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"pow":   {2, 2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"log":   {1, 2, logFunc},
+	"log10": {1, 1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"ln":    {1, 1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"exp":   {1, 1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"sin":   {1, 1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, 1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, 1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"floor": {1, 1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, 1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {1, 1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"min":   {1, -1, minFunc},
+	"max":   {1, -1, maxFunc},
+}
+
+// logFunc is the natural logarithm, or the logarithm in the given base when called as log(x, base)
+func logFunc(a []float64) float64 {
+	if len(a) == 2 {
+		return math.Log(a[0]) / math.Log(a[1])
+	}
+	return math.Log(a[0])
+}
+
+func minFunc(a []float64) float64 {
+	result := a[0]
+	for _, v := range a[1:] {
+		result = math.Min(result, v)
+	}
+	return result
+}
+
+func maxFunc(a []float64) float64 {
+	result := a[0]
+	for _, v := range a[1:] {
+		result = math.Max(result, v)
+	}
+	return result
+}
+
 func NewCalculatorPlugin() *CalculatorPlugin {
 	return &CalculatorPlugin{}
 }
@@ -57,7 +119,41 @@ func (p *CalculatorPlugin) GetActions() map[string]ActionSpec {
 				"expression": {
 					Type:        "string",
 					Required:    true,
-					Description: "Mathematical expression to evaluate (supports +, -, *, /, %, parentheses)",
+					Description: "Mathematical expression to evaluate (supports +, -, *, /, %, ^, parentheses, pi, e, and sqrt, abs, pow, log, log10, ln, exp, sin, cos, tan, floor, ceil, round, min, max)",
+				},
+				"precision": {
+					Type:        "number",
+					Required:    false,
+					Default:     2,
+					Description: "Decimal precision for results",
+				},
+				"output_type": {
+					Type:        "string",
+					Required:    false,
+					Default:     "float",
+					Description: "float, or int for truncating integer division and an integer result",
+				},
+				"variables": {
+					Type:        "object",
+					Required:    false,
+					Description: "Values for identifiers in the expression, e.g. {\"a\": 2, \"x\": 5}",
+				},
+			},
+			Outputs: map[string]IOSpec{
+				"result":      {Type: "number", Description: "Calculation result"},
+				"expression":  {Type: "string", Description: "Original expression"},
+				"output_type": {Type: "string", Description: "Output type used"},
+				"is_integer":  {Type: "boolean", Description: "Whether the result has no fractional part"},
+				"variables":   {Type: "object", Description: "Variables referenced by the expression and their values"},
+			},
+		},
+		"batch": {
+			Description: "Evaluate many expressions in one call, continuing past individual failures",
+			Inputs: map[string]IOSpec{
+				"expressions": {
+					Type:        "array",
+					Required:    true,
+					Description: "Array of expressions, or an object mapping names to expressions",
 				},
 				"precision": {
 					Type:        "number",
@@ -65,10 +161,22 @@ func (p *CalculatorPlugin) GetActions() map[string]ActionSpec {
 					Default:     2,
 					Description: "Decimal precision for results",
 				},
+				"output_type": {
+					Type:        "string",
+					Required:    false,
+					Default:     "float",
+					Description: "float, or int for truncating integer division and integer results",
+				},
+				"variables": {
+					Type:        "object",
+					Required:    false,
+					Description: "Values for identifiers, shared by every expression",
+				},
 			},
 			Outputs: map[string]IOSpec{
-				"result":     {Type: "number", Description: "Calculation result"},
-				"expression": {Type: "string", Description: "Original expression"},
+				"results":   {Type: "array", Description: "Per-expression results (an object when expressions is an object); failed items carry an error"},
+				"succeeded": {Type: "number", Description: "Number of expressions evaluated successfully"},
+				"failed":    {Type: "number", Description: "Number of expressions that failed"},
 			},
 		},
 	}
@@ -78,6 +186,8 @@ func (p *CalculatorPlugin) Execute(action string, params map[string]interface{})
 	switch action {
 	case "calculate":
 		return p.calculate(params)
+	case "batch":
+		return p.batch(params)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
@@ -97,8 +207,31 @@ func (p *CalculatorPlugin) calculate(params map[string]interface{}) (map[string]
 		}
 	}
 
+	outputType := "float"
+	if ot, ok := params["output_type"].(string); ok && ot != "" {
+		outputType = ot
+	}
+	if outputType != "float" && outputType != "int" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported output_type: %s (use float or int)", outputType)}, nil
+	}
+
+	ev := &evaluator{
+		integer:   outputType == "int",
+		variables: make(map[string]float64),
+		resolved:  make(map[string]float64),
+	}
+	if vars, ok := params["variables"].(map[string]interface{}); ok {
+		for name, value := range vars {
+			num, ok := value.(float64)
+			if !ok {
+				return map[string]interface{}{"error": fmt.Sprintf("variable %s must be a number", name)}, nil
+			}
+			ev.variables[name] = num
+		}
+	}
+
 	// Parse and evaluate the expression using AST
-	result, err := p.evaluateExpression(expression)
+	result, err := p.evaluateExpression(expression, ev)
 	if err != nil {
 		return map[string]interface{}{
 			"error":      fmt.Sprintf("Invalid expression: %v", err),
@@ -106,6 +239,30 @@ func (p *CalculatorPlugin) calculate(params map[string]interface{}) (map[string]
 		}, nil
 	}
 
+	if ev.integer {
+		// Converting NaN, infinities or values outside int64 range is undefined, so reject them
+		if math.IsNaN(result) || math.IsInf(result, 0) {
+			return map[string]interface{}{
+				"error":      fmt.Sprintf("result %v is not a finite number", result),
+				"expression": expression,
+			}, nil
+		}
+		truncated := math.Trunc(result)
+		if truncated < math.MinInt64 || truncated >= -math.MinInt64 {
+			return map[string]interface{}{
+				"error":      fmt.Sprintf("result %g does not fit in a 64-bit integer", result),
+				"expression": expression,
+			}, nil
+		}
+		return map[string]interface{}{
+			"result":      int64(truncated),
+			"expression":  expression,
+			"output_type": outputType,
+			"is_integer":  truncated == result,
+			"variables":   ev.resolved,
+		}, nil
+	}
+
 	// Apply precision
 	if precision > 0 {
 		multiplier := math.Pow(10, float64(precision))
@@ -115,12 +272,76 @@ func (p *CalculatorPlugin) calculate(params map[string]interface{}) (map[string]
 	}
 
 	return map[string]interface{}{
-		"result":     result,
-		"expression": expression,
+		"result":      result,
+		"expression":  expression,
+		"output_type": outputType,
+		"is_integer":  result == math.Trunc(result),
+		"variables":   ev.resolved,
+	}, nil
+}
+
+func (p *CalculatorPlugin) batch(params map[string]interface{}) (map[string]interface{}, error) {
+	succeeded, failed := 0, 0
+
+	// Each item goes through calculate with the shared settings, so results match single calls
+	evaluate := func(item interface{}) map[string]interface{} {
+		expression, ok := item.(string)
+		if !ok {
+			failed++
+			return map[string]interface{}{"error": "expression must be a string"}
+		}
+
+		itemParams := map[string]interface{}{"expression": expression}
+		for _, key := range []string{"precision", "output_type", "variables"} {
+			if val, ok := params[key]; ok {
+				itemParams[key] = val
+			}
+		}
+
+		result, _ := p.calculate(itemParams)
+		if _, hasErr := result["error"]; hasErr {
+			failed++
+		} else {
+			succeeded++
+		}
+		return result
+	}
+
+	var results interface{}
+	switch expressions := params["expressions"].(type) {
+	case []interface{}:
+		list := make([]map[string]interface{}, len(expressions))
+		for i, expression := range expressions {
+			list[i] = evaluate(expression)
+		}
+		results = list
+	case map[string]interface{}:
+		named := make(map[string]interface{}, len(expressions))
+		for name, expression := range expressions {
+			named[name] = evaluate(expression)
+		}
+		results = named
+	default:
+		return map[string]interface{}{"error": "expressions parameter is required (array or object)"}, nil
+	}
+
+	return map[string]interface{}{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    failed,
 	}, nil
 }
 
-func (p *CalculatorPlugin) evaluateExpression(expr string) (float64, error) {
+func (p *CalculatorPlugin) evaluateExpression(expr string, ev *evaluator) (float64, error) {
+	// Go has no exponent operator, so a^b is rewritten to pow(a, b) before parsing
+	if strings.Contains(expr, "^") {
+		rewritten, err := rewritePowers(expr)
+		if err != nil {
+			return 0, err
+		}
+		expr = rewritten
+	}
+
 	// Parse the expression into an AST
 	node, err := parser.ParseExpr(expr)
 	if err != nil {
@@ -128,41 +349,177 @@ func (p *CalculatorPlugin) evaluateExpression(expr string) (float64, error) {
 	}
 
 	// Evaluate the AST
-	return p.evalNode(node)
+	return ev.evalNode(node)
+}
+
+// rewritePowers tokenizes expr and replaces every a ^ b with pow(a, b). It binds tighter than
+// unary minus and is right-associative, so -2^2 is -4 and 2^3^2 is 2^(3^2).
+func rewritePowers(expr string) (string, error) {
+	var tokens []string
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(expr))
+	var scanErr error
+	s.Init(file, []byte(expr), func(_ token.Position, msg string) { scanErr = fmt.Errorf("syntax error: %s", msg) }, 0)
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// The scanner inserts a semicolon at the end of the line
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		if lit != "" {
+			tokens = append(tokens, lit)
+		} else {
+			tokens = append(tokens, tok.String())
+		}
+	}
+	if scanErr != nil {
+		return "", scanErr
+	}
+
+	// Rewrite from the rightmost operator so chains nest right-associatively
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i] != "^" {
+			continue
+		}
+
+		start := primaryStart(tokens, i)
+		if start < 0 {
+			return "", fmt.Errorf("missing left operand for ^")
+		}
+
+		// The exponent may carry its own sign, as in 2^-1
+		end := i + 1
+		if end < len(tokens) && (tokens[end] == "-" || tokens[end] == "+") {
+			end++
+		}
+		end = primaryEnd(tokens, end)
+		if end < 0 {
+			return "", fmt.Errorf("missing right operand for ^")
+		}
+
+		rewritten := []string{"pow", "("}
+		rewritten = append(rewritten, tokens[start:i]...)
+		rewritten = append(rewritten, ",")
+		rewritten = append(rewritten, tokens[i+1:end]...)
+		rewritten = append(rewritten, ")")
+		tokens = append(tokens[:start], append(rewritten, tokens[end:]...)...)
+		i = start
+	}
+
+	return strings.Join(tokens, " "), nil
+}
+
+// primaryStart returns the index where the operand ending just before tokens[i] begins, or -1
+func primaryStart(tokens []string, i int) int {
+	j := i - 1
+	if j < 0 {
+		return -1
+	}
+	if tokens[j] != ")" {
+		if isOperandToken(tokens[j]) {
+			return j
+		}
+		return -1
+	}
+
+	// Walk back to the matching parenthesis, then include a function name if there is one
+	depth := 0
+	for ; j >= 0; j-- {
+		switch tokens[j] {
+		case ")":
+			depth++
+		case "(":
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j < 0 {
+		return -1
+	}
+	if j > 0 && isOperandToken(tokens[j-1]) {
+		j--
+	}
+	return j
 }
 
-func (p *CalculatorPlugin) evalNode(node ast.Node) (float64, error) {
+// primaryEnd returns the index just past the operand starting at tokens[i], or -1
+func primaryEnd(tokens []string, i int) int {
+	if i >= len(tokens) || !isOperandToken(tokens[i]) && tokens[i] != "(" {
+		return -1
+	}
+	if tokens[i] != "(" {
+		i++
+		if i >= len(tokens) || tokens[i] != "(" {
+			return i
+		}
+	}
+
+	// Consume a parenthesized group (or a function call's argument list)
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// isOperandToken reports whether tok is a number or identifier
+func isOperandToken(tok string) bool {
+	r := tok[0]
+	return r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func (ev *evaluator) evalNode(node ast.Node) (float64, error) {
 	switch n := node.(type) {
 	case *ast.BinaryExpr:
-		return p.evalBinaryExpr(n)
+		return ev.evalBinaryExpr(n)
 	case *ast.UnaryExpr:
-		return p.evalUnaryExpr(n)
+		return ev.evalUnaryExpr(n)
 	case *ast.ParenExpr:
-		return p.evalNode(n.X)
+		return ev.evalNode(n.X)
 	case *ast.BasicLit:
-		return p.evalBasicLit(n)
+		return ev.evalBasicLit(n)
+	case *ast.CallExpr:
+		return ev.evalCallExpr(n)
 	case *ast.Ident:
-		// Only allow math constants
+		// Bound variables first, then the math constants; nothing else resolves
+		if val, ok := ev.variables[n.Name]; ok {
+			ev.resolved[n.Name] = val
+			return val, nil
+		}
 		switch n.Name {
 		case "pi":
 			return math.Pi, nil
 		case "e":
 			return math.E, nil
 		default:
-			return 0, fmt.Errorf("undefined identifier: %s", n.Name)
+			return 0, fmt.Errorf("unbound variable: %s", n.Name)
 		}
 	default:
 		return 0, fmt.Errorf("unsupported expression type: %T", node)
 	}
 }
 
-func (p *CalculatorPlugin) evalBinaryExpr(expr *ast.BinaryExpr) (float64, error) {
-	left, err := p.evalNode(expr.X)
+func (ev *evaluator) evalBinaryExpr(expr *ast.BinaryExpr) (float64, error) {
+	left, err := ev.evalNode(expr.X)
 	if err != nil {
 		return 0, err
 	}
 
-	right, err := p.evalNode(expr.Y)
+	right, err := ev.evalNode(expr.Y)
 	if err != nil {
 		return 0, err
 	}
@@ -178,6 +535,9 @@ func (p *CalculatorPlugin) evalBinaryExpr(expr *ast.BinaryExpr) (float64, error)
 		if right == 0 {
 			return 0, fmt.Errorf("division by zero")
 		}
+		if ev.integer {
+			return math.Trunc(left / right), nil
+		}
 		return left / right, nil
 	case token.REM:
 		if right == 0 {
@@ -189,8 +549,8 @@ func (p *CalculatorPlugin) evalBinaryExpr(expr *ast.BinaryExpr) (float64, error)
 	}
 }
 
-func (p *CalculatorPlugin) evalUnaryExpr(expr *ast.UnaryExpr) (float64, error) {
-	operand, err := p.evalNode(expr.X)
+func (ev *evaluator) evalUnaryExpr(expr *ast.UnaryExpr) (float64, error) {
+	operand, err := ev.evalNode(expr.X)
 	if err != nil {
 		return 0, err
 	}
@@ -205,7 +565,47 @@ func (p *CalculatorPlugin) evalUnaryExpr(expr *ast.UnaryExpr) (float64, error) {
 	}
 }
 
-func (p *CalculatorPlugin) evalBasicLit(lit *ast.BasicLit) (float64, error) {
+func (ev *evaluator) evalCallExpr(expr *ast.CallExpr) (float64, error) {
+	// Only plain function names are callable, never selectors like math.Sqrt
+	ident, ok := expr.Fun.(*ast.Ident)
+	if !ok {
+		return 0, fmt.Errorf("unsupported function call: %T", expr.Fun)
+	}
+
+	fn, ok := mathFunctions[ident.Name]
+	if !ok {
+		return 0, fmt.Errorf("unknown function: %s", ident.Name)
+	}
+
+	if expr.Ellipsis.IsValid() {
+		return 0, fmt.Errorf("%s: variadic arguments are not supported", ident.Name)
+	}
+
+	argCount := len(expr.Args)
+	switch {
+	case argCount < fn.minArgs:
+		return 0, fmt.Errorf("%s expects at least %d argument(s), got %d", ident.Name, fn.minArgs, argCount)
+	case fn.maxArgs >= 0 && argCount > fn.maxArgs:
+		return 0, fmt.Errorf("%s expects at most %d argument(s), got %d", ident.Name, fn.maxArgs, argCount)
+	}
+
+	args := make([]float64, argCount)
+	for i, arg := range expr.Args {
+		val, err := ev.evalNode(arg)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = val
+	}
+
+	result := fn.call(args)
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, fmt.Errorf("%s: result is undefined for the given arguments", ident.Name)
+	}
+	return result, nil
+}
+
+func (ev *evaluator) evalBasicLit(lit *ast.BasicLit) (float64, error) {
 	switch lit.Kind {
 	case token.INT:
 		val, err := strconv.ParseInt(lit.Value, 10, 64)
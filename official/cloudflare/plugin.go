@@ -0,0 +1,740 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// defaultAPIURL is used unless CF_API_URL overrides it
+const defaultAPIURL = "https://api.cloudflare.com/client/v4"
+
+// purgeBatchSize is the most URLs one purge_cache request accepts
+const purgeBatchSize = 30
+
+type CloudflarePlugin struct {
+	apiToken string
+	baseURL  string
+	client   *http.Client
+}
+
+// apiResponse is the envelope wrapped around every Cloudflare v4 JSON response
+type apiResponse struct {
+	Success    bool            `json:"success"`
+	Errors     []apiMessage    `json:"errors"`
+	Result     json.RawMessage `json:"result"`
+	ResultInfo struct {
+		Page       int `json:"page"`
+		TotalPages int `json:"total_pages"`
+		TotalCount int `json:"total_count"`
+	} `json:"result_info"`
+}
+
+type apiMessage struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewCloudflarePlugin() *CloudflarePlugin {
+	baseURL := os.Getenv("CF_API_URL")
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	return &CloudflarePlugin{
+		apiToken: os.Getenv("CF_API_TOKEN"),
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (p *CloudflarePlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "cloudflare",
+		Version:     "1.0.0",
+		Description: "Cloudflare DNS records, cache purging, Workers and Workers KV",
+		Author:      "Corynth Team",
+		Tags:        []string{"cloudflare", "dns", "cdn", "cache", "workers", "kv"},
+	}
+}
+
+func (p *CloudflarePlugin) GetActions() map[string]ActionSpec {
+	recordOutputs := map[string]IOSpec{
+		"id":      {Type: "string", Description: "Record ID"},
+		"type":    {Type: "string", Description: "Record type"},
+		"name":    {Type: "string", Description: "Fully qualified record name"},
+		"content": {Type: "string", Description: "Record content"},
+		"ttl":     {Type: "number", Description: "TTL in seconds (1 means automatic)"},
+		"proxied": {Type: "boolean", Description: "Whether traffic is proxied through Cloudflare"},
+	}
+	kvInputs := func(extra map[string]IOSpec) map[string]IOSpec {
+		inputs := map[string]IOSpec{
+			"account_id":   {Type: "string", Required: true, Description: "Account ID"},
+			"namespace_id": {Type: "string", Required: true, Description: "KV namespace ID"},
+			"key":          {Type: "string", Required: true, Description: "Key name"},
+		}
+		for name, spec := range extra {
+			inputs[name] = spec
+		}
+		return inputs
+	}
+
+	return map[string]ActionSpec{
+		"dns_list": {
+			Description: "List DNS records in a zone",
+			Inputs: map[string]IOSpec{
+				"zone_id": {Type: "string", Required: true, Description: "Zone ID"},
+				"type":    {Type: "string", Required: false, Description: "Only records of this type, e.g. A or CNAME"},
+				"name":    {Type: "string", Required: false, Description: "Only records with this fully qualified name"},
+			},
+			Outputs: map[string]IOSpec{
+				"records": {Type: "array", Description: "Records as {id, type, name, content, ttl, proxied, priority}"},
+				"count":   {Type: "number", Description: "Number of records"},
+			},
+		},
+		"dns_create": {
+			Description: "Create a DNS record",
+			Inputs: map[string]IOSpec{
+				"zone_id":  {Type: "string", Required: true, Description: "Zone ID"},
+				"type":     {Type: "string", Required: true, Description: "Record type, e.g. A, AAAA, CNAME, TXT or MX"},
+				"name":     {Type: "string", Required: true, Description: "Record name, e.g. www or www.example.com"},
+				"content":  {Type: "string", Required: true, Description: "Record content, e.g. an IP address or hostname"},
+				"ttl":      {Type: "number", Required: false, Default: 1, Description: "TTL in seconds (1 means automatic)"},
+				"proxied":  {Type: "boolean", Required: false, Default: false, Description: "Proxy traffic through Cloudflare"},
+				"priority": {Type: "number", Required: false, Description: "Priority for MX records"},
+			},
+			Outputs: recordOutputs,
+		},
+		"dns_update": {
+			Description: "Update the content or proxy status of a DNS record",
+			Inputs: map[string]IOSpec{
+				"zone_id":   {Type: "string", Required: true, Description: "Zone ID"},
+				"record_id": {Type: "string", Required: true, Description: "Record ID"},
+				"content":   {Type: "string", Required: false, Description: "New record content"},
+				"proxied":   {Type: "boolean", Required: false, Description: "New proxy status"},
+				"ttl":       {Type: "number", Required: false, Description: "New TTL in seconds (1 means automatic)"},
+			},
+			Outputs: recordOutputs,
+		},
+		"dns_delete": {
+			Description: "Delete a DNS record",
+			Inputs: map[string]IOSpec{
+				"zone_id":   {Type: "string", Required: true, Description: "Zone ID"},
+				"record_id": {Type: "string", Required: true, Description: "Record ID"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":   {Type: "boolean", Description: "Whether the record was deleted"},
+				"record_id": {Type: "string", Description: "Deleted record ID"},
+			},
+		},
+		"cache_purge_all": {
+			Description: "Purge everything from a zone's cache",
+			Inputs: map[string]IOSpec{
+				"zone_id": {Type: "string", Required: true, Description: "Zone ID"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the purge was accepted"},
+			},
+		},
+		"cache_purge_files": {
+			Description: "Purge specific URLs from a zone's cache",
+			Inputs: map[string]IOSpec{
+				"zone_id": {Type: "string", Required: true, Description: "Zone ID"},
+				"files":   {Type: "array", Required: true, Description: "Full URLs to purge; sent in batches of 30"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether every batch was accepted"},
+				"purged":  {Type: "number", Description: "Number of URLs purged"},
+			},
+		},
+		"worker_deploy": {
+			Description: "Upload a Worker script, creating or replacing it",
+			Inputs: map[string]IOSpec{
+				"account_id":         {Type: "string", Required: true, Description: "Account ID"},
+				"script_name":        {Type: "string", Required: true, Description: "Worker name"},
+				"script_content":     {Type: "string", Required: true, Description: "JavaScript source"},
+				"format":             {Type: "string", Required: false, Description: "module or service-worker (detected from export default when omitted)"},
+				"compatibility_date": {Type: "string", Required: false, Description: "Workers compatibility date, e.g. 2024-09-23"},
+			},
+			Outputs: map[string]IOSpec{
+				"id":          {Type: "string", Description: "Worker name"},
+				"etag":        {Type: "string", Description: "Script ETag"},
+				"modified_on": {Type: "string", Description: "Time of this deployment"},
+				"format":      {Type: "string", Description: "Format the script was uploaded as"},
+			},
+		},
+		"kv_put": {
+			Description: "Write a value to a Workers KV namespace",
+			Inputs: kvInputs(map[string]IOSpec{
+				"value":          {Type: "string", Required: true, Description: "Value to store"},
+				"expiration_ttl": {Type: "number", Required: false, Description: "Seconds until the key expires (at least 60)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the value was written"},
+			},
+		},
+		"kv_get": {
+			Description: "Read a value from a Workers KV namespace",
+			Inputs:      kvInputs(nil),
+			Outputs: map[string]IOSpec{
+				"found": {Type: "boolean", Description: "Whether the key exists"},
+				"value": {Type: "string", Description: "Stored value"},
+			},
+		},
+		"kv_delete": {
+			Description: "Delete a key from a Workers KV namespace",
+			Inputs:      kvInputs(nil),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the key was deleted"},
+			},
+		},
+	}
+}
+
+func (p *CloudflarePlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(map[string]interface{}) (map[string]interface{}, error)
+	switch action {
+	case "dns_list":
+		run = p.dnsList
+	case "dns_create":
+		run = p.dnsCreate
+	case "dns_update":
+		run = p.dnsUpdate
+	case "dns_delete":
+		run = p.dnsDelete
+	case "cache_purge_all":
+		run = p.cachePurgeAll
+	case "cache_purge_files":
+		run = p.cachePurgeFiles
+	case "worker_deploy":
+		run = p.workerDeploy
+	case "kv_put":
+		run = p.kvPut
+	case "kv_get":
+		run = p.kvGet
+	case "kv_delete":
+		run = p.kvDelete
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	if p.apiToken == "" {
+		return map[string]interface{}{"error": "CF_API_TOKEN not configured"}, nil
+	}
+
+	result, err := run(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func requireStrings(params map[string]interface{}, keys ...string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = getStringParam(params, key, "")
+		if values[i] == "" {
+			return nil, fmt.Errorf("%s is required", key)
+		}
+	}
+	return values, nil
+}
+
+func (p *CloudflarePlugin) dnsList(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone_id")
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if recordType := getStringParam(params, "type", ""); recordType != "" {
+		query.Set("type", strings.ToUpper(recordType))
+	}
+	if name := getStringParam(params, "name", ""); name != "" {
+		query.Set("name", name)
+	}
+	query.Set("per_page", "100")
+
+	records := []map[string]interface{}{}
+	for page := 1; ; page++ {
+		query.Set("page", fmt.Sprint(page))
+		resp, err := p.callAPI("GET", "/zones/"+url.PathEscape(values[0])+"/dns_records?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []map[string]interface{}
+		if err := json.Unmarshal(resp.Result, &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse records: %v", err)
+		}
+		for _, record := range batch {
+			entry := recordResult(record)
+			if priority, ok := record["priority"]; ok {
+				entry["priority"] = priority
+			}
+			records = append(records, entry)
+		}
+
+		if page >= resp.ResultInfo.TotalPages {
+			break
+		}
+	}
+
+	return map[string]interface{}{
+		"records": records,
+		"count":   len(records),
+	}, nil
+}
+
+func (p *CloudflarePlugin) dnsCreate(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone_id", "type", "name", "content")
+	if err != nil {
+		return nil, err
+	}
+
+	record := map[string]interface{}{
+		"type":    strings.ToUpper(values[1]),
+		"name":    values[2],
+		"content": values[3],
+		"ttl":     getIntParam(params, "ttl", 1),
+		"proxied": getBoolParam(params, "proxied", false),
+	}
+	if priority, ok := params["priority"].(float64); ok {
+		record["priority"] = int(priority)
+	}
+
+	return p.saveRecord("POST", "/zones/"+url.PathEscape(values[0])+"/dns_records", record)
+}
+
+func (p *CloudflarePlugin) dnsUpdate(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone_id", "record_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// PATCH leaves every field that is not sent unchanged
+	changes := map[string]interface{}{}
+	if content := getStringParam(params, "content", ""); content != "" {
+		changes["content"] = content
+	}
+	if proxied, ok := params["proxied"].(bool); ok {
+		changes["proxied"] = proxied
+	}
+	if ttl, ok := params["ttl"].(float64); ok {
+		changes["ttl"] = int(ttl)
+	}
+	if len(changes) == 0 {
+		return nil, errors.New("at least one of content, proxied or ttl is required")
+	}
+
+	return p.saveRecord("PATCH", "/zones/"+url.PathEscape(values[0])+"/dns_records/"+url.PathEscape(values[1]), changes)
+}
+
+func (p *CloudflarePlugin) saveRecord(method, path string, body map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := p.callAPI(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(resp.Result, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse record: %v", err)
+	}
+	return recordResult(record), nil
+}
+
+func (p *CloudflarePlugin) dnsDelete(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone_id", "record_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.callAPI("DELETE", "/zones/"+url.PathEscape(values[0])+"/dns_records/"+url.PathEscape(values[1]), nil); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"record_id": values[1],
+	}, nil
+}
+
+func (p *CloudflarePlugin) cachePurgeAll(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.callAPI("POST", "/zones/"+url.PathEscape(values[0])+"/purge_cache", map[string]interface{}{"purge_everything": true}); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"success": true}, nil
+}
+
+func (p *CloudflarePlugin) cachePurgeFiles(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone_id")
+	if err != nil {
+		return nil, err
+	}
+	files := getStringSlice(params, "files")
+	if len(files) == 0 {
+		return nil, errors.New("files is required")
+	}
+
+	purged := 0
+	for start := 0; start < len(files); start += purgeBatchSize {
+		end := start + purgeBatchSize
+		if end > len(files) {
+			end = len(files)
+		}
+		if _, err := p.callAPI("POST", "/zones/"+url.PathEscape(values[0])+"/purge_cache", map[string]interface{}{"files": files[start:end]}); err != nil {
+			return nil, fmt.Errorf("%v (%d of %d URLs purged)", err, purged, len(files))
+		}
+		purged = end
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"purged":  purged,
+	}, nil
+}
+
+func (p *CloudflarePlugin) workerDeploy(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "account_id", "script_name", "script_content")
+	if err != nil {
+		return nil, err
+	}
+	script := values[2]
+
+	format := getStringParam(params, "format", "")
+	if format == "" {
+		format = "service-worker"
+		if strings.Contains(script, "export default") {
+			format = "module"
+		}
+	}
+
+	// Module workers name their entry point with main_module, service workers with body_part
+	metadata := map[string]interface{}{}
+	partName, contentType := "worker.js", "application/javascript+module"
+	switch format {
+	case "module":
+		metadata["main_module"] = partName
+	case "service-worker":
+		partName, contentType = "script", "application/javascript"
+		metadata["body_part"] = partName
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (use module or service-worker)", format)
+	}
+	if date := getStringParam(params, "compatibility_date", ""); date != "" {
+		metadata["compatibility_date"] = date
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %v", err)
+	}
+	if err := writeFormPart(writer, "metadata", "", "application/json", metadataJSON); err != nil {
+		return nil, err
+	}
+	if err := writeFormPart(writer, partName, partName, contentType, []byte(script)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build upload: %v", err)
+	}
+
+	path := "/accounts/" + url.PathEscape(values[0]) + "/workers/scripts/" + url.PathEscape(values[1])
+	status, data, err := p.send("PUT", path, writer.FormDataContentType(), &body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := decodeResponse(status, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var worker map[string]interface{}
+	if err := json.Unmarshal(resp.Result, &worker); err != nil {
+		return nil, fmt.Errorf("failed to parse worker: %v", err)
+	}
+
+	return map[string]interface{}{
+		"id":          worker["id"],
+		"etag":        worker["etag"],
+		"modified_on": worker["modified_on"],
+		"format":      format,
+	}, nil
+}
+
+func writeFormPart(writer *multipart.Writer, name, filename, contentType string, content []byte) error {
+	header := textproto.MIMEHeader{}
+	disposition := fmt.Sprintf(`form-data; name="%s"`, name)
+	if filename != "" {
+		disposition += fmt.Sprintf(`; filename="%s"`, filename)
+	}
+	header.Set("Content-Disposition", disposition)
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to build upload: %v", err)
+	}
+	_, err = part.Write(content)
+	return err
+}
+
+func (p *CloudflarePlugin) kvPut(params map[string]interface{}) (map[string]interface{}, error) {
+	path, err := kvValuePath(params)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := params["value"].(string)
+	if !ok {
+		return nil, errors.New("value is required")
+	}
+	if ttl := getIntParam(params, "expiration_ttl", 0); ttl > 0 {
+		path += "?expiration_ttl=" + fmt.Sprint(ttl)
+	}
+
+	status, data, err := p.send("PUT", path, "text/plain", strings.NewReader(value))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decodeResponse(status, data); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"success": true}, nil
+}
+
+func (p *CloudflarePlugin) kvGet(params map[string]interface{}) (map[string]interface{}, error) {
+	path, err := kvValuePath(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Values come back raw rather than in the JSON envelope; only errors are wrapped
+	status, data, err := p.send("GET", path, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return map[string]interface{}{"found": false, "value": nil}, nil
+	}
+	if status >= 300 {
+		_, err := decodeResponse(status, data)
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"found": true,
+		"value": string(data),
+	}, nil
+}
+
+func (p *CloudflarePlugin) kvDelete(params map[string]interface{}) (map[string]interface{}, error) {
+	path, err := kvValuePath(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.callAPI("DELETE", path, nil); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"success": true}, nil
+}
+
+func kvValuePath(params map[string]interface{}) (string, error) {
+	values, err := requireStrings(params, "account_id", "namespace_id", "key")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/accounts/%s/storage/kv/namespaces/%s/values/%s",
+		url.PathEscape(values[0]), url.PathEscape(values[1]), url.PathEscape(values[2])), nil
+}
+
+// recordResult extracts the fields every DNS record action returns
+func recordResult(record map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":      record["id"],
+		"type":    record["type"],
+		"name":    record["name"],
+		"content": record["content"],
+		"ttl":     record["ttl"],
+		"proxied": record["proxied"],
+	}
+}
+
+// callAPI sends a JSON request and unwraps the v4 response envelope
+func (p *CloudflarePlugin) callAPI(method, path string, body interface{}) (*apiResponse, error) {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	status, data, err := p.send(method, path, contentType, reader)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResponse(status, data)
+}
+
+// send performs an authenticated request and returns the status code and raw body
+func (p *CloudflarePlugin) send(method, path, contentType string, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequest(method, p.baseURL+path, body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	return resp.StatusCode, data, nil
+}
+
+// decodeResponse parses the response envelope, turning its errors list into an error
+func decodeResponse(status int, data []byte) (*apiResponse, error) {
+	var resp apiResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		if status >= 300 {
+			return nil, fmt.Errorf("Cloudflare API error (%d): %s", status, strings.TrimSpace(string(data)))
+		}
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if status >= 300 || !resp.Success {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, fmt.Sprintf("%s (code %d)", e.Message, e.Code))
+		}
+		if len(messages) == 0 {
+			messages = append(messages, strings.TrimSpace(string(data)))
+		}
+		return nil, fmt.Errorf("Cloudflare API error (%d): %s", status, strings.Join(messages, "; "))
+	}
+	return &resp, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewCloudflarePlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
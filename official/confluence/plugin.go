@@ -0,0 +1,658 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type ConfluencePlugin struct {
+	baseURL string
+	email   string
+	token   string
+	client  *http.Client
+}
+
+func NewConfluencePlugin() *ConfluencePlugin {
+	// Accept the site URL with or without the /wiki context path
+	baseURL := strings.TrimRight(os.Getenv("CONFLUENCE_URL"), "/")
+	baseURL = strings.TrimSuffix(baseURL, "/wiki")
+
+	return &ConfluencePlugin{
+		baseURL: baseURL,
+		email:   os.Getenv("CONFLUENCE_EMAIL"),
+		token:   os.Getenv("CONFLUENCE_TOKEN"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (p *ConfluencePlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "confluence",
+		Version:     "1.0.0",
+		Description: "Confluence page publishing, updates, CQL search, labels and attachments",
+		Author:      "Corynth Team",
+		Tags:        []string{"confluence", "atlassian", "documentation", "wiki"},
+	}
+}
+
+func (p *ConfluencePlugin) GetActions() map[string]ActionSpec {
+	pageOutputs := map[string]IOSpec{
+		"page_id":  {Type: "string", Description: "Page ID"},
+		"title":    {Type: "string", Description: "Page title"},
+		"space_id": {Type: "string", Description: "ID of the space containing the page"},
+		"status":   {Type: "string", Description: "Page status (e.g. current, draft)"},
+		"version":  {Type: "number", Description: "Current version number"},
+		"body":     {Type: "string", Description: "Page body in storage format"},
+		"url":      {Type: "string", Description: "Page web URL"},
+	}
+
+	return map[string]ActionSpec{
+		"create_page": {
+			Description: "Create a page in a space",
+			Inputs: map[string]IOSpec{
+				"space_key":    {Type: "string", Required: true, Description: "Space key (e.g. OPS)"},
+				"title":        {Type: "string", Required: true, Description: "Page title"},
+				"body_storage": {Type: "string", Required: false, Description: "Page body in storage format (XHTML)"},
+				"body_wiki":    {Type: "string", Required: false, Description: "Page body in wiki markup, used instead of body_storage"},
+				"parent_id":    {Type: "string", Required: false, Description: "Parent page ID (defaults to the space homepage)"},
+			},
+			Outputs: pageOutputs,
+		},
+		"update_page": {
+			Description: "Replace the title and body of a page",
+			Inputs: map[string]IOSpec{
+				"page_id":        {Type: "string", Required: true, Description: "Page ID"},
+				"title":          {Type: "string", Required: false, Description: "Page title (keeps the current title when omitted)"},
+				"body":           {Type: "string", Required: true, Description: "New page body"},
+				"representation": {Type: "string", Required: false, Default: "storage", Description: "Body format: storage or wiki"},
+				"version":        {Type: "number", Required: false, Description: "New version number, which must be the current version + 1 (looked up when omitted)"},
+				"message":        {Type: "string", Required: false, Description: "Version comment"},
+			},
+			Outputs: pageOutputs,
+		},
+		"get_page": {
+			Description: "Get a page by ID, or by space key and title",
+			Inputs: map[string]IOSpec{
+				"page_id":   {Type: "string", Required: false, Description: "Page ID"},
+				"space_key": {Type: "string", Required: false, Description: "Space key, used with title when page_id is omitted"},
+				"title":     {Type: "string", Required: false, Description: "Exact page title, used with space_key"},
+			},
+			Outputs: pageOutputs,
+		},
+		"search_pages": {
+			Description: "Search content with CQL",
+			Inputs: map[string]IOSpec{
+				"cql":   {Type: "string", Required: true, Description: "CQL query (e.g. space = OPS and title ~ \"report\")"},
+				"limit": {Type: "number", Required: false, Default: 25, Description: "Maximum number of results to return"},
+			},
+			Outputs: map[string]IOSpec{
+				"results": {Type: "array", Description: "Matches with page_id, type, title, excerpt and url"},
+				"count":   {Type: "number", Description: "Number of results returned"},
+				"total":   {Type: "number", Description: "Total number of matching results"},
+			},
+		},
+		"add_label": {
+			Description: "Add labels to a page",
+			Inputs: map[string]IOSpec{
+				"page_id": {Type: "string", Required: true, Description: "Page ID"},
+				"labels":  {Type: "array", Required: true, Description: "Label names to add"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the labels were added"},
+				"page_id": {Type: "string", Description: "Page ID"},
+				"labels":  {Type: "array", Description: "All labels now on the page"},
+			},
+		},
+		"upload_attachment": {
+			Description: "Attach a file to a page, adding a new version if the file name already exists",
+			Inputs: map[string]IOSpec{
+				"page_id":   {Type: "string", Required: true, Description: "Page ID"},
+				"file_path": {Type: "string", Required: true, Description: "Local file to upload"},
+				"comment":   {Type: "string", Required: false, Description: "Attachment comment"},
+			},
+			Outputs: map[string]IOSpec{
+				"attachment_id": {Type: "string", Description: "Attachment ID"},
+				"title":         {Type: "string", Description: "Attachment file name"},
+				"version":       {Type: "number", Description: "Attachment version number"},
+				"download_url":  {Type: "string", Description: "Attachment download URL"},
+			},
+		},
+	}
+}
+
+func (p *ConfluencePlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	if p.baseURL == "" || p.email == "" || p.token == "" {
+		return map[string]interface{}{"error": "CONFLUENCE_URL, CONFLUENCE_EMAIL and CONFLUENCE_TOKEN must be set"}, nil
+	}
+
+	switch action {
+	case "create_page":
+		return p.createPage(params)
+	case "update_page":
+		return p.updatePage(params)
+	case "get_page":
+		return p.getPage(params)
+	case "search_pages":
+		return p.searchPages(params)
+	case "add_label":
+		return p.addLabel(params)
+	case "upload_attachment":
+		return p.uploadAttachment(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *ConfluencePlugin) createPage(params map[string]interface{}) (map[string]interface{}, error) {
+	spaceKey := getStringParam(params, "space_key", "")
+	title := getStringParam(params, "title", "")
+	if spaceKey == "" || title == "" {
+		return map[string]interface{}{"error": "space_key and title are required"}, nil
+	}
+
+	representation, value := "storage", getStringParam(params, "body_storage", "")
+	if wiki := getStringParam(params, "body_wiki", ""); wiki != "" {
+		if value != "" {
+			return map[string]interface{}{"error": "body_storage and body_wiki are mutually exclusive"}, nil
+		}
+		representation, value = "wiki", wiki
+	}
+
+	spaceID, err := p.spaceID(spaceKey)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	body := map[string]interface{}{
+		"spaceId": spaceID,
+		"status":  "current",
+		"title":   title,
+		"body":    map[string]interface{}{"representation": representation, "value": value},
+	}
+	if parentID := getIDParam(params, "parent_id"); parentID != "" {
+		body["parentId"] = parentID
+	}
+
+	created, err := p.callAPI("POST", "/api/v2/pages", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return p.pageResult(created), nil
+}
+
+func (p *ConfluencePlugin) updatePage(params map[string]interface{}) (map[string]interface{}, error) {
+	pageID := getIDParam(params, "page_id")
+	if pageID == "" {
+		return map[string]interface{}{"error": "page_id is required"}, nil
+	}
+	value, ok := params["body"].(string)
+	if !ok {
+		return map[string]interface{}{"error": "body is required"}, nil
+	}
+
+	representation := getStringParam(params, "representation", "storage")
+	if representation != "storage" && representation != "wiki" {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid representation %q (use storage or wiki)", representation)}, nil
+	}
+
+	title := getStringParam(params, "title", "")
+	version := getIntParam(params, "version", 0)
+
+	// Confluence rejects updates whose version is not exactly current + 1, so fill it in when not given
+	if title == "" || version == 0 {
+		current, err := p.callAPI("GET", "/api/v2/pages/"+url.PathEscape(pageID), nil)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		if title == "" {
+			title, _ = current["title"].(string)
+		}
+		if version == 0 {
+			version = pageVersion(current) + 1
+		}
+	}
+
+	versionBody := map[string]interface{}{"number": version}
+	if message := getStringParam(params, "message", ""); message != "" {
+		versionBody["message"] = message
+	}
+
+	updated, err := p.callAPI("PUT", "/api/v2/pages/"+url.PathEscape(pageID), map[string]interface{}{
+		"id":      pageID,
+		"status":  "current",
+		"title":   title,
+		"body":    map[string]interface{}{"representation": representation, "value": value},
+		"version": versionBody,
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return p.pageResult(updated), nil
+}
+
+func (p *ConfluencePlugin) getPage(params map[string]interface{}) (map[string]interface{}, error) {
+	if pageID := getIDParam(params, "page_id"); pageID != "" {
+		page, err := p.callAPI("GET", "/api/v2/pages/"+url.PathEscape(pageID)+"?body-format=storage", nil)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		return p.pageResult(page), nil
+	}
+
+	spaceKey := getStringParam(params, "space_key", "")
+	title := getStringParam(params, "title", "")
+	if spaceKey == "" || title == "" {
+		return map[string]interface{}{"error": "page_id, or space_key and title, is required"}, nil
+	}
+
+	spaceID, err := p.spaceID(spaceKey)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	query := url.Values{}
+	query.Set("space-id", spaceID)
+	query.Set("title", title)
+	query.Set("body-format", "storage")
+	found, err := p.callAPI("GET", "/api/v2/pages?"+query.Encode(), nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	results, _ := found["results"].([]interface{})
+	if len(results) == 0 {
+		return map[string]interface{}{"error": fmt.Sprintf("page %q not found in space %s", title, spaceKey)}, nil
+	}
+	page, _ := results[0].(map[string]interface{})
+	return p.pageResult(page), nil
+}
+
+// searchPages uses the v1 search endpoint because API v2 has no CQL support
+func (p *ConfluencePlugin) searchPages(params map[string]interface{}) (map[string]interface{}, error) {
+	cql := getStringParam(params, "cql", "")
+	if cql == "" {
+		return map[string]interface{}{"error": "cql is required"}, nil
+	}
+
+	query := url.Values{}
+	query.Set("cql", cql)
+	query.Set("limit", strconv.Itoa(getIntParam(params, "limit", 25)))
+	found, err := p.callAPI("GET", "/rest/api/search?"+query.Encode(), nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	results := []interface{}{}
+	items, _ := found["results"].([]interface{})
+	for _, item := range items {
+		match, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		content, _ := match["content"].(map[string]interface{})
+		if content == nil {
+			content = map[string]interface{}{}
+		}
+		title, _ := match["title"].(string)
+		if contentTitle, ok := content["title"].(string); ok {
+			title = contentTitle
+		}
+		excerpt, _ := match["excerpt"].(string)
+		contentType, _ := content["type"].(string)
+
+		results = append(results, map[string]interface{}{
+			"page_id": content["id"],
+			"type":    contentType,
+			"title":   title,
+			"excerpt": excerpt,
+			"url":     p.webURL(nestedString(match, "url")),
+		})
+	}
+
+	return map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+		"total":   found["totalSize"],
+	}, nil
+}
+
+// addLabel uses the v1 content API because API v2 can only read labels
+func (p *ConfluencePlugin) addLabel(params map[string]interface{}) (map[string]interface{}, error) {
+	pageID := getIDParam(params, "page_id")
+	labels := getStringSlice(params, "labels")
+	if pageID == "" || len(labels) == 0 {
+		return map[string]interface{}{"error": "page_id and labels are required"}, nil
+	}
+
+	body := make([]map[string]interface{}, 0, len(labels))
+	for _, label := range labels {
+		body = append(body, map[string]interface{}{"prefix": "global", "name": label})
+	}
+
+	added, err := p.callAPI("POST", "/rest/api/content/"+url.PathEscape(pageID)+"/label", body)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	names := []string{}
+	items, _ := added["results"].([]interface{})
+	for _, item := range items {
+		if label, ok := item.(map[string]interface{}); ok {
+			if name, ok := label["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"page_id": pageID,
+		"labels":  names,
+	}, nil
+}
+
+// uploadAttachment uses the v1 content API because API v2 cannot upload files
+func (p *ConfluencePlugin) uploadAttachment(params map[string]interface{}) (map[string]interface{}, error) {
+	pageID := getIDParam(params, "page_id")
+	filePath := getStringParam(params, "file_path", "")
+	if pageID == "" || filePath == "" {
+		return map[string]interface{}{"error": "page_id and file_path are required"}, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to open file: %v", err)}, nil
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to build upload: %v", err)}, nil
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to read file: %v", err)}, nil
+	}
+	if comment := getStringParam(params, "comment", ""); comment != "" {
+		writer.WriteField("comment", comment)
+	}
+	writer.WriteField("minorEdit", "true")
+	if err := writer.Close(); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to build upload: %v", err)}, nil
+	}
+
+	// PUT creates the attachment, or adds a version when one with the same file name exists
+	req, err := http.NewRequest("PUT", p.baseURL+"/wiki/rest/api/content/"+url.PathEscape(pageID)+"/child/attachment", &buf)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	uploaded, err := p.send(req)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	results, _ := uploaded["results"].([]interface{})
+	if len(results) == 0 {
+		return map[string]interface{}{"error": "upload returned no attachment"}, nil
+	}
+	attachment, _ := results[0].(map[string]interface{})
+	title, _ := attachment["title"].(string)
+
+	return map[string]interface{}{
+		"attachment_id": attachment["id"],
+		"title":         title,
+		"version":       pageVersion(attachment),
+		"download_url":  p.webURL(nestedString(attachment, "_links", "download")),
+	}, nil
+}
+
+// spaceID resolves a space key to the numeric ID that API v2 requires
+func (p *ConfluencePlugin) spaceID(key string) (string, error) {
+	found, err := p.callAPI("GET", "/api/v2/spaces?keys="+url.QueryEscape(key), nil)
+	if err != nil {
+		return "", err
+	}
+	results, _ := found["results"].([]interface{})
+	if len(results) == 0 {
+		return "", fmt.Errorf("space %s not found", key)
+	}
+	space, _ := results[0].(map[string]interface{})
+	id, _ := space["id"].(string)
+	return id, nil
+}
+
+// pageResult flattens the commonly used fields of an API v2 page
+func (p *ConfluencePlugin) pageResult(page map[string]interface{}) map[string]interface{} {
+	id, _ := page["id"].(string)
+	title, _ := page["title"].(string)
+	spaceID, _ := page["spaceId"].(string)
+	status, _ := page["status"].(string)
+
+	return map[string]interface{}{
+		"page_id":  id,
+		"title":    title,
+		"space_id": spaceID,
+		"status":   status,
+		"version":  pageVersion(page),
+		"body":     nestedString(page, "body", "storage", "value"),
+		"url":      p.webURL(nestedString(page, "_links", "webui")),
+	}
+}
+
+// webURL turns a link relative to the /wiki context path into an absolute URL
+func (p *ConfluencePlugin) webURL(link string) string {
+	if link == "" || strings.HasPrefix(link, "http") {
+		return link
+	}
+	return p.baseURL + "/wiki" + link
+}
+
+// callAPI sends a JSON request to a path under /wiki and decodes the JSON object it returns
+func (p *ConfluencePlugin) callAPI(method, path string, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+"/wiki"+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return p.send(req)
+}
+
+func (p *ConfluencePlugin) send(req *http.Request) (map[string]interface{}, error) {
+	req.SetBasicAuth(p.email, p.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, apiError(resp.StatusCode, result, data)
+	}
+	return result, nil
+}
+
+// apiError reads both the v2 errors list and the v1 message field
+func apiError(status int, result map[string]interface{}, raw []byte) error {
+	var parts []string
+	if errs, ok := result["errors"].([]interface{}); ok {
+		for _, item := range errs {
+			e, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			msg := nestedString(e, "title")
+			if detail := nestedString(e, "detail"); detail != "" {
+				msg = strings.TrimSpace(msg + " " + detail)
+			}
+			if msg != "" {
+				parts = append(parts, msg)
+			}
+		}
+	}
+	if message, ok := result["message"].(string); ok && message != "" {
+		parts = append(parts, message)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, strings.TrimSpace(string(raw)))
+	}
+	return fmt.Errorf("Confluence API error (%d): %s", status, strings.Join(parts, "; "))
+}
+
+func pageVersion(item map[string]interface{}) int {
+	version, _ := item["version"].(map[string]interface{})
+	number, _ := version["number"].(float64)
+	return int(number)
+}
+
+func nestedString(m map[string]interface{}, keys ...string) string {
+	var current interface{} = m
+	for _, key := range keys {
+		next, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = next[key]
+	}
+	s, _ := current.(string)
+	return s
+}
+
+// getIDParam accepts page IDs given either as strings or as JSON numbers
+func getIDParam(params map[string]interface{}, key string) string {
+	switch val := params[key].(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatInt(int64(val), 10)
+	}
+	return ""
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewConfluencePlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
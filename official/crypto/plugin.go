@@ -0,0 +1,562 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type CryptoPlugin struct{}
+
+func NewCryptoPlugin() *CryptoPlugin {
+	return &CryptoPlugin{}
+}
+
+func (p *CryptoPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "crypto",
+		Version:     "1.0.0",
+		Description: "AES and RSA encryption, HMAC signing and key generation",
+		Author:      "Corynth Team",
+		Tags:        []string{"crypto", "encryption", "aes", "rsa", "hmac", "security"},
+	}
+}
+
+func (p *CryptoPlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"aes_encrypt": {
+			Description: "Encrypt text with AES",
+			Inputs: map[string]IOSpec{
+				"plaintext": {Type: "string", Required: true, Description: "Text to encrypt"},
+				"key_hex":   {Type: "string", Required: true, Description: "Hex-encoded 16, 24 or 32 byte key"},
+				"mode":      {Type: "string", Required: false, Default: "GCM", Description: "GCM (authenticated) or CBC (PKCS#7 padded)"},
+				"iv_hex":    {Type: "string", Required: false, Description: "Hex-encoded IV, 12 bytes for GCM or 16 for CBC (random by default; never reuse one with the same key)"},
+			},
+			Outputs: map[string]IOSpec{
+				"ciphertext_hex": {Type: "string", Description: "Hex-encoded ciphertext; for GCM the authentication tag is appended"},
+				"iv_hex":         {Type: "string", Description: "Hex-encoded IV, needed to decrypt"},
+				"mode":           {Type: "string", Description: "Mode used"},
+			},
+		},
+		"aes_decrypt": {
+			Description: "Decrypt AES ciphertext",
+			Inputs: map[string]IOSpec{
+				"ciphertext_hex": {Type: "string", Required: true, Description: "Hex-encoded ciphertext"},
+				"key_hex":        {Type: "string", Required: true, Description: "Hex-encoded 16, 24 or 32 byte key"},
+				"mode":           {Type: "string", Required: false, Default: "GCM", Description: "GCM or CBC"},
+				"iv_hex":         {Type: "string", Required: true, Description: "Hex-encoded IV returned by aes_encrypt"},
+			},
+			Outputs: map[string]IOSpec{
+				"plaintext": {Type: "string", Description: "Decrypted text"},
+			},
+		},
+		"rsa_encrypt": {
+			Description: "Encrypt text with an RSA public key (OAEP, SHA-256)",
+			Inputs: map[string]IOSpec{
+				"plaintext":      {Type: "string", Required: true, Description: "Text to encrypt; at most the key size in bytes minus 66"},
+				"public_key_pem": {Type: "string", Required: true, Description: "PEM public key (PKIX or PKCS#1) or certificate"},
+			},
+			Outputs: map[string]IOSpec{
+				"ciphertext_b64": {Type: "string", Description: "Base64-encoded ciphertext"},
+			},
+		},
+		"rsa_decrypt": {
+			Description: "Decrypt RSA OAEP ciphertext",
+			Inputs: map[string]IOSpec{
+				"ciphertext_b64":  {Type: "string", Required: true, Description: "Base64-encoded ciphertext"},
+				"private_key_pem": {Type: "string", Required: true, Description: "PEM private key (PKCS#8 or PKCS#1)"},
+			},
+			Outputs: map[string]IOSpec{
+				"plaintext": {Type: "string", Description: "Decrypted text"},
+			},
+		},
+		"hmac": {
+			Description: "Compute an HMAC signature",
+			Inputs: map[string]IOSpec{
+				"data":      {Type: "string", Required: true, Description: "Data to sign"},
+				"key_hex":   {Type: "string", Required: true, Description: "Hex-encoded key"},
+				"algorithm": {Type: "string", Required: false, Default: "SHA256", Description: "SHA256 or SHA512"},
+			},
+			Outputs: map[string]IOSpec{
+				"signature_hex": {Type: "string", Description: "Hex-encoded signature"},
+			},
+		},
+		"verify_hmac": {
+			Description: "Check an HMAC signature in constant time",
+			Inputs: map[string]IOSpec{
+				"data":          {Type: "string", Required: true, Description: "Signed data"},
+				"signature_hex": {Type: "string", Required: true, Description: "Hex-encoded signature to check"},
+				"key_hex":       {Type: "string", Required: true, Description: "Hex-encoded key"},
+				"algorithm":     {Type: "string", Required: false, Default: "SHA256", Description: "SHA256 or SHA512"},
+			},
+			Outputs: map[string]IOSpec{
+				"valid": {Type: "boolean", Description: "Whether the signature matches"},
+			},
+		},
+		"generate_key": {
+			Description: "Generate a random AES key or an RSA key pair",
+			Inputs: map[string]IOSpec{
+				"algorithm": {Type: "string", Required: false, Default: "AES256", Description: "AES128, AES256, RSA2048 or RSA4096"},
+			},
+			Outputs: map[string]IOSpec{
+				"algorithm":       {Type: "string", Description: "Algorithm the key is for"},
+				"key":             {Type: "string", Description: "AES key, base64-encoded"},
+				"key_hex":         {Type: "string", Description: "AES key, hex-encoded for aes_encrypt and hmac"},
+				"private_key_pem": {Type: "string", Description: "RSA private key (PKCS#8 PEM)"},
+				"public_key_pem":  {Type: "string", Description: "RSA public key (PKIX PEM)"},
+			},
+		},
+	}
+}
+
+func (p *CryptoPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(map[string]interface{}) (map[string]interface{}, error)
+	switch action {
+	case "aes_encrypt":
+		run = p.aesEncrypt
+	case "aes_decrypt":
+		run = p.aesDecrypt
+	case "rsa_encrypt":
+		run = p.rsaEncrypt
+	case "rsa_decrypt":
+		run = p.rsaDecrypt
+	case "hmac":
+		run = p.hmacSign
+	case "verify_hmac":
+		run = p.verifyHMAC
+	case "generate_key":
+		run = p.generateKey
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	result, err := run(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func (p *CryptoPlugin) aesEncrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	plaintext, ok := params["plaintext"].(string)
+	if !ok {
+		return nil, errors.New("plaintext is required")
+	}
+	block, err := aesCipher(params)
+	if err != nil {
+		return nil, err
+	}
+	mode := strings.ToUpper(getStringParam(params, "mode", "GCM"))
+
+	var ciphertext, iv []byte
+	switch mode {
+	case "GCM":
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		if iv, err = ivParam(params, gcm.NonceSize(), true); err != nil {
+			return nil, err
+		}
+		ciphertext = gcm.Seal(nil, iv, []byte(plaintext), nil)
+	case "CBC":
+		if iv, err = ivParam(params, aes.BlockSize, true); err != nil {
+			return nil, err
+		}
+		padded := pkcs7Pad([]byte(plaintext), aes.BlockSize)
+		ciphertext = make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+	default:
+		return nil, fmt.Errorf("unsupported mode: %s (use GCM or CBC)", mode)
+	}
+
+	return map[string]interface{}{
+		"ciphertext_hex": hex.EncodeToString(ciphertext),
+		"iv_hex":         hex.EncodeToString(iv),
+		"mode":           mode,
+	}, nil
+}
+
+func (p *CryptoPlugin) aesDecrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	ciphertext, err := hexParam(params, "ciphertext_hex")
+	if err != nil {
+		return nil, err
+	}
+	block, err := aesCipher(params)
+	if err != nil {
+		return nil, err
+	}
+	mode := strings.ToUpper(getStringParam(params, "mode", "GCM"))
+
+	var plaintext []byte
+	switch mode {
+	case "GCM":
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		iv, err := ivParam(params, gcm.NonceSize(), false)
+		if err != nil {
+			return nil, err
+		}
+		if plaintext, err = gcm.Open(nil, iv, ciphertext, nil); err != nil {
+			return nil, errors.New("decryption failed: wrong key or IV, or the ciphertext was modified")
+		}
+	case "CBC":
+		iv, err := ivParam(params, aes.BlockSize, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("ciphertext must be a non-empty multiple of %d bytes for CBC", aes.BlockSize)
+		}
+		decrypted := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, ciphertext)
+		if plaintext, err = pkcs7Unpad(decrypted, aes.BlockSize); err != nil {
+			return nil, errors.New("decryption failed: wrong key or IV, or the ciphertext was modified")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported mode: %s (use GCM or CBC)", mode)
+	}
+
+	return map[string]interface{}{
+		"plaintext": string(plaintext),
+	}, nil
+}
+
+func (p *CryptoPlugin) rsaEncrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	plaintext, ok := params["plaintext"].(string)
+	if !ok {
+		return nil, errors.New("plaintext is required")
+	}
+	key, err := parsePublicKey(getStringParam(params, "public_key_pem", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, []byte(plaintext), nil)
+	if err != nil {
+		return nil, fmt.Errorf("encryption failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"ciphertext_b64": base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+func (p *CryptoPlugin) rsaDecrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(getStringParam(params, "ciphertext_b64", ""))
+	if err != nil || len(ciphertext) == 0 {
+		return nil, errors.New("ciphertext_b64 must be non-empty base64")
+	}
+	key, err := parsePrivateKey(getStringParam(params, "private_key_pem", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("decryption failed: wrong key or corrupted ciphertext")
+	}
+
+	return map[string]interface{}{
+		"plaintext": string(plaintext),
+	}, nil
+}
+
+func (p *CryptoPlugin) hmacSign(params map[string]interface{}) (map[string]interface{}, error) {
+	mac, err := newHMAC(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"signature_hex": hex.EncodeToString(mac),
+	}, nil
+}
+
+func (p *CryptoPlugin) verifyHMAC(params map[string]interface{}) (map[string]interface{}, error) {
+	signature, err := hexParam(params, "signature_hex")
+	if err != nil {
+		return nil, err
+	}
+	mac, err := newHMAC(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"valid": hmac.Equal(mac, signature),
+	}, nil
+}
+
+func (p *CryptoPlugin) generateKey(params map[string]interface{}) (map[string]interface{}, error) {
+	algorithm := strings.ToUpper(getStringParam(params, "algorithm", "AES256"))
+
+	switch algorithm {
+	case "AES128", "AES256":
+		key := make([]byte, 16)
+		if algorithm == "AES256" {
+			key = make([]byte, 32)
+		}
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"algorithm": algorithm,
+			"key":       base64.StdEncoding.EncodeToString(key),
+			"key_hex":   hex.EncodeToString(key),
+		}, nil
+
+	case "RSA2048", "RSA4096":
+		bits := 2048
+		if algorithm == "RSA4096" {
+			bits = 4096
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %v", err)
+		}
+		private, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"algorithm":       algorithm,
+			"private_key_pem": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private})),
+			"public_key_pem":  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported algorithm: %s (use AES128, AES256, RSA2048 or RSA4096)", algorithm)
+}
+
+func aesCipher(params map[string]interface{}) (cipher.Block, error) {
+	key, err := hexParam(params, "key_hex")
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		return nil, fmt.Errorf("key_hex must decode to 16, 24 or 32 bytes, got %d", len(key))
+	}
+	return aes.NewCipher(key)
+}
+
+// ivParam decodes iv_hex, generating a random IV when it is absent and generate is set
+func ivParam(params map[string]interface{}, size int, generate bool) ([]byte, error) {
+	if getStringParam(params, "iv_hex", "") == "" {
+		if !generate {
+			return nil, errors.New("iv_hex is required")
+		}
+		iv := make([]byte, size)
+		_, err := rand.Read(iv)
+		return iv, err
+	}
+	iv, err := hexParam(params, "iv_hex")
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != size {
+		return nil, fmt.Errorf("iv_hex must decode to %d bytes, got %d", size, len(iv))
+	}
+	return iv, nil
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize || padding > len(data) {
+		return nil, errors.New("invalid padding")
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("invalid padding")
+		}
+	}
+	return data[:len(data)-padding], nil
+}
+
+func newHMAC(params map[string]interface{}) ([]byte, error) {
+	data, ok := params["data"].(string)
+	if !ok {
+		return nil, errors.New("data is required")
+	}
+	key, err := hexParam(params, "key_hex")
+	if err != nil {
+		return nil, err
+	}
+
+	var newHash func() hash.Hash
+	switch algorithm := strings.ToUpper(getStringParam(params, "algorithm", "SHA256")); algorithm {
+	case "SHA256":
+		newHash = sha256.New
+	case "SHA512":
+		newHash = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s (use SHA256 or SHA512)", algorithm)
+	}
+
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil), nil
+}
+
+func parsePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("public_key_pem must be a PEM-encoded RSA public key")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public_key_pem is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("private_key_pem must be a PEM-encoded RSA private key")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %v", err)
+		}
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private_key_pem is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func hexParam(params map[string]interface{}, key string) ([]byte, error) {
+	value := getStringParam(params, key, "")
+	if value == "" {
+		return nil, fmt.Errorf("%s is required", key)
+	}
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid hex: %v", key, err)
+	}
+	return decoded, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewCryptoPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -0,0 +1,647 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// defaultSite is the Datadog region used unless DD_SITE or the site input says otherwise
+const defaultSite = "datadoghq.com"
+
+// appKeyActions read or change account configuration and need an application key
+var appKeyActions = map[string]bool{
+	"create_monitor": true,
+	"get_monitor":    true,
+	"mute_monitor":   true,
+	"query_metrics":  true,
+}
+
+type DatadogPlugin struct {
+	client *http.Client
+}
+
+func NewDatadogPlugin() *DatadogPlugin {
+	return &DatadogPlugin{
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *DatadogPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "datadog",
+		Version:     "1.0.0",
+		Description: "Datadog metrics, events and monitors",
+		Author:      "Corynth Team",
+		Tags:        []string{"datadog", "monitoring", "metrics", "observability", "alerting"},
+	}
+}
+
+func (p *DatadogPlugin) GetActions() map[string]ActionSpec {
+	withAuth := func(inputs map[string]IOSpec) map[string]IOSpec {
+		inputs["api_key"] = IOSpec{Type: "string", Required: false, Description: "API key (defaults to DD_API_KEY)"}
+		inputs["app_key"] = IOSpec{Type: "string", Required: false, Description: "Application key (defaults to DD_APP_KEY)"}
+		inputs["site"] = IOSpec{Type: "string", Required: false, Default: defaultSite, Description: "Datadog site, e.g. datadoghq.eu (defaults to DD_SITE)"}
+		return inputs
+	}
+
+	monitorOutputs := map[string]IOSpec{
+		"monitor_id":    {Type: "number", Description: "Monitor ID"},
+		"name":          {Type: "string", Description: "Monitor name"},
+		"type":          {Type: "string", Description: "Monitor type"},
+		"query":         {Type: "string", Description: "Monitor query"},
+		"overall_state": {Type: "string", Description: "Current state (OK, Alert, Warn, No Data)"},
+	}
+
+	return map[string]ActionSpec{
+		"submit_metric": {
+			Description: "Submit metric points",
+			Inputs: withAuth(map[string]IOSpec{
+				"series": {Type: "array", Required: true, Description: "Series of {metric, points, tags, type: gauge/count/rate, host, interval}; points are values or [timestamp, value] pairs"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success":      {Type: "boolean", Description: "Whether the metrics were accepted"},
+				"series_count": {Type: "number", Description: "Number of series submitted"},
+			},
+		},
+		"post_event": {
+			Description: "Post an event to the event stream",
+			Inputs: withAuth(map[string]IOSpec{
+				"title":           {Type: "string", Required: true, Description: "Event title"},
+				"text":            {Type: "string", Required: true, Description: "Event body (supports markdown with %%% delimiters)"},
+				"alert_type":      {Type: "string", Required: false, Default: "info", Description: "error, warning, info, success"},
+				"tags":            {Type: "array", Required: false, Description: "Tags such as env:prod"},
+				"aggregation_key": {Type: "string", Required: false, Description: "Key used to group related events"},
+			}),
+			Outputs: map[string]IOSpec{
+				"event_id": {Type: "number", Description: "Event ID"},
+				"url":      {Type: "string", Description: "Event URL"},
+			},
+		},
+		"create_monitor": {
+			Description: "Create a monitor",
+			Inputs: withAuth(map[string]IOSpec{
+				"name":       {Type: "string", Required: true, Description: "Monitor name"},
+				"type":       {Type: "string", Required: false, Default: "metric alert", Description: "Monitor type (metric alert, query alert, service check, log alert, ...)"},
+				"query":      {Type: "string", Required: true, Description: "Monitor query"},
+				"message":    {Type: "string", Required: false, Description: "Notification message, including @-mentions"},
+				"tags":       {Type: "array", Required: false, Description: "Monitor tags"},
+				"thresholds": {Type: "object", Required: false, Description: "Thresholds such as {critical: 90, warning: 80}"},
+				"options":    {Type: "object", Required: false, Description: "Additional monitor options"},
+			}),
+			Outputs: monitorOutputs,
+		},
+		"get_monitor": {
+			Description: "Get a monitor",
+			Inputs: withAuth(map[string]IOSpec{
+				"monitor_id": {Type: "number", Required: true, Description: "Monitor ID"},
+			}),
+			Outputs: map[string]IOSpec{
+				"monitor_id":    {Type: "number", Description: "Monitor ID"},
+				"name":          {Type: "string", Description: "Monitor name"},
+				"type":          {Type: "string", Description: "Monitor type"},
+				"query":         {Type: "string", Description: "Monitor query"},
+				"overall_state": {Type: "string", Description: "Current state (OK, Alert, Warn, No Data)"},
+				"message":       {Type: "string", Description: "Notification message"},
+				"tags":          {Type: "array", Description: "Monitor tags"},
+				"options":       {Type: "object", Description: "Monitor options, including thresholds and silenced scopes"},
+			},
+		},
+		"mute_monitor": {
+			Description: "Mute a monitor",
+			Inputs: withAuth(map[string]IOSpec{
+				"monitor_id": {Type: "number", Required: true, Description: "Monitor ID"},
+				"end":        {Type: "string", Required: false, Description: "When to unmute, as a Unix timestamp or RFC 3339 time (mutes indefinitely if omitted)"},
+				"scope":      {Type: "string", Required: false, Description: "Scope to mute, e.g. host:web-1"},
+			}),
+			Outputs: map[string]IOSpec{
+				"monitor_id": {Type: "number", Description: "Monitor ID"},
+				"muted":      {Type: "boolean", Description: "Whether the monitor is muted"},
+				"end":        {Type: "number", Description: "Unix time the mute ends, if set"},
+			},
+		},
+		"query_metrics": {
+			Description: "Query metric timeseries",
+			Inputs: withAuth(map[string]IOSpec{
+				"query": {Type: "string", Required: true, Description: "Metric query, e.g. avg:system.cpu.user{env:prod}"},
+				"from":  {Type: "string", Required: false, Description: "Start as a Unix timestamp or RFC 3339 time (defaults to one hour ago)"},
+				"to":    {Type: "string", Required: false, Description: "End as a Unix timestamp or RFC 3339 time (defaults to now)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"series": {Type: "array", Description: "Series with metric, scope and [timestamp, value] points"},
+				"from":   {Type: "number", Description: "Queried start time"},
+				"to":     {Type: "number", Description: "Queried end time"},
+			},
+		},
+	}
+}
+
+func (p *DatadogPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	apiKey := getStringParam(params, "api_key", os.Getenv("DD_API_KEY"))
+	if apiKey == "" {
+		return map[string]interface{}{"error": "api_key or DD_API_KEY is required"}, nil
+	}
+	appKey := getStringParam(params, "app_key", os.Getenv("DD_APP_KEY"))
+
+	api := &datadogAPI{
+		baseURL: apiBaseURL(getStringParam(params, "site", os.Getenv("DD_SITE"))),
+		apiKey:  apiKey,
+		appKey:  appKey,
+		client:  p.client,
+	}
+
+	// Only metric and event submission work with an API key alone
+	if appKey == "" && appKeyActions[action] {
+		return map[string]interface{}{"error": "app_key or DD_APP_KEY is required for " + action}, nil
+	}
+
+	switch action {
+	case "submit_metric":
+		return p.submitMetric(api, params)
+	case "post_event":
+		return p.postEvent(api, params)
+	case "create_monitor":
+		return p.createMonitor(api, params)
+	case "get_monitor":
+		return p.getMonitor(api, params)
+	case "mute_monitor":
+		return p.muteMonitor(api, params)
+	case "query_metrics":
+		return p.queryMetrics(api, params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *DatadogPlugin) submitMetric(api *datadogAPI, params map[string]interface{}) (map[string]interface{}, error) {
+	items, ok := params["series"].([]interface{})
+	if !ok || len(items) == 0 {
+		return map[string]interface{}{"error": "series is required"}, nil
+	}
+
+	now := time.Now().Unix()
+	series := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		s, ok := item.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{"error": fmt.Sprintf("series[%d] must be an object", i)}, nil
+		}
+
+		metric := getStringParam(s, "metric", "")
+		if metric == "" {
+			return map[string]interface{}{"error": fmt.Sprintf("series[%d].metric is required", i)}, nil
+		}
+
+		metricType := getStringParam(s, "type", "gauge")
+		if metricType != "gauge" && metricType != "count" && metricType != "rate" {
+			return map[string]interface{}{"error": fmt.Sprintf("series[%d]: unsupported type %q (use gauge, count or rate)", i, metricType)}, nil
+		}
+
+		points, err := metricPoints(s["points"], now)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("series[%d]: %v", i, err)}, nil
+		}
+
+		entry := map[string]interface{}{
+			"metric": metric,
+			"type":   metricType,
+			"points": points,
+		}
+		if tags := getStringSlice(s, "tags"); len(tags) > 0 {
+			entry["tags"] = tags
+		}
+		if host := getStringParam(s, "host", ""); host != "" {
+			entry["host"] = host
+		}
+		if interval, ok := s["interval"].(float64); ok {
+			entry["interval"] = int64(interval)
+		}
+		series = append(series, entry)
+	}
+
+	if _, err := api.call("POST", "/api/v1/series", nil, map[string]interface{}{"series": series}); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"success":      true,
+		"series_count": len(series),
+	}, nil
+}
+
+// metricPoints accepts bare values (stamped with now) or [timestamp, value] pairs
+func metricPoints(raw interface{}, now int64) ([][2]float64, error) {
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		if value, ok := raw.(float64); ok {
+			return [][2]float64{{float64(now), value}}, nil
+		}
+		return nil, fmt.Errorf("points is required")
+	}
+
+	points := make([][2]float64, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			points = append(points, [2]float64{float64(now), v})
+		case []interface{}:
+			if len(v) != 2 {
+				return nil, fmt.Errorf("points must be values or [timestamp, value] pairs")
+			}
+			ts, tsOK := v[0].(float64)
+			value, valueOK := v[1].(float64)
+			if !tsOK || !valueOK {
+				return nil, fmt.Errorf("points must be values or [timestamp, value] pairs")
+			}
+			points = append(points, [2]float64{ts, value})
+		default:
+			return nil, fmt.Errorf("points must be values or [timestamp, value] pairs")
+		}
+	}
+	return points, nil
+}
+
+func (p *DatadogPlugin) postEvent(api *datadogAPI, params map[string]interface{}) (map[string]interface{}, error) {
+	title := getStringParam(params, "title", "")
+	text := getStringParam(params, "text", "")
+	if title == "" || text == "" {
+		return map[string]interface{}{"error": "title and text are required"}, nil
+	}
+
+	event := map[string]interface{}{
+		"title":      title,
+		"text":       text,
+		"alert_type": getStringParam(params, "alert_type", "info"),
+	}
+	if tags := getStringSlice(params, "tags"); len(tags) > 0 {
+		event["tags"] = tags
+	}
+	if key := getStringParam(params, "aggregation_key", ""); key != "" {
+		event["aggregation_key"] = key
+	}
+
+	result, err := api.call("POST", "/api/v1/events", nil, event)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	created, _ := result["event"].(map[string]interface{})
+	return map[string]interface{}{
+		"event_id": created["id"],
+		"url":      created["url"],
+	}, nil
+}
+
+func (p *DatadogPlugin) createMonitor(api *datadogAPI, params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	query := getStringParam(params, "query", "")
+	if name == "" || query == "" {
+		return map[string]interface{}{"error": "name and query are required"}, nil
+	}
+
+	options := map[string]interface{}{}
+	if extra, ok := params["options"].(map[string]interface{}); ok {
+		for key, value := range extra {
+			options[key] = value
+		}
+	}
+	if thresholds, ok := params["thresholds"].(map[string]interface{}); ok && len(thresholds) > 0 {
+		options["thresholds"] = thresholds
+	}
+
+	monitor := map[string]interface{}{
+		"name":    name,
+		"type":    getStringParam(params, "type", "metric alert"),
+		"query":   query,
+		"message": getStringParam(params, "message", ""),
+	}
+	if tags := getStringSlice(params, "tags"); len(tags) > 0 {
+		monitor["tags"] = tags
+	}
+	if len(options) > 0 {
+		monitor["options"] = options
+	}
+
+	created, err := api.call("POST", "/api/v1/monitor", nil, monitor)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"monitor_id":    created["id"],
+		"name":          created["name"],
+		"type":          created["type"],
+		"query":         created["query"],
+		"overall_state": created["overall_state"],
+	}, nil
+}
+
+func (p *DatadogPlugin) getMonitor(api *datadogAPI, params map[string]interface{}) (map[string]interface{}, error) {
+	monitorID := getIntParam(params, "monitor_id", 0)
+	if monitorID <= 0 {
+		return map[string]interface{}{"error": "monitor_id is required"}, nil
+	}
+
+	monitor, err := api.call("GET", fmt.Sprintf("/api/v1/monitor/%d", monitorID), nil, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"monitor_id":    monitor["id"],
+		"name":          monitor["name"],
+		"type":          monitor["type"],
+		"query":         monitor["query"],
+		"overall_state": monitor["overall_state"],
+		"message":       monitor["message"],
+		"tags":          monitor["tags"],
+		"options":       monitor["options"],
+	}, nil
+}
+
+func (p *DatadogPlugin) muteMonitor(api *datadogAPI, params map[string]interface{}) (map[string]interface{}, error) {
+	monitorID := getIntParam(params, "monitor_id", 0)
+	if monitorID <= 0 {
+		return map[string]interface{}{"error": "monitor_id is required"}, nil
+	}
+
+	body := map[string]interface{}{}
+	var end int64
+	if _, ok := params["end"]; ok {
+		parsed, err := parseTime(params["end"])
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid end: %v", err)}, nil
+		}
+		end = parsed
+		body["end"] = end
+	}
+	if scope := getStringParam(params, "scope", ""); scope != "" {
+		body["scope"] = scope
+	}
+
+	if _, err := api.call("POST", fmt.Sprintf("/api/v1/monitor/%d/mute", monitorID), nil, body); err != nil {
+		return map[string]interface{}{"muted": false, "error": err.Error()}, nil
+	}
+
+	result := map[string]interface{}{
+		"monitor_id": monitorID,
+		"muted":      true,
+	}
+	if end > 0 {
+		result["end"] = end
+	}
+	return result, nil
+}
+
+func (p *DatadogPlugin) queryMetrics(api *datadogAPI, params map[string]interface{}) (map[string]interface{}, error) {
+	query := getStringParam(params, "query", "")
+	if query == "" {
+		return map[string]interface{}{"error": "query is required"}, nil
+	}
+
+	to := time.Now().Unix()
+	if raw, ok := params["to"]; ok {
+		parsed, err := parseTime(raw)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid to: %v", err)}, nil
+		}
+		to = parsed
+	}
+	from := to - 3600
+	if raw, ok := params["from"]; ok {
+		parsed, err := parseTime(raw)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid from: %v", err)}, nil
+		}
+		from = parsed
+	}
+
+	values := url.Values{}
+	values.Set("query", query)
+	values.Set("from", strconv.FormatInt(from, 10))
+	values.Set("to", strconv.FormatInt(to, 10))
+
+	result, err := api.call("GET", "/api/v1/query", values, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	series := []interface{}{}
+	items, _ := result["series"].([]interface{})
+	for _, item := range items {
+		s, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		series = append(series, map[string]interface{}{
+			"metric": s["metric"],
+			"scope":  s["scope"],
+			"unit":   s["unit"],
+			"points": s["pointlist"],
+		})
+	}
+
+	return map[string]interface{}{
+		"series": series,
+		"from":   from,
+		"to":     to,
+	}, nil
+}
+
+// parseTime accepts Unix seconds as a number or string, or an RFC 3339 time
+func parseTime(raw interface{}) (int64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return 0, fmt.Errorf("expected Unix timestamp or RFC 3339 time, got %q", v)
+		}
+		return t.Unix(), nil
+	default:
+		return 0, fmt.Errorf("expected Unix timestamp or RFC 3339 time")
+	}
+}
+
+// apiBaseURL maps a Datadog site to its API host. DD_API_URL overrides it entirely,
+// which is useful for proxies.
+func apiBaseURL(site string) string {
+	if override := os.Getenv("DD_API_URL"); override != "" {
+		return strings.TrimRight(override, "/")
+	}
+	if site == "" {
+		site = defaultSite
+	}
+	return "https://api." + site
+}
+
+type datadogAPI struct {
+	baseURL string
+	apiKey  string
+	appKey  string
+	client  *http.Client
+}
+
+// call sends a JSON request with the API and application key headers and decodes the response object
+func (a *datadogAPI) call(method, path string, query url.Values, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := a.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("DD-API-KEY", a.apiKey)
+	if a.appKey != "" {
+		req.Header.Set("DD-APPLICATION-KEY", a.appKey)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(data))
+		if errs, ok := result["errors"].([]interface{}); ok && len(errs) > 0 {
+			parts := make([]string, 0, len(errs))
+			for _, e := range errs {
+				parts = append(parts, fmt.Sprintf("%v", e))
+			}
+			message = strings.Join(parts, "; ")
+		}
+		return nil, fmt.Errorf("Datadog API error (%d): %s", resp.StatusCode, message)
+	}
+	return result, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	if val, ok := params[key].(string); ok {
+		if n, err := strconv.Atoi(val); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewDatadogPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
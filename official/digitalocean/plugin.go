@@ -0,0 +1,658 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// actionTimeout bounds how long droplet_action waits for an action to finish
+const actionTimeout = 10 * time.Minute
+
+type DigitalOceanPlugin struct{}
+
+// firewallRule is the rule object accepted by firewall_add_rule
+type firewallRule struct {
+	Direction        string   `json:"direction"`
+	Protocol         string   `json:"protocol"`
+	Ports            string   `json:"ports"`
+	Addresses        []string `json:"addresses"`
+	Tags             []string `json:"tags"`
+	DropletIDs       []int    `json:"droplet_ids"`
+	LoadBalancerUIDs []string `json:"load_balancer_uids"`
+}
+
+func NewDigitalOceanPlugin() *DigitalOceanPlugin {
+	return &DigitalOceanPlugin{}
+}
+
+func (p *DigitalOceanPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "digitalocean",
+		Version:     "1.0.0",
+		Description: "DigitalOcean droplets, firewalls and Spaces object storage",
+		Author:      "Corynth Team",
+		Tags:        []string{"digitalocean", "cloud", "droplets", "spaces", "firewall"},
+	}
+}
+
+func (p *DigitalOceanPlugin) GetActions() map[string]ActionSpec {
+	spacesInputs := func(inputs map[string]IOSpec) map[string]IOSpec {
+		inputs["bucket"] = IOSpec{Type: "string", Required: true, Description: "Space (bucket) name"}
+		inputs["region"] = IOSpec{Type: "string", Required: true, Description: "Spaces region, e.g. nyc3 or fra1"}
+		inputs["object_key"] = IOSpec{Type: "string", Required: true, Description: "Object key"}
+		return inputs
+	}
+
+	return map[string]ActionSpec{
+		"droplet_list": {
+			Description: "List droplets",
+			Inputs: map[string]IOSpec{
+				"tag":    {Type: "string", Required: false, Description: "Only droplets with this tag"},
+				"region": {Type: "string", Required: false, Description: "Only droplets in this region, e.g. nyc3"},
+			},
+			Outputs: map[string]IOSpec{
+				"droplets": {Type: "array", Description: "Droplets as {id, name, status, region, size, image, public_ipv4, private_ipv4, tags, created_at}"},
+				"count":    {Type: "number", Description: "Number of droplets"},
+			},
+		},
+		"droplet_create": {
+			Description: "Create a droplet",
+			Inputs: map[string]IOSpec{
+				"name":      {Type: "string", Required: true, Description: "Droplet name"},
+				"region":    {Type: "string", Required: true, Description: "Region slug, e.g. nyc3"},
+				"size":      {Type: "string", Required: true, Description: "Size slug, e.g. s-1vcpu-1gb"},
+				"image":     {Type: "string", Required: true, Description: "Image slug, e.g. ubuntu-24-04-x64, or a numeric image ID"},
+				"ssh_keys":  {Type: "array", Required: false, Description: "SSH key IDs or fingerprints"},
+				"tags":      {Type: "array", Required: false, Description: "Tags to apply"},
+				"user_data": {Type: "string", Required: false, Description: "Cloud-init user data"},
+			},
+			Outputs: map[string]IOSpec{
+				"id":     {Type: "number", Description: "Droplet ID"},
+				"name":   {Type: "string", Description: "Droplet name"},
+				"status": {Type: "string", Description: "Droplet status, usually new"},
+			},
+		},
+		"droplet_delete": {
+			Description: "Delete a droplet by ID or name",
+			Inputs: map[string]IOSpec{
+				"id":   {Type: "number", Required: false, Description: "Droplet ID"},
+				"name": {Type: "string", Required: false, Description: "Droplet name, used when id is not given; must match exactly one droplet"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the droplet was deleted"},
+				"id":      {Type: "number", Description: "Deleted droplet ID"},
+			},
+		},
+		"droplet_action": {
+			Description: "Power on, power off, reboot or snapshot a droplet",
+			Inputs: map[string]IOSpec{
+				"id":            {Type: "number", Required: true, Description: "Droplet ID"},
+				"action":        {Type: "string", Required: true, Description: "power_on, power_off, reboot or snapshot"},
+				"snapshot_name": {Type: "string", Required: false, Description: "Snapshot name (defaults to the droplet ID and time)"},
+				"wait":          {Type: "boolean", Required: false, Default: false, Description: "Wait up to 10 minutes for the action to finish"},
+			},
+			Outputs: map[string]IOSpec{
+				"action_id": {Type: "number", Description: "Action ID"},
+				"status":    {Type: "string", Description: "in-progress, completed or errored"},
+				"type":      {Type: "string", Description: "Action type"},
+			},
+		},
+		"spaces_upload": {
+			Description: "Upload a file to a Space",
+			Inputs: spacesInputs(map[string]IOSpec{
+				"local_path": {Type: "string", Required: true, Description: "Local file to upload"},
+				"acl":        {Type: "string", Required: false, Default: "private", Description: "private or public-read"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success":    {Type: "boolean", Description: "Upload success"},
+				"url":        {Type: "string", Description: "Object URL"},
+				"etag":       {Type: "string", Description: "Object ETag"},
+				"size_bytes": {Type: "number", Description: "Bytes uploaded"},
+			},
+		},
+		"spaces_download": {
+			Description: "Download an object from a Space",
+			Inputs: spacesInputs(map[string]IOSpec{
+				"local_path": {Type: "string", Required: true, Description: "Destination file; parent directories are created"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success":    {Type: "boolean", Description: "Download success"},
+				"local_path": {Type: "string", Description: "Absolute path of the downloaded file"},
+				"size_bytes": {Type: "number", Description: "Bytes downloaded"},
+			},
+		},
+		"firewall_add_rule": {
+			Description: "Add an inbound or outbound rule to a cloud firewall",
+			Inputs: map[string]IOSpec{
+				"firewall_id": {Type: "string", Required: true, Description: "Firewall ID"},
+				"rule":        {Type: "object", Required: true, Description: "Rule as {direction: inbound|outbound, protocol: tcp|udp|icmp, ports, addresses, tags, droplet_ids, load_balancer_uids}"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the rule was added"},
+			},
+		},
+	}
+}
+
+func (p *DigitalOceanPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(context.Context, map[string]interface{}) (map[string]interface{}, error)
+	switch action {
+	case "droplet_list":
+		run = p.dropletList
+	case "droplet_create":
+		run = p.dropletCreate
+	case "droplet_delete":
+		run = p.dropletDelete
+	case "droplet_action":
+		run = p.dropletAction
+	case "spaces_upload":
+		run = p.spacesUpload
+	case "spaces_download":
+		run = p.spacesDownload
+	case "firewall_add_rule":
+		run = p.firewallAddRule
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	result, err := run(context.Background(), params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func newClient() (*godo.Client, error) {
+	token := os.Getenv("DIGITALOCEAN_TOKEN")
+	if token == "" {
+		return nil, errors.New("DIGITALOCEAN_TOKEN not configured")
+	}
+	return godo.NewFromToken(token), nil
+}
+
+// newSpacesClient connects to the S3-compatible endpoint of a Spaces region
+func newSpacesClient(region string) (*minio.Client, error) {
+	accessKey := os.Getenv("SPACES_ACCESS_KEY_ID")
+	secretKey := os.Getenv("SPACES_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("SPACES_ACCESS_KEY_ID and SPACES_SECRET_ACCESS_KEY must be configured")
+	}
+	client, err := minio.New(region+".digitaloceanspaces.com", &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: true,
+		Region: region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Spaces client: %v", err)
+	}
+	return client, nil
+}
+
+func requireStrings(params map[string]interface{}, keys ...string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = getStringParam(params, key, "")
+		if values[i] == "" {
+			return nil, fmt.Errorf("%s is required", key)
+		}
+	}
+	return values, nil
+}
+
+func (p *DigitalOceanPlugin) dropletList(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	tag := getStringParam(params, "tag", "")
+	region := getStringParam(params, "region", "")
+
+	droplets := []map[string]interface{}{}
+	opt := &godo.ListOptions{PerPage: 200}
+	for {
+		var page []godo.Droplet
+		var resp *godo.Response
+		if tag != "" {
+			page, resp, err = client.Droplets.ListByTag(ctx, tag, opt)
+		} else {
+			page, resp, err = client.Droplets.List(ctx, opt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list droplets: %v", err)
+		}
+
+		// The API filters by tag but not by region
+		for i := range page {
+			if region != "" && (page[i].Region == nil || page[i].Region.Slug != region) {
+				continue
+			}
+			droplets = append(droplets, dropletResult(&page[i]))
+		}
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		current, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page: %v", err)
+		}
+		opt.Page = current + 1
+	}
+
+	return map[string]interface{}{
+		"droplets": droplets,
+		"count":    len(droplets),
+	}, nil
+}
+
+func (p *DigitalOceanPlugin) dropletCreate(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "name", "region", "size", "image")
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	request := &godo.DropletCreateRequest{
+		Name:     values[0],
+		Region:   values[1],
+		Size:     values[2],
+		Tags:     getStringSlice(params, "tags"),
+		UserData: getStringParam(params, "user_data", ""),
+	}
+	if id, err := strconv.Atoi(values[3]); err == nil {
+		request.Image = godo.DropletCreateImage{ID: id}
+	} else {
+		request.Image = godo.DropletCreateImage{Slug: values[3]}
+	}
+
+	// Keys may be given as numeric IDs or as fingerprints
+	keys, _ := params["ssh_keys"].([]interface{})
+	for _, key := range keys {
+		switch v := key.(type) {
+		case float64:
+			request.SSHKeys = append(request.SSHKeys, godo.DropletCreateSSHKey{ID: int(v)})
+		case string:
+			if id, err := strconv.Atoi(v); err == nil {
+				request.SSHKeys = append(request.SSHKeys, godo.DropletCreateSSHKey{ID: id})
+			} else if v != "" {
+				request.SSHKeys = append(request.SSHKeys, godo.DropletCreateSSHKey{Fingerprint: v})
+			}
+		}
+	}
+
+	droplet, _, err := client.Droplets.Create(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create droplet: %v", err)
+	}
+
+	return map[string]interface{}{
+		"id":     droplet.ID,
+		"name":   droplet.Name,
+		"status": droplet.Status,
+	}, nil
+}
+
+func (p *DigitalOceanPlugin) dropletDelete(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	id := getIntParam(params, "id", 0)
+	if id == 0 {
+		name := getStringParam(params, "name", "")
+		if name == "" {
+			return nil, errors.New("id or name is required")
+		}
+		droplets, _, err := client.Droplets.ListByName(ctx, name, &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up droplet: %v", err)
+		}
+		// Names are not unique, so refuse to guess between several droplets
+		switch len(droplets) {
+		case 0:
+			return nil, fmt.Errorf("no droplet named %s", name)
+		case 1:
+			id = droplets[0].ID
+		default:
+			return nil, fmt.Errorf("%d droplets are named %s; pass id instead", len(droplets), name)
+		}
+	}
+
+	if _, err := client.Droplets.Delete(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete droplet: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"id":      id,
+	}, nil
+}
+
+func (p *DigitalOceanPlugin) dropletAction(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	id := getIntParam(params, "id", 0)
+	if id == 0 {
+		return nil, errors.New("id is required")
+	}
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var action *godo.Action
+	switch name := getStringParam(params, "action", ""); name {
+	case "power_on":
+		action, _, err = client.DropletActions.PowerOn(ctx, id)
+	case "power_off":
+		action, _, err = client.DropletActions.PowerOff(ctx, id)
+	case "reboot":
+		action, _, err = client.DropletActions.Reboot(ctx, id)
+	case "snapshot":
+		snapshotName := getStringParam(params, "snapshot_name", fmt.Sprintf("%d-%s", id, time.Now().UTC().Format("20060102150405")))
+		action, _, err = client.DropletActions.Snapshot(ctx, id, snapshotName)
+	case "":
+		return nil, errors.New("action is required")
+	default:
+		return nil, fmt.Errorf("unsupported action: %s (use power_on, power_off, reboot or snapshot)", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("droplet action failed: %v", err)
+	}
+
+	if getBoolParam(params, "wait", false) {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+		for action.Status == godo.ActionInProgress {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("timed out waiting for action %d", action.ID)
+			case <-time.After(5 * time.Second):
+			}
+			if action, _, err = client.DropletActions.Get(ctx, id, action.ID); err != nil {
+				return nil, fmt.Errorf("failed to check action: %v", err)
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"action_id": action.ID,
+		"status":    action.Status,
+		"type":      action.Type,
+	}, nil
+}
+
+func (p *DigitalOceanPlugin) spacesUpload(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "bucket", "region", "object_key", "local_path")
+	if err != nil {
+		return nil, err
+	}
+	bucket, region, key, localPath := values[0], values[1], values[2], values[3]
+
+	acl := getStringParam(params, "acl", "private")
+	if acl != "private" && acl != "public-read" {
+		return nil, fmt.Errorf("unsupported acl: %s (use private or public-read)", acl)
+	}
+	if info, err := os.Stat(localPath); err != nil {
+		return nil, fmt.Errorf("cannot read local_path: %v", err)
+	} else if info.IsDir() {
+		return nil, errors.New("local_path must be a file, not a directory")
+	}
+
+	client, err := newSpacesClient(region)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := client.FPutObject(ctx, bucket, key, localPath, minio.PutObjectOptions{
+		UserMetadata: map[string]string{"x-amz-acl": acl},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success":    true,
+		"url":        fmt.Sprintf("https://%s.%s.digitaloceanspaces.com/%s", bucket, region, key),
+		"etag":       upload.ETag,
+		"size_bytes": upload.Size,
+	}, nil
+}
+
+func (p *DigitalOceanPlugin) spacesDownload(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "bucket", "region", "object_key", "local_path")
+	if err != nil {
+		return nil, err
+	}
+
+	localPath, err := filepath.Abs(values[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid local_path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	client, err := newSpacesClient(values[1])
+	if err != nil {
+		return nil, err
+	}
+	if err := client.FGetObject(ctx, values[0], values[2], localPath, minio.GetObjectOptions{}); err != nil {
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("downloaded file not found: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success":    true,
+		"local_path": localPath,
+		"size_bytes": info.Size(),
+	}, nil
+}
+
+func (p *DigitalOceanPlugin) firewallAddRule(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "firewall_id")
+	if err != nil {
+		return nil, err
+	}
+	ruleParam, ok := params["rule"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("rule is required")
+	}
+
+	// Round-trip through JSON to validate the rule's field types
+	var rule firewallRule
+	data, _ := json.Marshal(ruleParam)
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, fmt.Errorf("invalid rule: %v", err)
+	}
+	if rule.Protocol == "" {
+		return nil, errors.New("rule.protocol is required")
+	}
+	if len(rule.Addresses)+len(rule.Tags)+len(rule.DropletIDs)+len(rule.LoadBalancerUIDs) == 0 {
+		return nil, errors.New("rule needs at least one of addresses, tags, droplet_ids or load_balancer_uids")
+	}
+
+	request := &godo.FirewallRulesRequest{}
+	switch rule.Direction {
+	case "", "inbound":
+		request.InboundRules = []godo.InboundRule{{
+			Protocol:  rule.Protocol,
+			PortRange: rule.Ports,
+			Sources: &godo.Sources{
+				Addresses:        rule.Addresses,
+				Tags:             rule.Tags,
+				DropletIDs:       rule.DropletIDs,
+				LoadBalancerUIDs: rule.LoadBalancerUIDs,
+			},
+		}}
+	case "outbound":
+		request.OutboundRules = []godo.OutboundRule{{
+			Protocol:  rule.Protocol,
+			PortRange: rule.Ports,
+			Destinations: &godo.Destinations{
+				Addresses:        rule.Addresses,
+				Tags:             rule.Tags,
+				DropletIDs:       rule.DropletIDs,
+				LoadBalancerUIDs: rule.LoadBalancerUIDs,
+			},
+		}}
+	default:
+		return nil, fmt.Errorf("unsupported rule.direction: %s (use inbound or outbound)", rule.Direction)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Firewalls.AddRules(ctx, values[0], request); err != nil {
+		return nil, fmt.Errorf("failed to add rule: %v", err)
+	}
+
+	return map[string]interface{}{"success": true}, nil
+}
+
+// dropletResult extracts the fields droplet_list returns
+func dropletResult(droplet *godo.Droplet) map[string]interface{} {
+	publicIP, _ := droplet.PublicIPv4()
+	privateIP, _ := droplet.PrivateIPv4()
+
+	var region, image string
+	if droplet.Region != nil {
+		region = droplet.Region.Slug
+	}
+	if droplet.Image != nil {
+		image = droplet.Image.Slug
+		if image == "" {
+			image = droplet.Image.Name
+		}
+	}
+
+	return map[string]interface{}{
+		"id":           droplet.ID,
+		"name":         droplet.Name,
+		"status":       droplet.Status,
+		"region":       region,
+		"size":         droplet.SizeSlug,
+		"image":        image,
+		"public_ipv4":  publicIP,
+		"private_ipv4": privateIP,
+		"tags":         droplet.Tags,
+		"created_at":   droplet.Created,
+	}
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	if val, ok := params[key].(string); ok {
+		if n, err := strconv.Atoi(val); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewDigitalOceanPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
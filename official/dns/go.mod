@@ -0,0 +1,5 @@
+module dns-plugin
+
+go 1.23.0
+
+require golang.org/x/net v0.42.0
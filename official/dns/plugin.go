@@ -0,0 +1,652 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type DNSPlugin struct{}
+
+// lookupResult is the answer to a single query against one resolver
+type lookupResult struct {
+	records       []map[string]interface{}
+	ttl           uint32
+	authoritative bool
+	rcode         string
+}
+
+var recordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"TXT":   dnsmessage.TypeTXT,
+	"NS":    dnsmessage.TypeNS,
+	"SRV":   dnsmessage.TypeSRV,
+}
+
+// defaultPropagationResolvers are public resolvers operated by different providers
+var defaultPropagationResolvers = []string{"8.8.8.8", "1.1.1.1", "9.9.9.9", "208.67.222.222"}
+
+func NewDNSPlugin() *DNSPlugin {
+	return &DNSPlugin{}
+}
+
+func (p *DNSPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "dns",
+		Version:     "1.0.0",
+		Description: "DNS lookups, propagation checks and MX validation",
+		Author:      "Corynth Team",
+		Tags:        []string{"dns", "network", "lookup", "mx", "diagnostics"},
+	}
+}
+
+func (p *DNSPlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"lookup": {
+			Description: "Resolve a DNS name",
+			Inputs: map[string]IOSpec{
+				"name":     {Type: "string", Required: true, Description: "Name to resolve"},
+				"type":     {Type: "string", Required: false, Default: "A", Description: "Record type: A, AAAA, CNAME, MX, TXT, NS or SRV"},
+				"resolver": {Type: "string", Required: false, Description: "Resolver IP, optionally with port (defaults to the system resolver)"},
+				"timeout":  {Type: "number", Required: false, Default: 5, Description: "Query timeout in seconds"},
+			},
+			Outputs: map[string]IOSpec{
+				"records":       {Type: "array", Description: "Records as {type, value, ttl}; MX adds priority, SRV adds priority, weight and port"},
+				"ttl":           {Type: "number", Description: "Lowest TTL of the returned records"},
+				"authoritative": {Type: "boolean", Description: "Whether the answer came from an authoritative server"},
+				"rcode":         {Type: "string", Description: "Response code, e.g. NOERROR or NXDOMAIN"},
+				"resolver":      {Type: "string", Description: "Resolver that answered"},
+			},
+		},
+		"reverse_lookup": {
+			Description: "Find the host names for an IP address (PTR)",
+			Inputs: map[string]IOSpec{
+				"ip":       {Type: "string", Required: true, Description: "IPv4 or IPv6 address"},
+				"resolver": {Type: "string", Required: false, Description: "Resolver IP, optionally with port (defaults to the system resolver)"},
+				"timeout":  {Type: "number", Required: false, Default: 5, Description: "Query timeout in seconds"},
+			},
+			Outputs: map[string]IOSpec{
+				"names": {Type: "array", Description: "Host names"},
+			},
+		},
+		"check_propagation": {
+			Description: "Check whether several resolvers return an expected value",
+			Inputs: map[string]IOSpec{
+				"name":           {Type: "string", Required: true, Description: "Name to resolve"},
+				"type":           {Type: "string", Required: false, Default: "A", Description: "Record type"},
+				"expected_value": {Type: "string", Required: true, Description: "Value that should be among the records, e.g. an IP or host name"},
+				"resolvers":      {Type: "array", Required: false, Description: "Resolver IPs (defaults to Google, Cloudflare, Quad9 and OpenDNS)"},
+				"timeout":        {Type: "number", Required: false, Default: 5, Description: "Query timeout in seconds per resolver"},
+			},
+			Outputs: map[string]IOSpec{
+				"percentage": {Type: "number", Description: "Percentage of resolvers returning the expected value"},
+				"propagated": {Type: "boolean", Description: "Whether every resolver returns the expected value"},
+				"results":    {Type: "array", Description: "Per-resolver {resolver, matched, values, ttl, error}"},
+			},
+		},
+		"validate_mx": {
+			Description: "Verify a domain's MX records and SMTP connectivity",
+			Inputs: map[string]IOSpec{
+				"domain":     {Type: "string", Required: true, Description: "Mail domain"},
+				"check_smtp": {Type: "boolean", Required: false, Default: true, Description: "Connect to port 25 of each mail server and read its greeting"},
+				"resolver":   {Type: "string", Required: false, Description: "Resolver IP, optionally with port (defaults to the system resolver)"},
+				"timeout":    {Type: "number", Required: false, Default: 10, Description: "Timeout in seconds for the lookup and each SMTP connection"},
+			},
+			Outputs: map[string]IOSpec{
+				"valid":      {Type: "boolean", Description: "Whether the domain has MX records and, when checked, a mail server answers"},
+				"mx_records": {Type: "array", Description: "Mail servers as {host, priority, smtp_reachable, banner, error}, by priority"},
+				"null_mx":    {Type: "boolean", Description: "Whether the domain declares it accepts no mail (RFC 7505)"},
+			},
+		},
+	}
+}
+
+func (p *DNSPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	switch action {
+	case "lookup":
+		return p.lookup(params)
+	case "reverse_lookup":
+		return p.reverseLookup(params)
+	case "check_propagation":
+		return p.checkPropagation(params)
+	case "validate_mx":
+		return p.validateMX(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *DNSPlugin) lookup(params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return map[string]interface{}{"error": "name is required"}, nil
+	}
+	recordType, ok := recordTypes[strings.ToUpper(getStringParam(params, "type", "A"))]
+	if !ok {
+		return map[string]interface{}{"error": "type must be one of A, AAAA, CNAME, MX, TXT, NS, SRV"}, nil
+	}
+
+	server, err := resolverAddress(getStringParam(params, "resolver", ""))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	timeout := time.Duration(getFloatParam(params, "timeout", 5) * float64(time.Second))
+
+	result, err := query(server, name, recordType, timeout)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"records":       result.records,
+		"ttl":           result.ttl,
+		"authoritative": result.authoritative,
+		"rcode":         result.rcode,
+		"resolver":      server,
+	}, nil
+}
+
+func (p *DNSPlugin) reverseLookup(params map[string]interface{}) (map[string]interface{}, error) {
+	ip := getStringParam(params, "ip", "")
+	if net.ParseIP(ip) == nil {
+		return map[string]interface{}{"error": "ip must be a valid IPv4 or IPv6 address"}, nil
+	}
+
+	resolver, err := newResolver(getStringParam(params, "resolver", ""))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(getFloatParam(params, "timeout", 5)*float64(time.Second)))
+	defer cancel()
+
+	names, err := resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return map[string]interface{}{"names": []string{}}, nil
+		}
+		return map[string]interface{}{"error": fmt.Sprintf("reverse lookup failed: %v", err)}, nil
+	}
+
+	for i, n := range names {
+		names[i] = strings.TrimSuffix(n, ".")
+	}
+
+	return map[string]interface{}{
+		"names": names,
+	}, nil
+}
+
+func (p *DNSPlugin) checkPropagation(params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return map[string]interface{}{"error": "name is required"}, nil
+	}
+	expected := getStringParam(params, "expected_value", "")
+	if expected == "" {
+		return map[string]interface{}{"error": "expected_value is required"}, nil
+	}
+	recordType, ok := recordTypes[strings.ToUpper(getStringParam(params, "type", "A"))]
+	if !ok {
+		return map[string]interface{}{"error": "type must be one of A, AAAA, CNAME, MX, TXT, NS, SRV"}, nil
+	}
+	timeout := time.Duration(getFloatParam(params, "timeout", 5) * float64(time.Second))
+
+	resolvers := defaultPropagationResolvers
+	if list, ok := params["resolvers"].([]interface{}); ok && len(list) > 0 {
+		resolvers = make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok && s != "" {
+				resolvers = append(resolvers, s)
+			}
+		}
+	}
+	if len(resolvers) == 0 {
+		return map[string]interface{}{"error": "resolvers must contain at least one resolver"}, nil
+	}
+
+	results := make([]map[string]interface{}, len(resolvers))
+	var wg sync.WaitGroup
+	for i, resolver := range resolvers {
+		wg.Add(1)
+		go func(i int, resolver string) {
+			defer wg.Done()
+			status := map[string]interface{}{"resolver": resolver, "matched": false, "values": []string{}}
+			results[i] = status
+
+			server, err := resolverAddress(resolver)
+			if err != nil {
+				status["error"] = err.Error()
+				return
+			}
+			result, err := query(server, name, recordType, timeout)
+			if err != nil {
+				status["error"] = err.Error()
+				return
+			}
+
+			values := make([]string, 0, len(result.records))
+			for _, record := range result.records {
+				value := record["value"].(string)
+				values = append(values, value)
+				if valuesMatch(value, expected) {
+					status["matched"] = true
+				}
+			}
+			status["values"] = values
+			status["ttl"] = result.ttl
+			if result.rcode != "NOERROR" {
+				status["error"] = result.rcode
+			}
+		}(i, resolver)
+	}
+	wg.Wait()
+
+	matched := 0
+	for _, status := range results {
+		if status["matched"] == true {
+			matched++
+		}
+	}
+	percentage := math.Round(float64(matched)/float64(len(results))*1000) / 10
+
+	return map[string]interface{}{
+		"percentage": percentage,
+		"propagated": matched == len(results),
+		"results":    results,
+	}, nil
+}
+
+func (p *DNSPlugin) validateMX(params map[string]interface{}) (map[string]interface{}, error) {
+	domain := getStringParam(params, "domain", "")
+	if domain == "" {
+		return map[string]interface{}{"error": "domain is required"}, nil
+	}
+	checkSMTP := getBoolParam(params, "check_smtp", true)
+	timeout := time.Duration(getFloatParam(params, "timeout", 10) * float64(time.Second))
+
+	resolver, err := newResolver(getStringParam(params, "resolver", ""))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	mxs, err := resolver.LookupMX(ctx, domain)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return map[string]interface{}{"valid": false, "mx_records": []interface{}{}, "null_mx": false}, nil
+		}
+		return map[string]interface{}{"error": fmt.Sprintf("MX lookup failed: %v", err)}, nil
+	}
+
+	// A single MX of "." means the domain accepts no mail
+	if len(mxs) == 1 && (mxs[0].Host == "." || mxs[0].Host == "") {
+		return map[string]interface{}{"valid": false, "mx_records": []interface{}{}, "null_mx": true}, nil
+	}
+
+	sort.SliceStable(mxs, func(i, j int) bool { return mxs[i].Pref < mxs[j].Pref })
+
+	records := make([]map[string]interface{}, len(mxs))
+	var wg sync.WaitGroup
+	for i, mx := range mxs {
+		host := strings.TrimSuffix(mx.Host, ".")
+		record := map[string]interface{}{"host": host, "priority": mx.Pref}
+		records[i] = record
+		if !checkSMTP {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			banner, err := smtpGreeting(host, timeout)
+			record["smtp_reachable"] = err == nil
+			if err != nil {
+				record["error"] = err.Error()
+			} else {
+				record["banner"] = banner
+			}
+		}()
+	}
+	wg.Wait()
+
+	valid := len(records) > 0
+	if checkSMTP {
+		valid = false
+		for _, record := range records {
+			if record["smtp_reachable"] == true {
+				valid = true
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"valid":      valid,
+		"mx_records": records,
+		"null_mx":    false,
+	}, nil
+}
+
+// query sends a single recursive query to server over UDP, retrying over TCP when the answer is truncated
+func query(server, name string, recordType dnsmessage.Type, timeout time.Duration) (*lookupResult, error) {
+	fqdn := name
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+	qname, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %q: %v", name, err)
+	}
+
+	id := uint16(rand.Intn(math.MaxUint16 + 1))
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: recordType, Class: dnsmessage.ClassINET}},
+	}
+	var opt dnsmessage.Resource
+	if err := opt.Header.SetEDNS0(4096, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	opt.Body = &dnsmessage.OPTResource{}
+	msg.Additionals = []dnsmessage.Resource{opt}
+
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %v", err)
+	}
+
+	response, err := exchange("udp", server, packed, timeout)
+	if err == nil && response.Header.Truncated {
+		response, err = exchange("tcp", server, packed, timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query to %s failed: %v", server, err)
+	}
+	if response.Header.ID != id {
+		return nil, fmt.Errorf("query to %s failed: mismatched response ID", server)
+	}
+
+	result := &lookupResult{
+		records:       []map[string]interface{}{},
+		authoritative: response.Header.Authoritative,
+		rcode:         rcodeName(response.Header.RCode),
+	}
+	for _, answer := range response.Answers {
+		if answer.Header.Type != recordType {
+			continue
+		}
+		record := recordValue(answer.Body)
+		if record == nil {
+			continue
+		}
+		record["type"] = strings.TrimPrefix(answer.Header.Type.String(), "Type")
+		record["ttl"] = answer.Header.TTL
+		result.records = append(result.records, record)
+		if len(result.records) == 1 || answer.Header.TTL < result.ttl {
+			result.ttl = answer.Header.TTL
+		}
+	}
+
+	return result, nil
+}
+
+func exchange(network, server string, packed []byte, timeout time.Duration) (*dnsmessage.Message, error) {
+	conn, err := net.DialTimeout(network, server, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	var raw []byte
+	if network == "tcp" {
+		// DNS over TCP prefixes each message with its length
+		framed := make([]byte, 2+len(packed))
+		binary.BigEndian.PutUint16(framed, uint16(len(packed)))
+		copy(framed[2:], packed)
+		if _, err := conn.Write(framed); err != nil {
+			return nil, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		raw = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, raw); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := conn.Write(packed); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 65535)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		raw = buf[:n]
+	}
+
+	var response dnsmessage.Message
+	if err := response.Unpack(raw); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return &response, nil
+}
+
+func recordValue(body dnsmessage.ResourceBody) map[string]interface{} {
+	switch r := body.(type) {
+	case *dnsmessage.AResource:
+		return map[string]interface{}{"value": netip.AddrFrom4(r.A).String()}
+	case *dnsmessage.AAAAResource:
+		return map[string]interface{}{"value": netip.AddrFrom16(r.AAAA).String()}
+	case *dnsmessage.CNAMEResource:
+		return map[string]interface{}{"value": hostName(r.CNAME)}
+	case *dnsmessage.NSResource:
+		return map[string]interface{}{"value": hostName(r.NS)}
+	case *dnsmessage.MXResource:
+		return map[string]interface{}{"value": hostName(r.MX), "priority": r.Pref}
+	case *dnsmessage.TXTResource:
+		// Long TXT values are split into 255-byte strings that belong together
+		return map[string]interface{}{"value": strings.Join(r.TXT, "")}
+	case *dnsmessage.SRVResource:
+		return map[string]interface{}{"value": hostName(r.Target), "priority": r.Priority, "weight": r.Weight, "port": r.Port}
+	}
+	return nil
+}
+
+func hostName(name dnsmessage.Name) string {
+	return strings.TrimSuffix(name.String(), ".")
+}
+
+func rcodeName(rcode dnsmessage.RCode) string {
+	switch rcode {
+	case dnsmessage.RCodeSuccess:
+		return "NOERROR"
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	}
+	return fmt.Sprintf("RCODE%d", rcode)
+}
+
+func valuesMatch(value, expected string) bool {
+	return strings.EqualFold(strings.TrimSuffix(value, "."), strings.TrimSuffix(strings.TrimSpace(expected), "."))
+}
+
+// resolverAddress returns host:port for a resolver, defaulting to port 53 and, when resolver is empty,
+// to the first nameserver in /etc/resolv.conf
+func resolverAddress(resolver string) (string, error) {
+	if resolver == "" {
+		resolver = systemNameserver()
+	}
+	if _, _, err := net.SplitHostPort(resolver); err == nil {
+		return resolver, nil
+	}
+	if net.ParseIP(resolver) == nil {
+		return "", fmt.Errorf("invalid resolver %q: expected an IP address, optionally with a port", resolver)
+	}
+	return net.JoinHostPort(resolver, "53"), nil
+}
+
+func systemNameserver() string {
+	file, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return "127.0.0.1"
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return fields[1]
+		}
+	}
+	return "127.0.0.1"
+}
+
+// newResolver returns a net.Resolver that sends its queries to resolver, or the system resolver when empty
+func newResolver(resolver string) (*net.Resolver, error) {
+	if resolver == "" {
+		return net.DefaultResolver, nil
+	}
+	server, err := resolverAddress(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}, nil
+}
+
+// smtpGreeting connects to port 25 and returns the server's 220 greeting line
+func smtpGreeting(host string, timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, "25"), timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("no SMTP greeting: %v", err)
+	}
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "220") {
+		return "", fmt.Errorf("unexpected SMTP greeting: %s", line)
+	}
+	conn.Write([]byte("QUIT\r\n"))
+	return line, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getFloatParam(params map[string]interface{}, key string, defaultValue float64) float64 {
+	if val, ok := params[key].(float64); ok && val > 0 {
+		return val
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewDNSPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -0,0 +1,646 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type ElasticsearchPlugin struct{}
+
+func NewElasticsearchPlugin() *ElasticsearchPlugin {
+	return &ElasticsearchPlugin{}
+}
+
+func (p *ElasticsearchPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "elasticsearch",
+		Version:     "1.0.0",
+		Description: "Elasticsearch document indexing, search and index management",
+		Author:      "Corynth Team",
+		Tags:        []string{"elasticsearch", "search", "logs", "database", "index"},
+	}
+}
+
+func (p *ElasticsearchPlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"index": {
+			Description: "Index a document",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Target index"},
+				"id":         {Type: "string", Required: false, Description: "Document ID (generated when omitted)"},
+				"document":   {Type: "object", Required: true, Description: "Document body"},
+				"refresh":    {Type: "boolean", Required: false, Default: false, Description: "Refresh the index so the document is immediately searchable"},
+			},
+			Outputs: map[string]IOSpec{
+				"id":      {Type: "string", Description: "Document ID"},
+				"result":  {Type: "string", Description: "created or updated"},
+				"version": {Type: "number", Description: "Document version"},
+			},
+		},
+		"bulk_index": {
+			Description: "Index several documents in one request",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Target index"},
+				"documents":  {Type: "array", Required: true, Description: "Documents to index"},
+				"id_field":   {Type: "string", Required: false, Description: "Document field to use as the document ID"},
+				"refresh":    {Type: "boolean", Required: false, Default: false, Description: "Refresh the index so the documents are immediately searchable"},
+			},
+			Outputs: map[string]IOSpec{
+				"indexed": {Type: "number", Description: "Number of documents indexed"},
+				"failed":  {Type: "number", Description: "Number of documents that failed"},
+				"errors":  {Type: "array", Description: "Failures as {position, id, reason}"},
+			},
+		},
+		"get": {
+			Description: "Get a document by ID",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Index name"},
+				"id":         {Type: "string", Required: true, Description: "Document ID"},
+			},
+			Outputs: map[string]IOSpec{
+				"found":    {Type: "boolean", Description: "Whether the document exists"},
+				"document": {Type: "object", Description: "Document source"},
+				"version":  {Type: "number", Description: "Document version"},
+			},
+		},
+		"search": {
+			Description: "Search documents with the query DSL",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Index name, alias or pattern such as logs-*"},
+				"query":      {Type: "object", Required: false, Description: "Query DSL, e.g. {\"match\": {\"message\": \"timeout\"}} (matches all documents by default)"},
+				"size":       {Type: "number", Required: false, Default: 10, Description: "Maximum number of hits"},
+				"from":       {Type: "number", Required: false, Default: 0, Description: "Offset of the first hit"},
+				"sort":       {Type: "array", Required: false, Description: "Sort specification, e.g. [{\"@timestamp\": \"desc\"}]"},
+			},
+			Outputs: map[string]IOSpec{
+				"hits":      {Type: "array", Description: "Hits as {id, index, score, document}"},
+				"total":     {Type: "number", Description: "Total number of matching documents"},
+				"max_score": {Type: "number", Description: "Highest score"},
+				"took":      {Type: "number", Description: "Search time in milliseconds"},
+			},
+		},
+		"delete": {
+			Description: "Delete a document by ID",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Index name"},
+				"id":         {Type: "string", Required: true, Description: "Document ID"},
+				"refresh":    {Type: "boolean", Required: false, Default: false, Description: "Refresh the index so the deletion is immediately visible"},
+			},
+			Outputs: map[string]IOSpec{
+				"deleted": {Type: "boolean", Description: "Whether the document existed and was deleted"},
+				"result":  {Type: "string", Description: "deleted or not_found"},
+			},
+		},
+		"delete_by_query": {
+			Description: "Delete all documents matching a query",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Index name or pattern"},
+				"query":      {Type: "object", Required: true, Description: "Query DSL; {\"match_all\": {}} deletes every document"},
+				"refresh":    {Type: "boolean", Required: false, Default: false, Description: "Refresh the affected indices when done"},
+			},
+			Outputs: map[string]IOSpec{
+				"deleted":  {Type: "number", Description: "Number of documents deleted"},
+				"total":    {Type: "number", Description: "Number of documents matched"},
+				"failures": {Type: "array", Description: "Per-document failures"},
+			},
+		},
+		"create_index": {
+			Description: "Create an index",
+			Inputs: map[string]IOSpec{
+				"name":     {Type: "string", Required: true, Description: "Index name"},
+				"mappings": {Type: "object", Required: false, Description: "Field mappings, e.g. {\"properties\": {\"message\": {\"type\": \"text\"}}}"},
+				"settings": {Type: "object", Required: false, Description: "Index settings, e.g. {\"number_of_shards\": 1}"},
+			},
+			Outputs: map[string]IOSpec{
+				"acknowledged": {Type: "boolean", Description: "Whether the cluster acknowledged the request"},
+				"index":        {Type: "string", Description: "Created index name"},
+			},
+		},
+		"delete_index": {
+			Description: "Delete an index",
+			Inputs: map[string]IOSpec{
+				"name": {Type: "string", Required: true, Description: "Index name"},
+			},
+			Outputs: map[string]IOSpec{
+				"acknowledged": {Type: "boolean", Description: "Whether the cluster acknowledged the request"},
+			},
+		},
+		"count": {
+			Description: "Count documents matching a query",
+			Inputs: map[string]IOSpec{
+				"index_name": {Type: "string", Required: true, Description: "Index name, alias or pattern"},
+				"query":      {Type: "object", Required: false, Description: "Query DSL (counts all documents by default)"},
+			},
+			Outputs: map[string]IOSpec{
+				"count": {Type: "number", Description: "Number of matching documents"},
+			},
+		},
+	}
+}
+
+func (p *ElasticsearchPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(context.Context, *elasticsearch.Client, map[string]interface{}) (map[string]interface{}, error)
+
+	switch action {
+	case "index":
+		run = p.indexDocument
+	case "bulk_index":
+		run = p.bulkIndex
+	case "get":
+		run = p.getDocument
+	case "search":
+		run = p.search
+	case "delete":
+		run = p.deleteDocument
+	case "delete_by_query":
+		run = p.deleteByQuery
+	case "create_index":
+		run = p.createIndex
+	case "delete_index":
+		run = p.deleteIndex
+	case "count":
+		run = p.count
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	url := os.Getenv("ELASTICSEARCH_URL")
+	if url == "" {
+		url = "http://localhost:9200"
+	}
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{url},
+		APIKey:    os.Getenv("ELASTICSEARCH_API_KEY"),
+	})
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create client: %v", err)}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := run(ctx, client, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func (p *ElasticsearchPlugin) indexDocument(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	if index == "" {
+		return nil, fmt.Errorf("index_name is required")
+	}
+	document, ok := params["document"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("document is required")
+	}
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %v", err)
+	}
+
+	req := esapi.IndexRequest{
+		Index:      index,
+		DocumentID: getStringParam(params, "id", ""),
+		Body:       bytes.NewReader(body),
+		Refresh:    refreshParam(params),
+	}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":      data["_id"],
+		"result":  data["result"],
+		"version": data["_version"],
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) bulkIndex(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	if index == "" {
+		return nil, fmt.Errorf("index_name is required")
+	}
+	documents, ok := params["documents"].([]interface{})
+	if !ok || len(documents) == 0 {
+		return nil, fmt.Errorf("documents is required")
+	}
+	idField := getStringParam(params, "id_field", "")
+
+	// The bulk API takes newline-delimited JSON: an action line followed by the document
+	var buf bytes.Buffer
+	for i, item := range documents {
+		document, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("document %d must be an object", i)
+		}
+
+		meta := map[string]interface{}{}
+		if idField != "" {
+			id, ok := document[idField]
+			if !ok || id == nil {
+				return nil, fmt.Errorf("document %d has no %s field", i, idField)
+			}
+			meta["_id"] = fmt.Sprintf("%v", id)
+		}
+
+		actionLine, _ := json.Marshal(map[string]interface{}{"index": meta})
+		source, err := json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode document %d: %v", i, err)
+		}
+		buf.Write(actionLine)
+		buf.WriteByte('\n')
+		buf.Write(source)
+		buf.WriteByte('\n')
+	}
+
+	req := esapi.BulkRequest{
+		Index:   index,
+		Body:    &buf,
+		Refresh: refreshParam(params),
+	}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := 0
+	errors := []map[string]interface{}{}
+	items, _ := data["items"].([]interface{})
+	for i, item := range items {
+		entry, _ := item.(map[string]interface{})
+		result, _ := entry["index"].(map[string]interface{})
+		if failure, ok := result["error"].(map[string]interface{}); ok {
+			errors = append(errors, map[string]interface{}{
+				"position": i,
+				"id":       result["_id"],
+				"reason":   errorReason(failure),
+			})
+			continue
+		}
+		indexed++
+	}
+
+	return map[string]interface{}{
+		"indexed": indexed,
+		"failed":  len(errors),
+		"errors":  errors,
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) getDocument(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	id := getStringParam(params, "id", "")
+	if index == "" || id == "" {
+		return nil, fmt.Errorf("index_name and id are required")
+	}
+
+	req := esapi.GetRequest{Index: index, DocumentID: id}
+	data, status, err := decodeResponse(req.Do(ctx, client))
+	if status == http.StatusNotFound && data["found"] == false {
+		return map[string]interface{}{"found": false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"found":    data["found"],
+		"document": data["_source"],
+		"version":  data["_version"],
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) search(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	if index == "" {
+		return nil, fmt.Errorf("index_name is required")
+	}
+
+	query := map[string]interface{}{"query": queryParam(params)}
+	query["size"] = getIntParam(params, "size", 10)
+	query["from"] = getIntParam(params, "from", 0)
+	if sort, ok := params["sort"]; ok && sort != nil {
+		query["sort"] = sort
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %v", err)
+	}
+
+	req := esapi.SearchRequest{Index: []string{index}, Body: bytes.NewReader(body)}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	hitsData, _ := data["hits"].(map[string]interface{})
+	hits := []map[string]interface{}{}
+	items, _ := hitsData["hits"].([]interface{})
+	for _, item := range items {
+		hit, _ := item.(map[string]interface{})
+		hits = append(hits, map[string]interface{}{
+			"id":       hit["_id"],
+			"index":    hit["_index"],
+			"score":    hit["_score"],
+			"document": hit["_source"],
+		})
+	}
+
+	// hits.total is an object ({value, relation}) since 7.0
+	total := hitsData["total"]
+	if totalData, ok := total.(map[string]interface{}); ok {
+		total = totalData["value"]
+	}
+
+	return map[string]interface{}{
+		"hits":      hits,
+		"total":     total,
+		"max_score": hitsData["max_score"],
+		"took":      data["took"],
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) deleteDocument(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	id := getStringParam(params, "id", "")
+	if index == "" || id == "" {
+		return nil, fmt.Errorf("index_name and id are required")
+	}
+
+	req := esapi.DeleteRequest{Index: index, DocumentID: id, Refresh: refreshParam(params)}
+	data, status, err := decodeResponse(req.Do(ctx, client))
+	if status == http.StatusNotFound && data["result"] == "not_found" {
+		return map[string]interface{}{"deleted": false, "result": "not_found"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"deleted": data["result"] == "deleted",
+		"result":  data["result"],
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) deleteByQuery(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	if index == "" {
+		return nil, fmt.Errorf("index_name is required")
+	}
+	// Unlike search, the query is required so that deleting everything takes an explicit match_all
+	query, ok := params["query"].(map[string]interface{})
+	if !ok || len(query) == 0 {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"query": query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %v", err)
+	}
+
+	refresh := getBoolParam(params, "refresh", false)
+	req := esapi.DeleteByQueryRequest{
+		Index:   []string{index},
+		Body:    bytes.NewReader(body),
+		Refresh: &refresh,
+	}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	failures, _ := data["failures"].([]interface{})
+	if failures == nil {
+		failures = []interface{}{}
+	}
+
+	return map[string]interface{}{
+		"deleted":  data["deleted"],
+		"total":    data["total"],
+		"failures": failures,
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) createIndex(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	definition := map[string]interface{}{}
+	if mappings, ok := params["mappings"].(map[string]interface{}); ok {
+		definition["mappings"] = mappings
+	}
+	if settings, ok := params["settings"].(map[string]interface{}); ok {
+		definition["settings"] = settings
+	}
+
+	body, err := json.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode index definition: %v", err)
+	}
+
+	req := esapi.IndicesCreateRequest{Index: name, Body: bytes.NewReader(body)}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"acknowledged": data["acknowledged"],
+		"index":        data["index"],
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) deleteIndex(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	req := esapi.IndicesDeleteRequest{Index: []string{name}}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"acknowledged": data["acknowledged"],
+	}, nil
+}
+
+func (p *ElasticsearchPlugin) count(ctx context.Context, client *elasticsearch.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	index := getStringParam(params, "index_name", "")
+	if index == "" {
+		return nil, fmt.Errorf("index_name is required")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"query": queryParam(params)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %v", err)
+	}
+
+	req := esapi.CountRequest{Index: []string{index}, Body: bytes.NewReader(body)}
+	data, _, err := decodeResponse(req.Do(ctx, client))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"count": data["count"],
+	}, nil
+}
+
+// decodeResponse reads a JSON response body. For error statuses it returns the decoded body as
+// well as an error, so callers can treat expected cases such as a missing document as results.
+func decodeResponse(res *esapi.Response, err error) (map[string]interface{}, int, error) {
+	if err != nil {
+		return map[string]interface{}{}, 0, fmt.Errorf("request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return map[string]interface{}{}, res.StatusCode, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	data := map[string]interface{}{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &data); err != nil && !res.IsError() {
+			return data, res.StatusCode, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if res.IsError() {
+		reason := string(raw)
+		if failure, ok := data["error"].(map[string]interface{}); ok {
+			reason = errorReason(failure)
+		} else if message, ok := data["error"].(string); ok {
+			reason = message
+		}
+		return data, res.StatusCode, fmt.Errorf("Elasticsearch error (%d): %s", res.StatusCode, reason)
+	}
+
+	return data, res.StatusCode, nil
+}
+
+func errorReason(failure map[string]interface{}) string {
+	reason, _ := failure["reason"].(string)
+	if errorType, ok := failure["type"].(string); ok {
+		return fmt.Sprintf("%s: %s", errorType, reason)
+	}
+	return reason
+}
+
+func queryParam(params map[string]interface{}) map[string]interface{} {
+	if query, ok := params["query"].(map[string]interface{}); ok && len(query) > 0 {
+		return query
+	}
+	return map[string]interface{}{"match_all": map[string]interface{}{}}
+}
+
+func refreshParam(params map[string]interface{}) string {
+	if getBoolParam(params, "refresh", false) {
+		return "true"
+	}
+	return ""
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewElasticsearchPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -1,17 +1,30 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	htmltemplate "html/template"
 	"io"
 	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	texttemplate "text/template"
 	"time"
 )
 
@@ -65,18 +78,64 @@ func (p *EmailPlugin) GetActions() map[string]ActionSpec {
 				"subject": {
 					Type:        "string",
 					Required:    true,
-					Description: "Email subject",
+					Description: "Email subject (optional when subject_template is set)",
 				},
 				"body": {
 					Type:        "string",
 					Required:    true,
-					Description: "Email body",
+					Description: "Email body (optional when email_template is set)",
+				},
+				"email_template": {
+					Type:        "string",
+					Required:    false,
+					Description: "Go html/template rendered with template_data as the HTML body",
+				},
+				"subject_template": {
+					Type:        "string",
+					Required:    false,
+					Description: "Go text/template rendered with template_data as the subject",
+				},
+				"template_data": {
+					Type:        "object",
+					Required:    false,
+					Description: "Data passed to email_template and subject_template",
+				},
+				"provider": {
+					Type:        "string",
+					Required:    false,
+					Default:     "smtp",
+					Description: "Delivery provider: smtp, sendgrid (SENDGRID_API_KEY), or mailgun (MAILGUN_API_KEY, MAILGUN_DOMAIN)",
+				},
+				"fallback_smtp_servers": {
+					Type:        "array",
+					Required:    false,
+					Description: "Fallback SMTP servers tried in order when the primary is unreachable or rejects auth (objects with server, port, user, password, tls)",
 				},
 				"from_email": {
 					Type:        "string",
 					Required:    false,
 					Description: "Sender email",
 				},
+				"from_name": {
+					Type:        "string",
+					Required:    false,
+					Description: "Sender display name",
+				},
+				"cc": {
+					Type:        "array",
+					Required:    false,
+					Description: "CC recipient emails",
+				},
+				"bcc": {
+					Type:        "array",
+					Required:    false,
+					Description: "BCC recipient emails (envelope only, not shown in headers)",
+				},
+				"reply_to": {
+					Type:        "array",
+					Required:    false,
+					Description: "Reply-To address(es) as string or array",
+				},
 				"attachments": {
 					Type:        "array",
 					Required:    false,
@@ -90,8 +149,139 @@ func (p *EmailPlugin) GetActions() map[string]ActionSpec {
 				},
 			},
 			Outputs: map[string]IOSpec{
-				"success":    {Type: "boolean", Description: "Email sent successfully"},
-				"message_id": {Type: "string", Description: "Message ID"},
+				"success":     {Type: "boolean", Description: "Email sent successfully"},
+				"message_id":  {Type: "string", Description: "Message ID"},
+				"provider":    {Type: "string", Description: "Provider that delivered the message"},
+				"used_server": {Type: "string", Description: "SMTP server (host:port) that delivered the message"},
+			},
+		},
+		"read_emails": {
+			Description: "Read the most recent emails from an IMAP mailbox",
+			Inputs: map[string]IOSpec{
+				"imap_server": {
+					Type:        "string",
+					Required:    true,
+					Description: "IMAP server hostname",
+				},
+				"imap_port": {
+					Type:        "number",
+					Required:    false,
+					Default:     993,
+					Description: "IMAP server port",
+				},
+				"username": {
+					Type:        "string",
+					Required:    true,
+					Description: "IMAP username",
+				},
+				"password": {
+					Type:        "string",
+					Required:    true,
+					Description: "IMAP password",
+				},
+				"mailbox": {
+					Type:        "string",
+					Required:    false,
+					Default:     "INBOX",
+					Description: "Mailbox to read",
+				},
+				"tls": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     true,
+					Description: "Use implicit TLS",
+				},
+				"limit": {
+					Type:        "number",
+					Required:    false,
+					Default:     10,
+					Description: "Maximum number of most recent messages to return",
+				},
+				"mark_as_read": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Set the \\Seen flag on returned messages",
+				},
+			},
+			Outputs: map[string]IOSpec{
+				"messages": {Type: "array", Description: "Messages with uid, from, subject, date, body, body_text, body_html, attachments"},
+				"count":    {Type: "number", Description: "Number of messages returned"},
+			},
+		},
+		"search_emails": {
+			Description: "Search an IMAP mailbox by subject, sender, date, and read state",
+			Inputs: map[string]IOSpec{
+				"imap_server": {
+					Type:        "string",
+					Required:    true,
+					Description: "IMAP server hostname",
+				},
+				"imap_port": {
+					Type:        "number",
+					Required:    false,
+					Default:     993,
+					Description: "IMAP server port",
+				},
+				"username": {
+					Type:        "string",
+					Required:    true,
+					Description: "IMAP username",
+				},
+				"password": {
+					Type:        "string",
+					Required:    true,
+					Description: "IMAP password",
+				},
+				"mailbox": {
+					Type:        "string",
+					Required:    false,
+					Default:     "INBOX",
+					Description: "Mailbox to read",
+				},
+				"tls": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     true,
+					Description: "Use implicit TLS",
+				},
+				"limit": {
+					Type:        "number",
+					Required:    false,
+					Default:     10,
+					Description: "Maximum number of most recent messages to return",
+				},
+				"subject": {
+					Type:        "string",
+					Required:    false,
+					Description: "Subject substring to match",
+				},
+				"from": {
+					Type:        "string",
+					Required:    false,
+					Description: "Sender address or name substring to match",
+				},
+				"since": {
+					Type:        "string",
+					Required:    false,
+					Description: "Only messages on or after this date (YYYY-MM-DD or RFC3339)",
+				},
+				"unseen_only": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Only return unread messages",
+				},
+				"mark_as_read": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Set the \\Seen flag on returned messages",
+				},
+			},
+			Outputs: map[string]IOSpec{
+				"messages": {Type: "array", Description: "Messages with uid, from, subject, date, body, body_text, body_html, attachments"},
+				"count":    {Type: "number", Description: "Number of messages returned"},
 			},
 		},
 	}
@@ -101,6 +291,10 @@ func (p *EmailPlugin) Execute(action string, params map[string]interface{}) (map
 	switch action {
 	case "send":
 		return p.sendEmail(params)
+	case "read_emails":
+		return p.readEmails(params, false)
+	case "search_emails":
+		return p.readEmails(params, true)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
@@ -113,13 +307,17 @@ func (p *EmailPlugin) sendEmail(params map[string]interface{}) (map[string]inter
 		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
-	subject, ok := params["subject"].(string)
-	if !ok || subject == "" {
+	emailTemplate, _ := params["email_template"].(string)
+	subjectTemplate, _ := params["subject_template"].(string)
+	templateData, _ := params["template_data"].(map[string]interface{})
+
+	subject, _ := params["subject"].(string)
+	if subject == "" && subjectTemplate == "" {
 		return map[string]interface{}{"error": "subject is required"}, nil
 	}
 
-	body, ok := params["body"].(string)
-	if !ok || body == "" {
+	body, _ := params["body"].(string)
+	if body == "" && emailTemplate == "" {
 		return map[string]interface{}{"error": "body is required"}, nil
 	}
 
@@ -134,12 +332,62 @@ func (p *EmailPlugin) sendEmail(params map[string]interface{}) (map[string]inter
 		return map[string]interface{}{"error": "from_email is required (parameter or SMTP_FROM_EMAIL env var)"}, nil
 	}
 
-	// Parse attachments
-	attachments := p.parseAttachments(params["attachments"])
+	ccEmails, err := p.parseOptionalEmails(params["cc"], "cc")
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	bccEmails, err := p.parseOptionalEmails(params["bcc"], "bcc")
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	replyTo, err := p.parseOptionalEmails(params["reply_to"], "reply_to")
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	fromName, _ := params["from_name"].(string)
+	fromHeader := fromEmail
+	if fromName != "" {
+		fromHeader = (&mail.Address{Name: fromName, Address: fromEmail}).String()
+	}
 
-	// Check if HTML email
-	isHTML := getBoolParam(params, "html", false)
+	msg := outgoingMessage{
+		From:            fromHeader,
+		FromEmail:       fromEmail,
+		FromName:        fromName,
+		To:              toEmails,
+		Cc:              ccEmails,
+		Bcc:             bccEmails,
+		ReplyTo:         replyTo,
+		Subject:         subject,
+		Body:            body,
+		IsHTML:          getBoolParam(params, "html", false),
+		Attachments:     p.parseAttachments(params["attachments"]),
+		EmailTemplate:   emailTemplate,
+		SubjectTemplate: subjectTemplate,
+		TemplateData:    templateData,
+	}
 
+	provider := "smtp"
+	if val, ok := params["provider"].(string); ok && val != "" {
+		provider = strings.ToLower(val)
+	}
+
+	switch provider {
+	case "smtp":
+		return p.sendViaSMTP(msg, params)
+	case "sendgrid":
+		return p.sendViaSendGrid(msg)
+	case "mailgun":
+		return p.sendViaMailgun(msg)
+	default:
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported provider: %s (use smtp, sendgrid, or mailgun)", provider)}, nil
+	}
+}
+
+func (p *EmailPlugin) sendViaSMTP(msg outgoingMessage, params map[string]interface{}) (map[string]interface{}, error) {
 	// Get SMTP configuration from environment
 	smtpServer := os.Getenv("SMTP_SERVER")
 	if smtpServer == "" {
@@ -155,28 +403,302 @@ func (p *EmailPlugin) sendEmail(params map[string]interface{}) (map[string]inter
 		return map[string]interface{}{"error": fmt.Sprintf("invalid SMTP_PORT: %v", err)}, nil
 	}
 
-	smtpUser := os.Getenv("SMTP_USER")
-	smtpPass := os.Getenv("SMTP_PASSWORD")
-	smtpTLS := getBoolFromEnv("SMTP_TLS", true)
+	servers := []smtpServerConfig{{
+		Server:   smtpServer,
+		Port:     smtpPort,
+		User:     os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		TLS:      getBoolFromEnv("SMTP_TLS", true),
+	}}
+
+	fallbacks, err := p.parseFallbackServers(params["fallback_smtp_servers"])
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	servers = append(servers, fallbacks...)
 
 	// Build email message
-	message, messageID, err := p.buildMessage(fromEmail, toEmails, subject, body, isHTML, attachments)
+	message, messageID, err := p.buildMessage(msg)
 	if err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("failed to build message: %v", err)}, nil
 	}
 
-	// Send email
-	err = p.sendSMTP(smtpServer, smtpPort, smtpUser, smtpPass, smtpTLS, fromEmail, toEmails, message)
+	// Envelope recipients include BCC, which never appears in the headers
+	recipients := append(append(append([]string{}, msg.To...), msg.Cc...), msg.Bcc...)
+
+	// Send email, moving on to the next server only when this one is unreachable or rejects auth
+	var failures []string
+	for _, server := range servers {
+		err = p.sendSMTP(server, msg.FromEmail, recipients, message)
+		if err == nil {
+			return map[string]interface{}{
+				"success":     true,
+				"message_id":  messageID,
+				"provider":    "smtp",
+				"used_server": server.Address(),
+			}, nil
+		}
+
+		var unavailable *smtpUnavailableError
+		if !errors.As(err, &unavailable) {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to send email via %s: %v", server.Address(), err)}, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", server.Address(), err))
+	}
+
+	return map[string]interface{}{"error": fmt.Sprintf("failed to send email: %s", strings.Join(failures, "; "))}, nil
+}
+
+func (p *EmailPlugin) sendViaSendGrid(msg outgoingMessage) (map[string]interface{}, error) {
+	apiKey := os.Getenv("SENDGRID_API_KEY")
+	if apiKey == "" {
+		return map[string]interface{}{"error": "SENDGRID_API_KEY environment variable is required for the sendgrid provider"}, nil
+	}
+
+	msg, err := p.renderTemplates(msg)
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to send email: %v", err)}, nil
+		return map[string]interface{}{"error": fmt.Sprintf("failed to build message: %v", err)}, nil
+	}
+
+	toAddresses := func(emails []string) []map[string]string {
+		addresses := make([]map[string]string, len(emails))
+		for i, email := range emails {
+			addresses[i] = map[string]string{"email": email}
+		}
+		return addresses
+	}
+
+	personalization := map[string]interface{}{"to": toAddresses(msg.To)}
+	if len(msg.Cc) > 0 {
+		personalization["cc"] = toAddresses(msg.Cc)
+	}
+	if len(msg.Bcc) > 0 {
+		personalization["bcc"] = toAddresses(msg.Bcc)
+	}
+
+	from := map[string]string{"email": msg.FromEmail}
+	if msg.FromName != "" {
+		from["name"] = msg.FromName
+	}
+
+	contentType := "text/plain"
+	if msg.IsHTML {
+		contentType = "text/html"
+	}
+
+	payload := map[string]interface{}{
+		"personalizations": []interface{}{personalization},
+		"from":             from,
+		"subject":          msg.Subject,
+		"content":          []map[string]string{{"type": contentType, "value": msg.Body}},
+	}
+	if len(msg.ReplyTo) == 1 {
+		payload["reply_to"] = map[string]string{"email": msg.ReplyTo[0]}
+	} else if len(msg.ReplyTo) > 1 {
+		payload["reply_to_list"] = toAddresses(msg.ReplyTo)
+	}
+
+	if len(msg.Attachments) > 0 {
+		attachments := make([]map[string]string, 0, len(msg.Attachments))
+		for _, filePath := range msg.Attachments {
+			content, contentType, err := readAttachment(filePath)
+			if err != nil {
+				return map[string]interface{}{"error": fmt.Sprintf("failed to add attachment %s: %v", filePath, err)}, nil
+			}
+			attachments = append(attachments, map[string]string{
+				"content":     base64.StdEncoding.EncodeToString(content),
+				"filename":    filepath.Base(filePath),
+				"type":        contentType,
+				"disposition": "attachment",
+			})
+		}
+		payload["attachments"] = attachments
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to marshal SendGrid request: %v", err)}, nil
+	}
+
+	req, err := http.NewRequest("POST", "https://api.sendgrid.com/v3/mail/send", bytes.NewReader(payloadBytes))
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("SendGrid request failed: %v", err)}, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return map[string]interface{}{"error": fmt.Sprintf("SendGrid API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))}, nil
 	}
 
 	return map[string]interface{}{
 		"success":    true,
-		"message_id": messageID,
+		"message_id": resp.Header.Get("X-Message-Id"),
+		"provider":   "sendgrid",
 	}, nil
 }
 
+func (p *EmailPlugin) sendViaMailgun(msg outgoingMessage) (map[string]interface{}, error) {
+	apiKey := os.Getenv("MAILGUN_API_KEY")
+	domain := os.Getenv("MAILGUN_DOMAIN")
+	if apiKey == "" || domain == "" {
+		return map[string]interface{}{"error": "MAILGUN_API_KEY and MAILGUN_DOMAIN environment variables are required for the mailgun provider"}, nil
+	}
+
+	// EU-region domains use https://api.eu.mailgun.net
+	apiBase := os.Getenv("MAILGUN_API_BASE")
+	if apiBase == "" {
+		apiBase = "https://api.mailgun.net"
+	}
+
+	msg, err := p.renderTemplates(msg)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to build message: %v", err)}, nil
+	}
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	writer.WriteField("from", msg.From)
+	for _, email := range msg.To {
+		writer.WriteField("to", email)
+	}
+	for _, email := range msg.Cc {
+		writer.WriteField("cc", email)
+	}
+	for _, email := range msg.Bcc {
+		writer.WriteField("bcc", email)
+	}
+	if len(msg.ReplyTo) > 0 {
+		writer.WriteField("h:Reply-To", strings.Join(msg.ReplyTo, ", "))
+	}
+	writer.WriteField("subject", msg.Subject)
+	if msg.IsHTML {
+		writer.WriteField("html", msg.Body)
+	} else {
+		writer.WriteField("text", msg.Body)
+	}
+
+	for _, filePath := range msg.Attachments {
+		content, _, err := readAttachment(filePath)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to add attachment %s: %v", filePath, err)}, nil
+		}
+		part, err := writer.CreateFormFile("attachment", filepath.Base(filePath))
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to add attachment %s: %v", filePath, err)}, nil
+		}
+		part.Write(content)
+	}
+
+	if err := writer.Close(); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to build Mailgun request: %v", err)}, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/v3/%s/messages", strings.TrimSuffix(apiBase, "/"), domain)
+	req, err := http.NewRequest("POST", endpoint, &form)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+	}
+	req.SetBasicAuth("api", apiKey)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("Mailgun request failed: %v", err)}, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return map[string]interface{}{"error": fmt.Sprintf("Mailgun API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))}, nil
+	}
+
+	var mailgunResp struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(respBody, &mailgunResp)
+
+	return map[string]interface{}{
+		"success":    true,
+		"message_id": mailgunResp.ID,
+		"provider":   "mailgun",
+	}, nil
+}
+
+// smtpServerConfig describes one SMTP server the send action may deliver through.
+type smtpServerConfig struct {
+	Server   string
+	Port     int
+	User     string
+	Password string
+	TLS      bool
+}
+
+func (c smtpServerConfig) Address() string {
+	return net.JoinHostPort(c.Server, strconv.Itoa(c.Port))
+}
+
+// smtpUnavailableError marks connection and authentication failures, where another server may succeed.
+type smtpUnavailableError struct {
+	err error
+}
+
+func (e *smtpUnavailableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *smtpUnavailableError) Unwrap() error {
+	return e.err
+}
+
+func (p *EmailPlugin) parseFallbackServers(value interface{}) ([]smtpServerConfig, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fallback_smtp_servers must be an array of objects")
+	}
+
+	servers := make([]smtpServerConfig, 0, len(list))
+	for i, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("fallback_smtp_servers[%d] must be an object", i)
+		}
+
+		server, _ := entry["server"].(string)
+		if server == "" {
+			return nil, fmt.Errorf("fallback_smtp_servers[%d].server is required", i)
+		}
+
+		port := 587
+		if val, ok := entry["port"].(float64); ok && val > 0 {
+			port = int(val)
+		}
+
+		user, _ := entry["user"].(string)
+		password, _ := entry["password"].(string)
+
+		servers = append(servers, smtpServerConfig{
+			Server:   server,
+			Port:     port,
+			User:     user,
+			Password: password,
+			TLS:      getBoolParam(entry, "tls", true),
+		})
+	}
+	return servers, nil
+}
+
 func (p *EmailPlugin) parseToEmails(to interface{}) ([]string, error) {
 	if to == nil {
 		return nil, fmt.Errorf("to is required")
@@ -206,6 +728,31 @@ func (p *EmailPlugin) parseToEmails(to interface{}) ([]string, error) {
 	}
 }
 
+// parseOptionalEmails accepts a string or array of strings and returns nil when absent.
+func (p *EmailPlugin) parseOptionalEmails(value interface{}, field string) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return []string{v}, nil
+	case []interface{}:
+		emails := make([]string, 0, len(v))
+		for i, email := range v {
+			emailStr, ok := email.(string)
+			if !ok || emailStr == "" {
+				return nil, fmt.Errorf("%s[%d] must be a non-empty string", field, i)
+			}
+			emails = append(emails, emailStr)
+		}
+		return emails, nil
+	default:
+		return nil, fmt.Errorf("%s must be a string or array of strings", field)
+	}
+}
+
 func (p *EmailPlugin) parseAttachments(attachments interface{}) []string {
 	if attachments == nil {
 		return nil
@@ -223,15 +770,79 @@ func (p *EmailPlugin) parseAttachments(attachments interface{}) []string {
 	return nil
 }
 
-func (p *EmailPlugin) buildMessage(fromEmail string, toEmails []string, subject, body string, isHTML bool, attachments []string) ([]byte, string, error) {
+// outgoingMessage holds everything buildMessage needs to render an email.
+type outgoingMessage struct {
+	From        string
+	FromEmail   string
+	FromName    string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     []string
+	Subject     string
+	Body        string
+	IsHTML      bool
+	Attachments []string
+
+	// Templates take precedence over Subject/Body when set
+	EmailTemplate   string
+	SubjectTemplate string
+	TemplateData    map[string]interface{}
+}
+
+// renderTemplates executes subject_template/email_template, replacing the literal subject and body.
+func (p *EmailPlugin) renderTemplates(msg outgoingMessage) (outgoingMessage, error) {
+	if msg.SubjectTemplate != "" {
+		tmpl, err := texttemplate.New("subject").Parse(msg.SubjectTemplate)
+		if err != nil {
+			return msg, fmt.Errorf("invalid subject_template: %v", err)
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, msg.TemplateData); err != nil {
+			return msg, fmt.Errorf("failed to render subject_template: %v", err)
+		}
+		// Header values must stay on a single line
+		msg.Subject = strings.Join(strings.Fields(rendered.String()), " ")
+	}
+
+	if msg.EmailTemplate != "" {
+		tmpl, err := htmltemplate.New("email").Parse(msg.EmailTemplate)
+		if err != nil {
+			return msg, fmt.Errorf("invalid email_template: %v", err)
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, msg.TemplateData); err != nil {
+			return msg, fmt.Errorf("failed to render email_template: %v", err)
+		}
+		msg.Body = rendered.String()
+		msg.IsHTML = true
+	}
+
+	return msg, nil
+}
+
+func (p *EmailPlugin) buildMessage(msg outgoingMessage) ([]byte, string, error) {
 	// Generate a message ID
 	messageID := fmt.Sprintf("<%d@corynth-email-plugin>", generateTimestamp())
 
+	msg, err := p.renderTemplates(msg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	subject, body, isHTML, attachments := msg.Subject, msg.Body, msg.IsHTML, msg.Attachments
+
 	var message strings.Builder
 
 	// Headers
-	message.WriteString(fmt.Sprintf("From: %s\r\n", fromEmail))
-	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(toEmails, ", ")))
+	message.WriteString(fmt.Sprintf("From: %s\r\n", msg.From))
+	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(msg.To, ", ")))
+	if len(msg.Cc) > 0 {
+		message.WriteString(fmt.Sprintf("Cc: %s\r\n", strings.Join(msg.Cc, ", ")))
+	}
+	if len(msg.ReplyTo) > 0 {
+		message.WriteString(fmt.Sprintf("Reply-To: %s\r\n", strings.Join(msg.ReplyTo, ", ")))
+	}
 	message.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
 	message.WriteString(fmt.Sprintf("Message-ID: %s\r\n", messageID))
 	message.WriteString("MIME-Version: 1.0\r\n")
@@ -280,23 +891,11 @@ func (p *EmailPlugin) buildMessage(fromEmail string, toEmails []string, subject,
 }
 
 func (p *EmailPlugin) addAttachment(message *strings.Builder, boundary, filePath string) error {
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return fmt.Errorf("file does not exist: %s", filePath)
-	}
-
-	// Read file content
-	fileContent, err := os.ReadFile(filePath)
+	fileContent, contentType, err := readAttachment(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %v", err)
+		return err
 	}
-
-	// Get filename and detect content type
 	filename := filepath.Base(filePath)
-	contentType := mime.TypeByExtension(filepath.Ext(filePath))
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
 
 	// Write attachment headers
 	message.WriteString(fmt.Sprintf("--%s\r\n", boundary))
@@ -321,10 +920,12 @@ func (p *EmailPlugin) addAttachment(message *strings.Builder, boundary, filePath
 	return nil
 }
 
-func (p *EmailPlugin) sendSMTP(server string, port int, username, password string, useTLS bool, from string, to []string, message []byte) error {
+func (p *EmailPlugin) sendSMTP(config smtpServerConfig, from string, to []string, message []byte) error {
+	server, port, username, password, useTLS := config.Server, config.Port, config.User, config.Password, config.TLS
+
 	// Connect to SMTP server
-	addr := fmt.Sprintf("%s:%d", server, port)
-	
+	addr := config.Address()
+
 	var client *smtp.Client
 	var err error
 
@@ -335,17 +936,17 @@ func (p *EmailPlugin) sendSMTP(server string, port int, username, password strin
 		}
 		conn, err := tls.Dial("tcp", addr, tlsConfig)
 		if err != nil {
-			return fmt.Errorf("failed to connect to SMTP server: %v", err)
+			return &smtpUnavailableError{fmt.Errorf("failed to connect to SMTP server: %v", err)}
 		}
 		client, err = smtp.NewClient(conn, server)
 		if err != nil {
-			return fmt.Errorf("failed to create SMTP client: %v", err)
+			return &smtpUnavailableError{fmt.Errorf("failed to create SMTP client: %v", err)}
 		}
 	} else {
 		// Regular SMTP connection
 		client, err = smtp.Dial(addr)
 		if err != nil {
-			return fmt.Errorf("failed to connect to SMTP server: %v", err)
+			return &smtpUnavailableError{fmt.Errorf("failed to connect to SMTP server: %v", err)}
 		}
 	}
 	defer client.Close()
@@ -357,7 +958,7 @@ func (p *EmailPlugin) sendSMTP(server string, port int, username, password strin
 				ServerName: server,
 			}
 			if err = client.StartTLS(tlsConfig); err != nil {
-				return fmt.Errorf("failed to start TLS: %v", err)
+				return &smtpUnavailableError{fmt.Errorf("failed to start TLS: %v", err)}
 			}
 		}
 	}
@@ -366,7 +967,7 @@ func (p *EmailPlugin) sendSMTP(server string, port int, username, password strin
 	if username != "" && password != "" {
 		auth := smtp.PlainAuth("", username, password, server)
 		if err = client.Auth(auth); err != nil {
-			return fmt.Errorf("SMTP authentication failed: %v", err)
+			return &smtpUnavailableError{fmt.Errorf("SMTP authentication failed: %v", err)}
 		}
 	}
 
@@ -400,7 +1001,437 @@ func (p *EmailPlugin) sendSMTP(server string, port int, username, password strin
 	return nil
 }
 
+func (p *EmailPlugin) readEmails(params map[string]interface{}, search bool) (map[string]interface{}, error) {
+	server, ok := params["imap_server"].(string)
+	if !ok || server == "" {
+		return map[string]interface{}{"error": "imap_server is required"}, nil
+	}
+
+	username, ok := params["username"].(string)
+	if !ok || username == "" {
+		return map[string]interface{}{"error": "username is required"}, nil
+	}
+
+	password, ok := params["password"].(string)
+	if !ok || password == "" {
+		return map[string]interface{}{"error": "password is required"}, nil
+	}
+
+	port := 993
+	if val, ok := params["imap_port"].(float64); ok && val > 0 {
+		port = int(val)
+	}
+
+	mailbox := "INBOX"
+	if val, ok := params["mailbox"].(string); ok && val != "" {
+		mailbox = val
+	}
+
+	limit := 10
+	if val, ok := params["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+
+	// Quoted IMAP strings cannot contain line breaks; passing them through would let a value inject extra commands
+	for _, key := range []string{"username", "password", "mailbox", "subject", "from"} {
+		if val, ok := params[key].(string); ok && strings.ContainsAny(val, "\r\n") {
+			return map[string]interface{}{"error": fmt.Sprintf("%s must not contain line breaks", key)}, nil
+		}
+	}
+
+	criteria := []string{"ALL"}
+	if search {
+		var err error
+		criteria, err = p.buildSearchCriteria(params)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+	}
+
+	client, err := dialIMAP(server, port, getBoolParam(params, "tls", true))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer client.Close()
+
+	if _, err := client.Command("LOGIN %s %s", imapQuote(username), imapQuote(password)); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("IMAP login failed: %v", err)}, nil
+	}
+
+	// EXAMINE opens the mailbox read-only so reading never changes \Seen flags;
+	// SELECT is only needed when the caller asks to mark messages as read
+	markAsRead := getBoolParam(params, "mark_as_read", false)
+	openCommand := "EXAMINE"
+	if markAsRead {
+		openCommand = "SELECT"
+	}
+	if _, err := client.Command("%s %s", openCommand, imapQuote(mailbox)); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to open mailbox %s: %v", mailbox, err)}, nil
+	}
+
+	responses, err := client.Command("UID SEARCH %s", strings.Join(criteria, " "))
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("IMAP search failed: %v", err)}, nil
+	}
+
+	var uids []int
+	for _, resp := range responses {
+		if !strings.HasPrefix(resp.Line, "* SEARCH") {
+			continue
+		}
+		for _, field := range strings.Fields(strings.TrimPrefix(resp.Line, "* SEARCH")) {
+			if uid, err := strconv.Atoi(field); err == nil {
+				uids = append(uids, uid)
+			}
+		}
+	}
+
+	// Newest messages first, capped at limit
+	sort.Sort(sort.Reverse(sort.IntSlice(uids)))
+	if len(uids) > limit {
+		uids = uids[:limit]
+	}
+
+	messages := make([]map[string]interface{}, 0, len(uids))
+	for _, uid := range uids {
+		message, err := p.fetchMessage(client, uid)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		if message != nil {
+			messages = append(messages, message)
+		}
+	}
+
+	if markAsRead && len(messages) > 0 {
+		uidSet := make([]string, 0, len(messages))
+		for _, message := range messages {
+			uidSet = append(uidSet, strconv.Itoa(message["uid"].(int)))
+		}
+		if _, err := client.Command("UID STORE %s +FLAGS.SILENT (\\Seen)", strings.Join(uidSet, ",")); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to mark messages as read: %v", err)}, nil
+		}
+	}
+
+	client.Command("LOGOUT")
+
+	return map[string]interface{}{
+		"messages": messages,
+		"count":    len(messages),
+	}, nil
+}
+
+func (p *EmailPlugin) buildSearchCriteria(params map[string]interface{}) ([]string, error) {
+	var criteria []string
+
+	if subject, ok := params["subject"].(string); ok && subject != "" {
+		criteria = append(criteria, "SUBJECT", imapQuote(subject))
+	}
+
+	if from, ok := params["from"].(string); ok && from != "" {
+		criteria = append(criteria, "FROM", imapQuote(from))
+	}
+
+	if since, ok := params["since"].(string); ok && since != "" {
+		date, err := parseDate(since)
+		if err != nil {
+			return nil, err
+		}
+		criteria = append(criteria, "SINCE", date.Format("02-Jan-2006"))
+	}
+
+	if getBoolParam(params, "unseen_only", false) {
+		criteria = append(criteria, "UNSEEN")
+	}
+
+	if len(criteria) == 0 {
+		criteria = []string{"ALL"}
+	}
+	return criteria, nil
+}
+
+func (p *EmailPlugin) fetchMessage(client *imapClient, uid int) (map[string]interface{}, error) {
+	// BODY.PEEK[] fetches the full message without setting the \Seen flag
+	responses, err := client.Command("UID FETCH %d (BODY.PEEK[])", uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message %d: %v", uid, err)
+	}
+
+	var raw []byte
+	for _, resp := range responses {
+		if strings.Contains(resp.Line, "FETCH") && len(resp.Literals) > 0 {
+			raw = resp.Literals[0]
+			break
+		}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message %d: %v", uid, err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	decodeHeader := func(value string) string {
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			return decoded
+		}
+		return value
+	}
+
+	date := msg.Header.Get("Date")
+	if parsed, err := msg.Header.Date(); err == nil {
+		date = parsed.Format(time.RFC3339)
+	}
+
+	parts := &messageParts{attachments: []map[string]interface{}{}}
+	parts.walk(textproto.MIMEHeader(msg.Header), msg.Body)
+
+	// body prefers the plain-text part and falls back to HTML for HTML-only mail
+	body := parts.text
+	if body == "" {
+		body = parts.html
+	}
+
+	return map[string]interface{}{
+		"uid":         uid,
+		"from":        decodeHeader(msg.Header.Get("From")),
+		"subject":     decodeHeader(msg.Header.Get("Subject")),
+		"date":        date,
+		"body":        body,
+		"body_text":   parts.text,
+		"body_html":   parts.html,
+		"attachments": parts.attachments,
+	}, nil
+}
+
+// messageParts collects the first text/plain and text/html bodies and a summary of each attachment.
+type messageParts struct {
+	text        string
+	html        string
+	attachments []map[string]interface{}
+}
+
+// walk descends a (possibly multipart) MIME entity, filling in bodies and attachments.
+func (m *messageParts) walk(header textproto.MIMEHeader, body io.Reader) {
+	mediaType, mediaParams, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, mediaParams["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				break
+			}
+			m.walk(part.Header, part)
+		}
+		return
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return
+	}
+
+	// Parts marked as attachments, or carrying a filename, are listed rather than treated as a body
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = mediaParams["name"]
+	}
+	if disposition == "attachment" || filename != "" {
+		if decoded, err := new(mime.WordDecoder).DecodeHeader(filename); err == nil {
+			filename = decoded
+		}
+		m.attachments = append(m.attachments, map[string]interface{}{
+			"filename":     filename,
+			"content_type": mediaType,
+			"size":         len(content),
+		})
+		return
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if m.text == "" {
+			m.text = string(content)
+		}
+	case "text/html":
+		if m.html == "" {
+			m.html = string(content)
+		}
+	}
+}
+
+// newlineStripper drops CR/LF so base64 bodies wrapped at 76 columns decode cleanly.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// imapClient is a minimal IMAP4rev1 client covering the commands needed to read mail.
+type imapClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+type imapResponse struct {
+	Line     string
+	Literals [][]byte
+}
+
+var imapLiteralPattern = regexp.MustCompile(`\{(\d+)\}$`)
+
+func dialIMAP(server string, port int, useTLS bool) (*imapClient, error) {
+	addr := net.JoinHostPort(server, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: server})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to IMAP server: %v", err)
+	}
+
+	client := &imapClient{conn: conn, reader: bufio.NewReader(conn)}
+
+	greeting, err := client.readResponse()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read IMAP greeting: %v", err)
+	}
+	if !strings.HasPrefix(greeting.Line, "* OK") && !strings.HasPrefix(greeting.Line, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", greeting.Line)
+	}
+
+	return client, nil
+}
+
+// Command sends a tagged command and collects untagged responses until the tagged completion.
+func (c *imapClient) Command(format string, args ...interface{}) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%03d", c.tag)
+
+	c.conn.SetDeadline(time.Now().Add(60 * time.Second))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(resp.Line, tag+" ") {
+			status := strings.TrimPrefix(resp.Line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("%s", status)
+			}
+			return responses, nil
+		}
+		responses = append(responses, resp)
+	}
+}
+
+// readResponse reads one logical response line, consuming any {n} literals it announces.
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return resp, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		resp.Line += line
+
+		match := imapLiteralPattern.FindStringSubmatch(line)
+		if match == nil {
+			return resp, nil
+		}
+		size, _ := strconv.Atoi(match[1])
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.reader, literal); err != nil {
+			return resp, err
+		}
+		resp.Literals = append(resp.Literals, literal)
+	}
+}
+
+func (c *imapClient) Close() error {
+	return c.conn.Close()
+}
+
+// imapQuote renders a quoted IMAP string; callers must reject values containing CR or LF first.
+func imapQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
 // Helper functions
+
+// apiClient is shared by the HTTP-based delivery providers.
+var apiClient = &http.Client{Timeout: 30 * time.Second}
+
+// readAttachment loads an attachment and detects its content type from the file extension.
+func readAttachment(filePath string) ([]byte, string, error) {
+	// Check if file exists
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("file does not exist: %s", filePath)
+	}
+
+	// Read file content
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return fileContent, contentType, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "02-Jan-2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or RFC3339)", value)
+}
+
 func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
 	if val, ok := params[key].(bool); ok {
 		return val
@@ -1,11 +1,30 @@
 package main
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
+	"html"
 	"io"
 	"os"
+	"os/user"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 type Metadata struct {
@@ -64,10 +83,40 @@ func (p *FilePlugin) GetActions() map[string]ActionSpec {
 				"content":     {Type: "string", Required: true, Description: "Content to write"},
 				"create_dirs": {Type: "boolean", Required: false, Default: false, Description: "Create directories if they don't exist"},
 				"append":      {Type: "boolean", Required: false, Default: false, Description: "Append to file instead of overwriting"},
+				"mode":        {Type: "string", Required: false, Default: "0644", Description: "File permissions as octal string (e.g. 0755)"},
 			},
 			Outputs: map[string]IOSpec{
 				"success": {Type: "boolean", Description: "Write success"},
 				"size":    {Type: "number", Description: "Bytes written"},
+				"mode":    {Type: "string", Description: "Applied file mode (only set when mode is provided)"},
+			},
+		},
+		"chmod": {
+			Description: "Change file or directory permissions",
+			Inputs: map[string]IOSpec{
+				"path":      {Type: "string", Required: true, Description: "File or directory path"},
+				"mode":      {Type: "string", Required: true, Description: "Permissions as octal string (e.g. 0755)"},
+				"recursive": {Type: "boolean", Required: false, Default: false, Description: "Apply to directory contents recursively"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Chmod success"},
+				"mode":    {Type: "string", Description: "Applied mode"},
+				"count":   {Type: "number", Description: "Number of paths changed"},
+			},
+		},
+		"chown": {
+			Description: "Change file or directory ownership (requires root)",
+			Inputs: map[string]IOSpec{
+				"path":      {Type: "string", Required: true, Description: "File or directory path"},
+				"user":      {Type: "string", Required: false, Description: "User name or numeric UID"},
+				"group":     {Type: "string", Required: false, Description: "Group name or numeric GID"},
+				"recursive": {Type: "boolean", Required: false, Default: false, Description: "Apply to directory contents recursively"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Chown success"},
+				"uid":     {Type: "number", Description: "Applied UID (-1 if unchanged)"},
+				"gid":     {Type: "number", Description: "Applied GID (-1 if unchanged)"},
+				"count":   {Type: "number", Description: "Number of paths changed"},
 			},
 		},
 		"copy": {
@@ -92,6 +141,126 @@ func (p *FilePlugin) GetActions() map[string]ActionSpec {
 				"success": {Type: "boolean", Description: "Move success"},
 			},
 		},
+		"list": {
+			Description: "List directory contents",
+			Inputs: map[string]IOSpec{
+				"path":           {Type: "string", Required: true, Description: "Directory path"},
+				"pattern":        {Type: "string", Required: false, Description: "Glob pattern matched against entry names (e.g. *.log)"},
+				"recursive":      {Type: "boolean", Required: false, Default: false, Description: "Include entries in subdirectories"},
+				"include_hidden": {Type: "boolean", Required: false, Default: false, Description: "Include entries whose names start with a dot"},
+				"sort_by":        {Type: "string", Required: false, Default: "name", Description: "Sort order (name, size, modified)"},
+			},
+			Outputs: map[string]IOSpec{
+				"files":            {Type: "array", Description: "Entries with name, path, size, is_dir, modified and permissions"},
+				"total_count":      {Type: "number", Description: "Number of entries returned"},
+				"total_size_bytes": {Type: "number", Description: "Combined size of returned files"},
+			},
+		},
+		"delete": {
+			Description: "Delete a file or directory",
+			Inputs: map[string]IOSpec{
+				"path":       {Type: "string", Required: true, Description: "File or directory path"},
+				"recursive":  {Type: "boolean", Required: false, Default: false, Description: "Delete a non-empty directory and its contents"},
+				"if_exists":  {Type: "boolean", Required: false, Default: false, Description: "Succeed without error if the path does not exist"},
+				"safe_paths": {Type: "array", Required: false, Description: "If set, only paths inside one of these directories may be deleted"},
+			},
+			Outputs: map[string]IOSpec{
+				"deleted": {Type: "boolean", Description: "Whether anything was deleted"},
+				"path":    {Type: "string", Description: "Resolved absolute path"},
+			},
+		},
+		"read_structured": {
+			Description: "Read and parse a JSON or YAML file",
+			Inputs: map[string]IOSpec{
+				"path":   {Type: "string", Required: true, Description: "File path to read"},
+				"format": {Type: "string", Required: false, Default: "auto", Description: "File format (json, yaml, auto detects from extension)"},
+			},
+			Outputs: map[string]IOSpec{
+				"data":    {Type: "object", Description: "Parsed file content as object or array"},
+				"content": {Type: "string", Description: "Raw file content"},
+				"format":  {Type: "string", Description: "Format used for parsing"},
+				"size":    {Type: "number", Description: "File size in bytes"},
+			},
+		},
+		"checksum": {
+			Description: "Compute file checksum (alias of hash with checksum-style outputs)",
+			Inputs: map[string]IOSpec{
+				"path":      {Type: "string", Required: true, Description: "File path to hash"},
+				"algorithm": {Type: "string", Required: false, Default: "sha256", Description: "Hash algorithm (md5, sha1, sha256, sha512)"},
+			},
+			Outputs: map[string]IOSpec{
+				"checksum":   {Type: "string", Description: "Lowercase hex digest"},
+				"algorithm":  {Type: "string", Description: "Algorithm used"},
+				"size_bytes": {Type: "number", Description: "File size in bytes"},
+			},
+		},
+		"verify_checksum": {
+			Description: "Verify a file against an expected checksum",
+			Inputs: map[string]IOSpec{
+				"path":      {Type: "string", Required: true, Description: "File path to verify"},
+				"expected":  {Type: "string", Required: true, Description: "Expected hex digest"},
+				"algorithm": {Type: "string", Required: false, Default: "sha256", Description: "Hash algorithm (md5, sha1, sha256, sha512)"},
+			},
+			Outputs: map[string]IOSpec{
+				"match":     {Type: "boolean", Description: "Whether the computed digest matches expected"},
+				"computed":  {Type: "string", Description: "Lowercase hex digest of the file"},
+				"algorithm": {Type: "string", Description: "Algorithm used"},
+			},
+		},
+		"render_template": {
+			Description: "Render a Go or Mustache template with data",
+			Inputs: map[string]IOSpec{
+				"template_path": {Type: "string", Required: false, Description: "Path to the template file"},
+				"template":      {Type: "string", Required: false, Description: "Inline template (used when template_path is not set)"},
+				"data":          {Type: "object", Required: false, Description: "Values available to the template"},
+				"engine":        {Type: "string", Required: false, Default: "go", Description: "Template engine (go, mustache)"},
+				"output_path":   {Type: "string", Required: false, Description: "Write the rendered output to this path"},
+				"mode":          {Type: "string", Required: false, Description: "Permissions for output_path in octal (e.g. 0600)"},
+			},
+			Outputs: map[string]IOSpec{
+				"output":  {Type: "string", Description: "Rendered content"},
+				"written": {Type: "boolean", Description: "Whether output was written to output_path"},
+			},
+		},
+		"compress": {
+			Description: "Create a zip or tar.gz archive from a file or directory",
+			Inputs: map[string]IOSpec{
+				"source":      {Type: "string", Required: true, Description: "File or directory to archive"},
+				"destination": {Type: "string", Required: true, Description: "Output archive path"},
+				"format":      {Type: "string", Required: false, Description: "Archive format (zip, tar.gz, tgz); detected from destination extension if omitted"},
+			},
+			Outputs: map[string]IOSpec{
+				"files_count":        {Type: "number", Description: "Number of files added to the archive"},
+				"archive_size_bytes": {Type: "number", Description: "Size of the created archive"},
+				"format":             {Type: "string", Description: "Archive format used"},
+			},
+		},
+		"decompress": {
+			Description: "Extract a zip or tar.gz archive into a directory",
+			Inputs: map[string]IOSpec{
+				"source":      {Type: "string", Required: true, Description: "Archive path"},
+				"destination": {Type: "string", Required: true, Description: "Directory to extract into"},
+				"format":      {Type: "string", Required: false, Description: "Archive format (zip, tar.gz, tgz); detected from source extension if omitted"},
+			},
+			Outputs: map[string]IOSpec{
+				"files_extracted": {Type: "array", Description: "Paths of extracted files"},
+				"format":          {Type: "string", Description: "Archive format used"},
+			},
+		},
+		"hash": {
+			Description: "Compute file checksum",
+			Inputs: map[string]IOSpec{
+				"path":      {Type: "string", Required: true, Description: "File path to hash"},
+				"algorithm": {Type: "string", Required: false, Default: "sha256", Description: "Hash algorithm (md5, sha1, sha256, sha512)"},
+				"expected":  {Type: "string", Required: false, Description: "Expected hex digest to compare against"},
+			},
+			Outputs: map[string]IOSpec{
+				"digest":    {Type: "string", Description: "Lowercase hex digest"},
+				"algorithm": {Type: "string", Description: "Algorithm used"},
+				"size":      {Type: "number", Description: "File size in bytes"},
+				"match":     {Type: "boolean", Description: "Whether digest matches expected (only set when expected is provided)"},
+			},
+		},
 	}
 }
 
@@ -101,10 +270,32 @@ func (p *FilePlugin) Execute(action string, params map[string]interface{}) (map[
 		return p.readFile(params)
 	case "write":
 		return p.writeFile(params)
+	case "chmod":
+		return p.chmod(params)
+	case "chown":
+		return p.chown(params)
 	case "copy":
 		return p.copyFile(params)
 	case "move":
 		return p.moveFile(params)
+	case "list":
+		return p.listDir(params)
+	case "delete":
+		return p.deletePath(params)
+	case "read_structured":
+		return p.readStructured(params)
+	case "hash":
+		return p.hashFile(params)
+	case "checksum":
+		return p.checksum(params)
+	case "verify_checksum":
+		return p.verifyChecksum(params)
+	case "render_template":
+		return p.renderTemplate(params)
+	case "compress":
+		return p.compress(params)
+	case "decompress":
+		return p.decompress(params)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
@@ -127,6 +318,62 @@ func (p *FilePlugin) readFile(params map[string]interface{}) (map[string]interfa
 	}, nil
 }
 
+func (p *FilePlugin) readStructured(params map[string]interface{}) (map[string]interface{}, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
+	}
+
+	format := "auto"
+	if val, ok := params["format"].(string); ok && val != "" {
+		format = strings.ToLower(val)
+	}
+
+	if format == "auto" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			format = "json"
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			return map[string]interface{}{"error": fmt.Sprintf("cannot detect format from extension of %s, set format to json or yaml", path)}, nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to read file: %v", err)}, nil
+	}
+
+	var data interface{}
+	switch format {
+	case "json":
+		if err := json.Unmarshal(content, &data); err != nil {
+			if syntaxErr, ok := err.(*json.SyntaxError); ok {
+				line := 1 + strings.Count(string(content[:syntaxErr.Offset]), "\n")
+				return map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON at line %d: %v", line, err)}, nil
+			}
+			return map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}, nil
+		}
+	case "yaml", "yml":
+		format = "yaml"
+		// yaml.v3 errors already include the offending line number
+		if err := yaml.Unmarshal(content, &data); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to parse YAML: %v", err)}, nil
+		}
+		data = normalizeYAML(data)
+	default:
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported format: %s (use json, yaml, or auto)", format)}, nil
+	}
+
+	return map[string]interface{}{
+		"data":    data,
+		"content": string(content),
+		"format":  format,
+		"size":    len(content),
+	}, nil
+}
+
 func (p *FilePlugin) writeFile(params map[string]interface{}) (map[string]interface{}, error) {
 	path, ok := params["path"].(string)
 	if !ok || path == "" {
@@ -141,6 +388,18 @@ func (p *FilePlugin) writeFile(params map[string]interface{}) (map[string]interf
 	createDirs := getBoolParam(params, "create_dirs", false)
 	appendMode := getBoolParam(params, "append", false)
 
+	fileMode := os.FileMode(0644)
+	modeStr, hasMode := params["mode"].(string)
+	if hasMode && modeStr != "" {
+		parsed, err := parseFileMode(modeStr)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error(), "success": false}, nil
+		}
+		fileMode = parsed
+	} else {
+		hasMode = false
+	}
+
 	// Create directories if requested
 	if createDirs {
 		dir := filepath.Dir(path)
@@ -160,7 +419,7 @@ func (p *FilePlugin) writeFile(params map[string]interface{}) (map[string]interf
 		flag |= os.O_TRUNC
 	}
 
-	file, err := os.OpenFile(path, flag, 0644)
+	file, err := os.OpenFile(path, flag, fileMode)
 	if err != nil {
 		return map[string]interface{}{
 			"error":   fmt.Sprintf("failed to open file: %v", err),
@@ -177,9 +436,109 @@ func (p *FilePlugin) writeFile(params map[string]interface{}) (map[string]interf
 		}, nil
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"success": true,
 		"size":    bytesWritten,
+	}
+
+	// OpenFile only applies the mode on creation and is subject to umask, so set it explicitly
+	if hasMode {
+		if err := file.Chmod(fileMode); err != nil {
+			return map[string]interface{}{
+				"error":   fmt.Sprintf("failed to set file mode: %v", err),
+				"success": false,
+			}, nil
+		}
+		result["mode"] = formatFileMode(fileMode)
+	}
+
+	return result, nil
+}
+
+func (p *FilePlugin) chmod(params map[string]interface{}) (map[string]interface{}, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
+	}
+
+	modeStr, ok := params["mode"].(string)
+	if !ok || modeStr == "" {
+		return map[string]interface{}{"error": "mode is required"}, nil
+	}
+
+	mode, err := parseFileMode(modeStr)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error(), "success": false}, nil
+	}
+
+	count := 0
+	err = walkTarget(path, getBoolParam(params, "recursive", false), func(target string) error {
+		count++
+		return os.Chmod(target, mode)
+	})
+	if err != nil {
+		return map[string]interface{}{
+			"error":   fmt.Sprintf("failed to chmod: %v", err),
+			"success": false,
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"mode":    formatFileMode(mode),
+		"count":   count,
+	}, nil
+}
+
+func (p *FilePlugin) chown(params map[string]interface{}) (map[string]interface{}, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
+	}
+
+	if os.Geteuid() != 0 {
+		return map[string]interface{}{"error": "chown requires the plugin to run as root", "success": false}, nil
+	}
+
+	userName, _ := params["user"].(string)
+	groupName, _ := params["group"].(string)
+	if userName == "" && groupName == "" {
+		return map[string]interface{}{"error": "user or group is required"}, nil
+	}
+
+	uid, gid := -1, -1
+	if userName != "" {
+		id, err := lookupUID(userName)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error(), "success": false}, nil
+		}
+		uid = id
+	}
+	if groupName != "" {
+		id, err := lookupGID(groupName)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error(), "success": false}, nil
+		}
+		gid = id
+	}
+
+	count := 0
+	err := walkTarget(path, getBoolParam(params, "recursive", false), func(target string) error {
+		count++
+		return os.Lchown(target, uid, gid)
+	})
+	if err != nil {
+		return map[string]interface{}{
+			"error":   fmt.Sprintf("failed to chown: %v", err),
+			"success": false,
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"uid":     uid,
+		"gid":     gid,
+		"count":   count,
 	}, nil
 }
 
@@ -273,12 +632,974 @@ func (p *FilePlugin) moveFile(params map[string]interface{}) (map[string]interfa
 	}, nil
 }
 
-// Helper functions
-func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
-	if val, ok := params[key].(bool); ok {
-		return val
+func (p *FilePlugin) listDir(params map[string]interface{}) (map[string]interface{}, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
 	}
-	return defaultValue
+
+	pattern, _ := params["pattern"].(string)
+	if pattern != "" {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid pattern: %v", err)}, nil
+		}
+	}
+
+	sortBy, _ := params["sort_by"].(string)
+	if sortBy == "" {
+		sortBy = "name"
+	}
+	if sortBy != "name" && sortBy != "size" && sortBy != "modified" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported sort_by: %s", sortBy)}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to stat path: %v", err)}, nil
+	}
+	if !info.IsDir() {
+		return map[string]interface{}{"error": fmt.Sprintf("%s is not a directory", path)}, nil
+	}
+
+	recursive := getBoolParam(params, "recursive", false)
+	includeHidden := getBoolParam(params, "include_hidden", false)
+
+	var entries []os.FileInfo
+	var paths []string
+	err = filepath.WalkDir(path, func(target string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if target == path {
+			return nil
+		}
+		if !includeHidden && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if pattern == "" || matchName(pattern, d.Name()) {
+			entryInfo, err := d.Info()
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entryInfo)
+			paths = append(paths, target)
+		}
+		if d.IsDir() && !recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to list directory: %v", err)}, nil
+	}
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		left, right := entries[order[a]], entries[order[b]]
+		switch sortBy {
+		case "size":
+			if left.Size() != right.Size() {
+				return left.Size() < right.Size()
+			}
+		case "modified":
+			if !left.ModTime().Equal(right.ModTime()) {
+				return left.ModTime().Before(right.ModTime())
+			}
+		}
+		return paths[order[a]] < paths[order[b]]
+	})
+
+	files := make([]map[string]interface{}, 0, len(entries))
+	var totalSize int64
+	for _, i := range order {
+		entry := entries[i]
+		if !entry.IsDir() {
+			totalSize += entry.Size()
+		}
+		files = append(files, map[string]interface{}{
+			"name":        entry.Name(),
+			"path":        paths[i],
+			"size":        entry.Size(),
+			"is_dir":      entry.IsDir(),
+			"modified":    entry.ModTime().Format(time.RFC3339),
+			"permissions": formatFileMode(entry.Mode()),
+		})
+	}
+
+	return map[string]interface{}{
+		"files":            files,
+		"total_count":      len(files),
+		"total_size_bytes": totalSize,
+	}, nil
+}
+
+func (p *FilePlugin) deletePath(params map[string]interface{}) (map[string]interface{}, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to resolve path: %v", err)}, nil
+	}
+
+	if err := checkDeletable(resolved, params["safe_paths"]); err != nil {
+		return map[string]interface{}{"error": err.Error(), "deleted": false, "path": resolved}, nil
+	}
+
+	info, err := os.Lstat(resolved)
+	if os.IsNotExist(err) && getBoolParam(params, "if_exists", false) {
+		return map[string]interface{}{"deleted": false, "path": resolved}, nil
+	}
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to delete: %v", err), "deleted": false, "path": resolved}, nil
+	}
+
+	// os.Remove refuses non-empty directories, so recursive deletion must be requested explicitly
+	if info.IsDir() && getBoolParam(params, "recursive", false) {
+		err = os.RemoveAll(resolved)
+	} else {
+		err = os.Remove(resolved)
+	}
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to delete: %v", err), "deleted": false, "path": resolved}, nil
+	}
+
+	return map[string]interface{}{
+		"deleted": true,
+		"path":    resolved,
+	}, nil
+}
+
+func (p *FilePlugin) hashFile(params map[string]interface{}) (map[string]interface{}, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
+	}
+
+	algorithm := "sha256"
+	if val, ok := params["algorithm"].(string); ok && val != "" {
+		algorithm = strings.ToLower(val)
+	}
+
+	hasher, err := newHasher(algorithm)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to open file: %v", err)}, nil
+	}
+	defer file.Close()
+
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to hash file: %v", err)}, nil
+	}
+
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	result := map[string]interface{}{
+		"digest":    digest,
+		"algorithm": algorithm,
+		"size":      size,
+	}
+
+	if expected, ok := params["expected"].(string); ok && expected != "" {
+		result["match"] = strings.EqualFold(strings.TrimSpace(expected), digest)
+	}
+
+	return result, nil
+}
+
+// checksum and verifyChecksum reuse hashFile, which streams the file through the hasher
+func (p *FilePlugin) checksum(params map[string]interface{}) (map[string]interface{}, error) {
+	result, err := p.hashFile(map[string]interface{}{
+		"path":      params["path"],
+		"algorithm": params["algorithm"],
+	})
+	if err != nil || result["error"] != nil {
+		return result, err
+	}
+
+	return map[string]interface{}{
+		"checksum":   result["digest"],
+		"algorithm":  result["algorithm"],
+		"size_bytes": result["size"],
+	}, nil
+}
+
+func (p *FilePlugin) verifyChecksum(params map[string]interface{}) (map[string]interface{}, error) {
+	expected, ok := params["expected"].(string)
+	if !ok || strings.TrimSpace(expected) == "" {
+		return map[string]interface{}{"error": "expected is required"}, nil
+	}
+
+	result, err := p.hashFile(params)
+	if err != nil || result["error"] != nil {
+		return result, err
+	}
+
+	return map[string]interface{}{
+		"match":     result["match"],
+		"computed":  result["digest"],
+		"algorithm": result["algorithm"],
+	}, nil
+}
+
+func (p *FilePlugin) renderTemplate(params map[string]interface{}) (map[string]interface{}, error) {
+	source, _ := params["template"].(string)
+	if templatePath, ok := params["template_path"].(string); ok && templatePath != "" {
+		content, err := os.ReadFile(templatePath)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to read template: %v", err)}, nil
+		}
+		source = string(content)
+	} else if _, ok := params["template"].(string); !ok {
+		return map[string]interface{}{"error": "template_path or template is required"}, nil
+	}
+
+	data, _ := params["data"].(map[string]interface{})
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	engine, _ := params["engine"].(string)
+	var output string
+	var err error
+	switch strings.ToLower(engine) {
+	case "", "go":
+		output, err = renderGoTemplate(source, data)
+	case "mustache":
+		output, err = renderMustache(source, data)
+	default:
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported engine: %s", engine)}, nil
+	}
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to render template: %v", err)}, nil
+	}
+
+	result := map[string]interface{}{
+		"output":  output,
+		"written": false,
+	}
+
+	if outputPath, ok := params["output_path"].(string); ok && outputPath != "" {
+		written, err := p.writeFile(map[string]interface{}{
+			"path":        outputPath,
+			"content":     output,
+			"mode":        params["mode"],
+			"create_dirs": true,
+		})
+		if err != nil || written["error"] != nil {
+			return written, err
+		}
+		result["written"] = true
+	}
+
+	return result, nil
+}
+
+func (p *FilePlugin) compress(params map[string]interface{}) (map[string]interface{}, error) {
+	source, ok := params["source"].(string)
+	if !ok || source == "" {
+		return map[string]interface{}{"error": "source is required"}, nil
+	}
+
+	destination, ok := params["destination"].(string)
+	if !ok || destination == "" {
+		return map[string]interface{}{"error": "destination is required"}, nil
+	}
+
+	format, err := archiveFormat(params, destination)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	if _, err := os.Stat(source); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to stat source: %v", err)}, nil
+	}
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create archive: %v", err)}, nil
+	}
+
+	// The archive may be created inside source; the walk skips it so it never adds itself
+	self, err := out.Stat()
+	var count int
+	if err == nil {
+		if format == "zip" {
+			count, err = writeZip(out, source, self)
+		} else {
+			count, err = writeTarGz(out, source, self)
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(destination)
+		return map[string]interface{}{"error": fmt.Sprintf("failed to compress: %v", err)}, nil
+	}
+
+	info, err := os.Stat(destination)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to stat archive: %v", err)}, nil
+	}
+
+	return map[string]interface{}{
+		"files_count":        count,
+		"archive_size_bytes": info.Size(),
+		"format":             format,
+	}, nil
+}
+
+func (p *FilePlugin) decompress(params map[string]interface{}) (map[string]interface{}, error) {
+	source, ok := params["source"].(string)
+	if !ok || source == "" {
+		return map[string]interface{}{"error": "source is required"}, nil
+	}
+
+	destination, ok := params["destination"].(string)
+	if !ok || destination == "" {
+		return map[string]interface{}{"error": "destination is required"}, nil
+	}
+
+	format, err := archiveFormat(params, source)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	if err := os.MkdirAll(destination, 0755); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create destination: %v", err)}, nil
+	}
+
+	var extracted []string
+	if format == "zip" {
+		extracted, err = extractZip(source, destination)
+	} else {
+		extracted, err = extractTarGz(source, destination)
+	}
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to decompress: %v", err)}, nil
+	}
+
+	return map[string]interface{}{
+		"files_extracted": extracted,
+		"format":          format,
+	}, nil
+}
+
+// Helper functions
+
+// normalizeYAML converts map[interface{}]interface{} nodes so the result is JSON-encodable.
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYAML(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func newHasher(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s (use md5, sha1, sha256, sha512)", algorithm)
+	}
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+// parseFileMode accepts octal permission strings such as "755", "0755", or "0o755".
+func parseFileMode(value string) (os.FileMode, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(value), "0o"), "0O")
+	mode, err := strconv.ParseUint(trimmed, 8, 32)
+	if err != nil || mode > 07777 {
+		return 0, fmt.Errorf("invalid mode %q: must be an octal string like 0755", value)
+	}
+
+	// Map the setuid/setgid/sticky octal bits onto Go's FileMode flags
+	fileMode := os.FileMode(mode & 0777)
+	if mode&04000 != 0 {
+		fileMode |= os.ModeSetuid
+	}
+	if mode&02000 != 0 {
+		fileMode |= os.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		fileMode |= os.ModeSticky
+	}
+	return fileMode, nil
+}
+
+func formatFileMode(mode os.FileMode) string {
+	octal := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		octal |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		octal |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		octal |= 01000
+	}
+	return fmt.Sprintf("%04o", octal)
+}
+
+// renderGoTemplate executes a text/template with data as dot, failing on missing keys.
+func renderGoTemplate(source string, data map[string]interface{}) (string, error) {
+	tmpl, err := template.New("template").Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// mustacheNode is a parsed piece of a Mustache template. Kind is one of
+// 't' (text), 'v' (escaped variable), 'u' (unescaped variable),
+// '#' (section) or '^' (inverted section).
+type mustacheNode struct {
+	kind     byte
+	value    string
+	children []mustacheNode
+}
+
+// renderMustache renders the Mustache subset used in config files: variables,
+// dotted names, sections, inverted sections and comments. Partials and
+// delimiter changes are not supported. Missing variables are an error, while
+// missing sections are treated as false.
+func renderMustache(source string, data map[string]interface{}) (string, error) {
+	nodes, _, err := parseMustache(source, 0, "")
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err := renderMustacheNodes(&buf, nodes, []interface{}{data}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parseMustache(source string, pos int, closing string) ([]mustacheNode, int, error) {
+	var nodes []mustacheNode
+	for {
+		start := strings.Index(source[pos:], "{{")
+		if start < 0 {
+			if closing != "" {
+				return nil, 0, fmt.Errorf("unclosed section %q", closing)
+			}
+			return append(nodes, mustacheNode{kind: 't', value: source[pos:]}), len(source), nil
+		}
+		nodes = append(nodes, mustacheNode{kind: 't', value: source[pos : pos+start]})
+		pos += start
+
+		if strings.HasPrefix(source[pos:], "{{{") {
+			end := strings.Index(source[pos+3:], "}}}")
+			if end < 0 {
+				return nil, 0, fmt.Errorf("unclosed tag at offset %d", pos)
+			}
+			nodes = append(nodes, mustacheNode{kind: 'u', value: strings.TrimSpace(source[pos+3 : pos+3+end])})
+			pos += 3 + end + 3
+			continue
+		}
+
+		end := strings.Index(source[pos+2:], "}}")
+		if end < 0 {
+			return nil, 0, fmt.Errorf("unclosed tag at offset %d", pos)
+		}
+		tag := strings.TrimSpace(source[pos+2 : pos+2+end])
+		pos += 2 + end + 2
+		if tag == "" {
+			return nil, 0, fmt.Errorf("empty tag at offset %d", pos)
+		}
+
+		name := strings.TrimSpace(tag[1:])
+		switch tag[0] {
+		case '!':
+		case '&':
+			nodes = append(nodes, mustacheNode{kind: 'u', value: name})
+		case '#', '^':
+			children, next, err := parseMustache(source, pos, name)
+			if err != nil {
+				return nil, 0, err
+			}
+			nodes = append(nodes, mustacheNode{kind: tag[0], value: name, children: children})
+			pos = next
+		case '/':
+			if name != closing {
+				return nil, 0, fmt.Errorf("unexpected closing tag %q", name)
+			}
+			return nodes, pos, nil
+		default:
+			nodes = append(nodes, mustacheNode{kind: 'v', value: tag})
+		}
+	}
+}
+
+func renderMustacheNodes(buf *strings.Builder, nodes []mustacheNode, stack []interface{}) error {
+	for _, node := range nodes {
+		switch node.kind {
+		case 't':
+			buf.WriteString(node.value)
+		case 'v', 'u':
+			value, found := lookupMustache(stack, node.value)
+			if !found {
+				return fmt.Errorf("missing template variable %q", node.value)
+			}
+			text := formatMustacheValue(value)
+			if node.kind == 'v' {
+				text = html.EscapeString(text)
+			}
+			buf.WriteString(text)
+		case '#':
+			value, _ := lookupMustache(stack, node.value)
+			if !mustacheTruthy(value) {
+				continue
+			}
+			items, isList := value.([]interface{})
+			if !isList {
+				items = []interface{}{value}
+			}
+			for _, item := range items {
+				if err := renderMustacheNodes(buf, node.children, append(stack, item)); err != nil {
+					return err
+				}
+			}
+		case '^':
+			if value, _ := lookupMustache(stack, node.value); !mustacheTruthy(value) {
+				if err := renderMustacheNodes(buf, node.children, stack); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lookupMustache resolves a possibly dotted name against the context stack, innermost first.
+func lookupMustache(stack []interface{}, name string) (interface{}, bool) {
+	if name == "." {
+		return stack[len(stack)-1], true
+	}
+	parts := strings.Split(name, ".")
+	for i := len(stack) - 1; i >= 0; i-- {
+		scope, ok := stack[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, ok := scope[parts[0]]
+		if !ok {
+			continue
+		}
+		for _, part := range parts[1:] {
+			nested, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = nested[part]; !ok {
+				return nil, false
+			}
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+func mustacheTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+func formatMustacheValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return fmt.Sprint(value)
+}
+
+// archiveFormat returns the requested archive format, falling back to the extension of path.
+func archiveFormat(params map[string]interface{}, path string) (string, error) {
+	format, _ := params["format"].(string)
+	if format == "" {
+		lower := strings.ToLower(path)
+		switch {
+		case strings.HasSuffix(lower, ".zip"):
+			format = "zip"
+		case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+			format = "tar.gz"
+		default:
+			return "", fmt.Errorf("cannot detect archive format from %s, set format explicitly", path)
+		}
+	}
+	switch strings.ToLower(format) {
+	case "zip":
+		return "zip", nil
+	case "tar.gz", "tgz":
+		return "tar.gz", nil
+	}
+	return "", fmt.Errorf("unsupported archive format: %s", format)
+}
+
+// walkArchiveSource visits source and everything below it, passing each entry's
+// slash-separated archive name rooted at the base name of source. The file skip
+// (the archive being written) is left out.
+func walkArchiveSource(source string, skip os.FileInfo, fn func(path, name string, info os.FileInfo) error) error {
+	base := filepath.Dir(filepath.Clean(source))
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if skip != nil && os.SameFile(info, skip) {
+			return nil
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		return fn(path, filepath.ToSlash(rel), info)
+	})
+}
+
+func writeZip(out io.Writer, source string, skip os.FileInfo) (int, error) {
+	zw := zip.NewWriter(out)
+	count := 0
+	err := walkArchiveSource(source, skip, func(path, name string, info os.FileInfo) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+			_, err = zw.CreateHeader(header)
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		header.Method = zip.Deflate
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := copyInto(w, path); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, zw.Close()
+}
+
+func writeTarGz(out io.Writer, source string, skip os.FileInfo) (int, error) {
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	count := 0
+	err := walkArchiveSource(source, skip, func(path, name string, info os.FileInfo) error {
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if err := copyInto(tw, path); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	return count, gw.Close()
+}
+
+func copyInto(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// extractTarget joins an archive entry name onto destination, rejecting names
+// that would escape it (zip slip).
+func extractTarget(destination, name string) (string, error) {
+	target := filepath.Join(destination, filepath.FromSlash(name))
+	rel, err := filepath.Rel(destination, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(name) {
+		return "", fmt.Errorf("archive entry %q escapes destination", name)
+	}
+	return target, nil
+}
+
+func writeExtracted(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func extractZip(source, destination string) ([]string, error) {
+	zr, err := zip.OpenReader(source)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	extracted := []string{}
+	for _, entry := range zr.File {
+		target, err := extractTarget(destination, entry.Name)
+		if err != nil {
+			return extracted, err
+		}
+		if entry.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return extracted, err
+			}
+			continue
+		}
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return extracted, err
+		}
+		err = writeExtracted(target, rc, entry.Mode())
+		rc.Close()
+		if err != nil {
+			return extracted, err
+		}
+		extracted = append(extracted, target)
+	}
+	return extracted, nil
+}
+
+func extractTarGz(source, destination string) ([]string, error) {
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	extracted := []string{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return extracted, nil
+		}
+		if err != nil {
+			return extracted, err
+		}
+		target, err := extractTarget(destination, header.Name)
+		if err != nil {
+			return extracted, err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return extracted, err
+			}
+		case tar.TypeReg:
+			if err := writeExtracted(target, tr, header.FileInfo().Mode()); err != nil {
+				return extracted, err
+			}
+			extracted = append(extracted, target)
+		}
+		// Links and special files are skipped so an archive cannot point outside destination
+	}
+}
+
+// matchName reports whether name matches a glob pattern already validated by filepath.Match.
+func matchName(pattern, name string) bool {
+	matched, _ := filepath.Match(pattern, name)
+	return matched
+}
+
+// protectedPaths are system directories that delete always refuses to remove.
+var protectedPaths = []string{
+	"/", "/bin", "/boot", "/dev", "/etc", "/home", "/lib", "/lib64", "/opt", "/proc",
+	"/root", "/sbin", "/sys", "/tmp", "/usr", "/var", "/Applications", "/Library", "/System", "/Users",
+}
+
+// resolvePath makes path absolute and resolves symlinks in its parent directories.
+// The final element is left alone so a symlink itself, not its target, is affected.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return abs, nil
+		}
+		return "", err
+	}
+	return filepath.Join(parent, filepath.Base(abs)), nil
+}
+
+// checkDeletable rejects system directories and, when safe_paths is given, anything outside them.
+func checkDeletable(resolved string, safePaths interface{}) error {
+	for _, protected := range protectedPaths {
+		if resolved == protected {
+			return fmt.Errorf("refusing to delete system path %s", resolved)
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil && resolved == filepath.Clean(home) {
+		return fmt.Errorf("refusing to delete home directory %s", resolved)
+	}
+
+	allowed, ok := safePaths.([]interface{})
+	if !ok || len(allowed) == 0 {
+		return nil
+	}
+	for _, item := range allowed {
+		safe, ok := item.(string)
+		if !ok || safe == "" {
+			continue
+		}
+		safeResolved, err := filepath.Abs(safe)
+		if err != nil {
+			continue
+		}
+		if evaluated, err := filepath.EvalSymlinks(safeResolved); err == nil {
+			safeResolved = evaluated
+		}
+		// Only paths strictly inside a safe directory qualify, never the directory itself
+		if rel, err := filepath.Rel(safeResolved, resolved); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return nil
+		}
+	}
+	return fmt.Errorf("refusing to delete %s: not inside any of safe_paths", resolved)
+}
+
+// walkTarget applies fn to path, and to everything beneath it when recursive is set.
+func walkTarget(path string, recursive bool, fn func(string) error) error {
+	if !recursive {
+		if _, err := os.Lstat(path); err != nil {
+			return err
+		}
+		return fn(path)
+	}
+	return filepath.Walk(path, func(target string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return fn(target)
+	})
+}
+
+func lookupUID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown user %s: %v", name, err)
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupGID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown group %s: %v", name, err)
+	}
+	return strconv.Atoi(g.Gid)
 }
 
 func copyFile(src, dst string) error {
@@ -0,0 +1,578 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type GCPPlugin struct{}
+
+func NewGCPPlugin() *GCPPlugin {
+	return &GCPPlugin{}
+}
+
+func (p *GCPPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "gcp",
+		Version:     "1.0.0",
+		Description: "Google Cloud Compute Engine, Cloud Storage, GKE and Cloud Run via the gcloud CLI",
+		Author:      "Corynth Team",
+		Tags:        []string{"gcp", "google-cloud", "cloud", "compute", "gcs", "gke", "cloud-run"},
+	}
+}
+
+func (p *GCPPlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"compute_list": {
+			Description: "List Compute Engine instances",
+			Inputs: withProject(map[string]IOSpec{
+				"zone":   {Type: "string", Required: false, Description: "Only list instances in this zone"},
+				"filter": {Type: "string", Required: false, Description: "gcloud filter expression, e.g. status=RUNNING"},
+			}),
+			Outputs: map[string]IOSpec{
+				"instances": {Type: "array", Description: "Instances as {name, zone, status, machine_type, internal_ip, external_ip, id}"},
+			},
+		},
+		"compute_start": computePowerActionSpec("Start a Compute Engine instance"),
+		"compute_stop":  computePowerActionSpec("Stop a Compute Engine instance"),
+		"gcs_upload": {
+			Description: "Upload a file to a Cloud Storage bucket",
+			Inputs: withProject(map[string]IOSpec{
+				"bucket":      {Type: "string", Required: true, Description: "Bucket name"},
+				"local_path":  {Type: "string", Required: true, Description: "Local file to upload"},
+				"object_path": {Type: "string", Required: false, Description: "Object name (defaults to the file name)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success":    {Type: "boolean", Description: "Upload success"},
+				"uri":        {Type: "string", Description: "gs:// URI of the object"},
+				"size_bytes": {Type: "number", Description: "Bytes uploaded"},
+			},
+		},
+		"gcs_download": {
+			Description: "Download an object from a Cloud Storage bucket",
+			Inputs: withProject(map[string]IOSpec{
+				"bucket":      {Type: "string", Required: true, Description: "Bucket name"},
+				"object_path": {Type: "string", Required: true, Description: "Object name"},
+				"local_path":  {Type: "string", Required: true, Description: "Destination file; parent directories are created"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success":    {Type: "boolean", Description: "Download success"},
+				"local_path": {Type: "string", Description: "Absolute path of the downloaded file"},
+				"size_bytes": {Type: "number", Description: "Bytes downloaded"},
+			},
+		},
+		"gcs_list": {
+			Description: "List objects in a Cloud Storage bucket",
+			Inputs: withProject(map[string]IOSpec{
+				"bucket": {Type: "string", Required: true, Description: "Bucket name"},
+				"prefix": {Type: "string", Required: false, Description: "Only list objects whose names start with this prefix"},
+			}),
+			Outputs: map[string]IOSpec{
+				"objects": {Type: "array", Description: "Objects as {name, uri, size, content_type, updated}"},
+				"count":   {Type: "number", Description: "Number of objects"},
+			},
+		},
+		"gke_get_credentials": {
+			Description: "Fetch a GKE cluster's credentials into a kubeconfig",
+			Inputs: withProject(map[string]IOSpec{
+				"cluster_name": {Type: "string", Required: true, Description: "GKE cluster name"},
+				"zone":         {Type: "string", Required: false, Description: "Zone of a zonal cluster (set zone or region)"},
+				"region":       {Type: "string", Required: false, Description: "Region of a regional cluster (set zone or region)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether credentials were fetched"},
+				"context": {Type: "string", Description: "kubectl context now current"},
+			},
+		},
+		"run_deploy": {
+			Description: "Deploy a container image to a Cloud Run service",
+			Inputs: withProject(map[string]IOSpec{
+				"service_name":  {Type: "string", Required: true, Description: "Cloud Run service name"},
+				"image":         {Type: "string", Required: true, Description: "Container image, e.g. us-docker.pkg.dev/project/repo/app:tag"},
+				"region":        {Type: "string", Required: true, Description: "Region, e.g. us-central1"},
+				"env_vars":      {Type: "object", Required: false, Description: "Environment variables as key/value pairs (replaces existing ones)"},
+				"min_instances": {Type: "number", Required: false, Description: "Minimum number of instances"},
+				"max_instances": {Type: "number", Required: false, Description: "Maximum number of instances"},
+			}),
+			Outputs: map[string]IOSpec{
+				"url":      {Type: "string", Description: "Service URL"},
+				"revision": {Type: "string", Description: "Latest ready revision"},
+			},
+		},
+	}
+}
+
+// withProject adds the project input shared by every action
+func withProject(inputs map[string]IOSpec) map[string]IOSpec {
+	inputs["project"] = IOSpec{
+		Type:        "string",
+		Required:    false,
+		Description: "Project ID (defaults to GOOGLE_CLOUD_PROJECT, then the gcloud default)",
+	}
+	return inputs
+}
+
+func computePowerActionSpec(description string) ActionSpec {
+	return ActionSpec{
+		Description: description,
+		Inputs: withProject(map[string]IOSpec{
+			"zone":          {Type: "string", Required: true, Description: "Zone of the instance"},
+			"instance_name": {Type: "string", Required: true, Description: "Instance name"},
+		}),
+		Outputs: map[string]IOSpec{
+			"success": {Type: "boolean", Description: "Operation success"},
+			"status":  {Type: "string", Description: "Instance status afterwards, e.g. RUNNING or TERMINATED"},
+		},
+	}
+}
+
+func (p *GCPPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(map[string]interface{}) (map[string]interface{}, error)
+	switch action {
+	case "compute_list":
+		run = p.computeList
+	case "compute_start", "compute_stop":
+		command := strings.TrimPrefix(action, "compute_")
+		run = func(params map[string]interface{}) (map[string]interface{}, error) {
+			return p.computePower(params, command)
+		}
+	case "gcs_upload":
+		run = p.gcsUpload
+	case "gcs_download":
+		run = p.gcsDownload
+	case "gcs_list":
+		run = p.gcsList
+	case "gke_get_credentials":
+		run = p.gkeGetCredentials
+	case "run_deploy":
+		run = p.runDeploy
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	result, err := run(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+// runGcloud runs a gcloud command for the selected project and returns its JSON output
+func runGcloud(params map[string]interface{}, args ...string) ([]byte, error) {
+	if project := getStringParam(params, "project", os.Getenv("GOOGLE_CLOUD_PROJECT")); project != "" {
+		args = append(args, "--project", project)
+	}
+	args = append(args, "--quiet", "--format", "json")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("gcloud", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// gcloud ignores GOOGLE_APPLICATION_CREDENTIALS, so point its credential override at the same key file
+	if credentials := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); credentials != "" && os.Getenv("CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE") == "" {
+		cmd.Env = append(os.Environ(), "CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE="+credentials)
+	}
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("gcloud %s failed: %s", strings.Join(args[:2], " "), cleanGcloudError(message))
+		}
+		return nil, fmt.Errorf("gcloud %s failed: %v", strings.Join(args[:2], " "), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func runGcloudJSON(params map[string]interface{}, result interface{}, args ...string) error {
+	output, err := runGcloud(params, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(output, result); err != nil {
+		return fmt.Errorf("failed to parse gcloud output: %v", err)
+	}
+	return nil
+}
+
+// cleanGcloudError keeps the ERROR line of gcloud's stderr without its prefix and command name
+func cleanGcloudError(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "ERROR: ") {
+			message = strings.TrimPrefix(line, "ERROR: ")
+			break
+		}
+	}
+	if strings.HasPrefix(message, "(gcloud.") {
+		if i := strings.Index(message, ") "); i >= 0 {
+			message = message[i+2:]
+		}
+	}
+	return message
+}
+
+func requireStrings(params map[string]interface{}, keys ...string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = getStringParam(params, key, "")
+		if values[i] == "" {
+			return nil, fmt.Errorf("%s is required", key)
+		}
+	}
+	return values, nil
+}
+
+func (p *GCPPlugin) computeList(params map[string]interface{}) (map[string]interface{}, error) {
+	args := []string{"compute", "instances", "list"}
+	if zone := getStringParam(params, "zone", ""); zone != "" {
+		args = append(args, "--zones", zone)
+	}
+	if filter := getStringParam(params, "filter", ""); filter != "" {
+		args = append(args, "--filter", filter)
+	}
+
+	var instances []map[string]interface{}
+	if err := runGcloudJSON(params, &instances, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(instances))
+	for _, instance := range instances {
+		var internalIP, externalIP interface{}
+		if nics, ok := instance["networkInterfaces"].([]interface{}); ok && len(nics) > 0 {
+			nic, _ := nics[0].(map[string]interface{})
+			internalIP = nic["networkIP"]
+			if configs, ok := nic["accessConfigs"].([]interface{}); ok && len(configs) > 0 {
+				config, _ := configs[0].(map[string]interface{})
+				externalIP = config["natIP"]
+			}
+		}
+		result = append(result, map[string]interface{}{
+			"name":         instance["name"],
+			"zone":         lastSegment(instance["zone"]),
+			"status":       instance["status"],
+			"machine_type": lastSegment(instance["machineType"]),
+			"internal_ip":  internalIP,
+			"external_ip":  externalIP,
+			"id":           instance["id"],
+		})
+	}
+
+	return map[string]interface{}{"instances": result}, nil
+}
+
+func (p *GCPPlugin) computePower(params map[string]interface{}, command string) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "zone", "instance_name")
+	if err != nil {
+		return nil, err
+	}
+	zone, name := values[0], values[1]
+
+	if _, err := runGcloud(params, "compute", "instances", command, name, "--zone", zone); err != nil {
+		return nil, err
+	}
+
+	var instance map[string]interface{}
+	if err := runGcloudJSON(params, &instance, "compute", "instances", "describe", name, "--zone", zone); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"status":  instance["status"],
+	}, nil
+}
+
+func (p *GCPPlugin) gcsUpload(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "bucket", "local_path")
+	if err != nil {
+		return nil, err
+	}
+	bucket, localPath := values[0], values[1]
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read local_path: %v", err)
+	}
+	if info.IsDir() {
+		return nil, errors.New("local_path must be a file, not a directory")
+	}
+
+	uri := gcsURI(bucket, getStringParam(params, "object_path", filepath.Base(localPath)))
+	if _, err := runGcloud(params, "storage", "cp", localPath, uri); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"success":    true,
+		"uri":        uri,
+		"size_bytes": info.Size(),
+	}, nil
+}
+
+func (p *GCPPlugin) gcsDownload(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "bucket", "object_path", "local_path")
+	if err != nil {
+		return nil, err
+	}
+
+	localPath, err := filepath.Abs(values[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid local_path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	if _, err := runGcloud(params, "storage", "cp", gcsURI(values[0], values[1]), localPath); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("downloaded file not found: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success":    true,
+		"local_path": localPath,
+		"size_bytes": info.Size(),
+	}, nil
+}
+
+func (p *GCPPlugin) gcsList(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "bucket")
+	if err != nil {
+		return nil, err
+	}
+
+	// ** matches across "/" so objects under the prefix are listed at any depth
+	var objects []map[string]interface{}
+	if err := runGcloudJSON(params, &objects, "storage", "objects", "list", gcsURI(values[0], getStringParam(params, "prefix", "")+"**")); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(objects))
+	for _, object := range objects {
+		name, _ := object["name"].(string)
+		result = append(result, map[string]interface{}{
+			"name":         name,
+			"uri":          gcsURI(values[0], name),
+			"size":         object["size"],
+			"content_type": firstOf(object, "content_type", "contentType"),
+			"updated":      firstOf(object, "update_time", "updated"),
+		})
+	}
+
+	return map[string]interface{}{
+		"objects": result,
+		"count":   len(result),
+	}, nil
+}
+
+func (p *GCPPlugin) gkeGetCredentials(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "cluster_name")
+	if err != nil {
+		return nil, err
+	}
+
+	zone := getStringParam(params, "zone", "")
+	region := getStringParam(params, "region", "")
+	if (zone == "") == (region == "") {
+		return nil, errors.New("set exactly one of zone or region")
+	}
+
+	args := []string{"container", "clusters", "get-credentials", values[0]}
+	location := zone
+	if zone != "" {
+		args = append(args, "--zone", zone)
+	} else {
+		args = append(args, "--region", region)
+		location = region
+	}
+	if _, err := runGcloud(params, args...); err != nil {
+		return nil, err
+	}
+
+	project := getStringParam(params, "project", os.Getenv("GOOGLE_CLOUD_PROJECT"))
+	if project == "" {
+		if err := runGcloudJSON(params, &project, "config", "get-value", "project"); err != nil {
+			return nil, err
+		}
+	}
+
+	// gcloud names the context gke_<project>_<location>_<cluster>
+	return map[string]interface{}{
+		"success": true,
+		"context": fmt.Sprintf("gke_%s_%s_%s", project, location, values[0]),
+	}, nil
+}
+
+func (p *GCPPlugin) runDeploy(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := requireStrings(params, "service_name", "image", "region")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"run", "deploy", values[0], "--image", values[1], "--region", values[2]}
+	if envVars, ok := params["env_vars"].(map[string]interface{}); ok && len(envVars) > 0 {
+		args = append(args, "--set-env-vars", formatEnvVars(envVars))
+	}
+	if minInstances := getIntParam(params, "min_instances", -1); minInstances >= 0 {
+		args = append(args, "--min-instances", fmt.Sprint(minInstances))
+	}
+	if maxInstances := getIntParam(params, "max_instances", -1); maxInstances >= 0 {
+		args = append(args, "--max-instances", fmt.Sprint(maxInstances))
+	}
+
+	var service map[string]interface{}
+	if err := runGcloudJSON(params, &service, args...); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"url":      nested(service, "status", "url"),
+		"revision": nested(service, "status", "latestReadyRevisionName"),
+	}, nil
+}
+
+// formatEnvVars builds a --set-env-vars value, switching gcloud's list delimiter
+// to "@" with the ^@^ escape when a value itself contains a comma
+func formatEnvVars(envVars map[string]interface{}) string {
+	keys := make([]string, 0, len(envVars))
+	for key := range envVars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	delimiter := ","
+	for _, key := range keys {
+		pair := fmt.Sprintf("%s=%v", key, envVars[key])
+		if strings.Contains(pair, ",") {
+			delimiter = "@"
+		}
+		pairs = append(pairs, pair)
+	}
+	if delimiter == "@" {
+		return "^@^" + strings.Join(pairs, "@")
+	}
+	return strings.Join(pairs, ",")
+}
+
+func gcsURI(bucket, object string) string {
+	return "gs://" + strings.TrimPrefix(bucket, "gs://") + "/" + strings.TrimPrefix(object, "/")
+}
+
+// lastSegment trims a resource URL such as .../zones/us-central1-a down to its final part
+func lastSegment(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return path.Base(s)
+	}
+	return value
+}
+
+// firstOf returns the first of keys present in obj, for fields gcloud versions name differently
+func firstOf(obj map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if value, ok := obj[key]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// nested walks a path of object keys, returning nil when any level is missing
+func nested(obj map[string]interface{}, keys ...string) interface{} {
+	var current interface{} = obj
+	for _, key := range keys {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewGCPPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
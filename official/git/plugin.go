@@ -0,0 +1,689 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type GitPlugin struct{}
+
+func NewGitPlugin() *GitPlugin {
+	return &GitPlugin{}
+}
+
+func (p *GitPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "git",
+		Version:     "1.0.0",
+		Description: "Git repository operations",
+		Author:      "Corynth Team",
+		Tags:        []string{"git", "vcs", "source-control", "ci-cd"},
+	}
+}
+
+func (p *GitPlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"clone": {
+			Description: "Clone a repository",
+			Inputs: map[string]IOSpec{
+				"url":    {Type: "string", Required: true, Description: "Repository URL"},
+				"dest":   {Type: "string", Required: false, Description: "Destination directory (defaults to the repository name)"},
+				"branch": {Type: "string", Required: false, Description: "Branch or tag to check out"},
+				"depth":  {Type: "number", Required: false, Description: "Create a shallow clone with this many commits"},
+				"token":  {Type: "string", Required: false, Description: "Access token for HTTPS authentication"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Operation success"},
+				"path":    {Type: "string", Description: "Absolute path of the clone"},
+				"commit":  {Type: "string", Description: "Checked out commit"},
+				"branch":  {Type: "string", Description: "Checked out branch"},
+			},
+		},
+		"pull": {
+			Description: "Pull changes from a remote",
+			Inputs: map[string]IOSpec{
+				"repo_path": {Type: "string", Required: true, Description: "Repository path"},
+				"remote":    {Type: "string", Required: false, Default: "origin", Description: "Remote name"},
+				"branch":    {Type: "string", Required: false, Description: "Remote branch (defaults to the upstream)"},
+				"token":     {Type: "string", Required: false, Description: "Access token for HTTPS authentication"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Operation success"},
+				"updated": {Type: "boolean", Description: "Whether HEAD moved"},
+				"commit":  {Type: "string", Description: "HEAD after the pull"},
+				"output":  {Type: "string", Description: "Command output"},
+			},
+		},
+		"commit": {
+			Description: "Stage files and create a commit",
+			Inputs: map[string]IOSpec{
+				"repo_path":    {Type: "string", Required: true, Description: "Repository path"},
+				"message":      {Type: "string", Required: true, Description: "Commit message"},
+				"files":        {Type: "array", Required: false, Description: "Files to stage before committing"},
+				"all":          {Type: "boolean", Required: false, Default: false, Description: "Stage all changes, including untracked files"},
+				"author_name":  {Type: "string", Required: false, Description: "Author name (defaults to git config)"},
+				"author_email": {Type: "string", Required: false, Description: "Author email (defaults to git config)"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":       {Type: "boolean", Description: "Operation success"},
+				"commit":        {Type: "string", Description: "New commit hash"},
+				"files_changed": {Type: "array", Description: "Files included in the commit"},
+			},
+		},
+		"push": {
+			Description: "Push a branch to a remote",
+			Inputs: map[string]IOSpec{
+				"repo_path": {Type: "string", Required: true, Description: "Repository path"},
+				"remote":    {Type: "string", Required: false, Default: "origin", Description: "Remote name"},
+				"branch":    {Type: "string", Required: false, Description: "Branch to push (defaults to the current branch)"},
+				"force":     {Type: "boolean", Required: false, Default: false, Description: "Force push"},
+				"token":     {Type: "string", Required: false, Description: "Access token for HTTPS authentication"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Operation success"},
+				"branch":  {Type: "string", Description: "Branch pushed"},
+				"output":  {Type: "string", Description: "Command output"},
+			},
+		},
+		"create_branch": {
+			Description: "Create a branch",
+			Inputs: map[string]IOSpec{
+				"repo_path": {Type: "string", Required: true, Description: "Repository path"},
+				"name":      {Type: "string", Required: true, Description: "Branch name"},
+				"from":      {Type: "string", Required: false, Description: "Start point (defaults to HEAD)"},
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Operation success"},
+				"branch":  {Type: "string", Description: "Branch name"},
+				"commit":  {Type: "string", Description: "Commit the branch points to"},
+			},
+		},
+		"checkout": {
+			Description: "Check out a branch, tag or commit",
+			Inputs: map[string]IOSpec{
+				"repo_path": {Type: "string", Required: true, Description: "Repository path"},
+				"ref":       {Type: "string", Required: true, Description: "Branch, tag or commit"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":  {Type: "boolean", Description: "Operation success"},
+				"commit":   {Type: "string", Description: "Checked out commit"},
+				"branch":   {Type: "string", Description: "Current branch (empty when detached)"},
+				"detached": {Type: "boolean", Description: "Whether HEAD is detached"},
+			},
+		},
+		"log": {
+			Description: "List commits",
+			Inputs: map[string]IOSpec{
+				"repo_path": {Type: "string", Required: true, Description: "Repository path"},
+				"limit":     {Type: "number", Required: false, Default: 10, Description: "Maximum number of commits"},
+				"ref":       {Type: "string", Required: false, Description: "Revision or range to list (defaults to HEAD)"},
+				"format":    {Type: "string", Required: false, Description: "Optional git pretty format; formatted lines are returned in output"},
+			},
+			Outputs: map[string]IOSpec{
+				"commits": {Type: "array", Description: "Commits with hash, short_hash, author, email, date and subject"},
+				"output":  {Type: "array", Description: "Lines rendered with format (only when format is set)"},
+			},
+		},
+		"status": {
+			Description: "Show working tree status",
+			Inputs: map[string]IOSpec{
+				"repo_path": {Type: "string", Required: true, Description: "Repository path"},
+			},
+			Outputs: map[string]IOSpec{
+				"branch":    {Type: "string", Description: "Current branch"},
+				"upstream":  {Type: "string", Description: "Upstream branch"},
+				"ahead":     {Type: "number", Description: "Commits ahead of upstream"},
+				"behind":    {Type: "number", Description: "Commits behind upstream"},
+				"clean":     {Type: "boolean", Description: "Whether there are no changes"},
+				"files":     {Type: "array", Description: "Changed files with path, index and worktree status"},
+				"staged":    {Type: "number", Description: "Number of staged files"},
+				"modified":  {Type: "number", Description: "Number of files modified in the worktree"},
+				"untracked": {Type: "number", Description: "Number of untracked files"},
+			},
+		},
+	}
+}
+
+func (p *GitPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	switch action {
+	case "clone":
+		return p.clone(params)
+	case "pull":
+		return p.pull(params)
+	case "commit":
+		return p.commit(params)
+	case "push":
+		return p.push(params)
+	case "create_branch":
+		return p.createBranch(params)
+	case "checkout":
+		return p.checkout(params)
+	case "log":
+		return p.log(params)
+	case "status":
+		return p.status(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *GitPlugin) clone(params map[string]interface{}) (map[string]interface{}, error) {
+	url := getStringParam(params, "url", "")
+	if url == "" {
+		return map[string]interface{}{"error": "url is required"}, nil
+	}
+
+	dest := getStringParam(params, "dest", "")
+	if dest == "" {
+		dest = strings.TrimSuffix(filepath.Base(strings.TrimRight(url, "/")), ".git")
+	}
+
+	args := []string{"clone"}
+	if branch := getStringParam(params, "branch", ""); branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	if depth := getIntParam(params, "depth", 0); depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	args = append(args, "--", url, dest)
+
+	if _, err := runGit("", authEnv(params), args...); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	path, err := filepath.Abs(dest)
+	if err != nil {
+		path = dest
+	}
+	commit, _ := runGit(path, nil, "rev-parse", "HEAD")
+	branch, _ := runGit(path, nil, "branch", "--show-current")
+
+	return map[string]interface{}{
+		"success": true,
+		"path":    path,
+		"commit":  commit,
+		"branch":  branch,
+	}, nil
+}
+
+func (p *GitPlugin) pull(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// A leading - would be parsed as an option such as --upload-pack, which runs a command
+	remote := getStringParam(params, "remote", "origin")
+	if strings.HasPrefix(remote, "-") {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid remote: %s", remote)}, nil
+	}
+	branch := getStringParam(params, "branch", "")
+	if strings.HasPrefix(branch, "-") {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid branch: %s", branch)}, nil
+	}
+
+	before, _ := runGit(repoPath, nil, "rev-parse", "HEAD")
+
+	args := []string{"pull", remote}
+	if branch != "" {
+		args = append(args, branch)
+	}
+
+	output, err := runGit(repoPath, authEnv(params), args...)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	after, _ := runGit(repoPath, nil, "rev-parse", "HEAD")
+	return map[string]interface{}{
+		"success": true,
+		"updated": before != after,
+		"commit":  after,
+		"output":  output,
+	}, nil
+}
+
+func (p *GitPlugin) commit(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	message := getStringParam(params, "message", "")
+	if message == "" {
+		return map[string]interface{}{"error": "message is required"}, nil
+	}
+
+	if getBoolParam(params, "all", false) {
+		if _, err := runGit(repoPath, nil, "add", "--all"); err != nil {
+			return map[string]interface{}{"success": false, "error": err.Error()}, nil
+		}
+	} else if files := getStringSlice(params, "files"); len(files) > 0 {
+		if _, err := runGit(repoPath, nil, append([]string{"add", "--"}, files...)...); err != nil {
+			return map[string]interface{}{"success": false, "error": err.Error()}, nil
+		}
+	}
+
+	staged, err := runGit(repoPath, nil, "diff", "--cached", "--name-only")
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+	if staged == "" {
+		return map[string]interface{}{"success": false, "error": "nothing to commit"}, nil
+	}
+
+	var env []string
+	if name := getStringParam(params, "author_name", ""); name != "" {
+		env = append(env, "GIT_AUTHOR_NAME="+name, "GIT_COMMITTER_NAME="+name)
+	}
+	if email := getStringParam(params, "author_email", ""); email != "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+email, "GIT_COMMITTER_EMAIL="+email)
+	}
+
+	if _, err := runGit(repoPath, env, "commit", "-m", message); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	commit, _ := runGit(repoPath, nil, "rev-parse", "HEAD")
+	return map[string]interface{}{
+		"success":       true,
+		"commit":        commit,
+		"files_changed": strings.Split(staged, "\n"),
+	}, nil
+}
+
+func (p *GitPlugin) push(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	branch := getStringParam(params, "branch", "")
+	if branch == "" {
+		current, err := runGit(repoPath, nil, "branch", "--show-current")
+		if err != nil || current == "" {
+			return map[string]interface{}{"error": "branch is required when HEAD is detached"}, nil
+		}
+		branch = current
+	}
+	if strings.HasPrefix(branch, "-") {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid branch: %s", branch)}, nil
+	}
+	// A leading - would be parsed as an option such as --receive-pack, which runs a command
+	remote := getStringParam(params, "remote", "origin")
+	if strings.HasPrefix(remote, "-") {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid remote: %s", remote)}, nil
+	}
+
+	args := []string{"push"}
+	if getBoolParam(params, "force", false) {
+		args = append(args, "--force")
+	}
+	args = append(args, remote, branch)
+
+	output, err := runGit(repoPath, authEnv(params), args...)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"branch":  branch,
+		"output":  output,
+	}, nil
+}
+
+func (p *GitPlugin) createBranch(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return map[string]interface{}{"error": "name is required"}, nil
+	}
+
+	args := []string{"branch", "--", name}
+	if from := getStringParam(params, "from", ""); from != "" {
+		if strings.HasPrefix(from, "-") {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid ref: %s", from)}, nil
+		}
+		args = append(args, from)
+	}
+	if _, err := runGit(repoPath, nil, args...); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	commit, _ := runGit(repoPath, nil, "rev-parse", "refs/heads/"+name)
+	return map[string]interface{}{
+		"success": true,
+		"branch":  name,
+		"commit":  commit,
+	}, nil
+}
+
+func (p *GitPlugin) checkout(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	ref := getStringParam(params, "ref", "")
+	if ref == "" {
+		return map[string]interface{}{"error": "ref is required"}, nil
+	}
+	if strings.HasPrefix(ref, "-") {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid ref: %s", ref)}, nil
+	}
+
+	if _, err := runGit(repoPath, nil, "checkout", ref, "--"); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	commit, _ := runGit(repoPath, nil, "rev-parse", "HEAD")
+	branch, _ := runGit(repoPath, nil, "branch", "--show-current")
+	return map[string]interface{}{
+		"success":  true,
+		"commit":   commit,
+		"branch":   branch,
+		"detached": branch == "",
+	}, nil
+}
+
+// logFieldSeparator and logRecordSeparator delimit fields in the structured log
+// format; control characters cannot appear in commit metadata
+const (
+	logFieldSeparator  = "\x1f"
+	logRecordSeparator = "\x1e"
+)
+
+func (p *GitPlugin) log(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	limit := getIntParam(params, "limit", 10)
+	if limit <= 0 {
+		limit = 10
+	}
+	ref := getStringParam(params, "ref", "HEAD")
+	if strings.HasPrefix(ref, "-") {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid ref: %s", ref)}, nil
+	}
+
+	pretty := strings.Join([]string{"%H", "%h", "%an", "%ae", "%aI", "%s"}, logFieldSeparator) + logRecordSeparator
+	output, err := runGit(repoPath, nil, "log", "-n", strconv.Itoa(limit), "--pretty=format:"+pretty, ref, "--")
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	commits := []map[string]interface{}{}
+	for _, record := range strings.Split(output, logRecordSeparator) {
+		fields := strings.Split(strings.TrimPrefix(record, "\n"), logFieldSeparator)
+		if len(fields) != 6 {
+			continue
+		}
+		commits = append(commits, map[string]interface{}{
+			"hash":       fields[0],
+			"short_hash": fields[1],
+			"author":     fields[2],
+			"email":      fields[3],
+			"date":       fields[4],
+			"subject":    fields[5],
+		})
+	}
+
+	result := map[string]interface{}{
+		"commits": commits,
+	}
+
+	if format := getStringParam(params, "format", ""); format != "" {
+		formatted, err := runGit(repoPath, nil, "log", "-n", strconv.Itoa(limit), "--pretty=format:"+format, ref, "--")
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		lines := []string{}
+		if formatted != "" {
+			lines = strings.Split(formatted, "\n")
+		}
+		result["output"] = lines
+	}
+
+	return result, nil
+}
+
+func (p *GitPlugin) status(params map[string]interface{}) (map[string]interface{}, error) {
+	repoPath, errResult := requireRepo(params)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	output, err := runGit(repoPath, nil, "status", "--porcelain=v1", "--branch", "--untracked-files=all")
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	result := map[string]interface{}{
+		"branch":   "",
+		"upstream": "",
+		"ahead":    0,
+		"behind":   0,
+	}
+	files := []map[string]interface{}{}
+	staged, modified, untracked := 0, 0, 0
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			parseBranchHeader(strings.TrimPrefix(line, "## "), result)
+			continue
+		}
+		if len(line) < 4 {
+			continue
+		}
+
+		index, worktree, path := string(line[0]), string(line[1]), line[3:]
+		switch {
+		case index == "?":
+			untracked++
+		default:
+			if index != " " {
+				staged++
+			}
+			if worktree != " " {
+				modified++
+			}
+		}
+		files = append(files, map[string]interface{}{
+			"path":     path,
+			"index":    index,
+			"worktree": worktree,
+		})
+	}
+
+	result["files"] = files
+	result["clean"] = len(files) == 0
+	result["staged"] = staged
+	result["modified"] = modified
+	result["untracked"] = untracked
+	return result, nil
+}
+
+// parseBranchHeader reads the "## branch...upstream [ahead 1, behind 2]" status line
+func parseBranchHeader(header string, result map[string]interface{}) {
+	tracking := ""
+	if idx := strings.Index(header, " ["); idx >= 0 {
+		tracking = strings.TrimSuffix(header[idx+2:], "]")
+		header = header[:idx]
+	}
+
+	header = strings.TrimPrefix(header, "No commits yet on ")
+	if parts := strings.SplitN(header, "...", 2); len(parts) == 2 {
+		result["branch"] = parts[0]
+		result["upstream"] = parts[1]
+	} else {
+		result["branch"] = header
+	}
+
+	for _, part := range strings.Split(tracking, ", ") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			continue
+		}
+		if count, err := strconv.Atoi(fields[1]); err == nil && (fields[0] == "ahead" || fields[0] == "behind") {
+			result[fields[0]] = count
+		}
+	}
+}
+
+// runGit runs git in dir with extra environment variables and returns trimmed stdout.
+// Errors include git's stderr so callers see why the command failed.
+func runGit(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// Never block waiting for credentials on a terminal that isn't there
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], message)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// authEnv passes an HTTPS token as an extra Authorization header through git's
+// environment config, so it is neither visible in the process list nor stored in
+// the repository's remote URL or .git/config
+func authEnv(params map[string]interface{}) []string {
+	token := getStringParam(params, "token", "")
+	if token == "" {
+		return nil
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + credentials,
+	}
+}
+
+// requireRepo validates repo_path, returning an error result when it is missing or not a repository
+func requireRepo(params map[string]interface{}) (string, map[string]interface{}) {
+	repoPath := getStringParam(params, "repo_path", "")
+	if repoPath == "" {
+		return "", map[string]interface{}{"error": "repo_path is required"}
+	}
+	if _, err := runGit(repoPath, nil, "rev-parse", "--git-dir"); err != nil {
+		return "", map[string]interface{}{"error": fmt.Sprintf("%s is not a git repository", repoPath)}
+	}
+	return repoPath, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewGitPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -0,0 +1,594 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// defaultAPIURL is used unless GITHUB_API_URL points at a GitHub Enterprise server
+const defaultAPIURL = "https://api.github.com"
+
+type GitHubPlugin struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewGitHubPlugin() *GitHubPlugin {
+	baseURL := os.Getenv("GITHUB_API_URL")
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	return &GitHubPlugin{
+		token:   os.Getenv("GITHUB_TOKEN"),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (p *GitHubPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "github",
+		Version:     "1.0.0",
+		Description: "GitHub pull requests, issues, releases and workflows",
+		Author:      "Corynth Team",
+		Tags:        []string{"github", "git", "ci-cd", "releases", "source-control"},
+	}
+}
+
+func (p *GitHubPlugin) GetActions() map[string]ActionSpec {
+	repoInputs := func(extra map[string]IOSpec) map[string]IOSpec {
+		inputs := map[string]IOSpec{
+			"owner": {Type: "string", Required: true, Description: "Repository owner (user or organization)"},
+			"repo":  {Type: "string", Required: true, Description: "Repository name"},
+		}
+		for name, spec := range extra {
+			inputs[name] = spec
+		}
+		return inputs
+	}
+
+	return map[string]ActionSpec{
+		"create_pr": {
+			Description: "Create a pull request",
+			Inputs: repoInputs(map[string]IOSpec{
+				"title": {Type: "string", Required: true, Description: "Pull request title"},
+				"body":  {Type: "string", Required: false, Description: "Pull request description"},
+				"head":  {Type: "string", Required: true, Description: "Branch with the changes (or owner:branch for forks)"},
+				"base":  {Type: "string", Required: true, Description: "Branch to merge into"},
+				"draft": {Type: "boolean", Required: false, Default: false, Description: "Open as a draft"},
+			}),
+			Outputs: map[string]IOSpec{
+				"number": {Type: "number", Description: "Pull request number"},
+				"url":    {Type: "string", Description: "Pull request web URL"},
+				"state":  {Type: "string", Description: "Pull request state"},
+			},
+		},
+		"merge_pr": {
+			Description: "Merge a pull request",
+			Inputs: repoInputs(map[string]IOSpec{
+				"pr_number":      {Type: "number", Required: true, Description: "Pull request number"},
+				"method":         {Type: "string", Required: false, Default: "merge", Description: "Merge method: merge, squash, rebase"},
+				"commit_title":   {Type: "string", Required: false, Description: "Title for the merge commit"},
+				"commit_message": {Type: "string", Required: false, Description: "Message for the merge commit"},
+			}),
+			Outputs: map[string]IOSpec{
+				"merged":  {Type: "boolean", Description: "Whether the pull request was merged"},
+				"sha":     {Type: "string", Description: "Merge commit SHA"},
+				"message": {Type: "string", Description: "Message from GitHub"},
+			},
+		},
+		"create_issue": {
+			Description: "Create an issue",
+			Inputs: repoInputs(map[string]IOSpec{
+				"title":     {Type: "string", Required: true, Description: "Issue title"},
+				"body":      {Type: "string", Required: false, Description: "Issue description"},
+				"labels":    {Type: "array", Required: false, Description: "Label names"},
+				"assignees": {Type: "array", Required: false, Description: "Usernames to assign"},
+			}),
+			Outputs: map[string]IOSpec{
+				"number": {Type: "number", Description: "Issue number"},
+				"url":    {Type: "string", Description: "Issue web URL"},
+			},
+		},
+		"close_issue": {
+			Description: "Close an issue",
+			Inputs: repoInputs(map[string]IOSpec{
+				"number":       {Type: "number", Required: true, Description: "Issue number"},
+				"state_reason": {Type: "string", Required: false, Default: "completed", Description: "Reason: completed or not_planned"},
+				"comment":      {Type: "string", Required: false, Description: "Comment to add before closing"},
+			}),
+			Outputs: map[string]IOSpec{
+				"number": {Type: "number", Description: "Issue number"},
+				"state":  {Type: "string", Description: "Issue state"},
+				"url":    {Type: "string", Description: "Issue web URL"},
+			},
+		},
+		"create_release": {
+			Description: "Create a release",
+			Inputs: repoInputs(map[string]IOSpec{
+				"tag_name":         {Type: "string", Required: true, Description: "Tag for the release"},
+				"target_commitish": {Type: "string", Required: false, Description: "Branch or commit to tag when the tag does not exist"},
+				"name":             {Type: "string", Required: false, Description: "Release name"},
+				"body":             {Type: "string", Required: false, Description: "Release notes"},
+				"draft":            {Type: "boolean", Required: false, Default: false, Description: "Create as a draft"},
+				"prerelease":       {Type: "boolean", Required: false, Default: false, Description: "Mark as a prerelease"},
+			}),
+			Outputs: map[string]IOSpec{
+				"id":         {Type: "number", Description: "Release ID"},
+				"url":        {Type: "string", Description: "Release web URL"},
+				"upload_url": {Type: "string", Description: "Asset upload URL template"},
+			},
+		},
+		"upload_release_asset": {
+			Description: "Upload a file to a release",
+			Inputs: repoInputs(map[string]IOSpec{
+				"release_id":   {Type: "number", Required: true, Description: "Release ID"},
+				"file_path":    {Type: "string", Required: true, Description: "File to upload"},
+				"name":         {Type: "string", Required: false, Description: "Asset name (defaults to the file name)"},
+				"label":        {Type: "string", Required: false, Description: "Asset label shown instead of the name"},
+				"content_type": {Type: "string", Required: false, Default: "application/octet-stream", Description: "Asset MIME type"},
+			}),
+			Outputs: map[string]IOSpec{
+				"id":           {Type: "number", Description: "Asset ID"},
+				"name":         {Type: "string", Description: "Asset name"},
+				"size":         {Type: "number", Description: "Asset size in bytes"},
+				"download_url": {Type: "string", Description: "Browser download URL"},
+			},
+		},
+		"trigger_workflow": {
+			Description: "Trigger a workflow_dispatch event",
+			Inputs: repoInputs(map[string]IOSpec{
+				"workflow_id": {Type: "string", Required: true, Description: "Workflow file name (e.g. deploy.yml) or numeric ID"},
+				"ref":         {Type: "string", Required: true, Description: "Branch or tag to run the workflow on"},
+				"inputs":      {Type: "object", Required: false, Description: "Workflow inputs"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the dispatch was accepted"},
+			},
+		},
+	}
+}
+
+func (p *GitHubPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	if p.token == "" {
+		return map[string]interface{}{"error": "GITHUB_TOKEN not configured"}, nil
+	}
+
+	owner := getStringParam(params, "owner", "")
+	repo := getStringParam(params, "repo", "")
+	if owner == "" || repo == "" {
+		return map[string]interface{}{"error": "owner and repo are required"}, nil
+	}
+	repoPath := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+
+	switch action {
+	case "create_pr":
+		return p.createPR(repoPath, params)
+	case "merge_pr":
+		return p.mergePR(repoPath, params)
+	case "create_issue":
+		return p.createIssue(repoPath, params)
+	case "close_issue":
+		return p.closeIssue(repoPath, params)
+	case "create_release":
+		return p.createRelease(repoPath, params)
+	case "upload_release_asset":
+		return p.uploadReleaseAsset(repoPath, params)
+	case "trigger_workflow":
+		return p.triggerWorkflow(repoPath, params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *GitHubPlugin) createPR(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	title := getStringParam(params, "title", "")
+	head := getStringParam(params, "head", "")
+	base := getStringParam(params, "base", "")
+	if title == "" || head == "" || base == "" {
+		return map[string]interface{}{"error": "title, head and base are required"}, nil
+	}
+
+	pr, err := p.callAPI("POST", repoPath+"/pulls", map[string]interface{}{
+		"title": title,
+		"body":  getStringParam(params, "body", ""),
+		"head":  head,
+		"base":  base,
+		"draft": getBoolParam(params, "draft", false),
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"number": pr["number"],
+		"url":    pr["html_url"],
+		"state":  pr["state"],
+	}, nil
+}
+
+func (p *GitHubPlugin) mergePR(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	number := getIntParam(params, "pr_number", 0)
+	if number <= 0 {
+		return map[string]interface{}{"error": "pr_number is required"}, nil
+	}
+
+	method := getStringParam(params, "method", "merge")
+	if method != "merge" && method != "squash" && method != "rebase" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported merge method: %s", method)}, nil
+	}
+
+	body := map[string]interface{}{"merge_method": method}
+	if title := getStringParam(params, "commit_title", ""); title != "" {
+		body["commit_title"] = title
+	}
+	if message := getStringParam(params, "commit_message", ""); message != "" {
+		body["commit_message"] = message
+	}
+
+	result, err := p.callAPI("PUT", fmt.Sprintf("%s/pulls/%d/merge", repoPath, number), body)
+	if err != nil {
+		return map[string]interface{}{"merged": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"merged":  result["merged"],
+		"sha":     result["sha"],
+		"message": result["message"],
+	}, nil
+}
+
+func (p *GitHubPlugin) createIssue(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	title := getStringParam(params, "title", "")
+	if title == "" {
+		return map[string]interface{}{"error": "title is required"}, nil
+	}
+
+	body := map[string]interface{}{
+		"title": title,
+		"body":  getStringParam(params, "body", ""),
+	}
+	if labels := getStringSlice(params, "labels"); len(labels) > 0 {
+		body["labels"] = labels
+	}
+	if assignees := getStringSlice(params, "assignees"); len(assignees) > 0 {
+		body["assignees"] = assignees
+	}
+
+	issue, err := p.callAPI("POST", repoPath+"/issues", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"number": issue["number"],
+		"url":    issue["html_url"],
+	}, nil
+}
+
+func (p *GitHubPlugin) closeIssue(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	number := getIntParam(params, "number", 0)
+	if number <= 0 {
+		return map[string]interface{}{"error": "number is required"}, nil
+	}
+	issuePath := fmt.Sprintf("%s/issues/%d", repoPath, number)
+
+	if comment := getStringParam(params, "comment", ""); comment != "" {
+		if _, err := p.callAPI("POST", issuePath+"/comments", map[string]interface{}{"body": comment}); err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+	}
+
+	issue, err := p.callAPI("PATCH", issuePath, map[string]interface{}{
+		"state":        "closed",
+		"state_reason": getStringParam(params, "state_reason", "completed"),
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"number": issue["number"],
+		"state":  issue["state"],
+		"url":    issue["html_url"],
+	}, nil
+}
+
+func (p *GitHubPlugin) createRelease(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	tagName := getStringParam(params, "tag_name", "")
+	if tagName == "" {
+		return map[string]interface{}{"error": "tag_name is required"}, nil
+	}
+
+	body := map[string]interface{}{
+		"tag_name":   tagName,
+		"name":       getStringParam(params, "name", tagName),
+		"body":       getStringParam(params, "body", ""),
+		"draft":      getBoolParam(params, "draft", false),
+		"prerelease": getBoolParam(params, "prerelease", false),
+	}
+	if target := getStringParam(params, "target_commitish", ""); target != "" {
+		body["target_commitish"] = target
+	}
+
+	release, err := p.callAPI("POST", repoPath+"/releases", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"id":         release["id"],
+		"url":        release["html_url"],
+		"upload_url": release["upload_url"],
+	}, nil
+}
+
+func (p *GitHubPlugin) uploadReleaseAsset(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	releaseID := getIntParam(params, "release_id", 0)
+	if releaseID <= 0 {
+		return map[string]interface{}{"error": "release_id is required"}, nil
+	}
+
+	filePath := getStringParam(params, "file_path", "")
+	if filePath == "" {
+		return map[string]interface{}{"error": "file_path is required"}, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to open file: %v", err)}, nil
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to stat file: %v", err)}, nil
+	}
+
+	// Assets go to a separate upload host, so take it from the release rather than guessing
+	release, err := p.callAPI("GET", fmt.Sprintf("%s/releases/%d", repoPath, releaseID), nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	uploadURL, _ := release["upload_url"].(string)
+	if idx := strings.Index(uploadURL, "{"); idx >= 0 {
+		uploadURL = uploadURL[:idx]
+	}
+	if uploadURL == "" {
+		return map[string]interface{}{"error": "release has no upload_url"}, nil
+	}
+
+	query := url.Values{}
+	query.Set("name", getStringParam(params, "name", filepath.Base(filePath)))
+	if label := getStringParam(params, "label", ""); label != "" {
+		query.Set("label", label)
+	}
+
+	req, err := http.NewRequest("POST", uploadURL+"?"+query.Encode(), file)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", getStringParam(params, "content_type", "application/octet-stream"))
+
+	// Large assets can take much longer than API calls, so upload without the client timeout
+	uploadClient := &http.Client{}
+	asset, err := p.do(uploadClient, req)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"id":           asset["id"],
+		"name":         asset["name"],
+		"size":         asset["size"],
+		"download_url": asset["browser_download_url"],
+	}, nil
+}
+
+func (p *GitHubPlugin) triggerWorkflow(repoPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	workflowID := getStringParam(params, "workflow_id", "")
+	if workflowID == "" {
+		if id := getIntParam(params, "workflow_id", 0); id > 0 {
+			workflowID = fmt.Sprintf("%d", id)
+		}
+	}
+	ref := getStringParam(params, "ref", "")
+	if workflowID == "" || ref == "" {
+		return map[string]interface{}{"error": "workflow_id and ref are required"}, nil
+	}
+
+	body := map[string]interface{}{"ref": ref}
+	if inputs, ok := params["inputs"].(map[string]interface{}); ok && len(inputs) > 0 {
+		body["inputs"] = inputs
+	}
+
+	if _, err := p.callAPI("POST", repoPath+"/actions/workflows/"+url.PathEscape(workflowID)+"/dispatches", body); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+// callAPI sends a JSON request to the REST API and decodes the JSON object it returns
+func (p *GitHubPlugin) callAPI(method, path string, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return p.do(p.client, req)
+}
+
+// do adds authentication headers, sends the request and turns non-2xx responses into errors
+func (p *GitHubPlugin) do(client *http.Client, req *http.Request) (map[string]interface{}, error) {
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "corynth-github-plugin")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, apiError(resp.StatusCode, result, data)
+	}
+	return result, nil
+}
+
+// apiError formats a GitHub error response, including field-level validation errors
+func apiError(status int, result map[string]interface{}, raw []byte) error {
+	message, _ := result["message"].(string)
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+
+	var details []string
+	if errs, ok := result["errors"].([]interface{}); ok {
+		for _, item := range errs {
+			if detail, ok := item.(map[string]interface{}); ok {
+				if msg, ok := detail["message"].(string); ok && msg != "" {
+					details = append(details, msg)
+				} else {
+					details = append(details, fmt.Sprintf("%v %v", detail["field"], detail["code"]))
+				}
+			}
+		}
+	}
+	if len(details) > 0 {
+		message += " (" + strings.Join(details, "; ") + ")"
+	}
+	return fmt.Errorf("GitHub API error (%d): %s", status, message)
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewGitHubPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -0,0 +1,601 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// defaultGitLabURL is used unless GITLAB_URL points at a self-hosted instance
+const defaultGitLabURL = "https://gitlab.com"
+
+// finishedPipelineStatuses are the states in which a pipeline will not change any further
+var finishedPipelineStatuses = map[string]bool{
+	"success":  true,
+	"failed":   true,
+	"canceled": true,
+	"skipped":  true,
+	"manual":   true,
+}
+
+type GitLabPlugin struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewGitLabPlugin() *GitLabPlugin {
+	baseURL := os.Getenv("GITLAB_URL")
+	if baseURL == "" {
+		baseURL = defaultGitLabURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/api/v4") {
+		baseURL += "/api/v4"
+	}
+	return &GitLabPlugin{
+		token:   os.Getenv("GITLAB_TOKEN"),
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (p *GitLabPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "gitlab",
+		Version:     "1.0.0",
+		Description: "GitLab merge requests, issues and CI/CD pipelines",
+		Author:      "Corynth Team",
+		Tags:        []string{"gitlab", "git", "ci-cd", "pipelines", "source-control"},
+	}
+}
+
+func (p *GitLabPlugin) GetActions() map[string]ActionSpec {
+	projectInputs := func(extra map[string]IOSpec) map[string]IOSpec {
+		inputs := map[string]IOSpec{
+			"project_id": {Type: "string", Required: true, Description: "Project ID or full path (e.g. group/project)"},
+		}
+		for name, spec := range extra {
+			inputs[name] = spec
+		}
+		return inputs
+	}
+
+	pipelineOutputs := map[string]IOSpec{
+		"id":     {Type: "number", Description: "Pipeline ID"},
+		"status": {Type: "string", Description: "Pipeline status"},
+		"ref":    {Type: "string", Description: "Branch or tag the pipeline runs on"},
+		"sha":    {Type: "string", Description: "Commit SHA"},
+		"url":    {Type: "string", Description: "Pipeline web URL"},
+	}
+
+	return map[string]ActionSpec{
+		"create_mr": {
+			Description: "Create a merge request",
+			Inputs: projectInputs(map[string]IOSpec{
+				"source_branch": {Type: "string", Required: true, Description: "Branch with the changes"},
+				"target_branch": {Type: "string", Required: true, Description: "Branch to merge into"},
+				"title":         {Type: "string", Required: true, Description: "Merge request title"},
+				"description":   {Type: "string", Required: false, Description: "Merge request description"},
+				"assignee_ids":  {Type: "array", Required: false, Description: "User IDs to assign"},
+			}),
+			Outputs: map[string]IOSpec{
+				"iid":   {Type: "number", Description: "Merge request IID within the project"},
+				"url":   {Type: "string", Description: "Merge request web URL"},
+				"state": {Type: "string", Description: "Merge request state"},
+			},
+		},
+		"merge_mr": {
+			Description: "Merge a merge request",
+			Inputs: projectInputs(map[string]IOSpec{
+				"mr_iid":               {Type: "number", Required: true, Description: "Merge request IID"},
+				"merge_commit_message": {Type: "string", Required: false, Description: "Custom merge commit message"},
+			}),
+			Outputs: map[string]IOSpec{
+				"state":            {Type: "string", Description: "Merge request state"},
+				"merge_commit_sha": {Type: "string", Description: "Merge commit SHA"},
+			},
+		},
+		"create_issue": {
+			Description: "Create an issue",
+			Inputs: projectInputs(map[string]IOSpec{
+				"title":       {Type: "string", Required: true, Description: "Issue title"},
+				"description": {Type: "string", Required: false, Description: "Issue description"},
+				"labels":      {Type: "array", Required: false, Description: "Label names"},
+			}),
+			Outputs: map[string]IOSpec{
+				"iid": {Type: "number", Description: "Issue IID within the project"},
+				"url": {Type: "string", Description: "Issue web URL"},
+			},
+		},
+		"close_issue": {
+			Description: "Close an issue",
+			Inputs: projectInputs(map[string]IOSpec{
+				"issue_iid": {Type: "number", Required: true, Description: "Issue IID"},
+			}),
+			Outputs: map[string]IOSpec{
+				"iid":   {Type: "number", Description: "Issue IID"},
+				"state": {Type: "string", Description: "Issue state"},
+			},
+		},
+		"trigger_pipeline": {
+			Description: "Run a pipeline for a branch or tag",
+			Inputs: projectInputs(map[string]IOSpec{
+				"ref":       {Type: "string", Required: true, Description: "Branch or tag to run the pipeline on"},
+				"variables": {Type: "object", Required: false, Description: "CI/CD variables as key/value pairs"},
+			}),
+			Outputs: pipelineOutputs,
+		},
+		"get_pipeline": {
+			Description: "Get pipeline status, optionally waiting for it to finish",
+			Inputs: projectInputs(map[string]IOSpec{
+				"pipeline_id":   {Type: "number", Required: true, Description: "Pipeline ID"},
+				"wait":          {Type: "boolean", Required: false, Default: false, Description: "Poll until the pipeline finishes"},
+				"timeout":       {Type: "number", Required: false, Default: 1800, Description: "Maximum seconds to wait"},
+				"poll_interval": {Type: "number", Required: false, Default: 10, Description: "Seconds between status checks"},
+			}),
+			Outputs: pipelineOutputs,
+		},
+		"list_jobs": {
+			Description: "List the jobs of a pipeline",
+			Inputs: projectInputs(map[string]IOSpec{
+				"pipeline_id": {Type: "number", Required: true, Description: "Pipeline ID"},
+			}),
+			Outputs: map[string]IOSpec{
+				"jobs":  {Type: "array", Description: "Jobs with id, name, stage, status, duration and url"},
+				"count": {Type: "number", Description: "Number of jobs"},
+			},
+		},
+	}
+}
+
+func (p *GitLabPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	if p.token == "" {
+		return map[string]interface{}{"error": "GITLAB_TOKEN not configured"}, nil
+	}
+
+	projectID := getStringParam(params, "project_id", "")
+	if projectID == "" {
+		if id := getIntParam(params, "project_id", 0); id > 0 {
+			projectID = fmt.Sprintf("%d", id)
+		}
+	}
+	if projectID == "" {
+		return map[string]interface{}{"error": "project_id is required"}, nil
+	}
+	// Paths such as group/project must be sent URL-encoded as a single segment
+	projectPath := "/projects/" + url.PathEscape(projectID)
+
+	switch action {
+	case "create_mr":
+		return p.createMR(projectPath, params)
+	case "merge_mr":
+		return p.mergeMR(projectPath, params)
+	case "create_issue":
+		return p.createIssue(projectPath, params)
+	case "close_issue":
+		return p.closeIssue(projectPath, params)
+	case "trigger_pipeline":
+		return p.triggerPipeline(projectPath, params)
+	case "get_pipeline":
+		return p.getPipeline(projectPath, params)
+	case "list_jobs":
+		return p.listJobs(projectPath, params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *GitLabPlugin) createMR(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	sourceBranch := getStringParam(params, "source_branch", "")
+	targetBranch := getStringParam(params, "target_branch", "")
+	title := getStringParam(params, "title", "")
+	if sourceBranch == "" || targetBranch == "" || title == "" {
+		return map[string]interface{}{"error": "source_branch, target_branch and title are required"}, nil
+	}
+
+	body := map[string]interface{}{
+		"source_branch": sourceBranch,
+		"target_branch": targetBranch,
+		"title":         title,
+		"description":   getStringParam(params, "description", ""),
+	}
+	if ids := getIntSlice(params, "assignee_ids"); len(ids) > 0 {
+		body["assignee_ids"] = ids
+	}
+
+	mr, err := p.callAPI("POST", projectPath+"/merge_requests", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"iid":   mr["iid"],
+		"url":   mr["web_url"],
+		"state": mr["state"],
+	}, nil
+}
+
+func (p *GitLabPlugin) mergeMR(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	iid := getIntParam(params, "mr_iid", 0)
+	if iid <= 0 {
+		return map[string]interface{}{"error": "mr_iid is required"}, nil
+	}
+
+	body := map[string]interface{}{}
+	if message := getStringParam(params, "merge_commit_message", ""); message != "" {
+		body["merge_commit_message"] = message
+	}
+
+	mr, err := p.callAPI("PUT", fmt.Sprintf("%s/merge_requests/%d/merge", projectPath, iid), body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"state":            mr["state"],
+		"merge_commit_sha": mr["merge_commit_sha"],
+	}, nil
+}
+
+func (p *GitLabPlugin) createIssue(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	title := getStringParam(params, "title", "")
+	if title == "" {
+		return map[string]interface{}{"error": "title is required"}, nil
+	}
+
+	body := map[string]interface{}{
+		"title":       title,
+		"description": getStringParam(params, "description", ""),
+	}
+	// The API takes labels as a single comma-separated string
+	if labels := getStringSlice(params, "labels"); len(labels) > 0 {
+		body["labels"] = strings.Join(labels, ",")
+	}
+
+	issue, err := p.callAPI("POST", projectPath+"/issues", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"iid": issue["iid"],
+		"url": issue["web_url"],
+	}, nil
+}
+
+func (p *GitLabPlugin) closeIssue(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	iid := getIntParam(params, "issue_iid", 0)
+	if iid <= 0 {
+		return map[string]interface{}{"error": "issue_iid is required"}, nil
+	}
+
+	issue, err := p.callAPI("PUT", fmt.Sprintf("%s/issues/%d", projectPath, iid), map[string]interface{}{
+		"state_event": "close",
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"iid":   issue["iid"],
+		"state": issue["state"],
+	}, nil
+}
+
+func (p *GitLabPlugin) triggerPipeline(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	ref := getStringParam(params, "ref", "")
+	if ref == "" {
+		return map[string]interface{}{"error": "ref is required"}, nil
+	}
+
+	body := map[string]interface{}{"ref": ref}
+	if vars, ok := params["variables"].(map[string]interface{}); ok && len(vars) > 0 {
+		keys := make([]string, 0, len(vars))
+		for key := range vars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		variables := make([]map[string]string, 0, len(keys))
+		for _, key := range keys {
+			variables = append(variables, map[string]string{"key": key, "value": fmt.Sprintf("%v", vars[key])})
+		}
+		body["variables"] = variables
+	}
+
+	pipeline, err := p.callAPI("POST", projectPath+"/pipeline", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return pipelineResult(pipeline), nil
+}
+
+func (p *GitLabPlugin) getPipeline(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	pipelineID := getIntParam(params, "pipeline_id", 0)
+	if pipelineID <= 0 {
+		return map[string]interface{}{"error": "pipeline_id is required"}, nil
+	}
+	pipelinePath := fmt.Sprintf("%s/pipelines/%d", projectPath, pipelineID)
+
+	wait := getBoolParam(params, "wait", false)
+	timeout := time.Duration(getIntParam(params, "timeout", 1800)) * time.Second
+	interval := time.Duration(getIntParam(params, "poll_interval", 10)) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pipeline, err := p.callAPI("GET", pipelinePath, nil)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+
+		status, _ := pipeline["status"].(string)
+		if !wait || finishedPipelineStatuses[status] {
+			return pipelineResult(pipeline), nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			result := pipelineResult(pipeline)
+			result["error"] = fmt.Sprintf("timed out waiting for pipeline %d (status: %s)", pipelineID, status)
+			return result, nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (p *GitLabPlugin) listJobs(projectPath string, params map[string]interface{}) (map[string]interface{}, error) {
+	pipelineID := getIntParam(params, "pipeline_id", 0)
+	if pipelineID <= 0 {
+		return map[string]interface{}{"error": "pipeline_id is required"}, nil
+	}
+
+	var jobs []interface{}
+	for page := 1; ; page++ {
+		var items []map[string]interface{}
+		next, err := p.request("GET", fmt.Sprintf("%s/pipelines/%d/jobs?per_page=100&page=%d", projectPath, pipelineID, page), nil, &items)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+
+		for _, job := range items {
+			jobs = append(jobs, map[string]interface{}{
+				"id":       job["id"],
+				"name":     job["name"],
+				"stage":    job["stage"],
+				"status":   job["status"],
+				"duration": job["duration"],
+				"url":      job["web_url"],
+			})
+		}
+		if next == "" {
+			break
+		}
+	}
+	if jobs == nil {
+		jobs = []interface{}{}
+	}
+
+	return map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	}, nil
+}
+
+func pipelineResult(pipeline map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":     pipeline["id"],
+		"status": pipeline["status"],
+		"ref":    pipeline["ref"],
+		"sha":    pipeline["sha"],
+		"url":    pipeline["web_url"],
+	}
+}
+
+// callAPI sends a JSON request to the REST API and decodes the JSON object it returns
+func (p *GitLabPlugin) callAPI(method, path string, body interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if _, err := p.request(method, path, body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// request sends a request, decodes the response into out and returns the X-Next-Page header
+func (p *GitLabPlugin) request(method, path string, body interface{}, out interface{}) (string, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return "", apiError(resp.StatusCode, data)
+	}
+
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return "", fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+	return resp.Header.Get("X-Next-Page"), nil
+}
+
+// apiError formats a GitLab error response, whose message may be a string, list or field map
+func apiError(status int, raw []byte) error {
+	var body map[string]interface{}
+	message := strings.TrimSpace(string(raw))
+	if err := json.Unmarshal(raw, &body); err == nil {
+		if msg, ok := body["message"]; ok {
+			message = formatMessage(msg)
+		} else if msg, ok := body["error"]; ok {
+			message = formatMessage(msg)
+		}
+	}
+	return fmt.Errorf("GitLab API error (%d): %s", status, message)
+}
+
+func formatMessage(msg interface{}) string {
+	switch m := msg.(type) {
+	case string:
+		return m
+	case []interface{}:
+		parts := make([]string, 0, len(m))
+		for _, item := range m {
+			parts = append(parts, formatMessage(item))
+		}
+		return strings.Join(parts, "; ")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, key+" "+formatMessage(m[key]))
+		}
+		return strings.Join(parts, "; ")
+	default:
+		return fmt.Sprintf("%v", m)
+	}
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func getIntSlice(params map[string]interface{}, key string) []int {
+	items, _ := params[key].([]interface{})
+	result := make([]int, 0, len(items))
+	for _, item := range items {
+		if n, ok := item.(float64); ok {
+			result = append(result, int(n))
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewGitLabPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -36,11 +43,17 @@ type HTTPPlugin struct {
 	client *http.Client
 }
 
+// defaultTimeout applies to any request that does not set its own timeout.
+const defaultTimeout = 30 * time.Second
+
+// maxTimeout is the client Timeout, a ceiling no per-request timeout can exceed.
+const maxTimeout = 10 * time.Minute
+
 func NewHTTPPlugin() *HTTPPlugin {
+	// The client is shared across calls, so its Timeout is never changed per request;
+	// each request gets a context deadline from doRequest instead.
 	return &HTTPPlugin{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: &http.Client{Timeout: maxTimeout},
 	}
 }
 
@@ -59,28 +72,172 @@ func (p *HTTPPlugin) GetActions() map[string]ActionSpec {
 		"get": {
 			Description: "Make HTTP GET requests with headers",
 			Inputs: map[string]IOSpec{
-				"url":     {Type: "string", Required: true, Description: "Request URL"},
-				"headers": {Type: "object", Required: false, Description: "HTTP headers"},
-				"timeout": {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds"},
-				"auth":    {Type: "object", Required: false, Description: "Basic auth with username/password"},
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
 			},
 			Outputs: map[string]IOSpec{
 				"status_code": {Type: "number", Description: "HTTP status code"},
 				"headers":     {Type: "object", Description: "Response headers"},
 				"content":     {Type: "string", Description: "Response body"},
 				"json":        {Type: "object", Description: "Parsed JSON response (if applicable)"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
 			},
 		},
 		"post": {
 			Description: "Make HTTP POST requests with JSON data",
 			Inputs: map[string]IOSpec{
-				"url":          {Type: "string", Required: true, Description: "Request URL"},
-				"headers":      {Type: "object", Required: false, Description: "HTTP headers"},
-				"body":         {Type: "string", Required: false, Description: "Request body as string"},
-				"json":         {Type: "object", Required: false, Description: "Request body as JSON"},
-				"timeout":      {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds"},
-				"auth":         {Type: "object", Required: false, Description: "Basic auth with username/password"},
-				"content_type": {Type: "string", Required: false, Default: "application/json", Description: "Content-Type header"},
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"body":                 {Type: "string", Required: false, Description: "Request body as string"},
+				"json":                 {Type: "object", Required: false, Description: "Request body as JSON"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+				"content_type":         {Type: "string", Required: false, Default: "application/json", Description: "Content-Type header"},
+			},
+			Outputs: map[string]IOSpec{
+				"status_code": {Type: "number", Description: "HTTP status code"},
+				"headers":     {Type: "object", Description: "Response headers"},
+				"content":     {Type: "string", Description: "Response body"},
+				"json":        {Type: "object", Description: "Parsed JSON response (if applicable)"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
+			},
+		},
+		"put": {
+			Description: "Make HTTP PUT requests with JSON data",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"body":                 {Type: "string", Required: false, Description: "Request body as string"},
+				"json":                 {Type: "object", Required: false, Description: "Request body as JSON"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+				"content_type":         {Type: "string", Required: false, Default: "application/json", Description: "Content-Type header"},
+			},
+			Outputs: map[string]IOSpec{
+				"status_code": {Type: "number", Description: "HTTP status code"},
+				"headers":     {Type: "object", Description: "Response headers"},
+				"content":     {Type: "string", Description: "Response body"},
+				"json":        {Type: "object", Description: "Parsed JSON response (if applicable)"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
+			},
+		},
+		"patch": {
+			Description: "Make HTTP PATCH requests with JSON data",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"body":                 {Type: "string", Required: false, Description: "Request body as string"},
+				"json":                 {Type: "object", Required: false, Description: "Request body as JSON"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+				"content_type":         {Type: "string", Required: false, Default: "application/json", Description: "Content-Type header"},
+			},
+			Outputs: map[string]IOSpec{
+				"status_code": {Type: "number", Description: "HTTP status code"},
+				"headers":     {Type: "object", Description: "Response headers"},
+				"content":     {Type: "string", Description: "Response body"},
+				"json":        {Type: "object", Description: "Parsed JSON response (if applicable)"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
+			},
+		},
+		"delete": {
+			Description: "Make HTTP DELETE requests with optional body",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"body":                 {Type: "string", Required: false, Description: "Request body as string"},
+				"json":                 {Type: "object", Required: false, Description: "Request body as JSON"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+				"content_type":         {Type: "string", Required: false, Default: "application/json", Description: "Content-Type header"},
+			},
+			Outputs: map[string]IOSpec{
+				"status_code": {Type: "number", Description: "HTTP status code"},
+				"headers":     {Type: "object", Description: "Response headers"},
+				"content":     {Type: "string", Description: "Response body"},
+				"json":        {Type: "object", Description: "Parsed JSON response (if applicable)"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
+			},
+		},
+		"head": {
+			Description: "Make HTTP HEAD requests to fetch response headers only",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+			},
+			Outputs: map[string]IOSpec{
+				"status_code": {Type: "number", Description: "HTTP status code"},
+				"headers":     {Type: "object", Description: "Response headers"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
+			},
+		},
+		"download": {
+			Description: "Stream an HTTP GET response body directly to a file",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"file_path":            {Type: "string", Required: true, Description: "Destination file path"},
+				"create_dirs":          {Type: "boolean", Required: false, Default: false, Description: "Create destination directories"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"timeout":              {Type: "number", Required: false, Default: 300, Description: "Request timeout in seconds (at most 600)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+			},
+			Outputs: map[string]IOSpec{
+				"file_path":    {Type: "string", Description: "Path the body was written to"},
+				"size":         {Type: "number", Description: "Bytes written"},
+				"status_code":  {Type: "number", Description: "HTTP status code"},
+				"content_type": {Type: "string", Description: "Response Content-Type"},
+				"final_url":    {Type: "string", Description: "URL after following redirects"},
+			},
+		},
+		"upload": {
+			Description: "Upload a file as multipart/form-data",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "Request URL"},
+				"file_path":            {Type: "string", Required: true, Description: "Path of the file to upload"},
+				"field_name":           {Type: "string", Required: false, Default: "file", Description: "Form field name for the file"},
+				"fields":               {Type: "object", Required: false, Description: "Additional form fields"},
+				"method":               {Type: "string", Required: false, Default: "POST", Description: "HTTP method (POST or PUT)"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
 			},
 			Outputs: map[string]IOSpec{
 				"status_code": {Type: "number", Description: "HTTP status code"},
@@ -89,6 +246,31 @@ func (p *HTTPPlugin) GetActions() map[string]ActionSpec {
 				"json":        {Type: "object", Description: "Parsed JSON response (if applicable)"},
 			},
 		},
+		"graphql": {
+			Description: "Send a GraphQL query or mutation",
+			Inputs: map[string]IOSpec{
+				"url":                  {Type: "string", Required: true, Description: "GraphQL endpoint URL"},
+				"query":                {Type: "string", Required: true, Description: "GraphQL query or mutation document"},
+				"variables":            {Type: "object", Required: false, Description: "Query variables"},
+				"operation_name":       {Type: "string", Required: false, Description: "Operation to run when the document defines several"},
+				"headers":              {Type: "object", Required: false, Description: "HTTP headers"},
+				"timeout":              {Type: "number", Required: false, Default: 30, Description: "Request timeout in seconds (at most 600)"},
+				"max_retries":          {Type: "number", Required: false, Default: 0, Description: "Retries after the first attempt (0 disables retries)"},
+				"retry_backoff_ms":     {Type: "number", Required: false, Default: 500, Description: "Base delay for jittered exponential backoff in milliseconds"},
+				"retry_on":             {Type: "array", Required: false, Description: "Status codes and/or \"connection_error\" that trigger a retry (default: connection_error, 429, 500, 502, 503, 504)"},
+				"auth":                 {Type: "object", Required: false, Description: "Auth settings: {type: basic, username, password}, {type: bearer, token}, or {type: api_key, header, value}"},
+				"insecure_skip_verify": {Type: "boolean", Required: false, Default: false, Description: "DANGEROUS: disables TLS certificate and hostname verification, leaving the connection open to man-in-the-middle attacks. Prefer ca_cert; only use against trusted test endpoints"},
+				"ca_cert":              {Type: "string", Required: false, Description: "PEM-encoded CA certificate (or path to one) trusted in addition to the system roots"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":     {Type: "boolean", Description: "True when the HTTP status is 2xx and no GraphQL errors were returned"},
+				"status_code": {Type: "number", Description: "HTTP status code"},
+				"headers":     {Type: "object", Description: "Response headers"},
+				"data":        {Type: "object", Description: "The data field of the response"},
+				"errors":      {Type: "array", Description: "The errors field of the response (empty when there were none)"},
+				"attempts":    {Type: "number", Description: "Number of attempts made"},
+			},
+		},
 	}
 }
 
@@ -98,66 +280,256 @@ func (p *HTTPPlugin) Execute(action string, params map[string]interface{}) (map[
 		return p.makeGetRequest(params)
 	case "post":
 		return p.makePostRequest(params)
+	case "put":
+		return p.makeRequest("PUT", params)
+	case "patch":
+		return p.makeRequest("PATCH", params)
+	case "delete":
+		return p.makeRequest("DELETE", params)
+	case "head":
+		return p.makeRequest("HEAD", params)
+	case "download":
+		return p.download(params)
+	case "upload":
+		return p.upload(params)
+	case "graphql":
+		return p.graphql(params)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
 }
 
 func (p *HTTPPlugin) makeGetRequest(params map[string]interface{}) (map[string]interface{}, error) {
+	return p.makeRequest("GET", params)
+}
+
+func (p *HTTPPlugin) makePostRequest(params map[string]interface{}) (map[string]interface{}, error) {
+	return p.makeRequest("POST", params)
+}
+
+// makeRequest builds and sends a request for any method, sharing body, header, auth, and timeout handling.
+func (p *HTTPPlugin) makeRequest(method string, params map[string]interface{}) (map[string]interface{}, error) {
+	resp, attempts, err := p.doWithRetry(method, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error(), "attempts": attempts}, nil
+	}
+	defer resp.Body.Close()
+
+	result, err := readResponse(resp)
+	if result != nil {
+		result["attempts"] = attempts
+	}
+	return result, err
+}
+
+// doWithRetry sends the request, rebuilding it for each attempt so the body is never reused.
+// Retries are opt-in via max_retries and use jittered exponential backoff, honoring Retry-After.
+func (p *HTTPPlugin) doWithRetry(method string, params map[string]interface{}) (*http.Response, int, error) {
+	maxRetries := 0
+	if val, ok := params["max_retries"].(float64); ok && val > 0 {
+		maxRetries = int(val)
+	}
+
+	backoff := 500 * time.Millisecond
+	if val, ok := params["retry_backoff_ms"].(float64); ok && val >= 0 {
+		backoff = time.Duration(val) * time.Millisecond
+	}
+
+	retryStatus, retryConnErr := parseRetryOn(params["retry_on"])
+
+	client, err := p.clientFor(params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := p.buildRequest(method, params)
+		if err != nil {
+			return nil, attempt, err
+		}
+
+		resp, err := doRequest(client, req, params)
+		canRetry := attempt < maxRetries
+
+		if err != nil {
+			if canRetry && retryConnErr {
+				time.Sleep(backoffDelay(backoff, attempt))
+				continue
+			}
+			return nil, attempt + 1, fmt.Errorf("request failed: %v", err)
+		}
+
+		if canRetry && retryStatus[resp.StatusCode] {
+			delay := backoffDelay(backoff, attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			// Drain so the connection can be reused by the next attempt
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			time.Sleep(delay)
+			continue
+		}
+
+		return resp, attempt + 1, nil
+	}
+}
+
+func (p *HTTPPlugin) upload(params map[string]interface{}) (map[string]interface{}, error) {
 	url, ok := params["url"].(string)
 	if !ok || url == "" {
 		return map[string]interface{}{"error": "url is required"}, nil
 	}
 
-	// Set timeout
-	if timeout, ok := params["timeout"].(float64); ok {
-		p.client.Timeout = time.Duration(timeout) * time.Second
+	filePath, ok := params["file_path"].(string)
+	if !ok || filePath == "" {
+		return map[string]interface{}{"error": "file_path is required"}, nil
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	fieldName := getStringParam(params, "field_name", "file")
+	if fieldName == "" {
+		fieldName = "file"
+	}
+
+	file, err := os.Open(filePath)
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+		return map[string]interface{}{"error": fmt.Sprintf("failed to open file: %v", err)}, nil
 	}
+	defer file.Close()
 
-	// Set headers
-	if headers, ok := params["headers"].(map[string]interface{}); ok {
-		for key, value := range headers {
-			if strValue, ok := value.(string); ok {
-				req.Header.Set(key, strValue)
-			}
-		}
+	client, err := p.clientFor(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
-	// Set authentication
-	if auth, ok := params["auth"].(map[string]interface{}); ok {
-		if username, hasUser := auth["username"].(string); hasUser {
-			if password, hasPass := auth["password"].(string); hasPass {
-				req.SetBasicAuth(username, password)
+	// The multipart body is produced by a goroutine and streamed, so the file is never held in memory
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+
+	go func() {
+		if fields, ok := params["fields"].(map[string]interface{}); ok {
+			for key, value := range fields {
+				if err := writer.WriteField(key, fmt.Sprint(value)); err != nil {
+					pipeWriter.CloseWithError(err)
+					return
+				}
 			}
 		}
+
+		part, err := writer.CreateFormFile(fieldName, filepath.Base(filePath))
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		pipeWriter.CloseWithError(writer.Close())
+	}()
+
+	method := strings.ToUpper(getStringParam(params, "method", "POST"))
+	// Closing the reader on every exit unblocks the writer goroutine if the body was not fully consumed
+	req, err := p.newRequest(method, url, pipeReader, "", params)
+	if err != nil {
+		pipeReader.CloseWithError(err)
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
+	// Always use the generated boundary, even if headers carried a Content-Type
+	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, err := p.client.Do(req)
+	resp, err := doRequest(client, req, params)
 	if err != nil {
+		pipeReader.CloseWithError(err)
 		return map[string]interface{}{"error": fmt.Sprintf("request failed: %v", err)}, nil
 	}
+	defer pipeReader.Close()
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	return readResponse(resp)
+}
+
+// graphql POSTs the standard {query, variables, operationName} body. GraphQL reports
+// failures in the response body, often with a 200 status, so success also requires
+// an empty errors array.
+func (p *HTTPPlugin) graphql(params map[string]interface{}) (map[string]interface{}, error) {
+	query := getStringParam(params, "query", "")
+	if query == "" {
+		return map[string]interface{}{"error": "query is required"}, nil
+	}
+
+	payload := map[string]interface{}{"query": query}
+	if variables, ok := params["variables"].(map[string]interface{}); ok && len(variables) > 0 {
+		payload["variables"] = variables
+	}
+	if operationName := getStringParam(params, "operation_name", ""); operationName != "" {
+		payload["operationName"] = operationName
+	}
+
+	// Reuse the POST path (auth, headers, TLS, retries) with the payload as its JSON body
+	requestParams := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		requestParams[key] = value
+	}
+	delete(requestParams, "body")
+	requestParams["json"] = payload
+	requestParams["content_type"] = "application/json"
+
+	resp, attempts, err := p.doWithRetry("POST", requestParams)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error(), "attempts": attempts}, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": fmt.Sprintf("failed to read response: %v", err), "attempts": attempts}, nil
+	}
+
+	result := map[string]interface{}{
+		"status_code": resp.StatusCode,
+		"headers":     convertHeaders(resp.Header),
+		"data":        nil,
+		"errors":      []interface{}{},
+		"attempts":    attempts,
+	}
+
+	var parsed struct {
+		Data   interface{}   `json:"data"`
+		Errors []interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		result["success"] = false
+		result["content"] = string(respBody)
+		result["error"] = fmt.Sprintf("response is not valid GraphQL JSON: %v", err)
+		return result, nil
+	}
+
+	result["data"] = parsed.Data
+	if parsed.Errors != nil {
+		result["errors"] = parsed.Errors
+	}
+	result["success"] = resp.StatusCode >= 200 && resp.StatusCode < 300 && len(parsed.Errors) == 0
+	return result, nil
+}
+
+// readResponse reads the body and converts the response into the plugin's standard output.
+func readResponse(resp *http.Response) (map[string]interface{}, error) {
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("failed to read response: %v", err)}, nil
 	}
 
 	result := map[string]interface{}{
 		"status_code": resp.StatusCode,
-		"content":     string(body),
+		"content":     string(respBody),
 		"headers":     convertHeaders(resp.Header),
 	}
 
-	// Try to parse JSON
-	if len(body) > 0 && strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+	// Try to parse JSON response
+	if len(respBody) > 0 && strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
 		var jsonData interface{}
-		if json.Unmarshal(body, &jsonData) == nil {
+		if json.Unmarshal(respBody, &jsonData) == nil {
 			result["json"] = jsonData
 		}
 	}
@@ -165,40 +537,193 @@ func (p *HTTPPlugin) makeGetRequest(params map[string]interface{}) (map[string]i
 	return result, nil
 }
 
-func (p *HTTPPlugin) makePostRequest(params map[string]interface{}) (map[string]interface{}, error) {
-	url, ok := params["url"].(string)
-	if !ok || url == "" {
-		return map[string]interface{}{"error": "url is required"}, nil
+func (p *HTTPPlugin) download(params map[string]interface{}) (map[string]interface{}, error) {
+	filePath, ok := params["file_path"].(string)
+	if !ok || filePath == "" {
+		return map[string]interface{}{"error": "file_path is required"}, nil
+	}
+
+	// Downloads are typically large, so allow more time than regular requests unless overridden;
+	// the default goes into a copy so the caller's params are left untouched
+	if _, ok := params["timeout"]; !ok {
+		withTimeout := make(map[string]interface{}, len(params)+1)
+		for key, value := range params {
+			withTimeout[key] = value
+		}
+		withTimeout["timeout"] = float64(300)
+		params = withTimeout
+	}
+
+	client, err := p.clientFor(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
-	// Set timeout
-	if timeout, ok := params["timeout"].(float64); ok {
-		p.client.Timeout = time.Duration(timeout) * time.Second
+	req, err := p.buildRequest("GET", params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	resp, err := doRequest(client, req, params)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("request failed: %v", err)}, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return map[string]interface{}{
+			"error":       fmt.Sprintf("download failed with status %d", resp.StatusCode),
+			"status_code": resp.StatusCode,
+		}, nil
+	}
+
+	if getBoolParam(params, "create_dirs", false) {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to create directories: %v", err)}, nil
+		}
+	}
+
+	// Stream into a temporary file beside the target so a failed transfer never leaves a partial file
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".download-*")
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create file: %v", err)}, nil
+	}
+	defer os.Remove(tmpFile.Name())
+	// CreateTemp uses 0600; match the permissions of a normally created file
+	tmpFile.Chmod(0644)
+
+	size, err := io.Copy(tmpFile, resp.Body)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to write file: %v", err)}, nil
+	}
+
+	if err := os.Rename(tmpFile.Name(), filePath); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to move download into place: %v", err)}, nil
+	}
+
+	return map[string]interface{}{
+		"file_path":    filePath,
+		"size":         size,
+		"status_code":  resp.StatusCode,
+		"content_type": resp.Header.Get("Content-Type"),
+		"final_url":    resp.Request.URL.String(),
+	}, nil
+}
+
+// buildRequest creates a request with the body, Content-Type, headers, and auth described by params.
+func (p *HTTPPlugin) buildRequest(method string, params map[string]interface{}) (*http.Request, error) {
+	url, ok := params["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("url is required")
 	}
 
 	// Prepare request body
 	var body io.Reader
-	contentType := getStringParam(params, "content_type", "application/json")
+	if method != "GET" && method != "HEAD" {
+		if jsonData, hasJSON := params["json"]; hasJSON {
+			// JSON body
+			jsonBytes, err := json.Marshal(jsonData)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal JSON: %v", err)
+			}
+			body = bytes.NewReader(jsonBytes)
+		} else if bodyStr, hasBody := params["body"].(string); hasBody {
+			// String body
+			body = strings.NewReader(bodyStr)
+		}
+	}
 
-	if jsonData, hasJSON := params["json"]; hasJSON {
-		// JSON body
-		jsonBytes, err := json.Marshal(jsonData)
-		if err != nil {
-			return map[string]interface{}{"error": fmt.Sprintf("failed to marshal JSON: %v", err)}, nil
+	// Set Content-Type; POST always sends one, other methods only when they carry a body
+	contentType := ""
+	if method == "POST" || body != nil {
+		contentType = getStringParam(params, "content_type", "application/json")
+	}
+
+	return p.newRequest(method, url, body, contentType, params)
+}
+
+// doRequest sends req on client bounded by the timeout param (default 30s, capped at the client's Timeout),
+// without touching the client itself. The deadline stays in force until the response body is closed,
+// so slow bodies are covered too.
+func doRequest(client *http.Client, req *http.Request, params map[string]interface{}) (*http.Response, error) {
+	timeout := defaultTimeout
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = time.Duration(val * float64(time.Second))
+	}
+	if client.Timeout > 0 && timeout > client.Timeout {
+		timeout = client.Timeout
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// clientFor returns the shared client, or a client with a dedicated transport when the
+// request asks for custom TLS settings, so those settings never leak into other requests.
+func (p *HTTPPlugin) clientFor(params map[string]interface{}) (*http.Client, error) {
+	skipVerify := getBoolParam(params, "insecure_skip_verify", false)
+	caCert := getStringParam(params, "ca_cert", "")
+	if !skipVerify && caCert == "" {
+		return p.client, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: skipVerify}
+	if caCert != "" {
+		pemData := []byte(caCert)
+		if !strings.Contains(caCert, "-----BEGIN") {
+			data, err := os.ReadFile(caCert)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ca_cert: %v", err)
+			}
+			pemData = data
 		}
-		body = bytes.NewReader(jsonBytes)
-	} else if bodyStr, hasBody := params["body"].(string); hasBody {
-		// String body
-		body = strings.NewReader(bodyStr)
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("ca_cert contains no valid PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
 	}
 
-	req, err := http.NewRequest("POST", url, body)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: p.client.Timeout}, nil
+}
+
+// cancelOnClose releases a request's context once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// newRequest creates a request and applies the Content-Type, user headers, and auth from params.
+func (p *HTTPPlugin) newRequest(method, url string, body io.Reader, contentType string, params map[string]interface{}) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	// Set Content-Type
-	req.Header.Set("Content-Type", contentType)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
 	// Set headers
 	if headers, ok := params["headers"].(map[string]interface{}); ok {
@@ -211,42 +736,105 @@ func (p *HTTPPlugin) makePostRequest(params map[string]interface{}) (map[string]
 
 	// Set authentication
 	if auth, ok := params["auth"].(map[string]interface{}); ok {
-		if username, hasUser := auth["username"].(string); hasUser {
-			if password, hasPass := auth["password"].(string); hasPass {
-				req.SetBasicAuth(username, password)
-			}
+		if err := applyAuth(req, auth); err != nil {
+			return nil, err
 		}
 	}
 
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("request failed: %v", err)}, nil
+	return req, nil
+}
+
+// applyAuth sets credentials according to auth.type. Without a type, the original
+// {username, password} shape is treated as basic auth.
+func applyAuth(req *http.Request, auth map[string]interface{}) error {
+	authType := strings.ToLower(getStringParam(auth, "type", "basic"))
+
+	switch authType {
+	case "basic":
+		username, hasUser := auth["username"].(string)
+		password, hasPass := auth["password"].(string)
+		if hasUser && hasPass {
+			req.SetBasicAuth(username, password)
+		}
+	case "bearer":
+		token := getStringParam(auth, "token", "")
+		if token == "" {
+			return fmt.Errorf("auth.token is required for bearer auth")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case "api_key":
+		header := getStringParam(auth, "header", "")
+		value := getStringParam(auth, "value", "")
+		if header == "" || value == "" {
+			return fmt.Errorf("auth.header and auth.value are required for api_key auth")
+		}
+		req.Header.Set(header, value)
+	default:
+		return fmt.Errorf("unsupported auth type: %s", authType)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to read response: %v", err)}, nil
+	return nil
+}
+
+// Helper functions
+
+// parseRetryOn returns the retryable status codes and whether connection errors are retried.
+func parseRetryOn(value interface{}) (map[int]bool, bool) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return map[int]bool{429: true, 500: true, 502: true, 503: true, 504: true}, true
 	}
 
-	result := map[string]interface{}{
-		"status_code": resp.StatusCode,
-		"content":     string(respBody),
-		"headers":     convertHeaders(resp.Header),
+	statuses := make(map[int]bool)
+	connErr := false
+	for _, item := range list {
+		switch v := item.(type) {
+		case float64:
+			statuses[int(v)] = true
+		case string:
+			if v == "connection_error" {
+				connErr = true
+			} else if code, err := strconv.Atoi(v); err == nil {
+				statuses[code] = true
+			}
+		}
 	}
+	return statuses, connErr
+}
 
-	// Try to parse JSON response
-	if len(respBody) > 0 && strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
-		var jsonData interface{}
-		if json.Unmarshal(respBody, &jsonData) == nil {
-			result["json"] = jsonData
+// backoffDelay returns base * 2^attempt scaled by a random factor in [0.5, 1.5).
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	if attempt > 16 {
+		attempt = 16
+	}
+	delay := base * time.Duration(1<<uint(attempt))
+	return time.Duration(float64(delay) * (0.5 + rand.Float64()))
+}
+
+// parseRetryAfter understands both delay-seconds and HTTP-date forms of Retry-After.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
 		}
+		return 0, true
 	}
+	return 0, false
+}
 
-	return result, nil
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
 }
 
-// Helper functions
 func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
 	if val, ok := params[key].(string); ok {
 		return val
@@ -303,4 +891,4 @@ func main() {
 	}
 
 	json.NewEncoder(os.Stdout).Encode(result)
-}
\ No newline at end of file
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func slowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTimeoutsDoNotLeakBetweenRequests(t *testing.T) {
+	server := slowServer(t, 300*time.Millisecond)
+	plugin := NewHTTPPlugin()
+
+	short, err := plugin.Execute("get", map[string]interface{}{"url": server.URL, "timeout": 0.1})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if _, failed := short["error"]; !failed {
+		t.Fatalf("expected a 0.1s request to a 0.3s handler to time out, got %v", short)
+	}
+
+	long, err := plugin.Execute("get", map[string]interface{}{"url": server.URL, "timeout": float64(5)})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if msg, failed := long["error"]; failed {
+		t.Fatalf("expected the 5s request to succeed after the short one, got error %v", msg)
+	}
+	if long["status_code"] != http.StatusOK {
+		t.Fatalf("expected status 200, got %v", long["status_code"])
+	}
+
+	if plugin.client.Timeout != maxTimeout {
+		t.Fatalf("shared client Timeout changed to %v", plugin.client.Timeout)
+	}
+}
+
+func TestTimeoutIsCappedByClient(t *testing.T) {
+	server := slowServer(t, 300*time.Millisecond)
+	plugin := &HTTPPlugin{client: &http.Client{Timeout: 100 * time.Millisecond}}
+
+	result, err := plugin.Execute("get", map[string]interface{}{"url": server.URL, "timeout": float64(5)})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if _, failed := result["error"]; !failed {
+		t.Fatalf("expected the client Timeout to cap a 5s request timeout, got %v", result)
+	}
+}
@@ -0,0 +1,605 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type JiraPlugin struct {
+	baseURL string
+	email   string
+	token   string
+	client  *http.Client
+}
+
+func NewJiraPlugin() *JiraPlugin {
+	return &JiraPlugin{
+		baseURL: strings.TrimRight(os.Getenv("JIRA_URL"), "/"),
+		email:   os.Getenv("JIRA_EMAIL"),
+		token:   os.Getenv("JIRA_TOKEN"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (p *JiraPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "jira",
+		Version:     "1.0.0",
+		Description: "Jira issue management, transitions and JQL search",
+		Author:      "Corynth Team",
+		Tags:        []string{"jira", "atlassian", "issues", "project-management"},
+	}
+}
+
+func (p *JiraPlugin) GetActions() map[string]ActionSpec {
+	issueOutputs := map[string]IOSpec{
+		"issue_key":   {Type: "string", Description: "Issue key (e.g. OPS-123)"},
+		"summary":     {Type: "string", Description: "Issue summary"},
+		"status":      {Type: "string", Description: "Issue status name"},
+		"description": {Type: "string", Description: "Issue description as plain text"},
+		"url":         {Type: "string", Description: "Issue web URL"},
+		"fields":      {Type: "object", Description: "All issue fields as returned by Jira"},
+	}
+
+	return map[string]ActionSpec{
+		"create_issue": {
+			Description: "Create an issue",
+			Inputs: map[string]IOSpec{
+				"project_key":   {Type: "string", Required: true, Description: "Project key (e.g. OPS)"},
+				"issuetype":     {Type: "string", Required: false, Default: "Task", Description: "Issue type name"},
+				"summary":       {Type: "string", Required: true, Description: "Issue summary"},
+				"description":   {Type: "string", Required: false, Description: "Issue description (plain text)"},
+				"priority":      {Type: "string", Required: false, Description: "Priority name (e.g. High)"},
+				"assignee":      {Type: "string", Required: false, Description: "Assignee account ID"},
+				"labels":        {Type: "array", Required: false, Description: "Labels to add"},
+				"custom_fields": {Type: "object", Required: false, Description: "Additional fields keyed by field ID (e.g. customfield_10010)"},
+			},
+			Outputs: map[string]IOSpec{
+				"issue_key": {Type: "string", Description: "Created issue key"},
+				"id":        {Type: "string", Description: "Created issue ID"},
+				"url":       {Type: "string", Description: "Issue web URL"},
+			},
+		},
+		"get_issue": {
+			Description: "Get an issue",
+			Inputs: map[string]IOSpec{
+				"issue_key": {Type: "string", Required: true, Description: "Issue key"},
+				"fields":    {Type: "array", Required: false, Description: "Fields to return (defaults to all navigable fields)"},
+			},
+			Outputs: issueOutputs,
+		},
+		"update_issue": {
+			Description: "Update issue fields",
+			Inputs: map[string]IOSpec{
+				"issue_key": {Type: "string", Required: true, Description: "Issue key"},
+				"fields":    {Type: "object", Required: true, Description: "Fields to set; a string description is converted to Jira document format"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":   {Type: "boolean", Description: "Whether the update succeeded"},
+				"issue_key": {Type: "string", Description: "Issue key"},
+			},
+		},
+		"transition_issue": {
+			Description: "Move an issue through its workflow",
+			Inputs: map[string]IOSpec{
+				"issue_key":       {Type: "string", Required: true, Description: "Issue key"},
+				"transition_name": {Type: "string", Required: false, Description: "Transition or target status name (e.g. Done)"},
+				"transition_id":   {Type: "string", Required: false, Description: "Transition ID, used instead of the name"},
+				"comment":         {Type: "string", Required: false, Description: "Comment to add with the transition"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":       {Type: "boolean", Description: "Whether the transition succeeded"},
+				"issue_key":     {Type: "string", Description: "Issue key"},
+				"transition_id": {Type: "string", Description: "Transition that was applied"},
+				"status":        {Type: "string", Description: "Status the transition leads to"},
+			},
+		},
+		"add_comment": {
+			Description: "Add a comment to an issue",
+			Inputs: map[string]IOSpec{
+				"issue_key": {Type: "string", Required: true, Description: "Issue key"},
+				"body":      {Type: "string", Required: true, Description: "Comment text"},
+			},
+			Outputs: map[string]IOSpec{
+				"comment_id": {Type: "string", Description: "Created comment ID"},
+				"issue_key":  {Type: "string", Description: "Issue key"},
+			},
+		},
+		"search": {
+			Description: "Search issues with JQL",
+			Inputs: map[string]IOSpec{
+				"jql":         {Type: "string", Required: true, Description: "JQL query"},
+				"fields":      {Type: "array", Required: false, Description: "Fields to return for each issue"},
+				"max_results": {Type: "number", Required: false, Default: 50, Description: "Maximum number of issues to return"},
+			},
+			Outputs: map[string]IOSpec{
+				"issues": {Type: "array", Description: "Matching issues with issue_key, summary, status and fields"},
+				"count":  {Type: "number", Description: "Number of issues returned"},
+				"total":  {Type: "number", Description: "Total number of matching issues"},
+			},
+		},
+	}
+}
+
+func (p *JiraPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	if p.baseURL == "" || p.email == "" || p.token == "" {
+		return map[string]interface{}{"error": "JIRA_URL, JIRA_EMAIL and JIRA_TOKEN must be set"}, nil
+	}
+
+	switch action {
+	case "create_issue":
+		return p.createIssue(params)
+	case "get_issue":
+		return p.getIssue(params)
+	case "update_issue":
+		return p.updateIssue(params)
+	case "transition_issue":
+		return p.transitionIssue(params)
+	case "add_comment":
+		return p.addComment(params)
+	case "search":
+		return p.search(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *JiraPlugin) createIssue(params map[string]interface{}) (map[string]interface{}, error) {
+	projectKey := getStringParam(params, "project_key", "")
+	summary := getStringParam(params, "summary", "")
+	if projectKey == "" || summary == "" {
+		return map[string]interface{}{"error": "project_key and summary are required"}, nil
+	}
+
+	fields := map[string]interface{}{
+		"project":   map[string]interface{}{"key": projectKey},
+		"issuetype": map[string]interface{}{"name": getStringParam(params, "issuetype", "Task")},
+		"summary":   summary,
+	}
+	if description := getStringParam(params, "description", ""); description != "" {
+		fields["description"] = textToADF(description)
+	}
+	if priority := getStringParam(params, "priority", ""); priority != "" {
+		fields["priority"] = map[string]interface{}{"name": priority}
+	}
+	if assignee := getStringParam(params, "assignee", ""); assignee != "" {
+		fields["assignee"] = map[string]interface{}{"accountId": assignee}
+	}
+	if labels := getStringSlice(params, "labels"); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+	if custom, ok := params["custom_fields"].(map[string]interface{}); ok {
+		for key, value := range custom {
+			fields[key] = value
+		}
+	}
+
+	created, err := p.callAPI("POST", "/issue", map[string]interface{}{"fields": fields})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	key, _ := created["key"].(string)
+	return map[string]interface{}{
+		"issue_key": key,
+		"id":        created["id"],
+		"url":       p.browseURL(key),
+	}, nil
+}
+
+func (p *JiraPlugin) getIssue(params map[string]interface{}) (map[string]interface{}, error) {
+	key := getStringParam(params, "issue_key", "")
+	if key == "" {
+		return map[string]interface{}{"error": "issue_key is required"}, nil
+	}
+
+	path := "/issue/" + url.PathEscape(key)
+	if fields := getStringSlice(params, "fields"); len(fields) > 0 {
+		path += "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+
+	issue, err := p.callAPI("GET", path, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return p.issueResult(issue), nil
+}
+
+func (p *JiraPlugin) updateIssue(params map[string]interface{}) (map[string]interface{}, error) {
+	key := getStringParam(params, "issue_key", "")
+	if key == "" {
+		return map[string]interface{}{"error": "issue_key is required"}, nil
+	}
+
+	fields, ok := params["fields"].(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		return map[string]interface{}{"error": "fields is required"}, nil
+	}
+	// API v3 only accepts rich text fields in Atlassian Document Format
+	if description, ok := fields["description"].(string); ok {
+		fields["description"] = textToADF(description)
+	}
+
+	if _, err := p.callAPI("PUT", "/issue/"+url.PathEscape(key), map[string]interface{}{"fields": fields}); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"issue_key": key,
+	}, nil
+}
+
+func (p *JiraPlugin) transitionIssue(params map[string]interface{}) (map[string]interface{}, error) {
+	key := getStringParam(params, "issue_key", "")
+	if key == "" {
+		return map[string]interface{}{"error": "issue_key is required"}, nil
+	}
+
+	name := getStringParam(params, "transition_name", "")
+	id := getStringParam(params, "transition_id", "")
+	if id == "" {
+		if n, ok := params["transition_id"].(float64); ok {
+			id = fmt.Sprintf("%d", int(n))
+		}
+	}
+	if name == "" && id == "" {
+		return map[string]interface{}{"error": "transition_name or transition_id is required"}, nil
+	}
+
+	transitionsPath := "/issue/" + url.PathEscape(key) + "/transitions"
+	available, err := p.callAPI("GET", transitionsPath, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	// Match the transition itself or the status it leads to, since workflows name them differently
+	var chosen map[string]interface{}
+	var names []string
+	transitions, _ := available["transitions"].([]interface{})
+	for _, item := range transitions {
+		transition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transitionID, _ := transition["id"].(string)
+		transitionName, _ := transition["name"].(string)
+		targetName := nestedString(transition, "to", "name")
+		names = append(names, transitionName)
+
+		if (id != "" && transitionID == id) ||
+			(id == "" && (strings.EqualFold(transitionName, name) || strings.EqualFold(targetName, name))) {
+			chosen = transition
+			break
+		}
+	}
+	if chosen == nil {
+		wanted := name
+		if id != "" {
+			wanted = id
+		}
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("transition %q not available for %s (available: %s)", wanted, key, strings.Join(names, ", ")),
+		}, nil
+	}
+
+	body := map[string]interface{}{
+		"transition": map[string]interface{}{"id": chosen["id"]},
+	}
+	if comment := getStringParam(params, "comment", ""); comment != "" {
+		body["update"] = map[string]interface{}{
+			"comment": []interface{}{
+				map[string]interface{}{"add": map[string]interface{}{"body": textToADF(comment)}},
+			},
+		}
+	}
+
+	if _, err := p.callAPI("POST", transitionsPath, body); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"success":       true,
+		"issue_key":     key,
+		"transition_id": chosen["id"],
+		"status":        nestedString(chosen, "to", "name"),
+	}, nil
+}
+
+func (p *JiraPlugin) addComment(params map[string]interface{}) (map[string]interface{}, error) {
+	key := getStringParam(params, "issue_key", "")
+	body := getStringParam(params, "body", "")
+	if key == "" || body == "" {
+		return map[string]interface{}{"error": "issue_key and body are required"}, nil
+	}
+
+	comment, err := p.callAPI("POST", "/issue/"+url.PathEscape(key)+"/comment", map[string]interface{}{
+		"body": textToADF(body),
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"comment_id": comment["id"],
+		"issue_key":  key,
+	}, nil
+}
+
+func (p *JiraPlugin) search(params map[string]interface{}) (map[string]interface{}, error) {
+	jql := getStringParam(params, "jql", "")
+	if jql == "" {
+		return map[string]interface{}{"error": "jql is required"}, nil
+	}
+
+	body := map[string]interface{}{
+		"jql":        jql,
+		"maxResults": getIntParam(params, "max_results", 50),
+	}
+	if fields := getStringSlice(params, "fields"); len(fields) > 0 {
+		body["fields"] = fields
+	}
+
+	found, err := p.callAPI("POST", "/search", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	issues := []interface{}{}
+	items, _ := found["issues"].([]interface{})
+	for _, item := range items {
+		if issue, ok := item.(map[string]interface{}); ok {
+			issues = append(issues, p.issueResult(issue))
+		}
+	}
+
+	return map[string]interface{}{
+		"issues": issues,
+		"count":  len(issues),
+		"total":  found["total"],
+	}, nil
+}
+
+// issueResult flattens the commonly used fields of an issue while keeping the full fields map
+func (p *JiraPlugin) issueResult(issue map[string]interface{}) map[string]interface{} {
+	key, _ := issue["key"].(string)
+	fields, _ := issue["fields"].(map[string]interface{})
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	summary, _ := fields["summary"].(string)
+
+	return map[string]interface{}{
+		"issue_key":   key,
+		"summary":     summary,
+		"status":      nestedString(fields, "status", "name"),
+		"description": adfToText(fields["description"]),
+		"url":         p.browseURL(key),
+		"fields":      fields,
+	}
+}
+
+func (p *JiraPlugin) browseURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return p.baseURL + "/browse/" + key
+}
+
+// callAPI sends a JSON request to /rest/api/3 and decodes the JSON object it returns
+func (p *JiraPlugin) callAPI(method, path string, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+"/rest/api/3"+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.SetBasicAuth(p.email, p.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, apiError(resp.StatusCode, result, data)
+	}
+	return result, nil
+}
+
+// apiError combines Jira's errorMessages list and per-field errors map into one message
+func apiError(status int, result map[string]interface{}, raw []byte) error {
+	var parts []string
+	if messages, ok := result["errorMessages"].([]interface{}); ok {
+		for _, msg := range messages {
+			parts = append(parts, fmt.Sprintf("%v", msg))
+		}
+	}
+	if fieldErrors, ok := result["errors"].(map[string]interface{}); ok {
+		for field, msg := range fieldErrors {
+			parts = append(parts, fmt.Sprintf("%s: %v", field, msg))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, strings.TrimSpace(string(raw)))
+	}
+	return fmt.Errorf("Jira API error (%d): %s", status, strings.Join(parts, "; "))
+}
+
+// textToADF wraps plain text in an Atlassian Document Format document, one paragraph per line
+func textToADF(text string) map[string]interface{} {
+	content := []interface{}{}
+	for _, line := range strings.Split(text, "\n") {
+		paragraph := map[string]interface{}{"type": "paragraph", "content": []interface{}{}}
+		if line != "" {
+			paragraph["content"] = []interface{}{
+				map[string]interface{}{"type": "text", "text": line},
+			}
+		}
+		content = append(content, paragraph)
+	}
+	return map[string]interface{}{
+		"type":    "doc",
+		"version": 1,
+		"content": content,
+	}
+}
+
+// adfToText extracts the text of an Atlassian Document Format node, separating blocks by newlines
+func adfToText(node interface{}) string {
+	switch n := node.(type) {
+	case string:
+		return n
+	case map[string]interface{}:
+		if text, ok := n["text"].(string); ok {
+			return text
+		}
+		if n["type"] == "hardBreak" {
+			return "\n"
+		}
+		children, _ := n["content"].([]interface{})
+		var b strings.Builder
+		for i, child := range children {
+			if i > 0 && n["type"] == "doc" {
+				b.WriteString("\n")
+			}
+			b.WriteString(adfToText(child))
+		}
+		return b.String()
+	default:
+		return ""
+	}
+}
+
+func nestedString(m map[string]interface{}, keys ...string) string {
+	var current interface{} = m
+	for _, key := range keys {
+		next, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = next[key]
+	}
+	s, _ := current.(string)
+	return s
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewJiraPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
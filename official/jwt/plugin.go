@@ -0,0 +1,461 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type JWTPlugin struct{}
+
+func NewJWTPlugin() *JWTPlugin {
+	return &JWTPlugin{}
+}
+
+func (p *JWTPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "jwt",
+		Version:     "1.0.0",
+		Description: "JSON Web Token creation, verification, decoding and refresh",
+		Author:      "Corynth Team",
+		Tags:        []string{"jwt", "auth", "token", "security", "oauth"},
+	}
+}
+
+func (p *JWTPlugin) GetActions() map[string]ActionSpec {
+	return map[string]ActionSpec{
+		"create": {
+			Description: "Sign a new token",
+			Inputs: withAlgorithm(map[string]IOSpec{
+				"claims":           {Type: "object", Required: false, Description: "Token claims, e.g. sub, iss, aud and custom fields"},
+				"secret":           {Type: "string", Required: false, Description: "HMAC secret for HS algorithms (defaults to JWT_SECRET)"},
+				"private_key_path": {Type: "string", Required: false, Description: "PEM private key for RS and ES algorithms"},
+				"ttl_seconds":      {Type: "number", Required: false, Default: 3600, Description: "Lifetime used to set exp, unless claims already has exp; 0 omits exp"},
+			}),
+			Outputs: tokenOutputs(),
+		},
+		"verify": {
+			Description: "Verify a token's signature and claims",
+			Inputs: withAlgorithm(map[string]IOSpec{
+				"token":           {Type: "string", Required: true, Description: "Token to verify"},
+				"secret":          {Type: "string", Required: false, Description: "HMAC secret for HS algorithms (defaults to JWT_SECRET)"},
+				"public_key_path": {Type: "string", Required: false, Description: "PEM public key or certificate for RS and ES algorithms"},
+				"issuer":          {Type: "string", Required: false, Description: "Required iss claim"},
+				"audience":        {Type: "string", Required: false, Description: "Audience that must be listed in the aud claim"},
+				"required_claims": {Type: "object", Required: false, Description: "Claims that must be present with these values"},
+				"leeway_seconds":  {Type: "number", Required: false, Default: 0, Description: "Clock skew allowed when checking exp, nbf and iat"},
+			}),
+			Outputs: map[string]IOSpec{
+				"valid":      {Type: "boolean", Description: "Whether the token is valid"},
+				"reason":     {Type: "string", Description: "Why the token is invalid"},
+				"header":     {Type: "object", Description: "Token header"},
+				"claims":     {Type: "object", Description: "Token claims"},
+				"expires_at": {Type: "string", Description: "Expiry time (RFC3339), empty when the token has no exp"},
+			},
+		},
+		"decode": {
+			Description: "Decode a token without verifying it",
+			Inputs: map[string]IOSpec{
+				"token": {Type: "string", Required: true, Description: "Token to decode"},
+			},
+			Outputs: map[string]IOSpec{
+				"header":     {Type: "object", Description: "Token header"},
+				"claims":     {Type: "object", Description: "Token claims"},
+				"expires_at": {Type: "string", Description: "Expiry time (RFC3339), empty when the token has no exp"},
+				"expired":    {Type: "boolean", Description: "Whether exp is in the past"},
+			},
+		},
+		"refresh": {
+			Description: "Re-sign a valid, unexpired token with a new expiry",
+			Inputs: withAlgorithm(map[string]IOSpec{
+				"token":            {Type: "string", Required: true, Description: "Token to refresh"},
+				"secret":           {Type: "string", Required: false, Description: "HMAC secret for HS algorithms (defaults to JWT_SECRET)"},
+				"private_key_path": {Type: "string", Required: false, Description: "PEM private key for RS and ES algorithms, also used to verify the token"},
+				"ttl_seconds":      {Type: "number", Required: false, Default: 3600, Description: "Lifetime of the refreshed token"},
+			}),
+			Outputs: tokenOutputs(),
+		},
+	}
+}
+
+// withAlgorithm adds the signing algorithm input shared by the signing and verifying actions
+func withAlgorithm(inputs map[string]IOSpec) map[string]IOSpec {
+	inputs["algorithm"] = IOSpec{
+		Type:        "string",
+		Required:    false,
+		Default:     "HS256",
+		Description: "Signing algorithm: HS256/384/512, RS256/384/512 or ES256/384/512",
+	}
+	return inputs
+}
+
+func tokenOutputs() map[string]IOSpec {
+	return map[string]IOSpec{
+		"token":      {Type: "string", Description: "Signed token"},
+		"expires_at": {Type: "string", Description: "Expiry time (RFC3339), empty when the token has no exp"},
+		"claims":     {Type: "object", Description: "Claims in the token"},
+	}
+}
+
+func (p *JWTPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	switch action {
+	case "create":
+		return p.create(params)
+	case "verify":
+		return p.verify(params)
+	case "decode":
+		return p.decode(params)
+	case "refresh":
+		return p.refresh(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *JWTPlugin) create(params map[string]interface{}) (map[string]interface{}, error) {
+	method, err := signingMethod(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	key, err := signingKey(method, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	claims := jwt.MapClaims{}
+	if input, ok := params["claims"].(map[string]interface{}); ok {
+		for k, v := range input {
+			claims[k] = v
+		}
+	}
+
+	now := time.Now()
+	if _, ok := claims["iat"]; !ok {
+		claims["iat"] = numericDate(now)
+	}
+	// An explicit ttl_seconds wins over an exp passed in claims
+	ttl, hasTTL := params["ttl_seconds"].(float64)
+	if _, hasExp := claims["exp"]; !hasExp || hasTTL {
+		if !hasTTL {
+			ttl = 3600
+		}
+		delete(claims, "exp")
+		if ttl > 0 {
+			claims["exp"] = numericDate(now.Add(time.Duration(ttl * float64(time.Second))))
+		}
+	}
+
+	return signClaims(method, key, claims)
+}
+
+func (p *JWTPlugin) verify(params map[string]interface{}) (map[string]interface{}, error) {
+	tokenString := getStringParam(params, "token", "")
+	if tokenString == "" {
+		return map[string]interface{}{"error": "token is required"}, nil
+	}
+	method, err := signingMethod(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	key, err := verificationKey(method, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{method.Alg()}), jwt.WithIssuedAt()}
+	if leeway, ok := params["leeway_seconds"].(float64); ok && leeway > 0 {
+		options = append(options, jwt.WithLeeway(time.Duration(leeway*float64(time.Second))))
+	}
+	if issuer := getStringParam(params, "issuer", ""); issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience := getStringParam(params, "audience", ""); audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.NewParser(options...).ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	})
+	if err == nil {
+		if required, ok := params["required_claims"].(map[string]interface{}); ok {
+			err = checkRequiredClaims(claims, required)
+		}
+	}
+
+	result := map[string]interface{}{
+		"valid":      err == nil,
+		"claims":     claims,
+		"expires_at": expiresAt(claims),
+	}
+	if token != nil {
+		result["header"] = token.Header
+	}
+	if err != nil {
+		result["reason"] = err.Error()
+	}
+	return result, nil
+}
+
+func (p *JWTPlugin) decode(params map[string]interface{}) (map[string]interface{}, error) {
+	tokenString := getStringParam(params, "token", "")
+	if tokenString == "" {
+		return map[string]interface{}{"error": "token is required"}, nil
+	}
+
+	claims := jwt.MapClaims{}
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to decode token: %v", err)}, nil
+	}
+
+	expired := false
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		expired = time.Now().After(exp.Time)
+	}
+
+	return map[string]interface{}{
+		"header":     token.Header,
+		"claims":     claims,
+		"expires_at": expiresAt(claims),
+		"expired":    expired,
+	}, nil
+}
+
+func (p *JWTPlugin) refresh(params map[string]interface{}) (map[string]interface{}, error) {
+	tokenString := getStringParam(params, "token", "")
+	if tokenString == "" {
+		return map[string]interface{}{"error": "token is required"}, nil
+	}
+	method, err := signingMethod(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	key, err := signingKey(method, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	// The token is verified with the signing key (or its public half) before it is re-signed
+	verifyKey := key
+	if signer, ok := key.(crypto.Signer); ok {
+		verifyKey = signer.Public()
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.NewParser(jwt.WithValidMethods([]string{method.Alg()})).ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return verifyKey, nil
+	})
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("cannot refresh token: %v", err)}, nil
+	}
+
+	ttl := float64(3600)
+	if val, ok := params["ttl_seconds"].(float64); ok && val > 0 {
+		ttl = val
+	}
+	now := time.Now()
+	claims["iat"] = numericDate(now)
+	claims["exp"] = numericDate(now.Add(time.Duration(ttl * float64(time.Second))))
+
+	return signClaims(method, key, claims)
+}
+
+func signClaims(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) (map[string]interface{}, error) {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to sign token: %v", err)}, nil
+	}
+
+	return map[string]interface{}{
+		"token":      token,
+		"expires_at": expiresAt(claims),
+		"claims":     claims,
+	}, nil
+}
+
+func signingMethod(params map[string]interface{}) (jwt.SigningMethod, error) {
+	alg := strings.ToUpper(getStringParam(params, "algorithm", "HS256"))
+	switch method := jwt.GetSigningMethod(alg).(type) {
+	case *jwt.SigningMethodHMAC, *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		return method, nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s (use HS256/384/512, RS256/384/512 or ES256/384/512)", alg)
+}
+
+func hmacSecret(params map[string]interface{}) ([]byte, error) {
+	secret := getStringParam(params, "secret", os.Getenv("JWT_SECRET"))
+	if secret == "" {
+		return nil, errors.New("secret is required for HS algorithms (or set JWT_SECRET)")
+	}
+	return []byte(secret), nil
+}
+
+func signingKey(method jwt.SigningMethod, params map[string]interface{}) (interface{}, error) {
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		return hmacSecret(params)
+	}
+
+	path := getStringParam(params, "private_key_path", "")
+	if path == "" {
+		return nil, fmt.Errorf("private_key_path is required for %s", method.Alg())
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %v", err)
+	}
+
+	var key interface{}
+	if _, ok := method.(*jwt.SigningMethodRSA); ok {
+		key, err = jwt.ParseRSAPrivateKeyFromPEM(pem)
+	} else {
+		key, err = jwt.ParseECPrivateKeyFromPEM(pem)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key for %s: %v", method.Alg(), err)
+	}
+	return key, nil
+}
+
+func verificationKey(method jwt.SigningMethod, params map[string]interface{}) (interface{}, error) {
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		return hmacSecret(params)
+	}
+
+	path := getStringParam(params, "public_key_path", "")
+	if path == "" {
+		return nil, fmt.Errorf("public_key_path is required for %s", method.Alg())
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %v", err)
+	}
+
+	var key interface{}
+	if _, ok := method.(*jwt.SigningMethodRSA); ok {
+		key, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+	} else {
+		key, err = jwt.ParseECPublicKeyFromPEM(pem)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key for %s: %v", method.Alg(), err)
+	}
+	return key, nil
+}
+
+// checkRequiredClaims reports the first required claim that is missing or differs. A string
+// requirement is also satisfied by an array claim containing it, as is common for aud.
+func checkRequiredClaims(claims jwt.MapClaims, required map[string]interface{}) error {
+	for name, want := range required {
+		got, ok := claims[name]
+		if !ok {
+			return fmt.Errorf("token is missing required claim %q", name)
+		}
+		if reflect.DeepEqual(got, want) {
+			continue
+		}
+		if list, ok := got.([]interface{}); ok {
+			found := false
+			for _, item := range list {
+				if reflect.DeepEqual(item, want) {
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		}
+		return fmt.Errorf("claim %q has value %v, expected %v", name, got, want)
+	}
+	return nil
+}
+
+// numericDate returns t as seconds since the epoch. MapClaims only reads time claims stored as float64.
+func numericDate(t time.Time) float64 {
+	return float64(t.Unix())
+}
+
+func expiresAt(claims jwt.MapClaims) string {
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return ""
+	}
+	return exp.UTC().Format(time.RFC3339)
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewJWTPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
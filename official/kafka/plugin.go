@@ -0,0 +1,747 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type KafkaPlugin struct{}
+
+func NewKafkaPlugin() *KafkaPlugin {
+	return &KafkaPlugin{}
+}
+
+func (p *KafkaPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "kafka",
+		Version:     "1.0.0",
+		Description: "Apache Kafka message production, consumption and topic management",
+		Author:      "Corynth Team",
+		Tags:        []string{"kafka", "messaging", "streaming", "events", "queue"},
+	}
+}
+
+func (p *KafkaPlugin) GetActions() map[string]ActionSpec {
+	withBrokers := func(inputs map[string]IOSpec) map[string]IOSpec {
+		inputs["brokers"] = IOSpec{Type: "string", Required: false, Description: "Comma-separated broker addresses (defaults to KAFKA_BROKERS)"}
+		return inputs
+	}
+
+	return map[string]ActionSpec{
+		"produce": {
+			Description: "Produce a message to a topic",
+			Inputs: withBrokers(map[string]IOSpec{
+				"topic":     {Type: "string", Required: true, Description: "Topic name"},
+				"key":       {Type: "string", Required: false, Description: "Message key"},
+				"value":     {Type: "string", Required: true, Description: "Message value; non-string values are sent as JSON"},
+				"headers":   {Type: "object", Required: false, Description: "Message headers"},
+				"partition": {Type: "number", Required: false, Description: "Target partition (chosen from the key hash by default)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"partition": {Type: "number", Description: "Partition the message was written to"},
+				"offset":    {Type: "number", Description: "Offset of the message"},
+			},
+		},
+		"produce_batch": {
+			Description: "Produce several messages to a topic",
+			Inputs: withBrokers(map[string]IOSpec{
+				"topic":    {Type: "string", Required: true, Description: "Topic name"},
+				"messages": {Type: "array", Required: true, Description: "Messages with value and optional key, headers and partition"},
+			}),
+			Outputs: map[string]IOSpec{
+				"results": {Type: "array", Description: "Partition and offset of each message, in input order"},
+				"count":   {Type: "number", Description: "Number of messages produced"},
+			},
+		},
+		"consume": {
+			Description: "Consume messages from a topic",
+			Inputs: withBrokers(map[string]IOSpec{
+				"topic":          {Type: "string", Required: true, Description: "Topic name"},
+				"group_id":       {Type: "string", Required: false, Description: "Consumer group; offsets are committed when set"},
+				"max_messages":   {Type: "number", Required: false, Default: 100, Description: "Stop after this many messages"},
+				"timeout_ms":     {Type: "number", Required: false, Default: 5000, Description: "Stop after this long"},
+				"from_beginning": {Type: "boolean", Required: false, Default: false, Description: "Start at the oldest offset (for groups, only when no offset is committed)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"messages": {Type: "array", Description: "Messages with topic, partition, offset, key, value, headers and timestamp"},
+				"count":    {Type: "number", Description: "Number of messages consumed"},
+			},
+		},
+		"create_topic": {
+			Description: "Create a topic",
+			Inputs: withBrokers(map[string]IOSpec{
+				"name":               {Type: "string", Required: true, Description: "Topic name"},
+				"partitions":         {Type: "number", Required: false, Default: 1, Description: "Number of partitions"},
+				"replication_factor": {Type: "number", Required: false, Default: 1, Description: "Replicas per partition"},
+				"config":             {Type: "object", Required: false, Description: "Topic configuration, e.g. {\"retention.ms\": \"86400000\"}"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the topic was created"},
+			},
+		},
+		"delete_topic": {
+			Description: "Delete a topic",
+			Inputs: withBrokers(map[string]IOSpec{
+				"name": {Type: "string", Required: true, Description: "Topic name"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the topic was deleted"},
+			},
+		},
+		"list_topics": {
+			Description: "List topics",
+			Inputs:      withBrokers(map[string]IOSpec{}),
+			Outputs: map[string]IOSpec{
+				"topics": {Type: "array", Description: "Topics with name, partitions and replication_factor"},
+			},
+		},
+		"describe_consumer_group": {
+			Description: "Describe a consumer group's members, offsets and lag",
+			Inputs: withBrokers(map[string]IOSpec{
+				"group_id": {Type: "string", Required: true, Description: "Consumer group ID"},
+			}),
+			Outputs: map[string]IOSpec{
+				"state":     {Type: "string", Description: "Group state, e.g. Stable or Empty"},
+				"members":   {Type: "array", Description: "Members with their client and partition assignments"},
+				"offsets":   {Type: "array", Description: "Committed offset and lag per topic partition"},
+				"total_lag": {Type: "number", Description: "Sum of lag across partitions"},
+			},
+		},
+	}
+}
+
+func (p *KafkaPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(sarama.Client, map[string]interface{}) (map[string]interface{}, error)
+
+	switch action {
+	case "produce":
+		run = p.produce
+	case "produce_batch":
+		run = p.produceBatch
+	case "consume":
+		run = p.consume
+	case "create_topic":
+		run = p.createTopic
+	case "delete_topic":
+		run = p.deleteTopic
+	case "list_topics":
+		run = p.listTopics
+	case "describe_consumer_group":
+		run = p.describeConsumerGroup
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	brokers := splitBrokers(getStringParam(params, "brokers", os.Getenv("KAFKA_BROKERS")))
+	if len(brokers) == 0 {
+		return map[string]interface{}{"error": "brokers or KAFKA_BROKERS is required"}, nil
+	}
+
+	config := sarama.NewConfig()
+	config.ClientID = "corynth"
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Partitioner = newExplicitPartitioner
+	if getBoolParam(params, "from_beginning", false) {
+		config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	} else {
+		config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to connect to brokers: %v", err)}, nil
+	}
+	defer client.Close()
+
+	result, err := run(client, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func (p *KafkaPlugin) produce(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	topic := getStringParam(params, "topic", "")
+	if topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+
+	msg, err := buildMessage(topic, params)
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer: %v", err)
+	}
+	defer producer.Close()
+
+	partition, offset, err := producer.SendMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("produce failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"partition": partition,
+		"offset":    offset,
+	}, nil
+}
+
+func (p *KafkaPlugin) produceBatch(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	topic := getStringParam(params, "topic", "")
+	if topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+
+	items, ok := params["messages"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("messages is required")
+	}
+
+	msgs := make([]*sarama.ProducerMessage, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("message %d must be an object", i)
+		}
+		msg, err := buildMessage(topic, fields)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %v", i, err)
+		}
+		msgs[i] = msg
+	}
+
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer: %v", err)
+	}
+	defer producer.Close()
+
+	if err := producer.SendMessages(msgs); err != nil {
+		return nil, fmt.Errorf("produce failed: %v", err)
+	}
+
+	results := make([]map[string]interface{}, len(msgs))
+	for i, msg := range msgs {
+		results[i] = map[string]interface{}{
+			"partition": msg.Partition,
+			"offset":    msg.Offset,
+		}
+	}
+
+	return map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+	}, nil
+}
+
+// consume reads until max_messages arrive or timeout_ms elapses. With a group_id the
+// messages are consumed through the group and their offsets committed; otherwise every
+// partition is read directly and no offsets are stored.
+func (p *KafkaPlugin) consume(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	topic := getStringParam(params, "topic", "")
+	if topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+
+	collector := &messageCollector{max: getIntParam(params, "max_messages", 100)}
+	if collector.max <= 0 {
+		return nil, fmt.Errorf("max_messages must be positive")
+	}
+
+	timeout := time.Duration(getIntParam(params, "timeout_ms", 5000)) * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	collector.done = cancel
+
+	var err error
+	if groupID := getStringParam(params, "group_id", ""); groupID != "" {
+		err = consumeGroup(ctx, client, groupID, topic, collector)
+	} else {
+		err = consumePartitions(ctx, client, topic, collector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages := collector.result()
+	return map[string]interface{}{
+		"messages": messages,
+		"count":    len(messages),
+	}, nil
+}
+
+func consumeGroup(ctx context.Context, client sarama.Client, groupID, topic string, collector *messageCollector) error {
+	group, err := sarama.NewConsumerGroupFromClient(groupID, client)
+	if err != nil {
+		return fmt.Errorf("failed to join consumer group: %v", err)
+	}
+	defer group.Close()
+
+	// Consume returns at every rebalance, so keep rejoining until done
+	for ctx.Err() == nil {
+		if err := group.Consume(ctx, []string{topic}, collector); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("consume failed: %v", err)
+		}
+	}
+	return nil
+}
+
+func consumePartitions(ctx context.Context, client sarama.Client, topic string, collector *messageCollector) error {
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to get partitions: %v", err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	var wg sync.WaitGroup
+	for _, partition := range partitions {
+		pc, err := consumer.ConsumePartition(topic, partition, client.Config().Consumer.Offsets.Initial)
+		if err != nil {
+			return fmt.Errorf("failed to consume partition %d: %v", partition, err)
+		}
+		defer pc.Close()
+
+		wg.Add(1)
+		go func(pc sarama.PartitionConsumer) {
+			defer wg.Done()
+			for {
+				select {
+				case msg := <-pc.Messages():
+					if _, more := collector.add(msg); !more {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(pc)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// messageCollector gathers consumed messages up to max and cancels the consume when full.
+// It is also the consumer group handler, marking each collected message for commit.
+type messageCollector struct {
+	mu       sync.Mutex
+	max      int
+	messages []map[string]interface{}
+	done     context.CancelFunc
+}
+
+// add reports whether msg was kept and whether there is room for more
+func (c *messageCollector) add(msg *sarama.ConsumerMessage) (added, more bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.messages) >= c.max {
+		return false, false
+	}
+	c.messages = append(c.messages, messageResult(msg))
+	if len(c.messages) >= c.max {
+		c.done()
+		return true, false
+	}
+	return true, true
+}
+
+func (c *messageCollector) result() []map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages == nil {
+		return []map[string]interface{}{}
+	}
+	return c.messages
+}
+
+func (c *messageCollector) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (c *messageCollector) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (c *messageCollector) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			// A message arriving after the collector is full is not committed
+			added, more := c.add(msg)
+			if added {
+				session.MarkMessage(msg, "")
+			}
+			if !more {
+				return nil
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (p *KafkaPlugin) createTopic(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	detail := &sarama.TopicDetail{
+		NumPartitions:     int32(getIntParam(params, "partitions", 1)),
+		ReplicationFactor: int16(getIntParam(params, "replication_factor", 1)),
+	}
+	if config, ok := params["config"].(map[string]interface{}); ok && len(config) > 0 {
+		detail.ConfigEntries = make(map[string]*string, len(config))
+		for key, value := range config {
+			s := fmt.Sprintf("%v", value)
+			detail.ConfigEntries[key] = &s
+		}
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin client: %v", err)
+	}
+
+	if err := admin.CreateTopic(name, detail, false); err != nil {
+		return nil, fmt.Errorf("create topic failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+func (p *KafkaPlugin) deleteTopic(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	name := getStringParam(params, "name", "")
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin client: %v", err)
+	}
+
+	if err := admin.DeleteTopic(name); err != nil {
+		return nil, fmt.Errorf("delete topic failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+func (p *KafkaPlugin) listTopics(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin client: %v", err)
+	}
+
+	details, err := admin.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("list topics failed: %v", err)
+	}
+
+	names := make([]string, 0, len(details))
+	for name := range details {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	topics := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		topics[i] = map[string]interface{}{
+			"name":               name,
+			"partitions":         details[name].NumPartitions,
+			"replication_factor": details[name].ReplicationFactor,
+		}
+	}
+
+	return map[string]interface{}{
+		"topics": topics,
+	}, nil
+}
+
+func (p *KafkaPlugin) describeConsumerGroup(client sarama.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	groupID := getStringParam(params, "group_id", "")
+	if groupID == "" {
+		return nil, fmt.Errorf("group_id is required")
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin client: %v", err)
+	}
+
+	groups, err := admin.DescribeConsumerGroups([]string{groupID})
+	if err != nil {
+		return nil, fmt.Errorf("describe consumer group failed: %v", err)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("consumer group %s not found", groupID)
+	}
+	group := groups[0]
+	if group.Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("describe consumer group failed: %v", group.Err)
+	}
+
+	members := []map[string]interface{}{}
+	for _, member := range group.Members {
+		assignments := map[string][]int32{}
+		if assignment, err := member.GetMemberAssignment(); err == nil && assignment != nil {
+			assignments = assignment.Topics
+		}
+		members = append(members, map[string]interface{}{
+			"member_id":   member.MemberId,
+			"client_id":   member.ClientId,
+			"client_host": member.ClientHost,
+			"assignments": assignments,
+		})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i]["member_id"].(string) < members[j]["member_id"].(string)
+	})
+
+	// A nil partition map asks for the offsets of every partition the group has committed
+	committed, err := admin.ListConsumerGroupOffsets(groupID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list consumer group offsets failed: %v", err)
+	}
+
+	offsets := []map[string]interface{}{}
+	var totalLag int64
+	for topic, partitions := range committed.Blocks {
+		for partition, block := range partitions {
+			if block.Offset < 0 {
+				continue
+			}
+			entry := map[string]interface{}{
+				"topic":     topic,
+				"partition": partition,
+				"offset":    block.Offset,
+			}
+			if newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest); err == nil {
+				lag := newest - block.Offset
+				if lag < 0 {
+					lag = 0
+				}
+				entry["log_end_offset"] = newest
+				entry["lag"] = lag
+				totalLag += lag
+			}
+			offsets = append(offsets, entry)
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool {
+		if offsets[i]["topic"] != offsets[j]["topic"] {
+			return offsets[i]["topic"].(string) < offsets[j]["topic"].(string)
+		}
+		return offsets[i]["partition"].(int32) < offsets[j]["partition"].(int32)
+	})
+
+	return map[string]interface{}{
+		"group_id":  group.GroupId,
+		"state":     group.State,
+		"protocol":  group.Protocol,
+		"members":   members,
+		"offsets":   offsets,
+		"total_lag": totalLag,
+	}, nil
+}
+
+// explicitPartitioner honours a partition requested on the message and hashes the key otherwise
+type explicitPartitioner struct {
+	hash sarama.Partitioner
+}
+
+func newExplicitPartitioner(topic string) sarama.Partitioner {
+	return &explicitPartitioner{hash: sarama.NewHashPartitioner(topic)}
+}
+
+func (p *explicitPartitioner) Partition(msg *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if partition, ok := msg.Metadata.(int32); ok {
+		if partition < 0 || partition >= numPartitions {
+			return -1, fmt.Errorf("partition %d out of range (topic has %d)", partition, numPartitions)
+		}
+		return partition, nil
+	}
+	return p.hash.Partition(msg, numPartitions)
+}
+
+func (p *explicitPartitioner) RequiresConsistency() bool {
+	return true
+}
+
+// buildMessage creates a producer message from value, key, headers and partition fields
+func buildMessage(topic string, fields map[string]interface{}) (*sarama.ProducerMessage, error) {
+	value, ok := fields["value"]
+	if !ok {
+		return nil, fmt.Errorf("value is required")
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.StringEncoder(toText(value)),
+	}
+	if key, ok := fields["key"]; ok && key != nil {
+		msg.Key = sarama.StringEncoder(toText(key))
+	}
+	if headers, ok := fields["headers"].(map[string]interface{}); ok {
+		for name, value := range headers {
+			msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(name), Value: []byte(toText(value))})
+		}
+	}
+	if partition, ok := fields["partition"].(float64); ok {
+		msg.Metadata = int32(partition)
+	}
+	return msg, nil
+}
+
+func messageResult(msg *sarama.ConsumerMessage) map[string]interface{} {
+	headers := map[string]string{}
+	for _, header := range msg.Headers {
+		if header != nil {
+			headers[string(header.Key)] = string(header.Value)
+		}
+	}
+
+	result := map[string]interface{}{
+		"topic":     msg.Topic,
+		"partition": msg.Partition,
+		"offset":    msg.Offset,
+		"key":       nil,
+		"value":     string(msg.Value),
+		"headers":   headers,
+		"timestamp": msg.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+	if msg.Key != nil {
+		result["key"] = string(msg.Key)
+	}
+	return result
+}
+
+// toText sends strings as-is and anything else as JSON
+func toText(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func splitBrokers(value string) []string {
+	brokers := []string{}
+	for _, broker := range strings.Split(value, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewKafkaPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
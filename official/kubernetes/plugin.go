@@ -7,8 +7,11 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Metadata struct {
@@ -53,14 +56,22 @@ func (p *KubernetesPlugin) GetActions() map[string]ActionSpec {
 		"apply": {
 			Description: "Apply Kubernetes manifests",
 			Inputs: map[string]IOSpec{
-				"manifest":  {Type: "string", Required: false, Description: "YAML manifest content"},
-				"file":      {Type: "string", Required: false, Description: "Path to manifest file"},
-				"namespace": {Type: "string", Required: false, Description: "Target namespace"},
-				"dry_run":   {Type: "boolean", Required: false, Default: false, Description: "Dry run mode"},
+				"manifest":        {Type: "string", Required: false, Description: "YAML manifest content"},
+				"file":            {Type: "string", Required: false, Description: "Path to manifest file"},
+				"kustomize_dir":   {Type: "string", Required: false, Description: "Directory containing a kustomization.yaml (applied with -k)"},
+				"namespace":       {Type: "string", Required: false, Description: "Target namespace"},
+				"dry_run":         {Type: "boolean", Required: false, Default: false, Description: "Dry run mode"},
+				"server_side":     {Type: "boolean", Required: false, Default: false, Description: "Use server-side apply"},
+				"field_manager":   {Type: "string", Required: false, Description: "Field manager name recorded for the applied fields"},
+				"force_conflicts": {Type: "boolean", Required: false, Default: false, Description: "Take ownership of fields owned by other managers (server-side apply only)"},
+				"prune":           {Type: "boolean", Required: false, Default: false, Description: "Delete resources matching the selector that are no longer in the manifest"},
+				"selector":        {Type: "string", Required: false, Description: "Label selector for the applied resources (required with prune)"},
 			},
 			Outputs: map[string]IOSpec{
 				"success":   {Type: "boolean", Description: "Operation success"},
 				"resources": {Type: "array", Description: "Applied resources"},
+				"pruned":    {Type: "array", Description: "Pruned resources"},
+				"conflicts": {Type: "array", Description: "Field ownership conflicts as {manager, field} when server-side apply fails"},
 			},
 		},
 		"get": {
@@ -141,17 +152,74 @@ func (p *KubernetesPlugin) GetActions() map[string]ActionSpec {
 		"delete": {
 			Description: "Delete Kubernetes resources",
 			Inputs: map[string]IOSpec{
-				"resource":  {Type: "string", Required: true, Description: "Resource type"},
-				"name":      {Type: "string", Required: false, Description: "Resource name"},
-				"file":      {Type: "string", Required: false, Description: "Manifest file to delete"},
-				"namespace": {Type: "string", Required: false, Description: "Target namespace"},
-				"selector":  {Type: "string", Required: false, Description: "Label selector"},
-				"force":     {Type: "boolean", Required: false, Default: false, Description: "Force deletion"},
+				"resource":      {Type: "string", Required: true, Description: "Resource type (not needed with file or kustomize_dir)"},
+				"name":          {Type: "string", Required: false, Description: "Resource name"},
+				"file":          {Type: "string", Required: false, Description: "Manifest file to delete"},
+				"kustomize_dir": {Type: "string", Required: false, Description: "Directory containing a kustomization.yaml whose resources are deleted"},
+				"namespace":     {Type: "string", Required: false, Description: "Target namespace"},
+				"selector":      {Type: "string", Required: false, Description: "Label selector"},
+				"force":         {Type: "boolean", Required: false, Default: false, Description: "Force deletion"},
 			},
 			Outputs: map[string]IOSpec{
 				"success": {Type: "boolean", Description: "Deletion success"},
 			},
 		},
+		"kustomize_build": {
+			Description: "Render a kustomization without applying it",
+			Inputs: map[string]IOSpec{
+				"kustomize_dir": {Type: "string", Required: true, Description: "Directory containing a kustomization.yaml"},
+			},
+			Outputs: map[string]IOSpec{
+				"manifest": {Type: "string", Description: "Rendered YAML manifest"},
+			},
+		},
+		"events": {
+			Description: "List cluster events, newest first",
+			Inputs: map[string]IOSpec{
+				"namespace":      {Type: "string", Required: false, Description: "Target namespace"},
+				"all_namespaces": {Type: "boolean", Required: false, Default: false, Description: "List events in all namespaces"},
+				"resource":       {Type: "string", Required: false, Description: "Kind of the involved object, e.g. 'pod' or 'deployment'"},
+				"name":           {Type: "string", Required: false, Description: "Name of the involved object"},
+				"types":          {Type: "array", Required: false, Description: "Event types to include, e.g. ['Warning']"},
+			},
+			Outputs: map[string]IOSpec{
+				"events": {Type: "array", Description: "Events with type, reason, message, count, last_timestamp and object"},
+			},
+		},
+		"label":    metadataActionSpec("Add, update or remove labels on resources", "labels", "labeled"),
+		"annotate": metadataActionSpec("Add, update or remove annotations on resources", "annotations", "annotated"),
+		"top": {
+			Description: "Show CPU and memory usage of pods or nodes",
+			Inputs: map[string]IOSpec{
+				"resource":       {Type: "string", Required: true, Description: "Either 'pods' or 'nodes'"},
+				"name":           {Type: "string", Required: false, Description: "Specific pod or node name"},
+				"namespace":      {Type: "string", Required: false, Description: "Target namespace (pods only)"},
+				"all_namespaces": {Type: "boolean", Required: false, Default: false, Description: "Report pods in all namespaces"},
+				"selector":       {Type: "string", Required: false, Description: "Label selector"},
+			},
+			Outputs: map[string]IOSpec{
+				"metrics": {Type: "array", Description: "Objects with name, cpu (millicores) and memory (bytes), plus namespace for pods and cpu_percent/memory_percent for nodes"},
+			},
+		},
+	}
+}
+
+func metadataActionSpec(description, noun, verb string) ActionSpec {
+	return ActionSpec{
+		Description: description,
+		Inputs: map[string]IOSpec{
+			"resource":  {Type: "string", Required: true, Description: "Resource type"},
+			"name":      {Type: "string", Required: false, Description: "Resource name"},
+			"selector":  {Type: "string", Required: false, Description: "Label selector (instead of name)"},
+			"namespace": {Type: "string", Required: false, Description: "Target namespace"},
+			"values":    {Type: "object", Required: true, Description: "Key/value " + noun + " to set; a null or empty value removes the key"},
+			"overwrite": {Type: "boolean", Required: false, Default: false, Description: "Allow existing " + noun + " to be changed"},
+		},
+		Outputs: map[string]IOSpec{
+			"success":   {Type: "boolean", Description: "Whether kubectl succeeded"},
+			"count":     {Type: "number", Description: "Number of resources " + verb},
+			"resources": {Type: "array", Description: "kubectl output line for each affected resource"},
+		},
 	}
 }
 
@@ -173,6 +241,16 @@ func (p *KubernetesPlugin) Execute(action string, params map[string]interface{})
 		return p.portForward(params)
 	case "delete":
 		return p.deleteResources(params)
+	case "top":
+		return p.topResources(params)
+	case "kustomize_build":
+		return p.kustomizeBuild(params)
+	case "events":
+		return p.getEvents(params)
+	case "label":
+		return p.setMetadata(params, "label")
+	case "annotate":
+		return p.setMetadata(params, "annotate")
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
@@ -211,8 +289,21 @@ func (p *KubernetesPlugin) runKubectlCommand(args []string, inputData string) (s
 func (p *KubernetesPlugin) applyManifest(params map[string]interface{}) (map[string]interface{}, error) {
 	manifest, _ := params["manifest"].(string)
 	filePath, _ := params["file"].(string)
+	kustomizeDir, _ := params["kustomize_dir"].(string)
 	namespace, _ := params["namespace"].(string)
 	dryRun := getBoolParam(params, "dry_run", false)
+	serverSide := getBoolParam(params, "server_side", false)
+	fieldManager, _ := params["field_manager"].(string)
+	forceConflicts := getBoolParam(params, "force_conflicts", false)
+	prune := getBoolParam(params, "prune", false)
+	selector, _ := params["selector"].(string)
+
+	if forceConflicts && !serverSide {
+		return map[string]interface{}{"error": "force_conflicts requires server_side"}, nil
+	}
+	if prune && selector == "" {
+		return map[string]interface{}{"error": "selector is required when prune is enabled"}, nil
+	}
 
 	args := []string{"apply"}
 
@@ -220,7 +311,26 @@ func (p *KubernetesPlugin) applyManifest(params map[string]interface{}) (map[str
 		args = append(args, "-n", namespace)
 	}
 	if dryRun {
-		args = append(args, "--dry-run=client")
+		if serverSide {
+			args = append(args, "--dry-run=server")
+		} else {
+			args = append(args, "--dry-run=client")
+		}
+	}
+	if serverSide {
+		args = append(args, "--server-side")
+	}
+	if fieldManager != "" {
+		args = append(args, "--field-manager", fieldManager)
+	}
+	if forceConflicts {
+		args = append(args, "--force-conflicts")
+	}
+	if selector != "" {
+		args = append(args, "-l", selector)
+	}
+	if prune {
+		args = append(args, "--prune")
 	}
 
 	var inputData string
@@ -229,24 +339,39 @@ func (p *KubernetesPlugin) applyManifest(params map[string]interface{}) (map[str
 		inputData = manifest
 	} else if filePath != "" {
 		args = append(args, "-f", filePath)
+	} else if kustomizeDir != "" {
+		if err := checkKustomizeDir(kustomizeDir); err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		args = append(args, "-k", kustomizeDir)
 	} else {
-		return map[string]interface{}{"error": "Either manifest or file parameter is required"}, nil
+		return map[string]interface{}{"error": "Either manifest, file or kustomize_dir parameter is required"}, nil
 	}
 
 	stdout, stderr, err := p.runKubectlCommand(args, inputData)
 
 	if err != nil {
-		return map[string]interface{}{
+		result := map[string]interface{}{
 			"success": false,
 			"error":   stderr,
-		}, nil
+		}
+		if conflicts := parseApplyConflicts(stderr); len(conflicts) > 0 {
+			result["conflicts"] = conflicts
+		}
+		return result, nil
 	}
 
 	resources := []string{}
+	pruned := []string{}
 	scanner := bufio.NewScanner(strings.NewReader(stdout))
 	for scanner.Scan() {
 		line := scanner.Text()
-		if line != "" && (strings.Contains(line, "configured") || strings.Contains(line, "created") || strings.Contains(line, "unchanged")) {
+		if line == "" {
+			continue
+		}
+		if strings.HasSuffix(line, " pruned") || strings.Contains(line, " pruned (") {
+			pruned = append(pruned, line)
+		} else if strings.Contains(line, "configured") || strings.Contains(line, "created") || strings.Contains(line, "unchanged") || strings.Contains(line, "serverside-applied") {
 			resources = append(resources, line)
 		}
 	}
@@ -254,9 +379,48 @@ func (p *KubernetesPlugin) applyManifest(params map[string]interface{}) (map[str
 	return map[string]interface{}{
 		"success":   true,
 		"resources": resources,
+		"pruned":    pruned,
 	}, nil
 }
 
+// parseApplyConflicts extracts the field ownership conflicts from a failed server-side apply. kubectl reports a
+// single conflict as `conflict with "helm" using apps/v1: .spec.replicas` and several conflicts with the same
+// manager as `conflicts with "helm" using apps/v1:` followed by one `- .field` line each.
+func parseApplyConflicts(stderr string) []map[string]interface{} {
+	conflicts := []map[string]interface{}{}
+	manager := ""
+	scanner := bufio.NewScanner(strings.NewReader(stderr))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if manager != "" && strings.HasPrefix(line, "- ") {
+			conflicts = append(conflicts, map[string]interface{}{"manager": manager, "field": line[2:]})
+			continue
+		}
+		manager = ""
+
+		if idx := strings.Index(line, `conflicts with "`); idx >= 0 {
+			manager = quotedPrefix(line[idx+len(`conflicts with `):])
+		} else if idx := strings.Index(line, `conflict with "`); idx >= 0 {
+			rest := line[idx+len(`conflict with `):]
+			if sep := strings.LastIndex(rest, ": "); sep >= 0 {
+				conflicts = append(conflicts, map[string]interface{}{"manager": quotedPrefix(rest), "field": rest[sep+2:]})
+			}
+		}
+	}
+	return conflicts
+}
+
+// quotedPrefix returns the contents of the double-quoted string at the start of s
+func quotedPrefix(s string) string {
+	if unquoted, err := strconv.QuotedPrefix(s); err == nil {
+		if value, err := strconv.Unquote(unquoted); err == nil {
+			return value
+		}
+	}
+	return ""
+}
+
 func (p *KubernetesPlugin) getResources(params map[string]interface{}) (map[string]interface{}, error) {
 	resource, ok := params["resource"].(string)
 	if !ok || resource == "" {
@@ -475,27 +639,33 @@ func (p *KubernetesPlugin) portForward(params map[string]interface{}) (map[strin
 }
 
 func (p *KubernetesPlugin) deleteResources(params map[string]interface{}) (map[string]interface{}, error) {
-	resource, ok := params["resource"].(string)
-	if !ok || resource == "" {
-		return map[string]interface{}{"error": "resource is required"}, nil
-	}
-
+	resource, _ := params["resource"].(string)
 	name, _ := params["name"].(string)
 	filePath, _ := params["file"].(string)
+	kustomizeDir, _ := params["kustomize_dir"].(string)
 	namespace, _ := params["namespace"].(string)
 	selector, _ := params["selector"].(string)
 	force := getBoolParam(params, "force", false)
 
+	if resource == "" && filePath == "" && kustomizeDir == "" {
+		return map[string]interface{}{"error": "resource is required"}, nil
+	}
+
 	args := []string{"delete"}
 
 	if filePath != "" {
 		args = append(args, "-f", filePath)
+	} else if kustomizeDir != "" {
+		if err := checkKustomizeDir(kustomizeDir); err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		args = append(args, "-k", kustomizeDir)
 	} else if name != "" {
 		args = append(args, resource, name)
 	} else if selector != "" {
 		args = append(args, resource, "-l", selector)
 	} else {
-		return map[string]interface{}{"error": "name, file, or selector parameter is required"}, nil
+		return map[string]interface{}{"error": "name, file, kustomize_dir, or selector parameter is required"}, nil
 	}
 
 	if namespace != "" {
@@ -512,6 +682,425 @@ func (p *KubernetesPlugin) deleteResources(params map[string]interface{}) (map[s
 	}, nil
 }
 
+func (p *KubernetesPlugin) kustomizeBuild(params map[string]interface{}) (map[string]interface{}, error) {
+	kustomizeDir, _ := params["kustomize_dir"].(string)
+	if kustomizeDir == "" {
+		return map[string]interface{}{"error": "kustomize_dir is required"}, nil
+	}
+	if err := checkKustomizeDir(kustomizeDir); err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	stdout, stderr, err := p.runKubectlCommand([]string{"kustomize", kustomizeDir}, "")
+
+	if err != nil {
+		return map[string]interface{}{"error": stderr}, nil
+	}
+
+	return map[string]interface{}{
+		"manifest": stdout,
+	}, nil
+}
+
+// checkKustomizeDir makes sure dir holds a kustomization file, so a wrong path fails with a clear message
+// instead of kubectl's lower-level error.
+func checkKustomizeDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("kustomize_dir %s does not exist", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("kustomize_dir %s is not a directory", dir)
+	}
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no kustomization.yaml found in %s; kustomize_dir must be the directory containing it", dir)
+}
+
+func (p *KubernetesPlugin) topResources(params map[string]interface{}) (map[string]interface{}, error) {
+	resource := getStringParam(params, "resource", "")
+	switch resource {
+	case "pod", "pods", "po":
+		resource = "pods"
+	case "node", "nodes", "no":
+		resource = "nodes"
+	default:
+		return map[string]interface{}{"error": "resource must be 'pods' or 'nodes'"}, nil
+	}
+
+	name, _ := params["name"].(string)
+	namespace, _ := params["namespace"].(string)
+	allNamespaces := getBoolParam(params, "all_namespaces", false)
+	selector, _ := params["selector"].(string)
+
+	args := []string{"top", resource}
+
+	if name != "" {
+		args = append(args, name)
+	}
+	if resource == "pods" {
+		if namespace != "" {
+			args = append(args, "-n", namespace)
+		} else if allNamespaces {
+			args = append(args, "--all-namespaces")
+		}
+	}
+	if selector != "" {
+		args = append(args, "-l", selector)
+	}
+
+	stdout, stderr, err := p.runKubectlCommand(args, "")
+
+	if err != nil {
+		if isMetricsUnavailable(stderr) {
+			return map[string]interface{}{
+				"error": "metrics are not available: metrics-server is not installed or not ready in the cluster (" + strings.TrimSpace(stderr) + ")",
+			}, nil
+		}
+		return map[string]interface{}{"error": stderr}, nil
+	}
+
+	return map[string]interface{}{"metrics": parseTopOutput(stdout)}, nil
+}
+
+// isMetricsUnavailable reports whether kubectl top failed because the metrics API is missing
+func isMetricsUnavailable(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, marker := range []string{"metrics api not available", "metrics not available yet", "heapster"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	// An unregistered or unhealthy metrics.k8s.io APIService; permission errors are reported as-is
+	return strings.Contains(lower, "metrics.k8s.io") &&
+		(strings.Contains(lower, "could not find the requested resource") || strings.Contains(lower, "unable to handle the request"))
+}
+
+// parseTopOutput converts kubectl top's table into metric objects. Columns are located by
+// header so the pod, node and all-namespaces layouts (and older CPU%/newer CPU(%) headers) all parse.
+func parseTopOutput(output string) []map[string]interface{} {
+	metrics := []map[string]interface{}{}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return metrics
+	}
+
+	header := strings.Fields(lines[0])
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != len(header) {
+			continue
+		}
+
+		metric := map[string]interface{}{}
+		for i, column := range header {
+			value := fields[i]
+			switch {
+			case column == "NAMESPACE":
+				metric["namespace"] = value
+			case column == "NAME":
+				metric["name"] = value
+			case strings.HasPrefix(column, "CPU") && strings.Contains(column, "%"):
+				metric["cpu_percent"] = parsePercent(value)
+			case strings.HasPrefix(column, "CPU"):
+				metric["cpu"] = parseCPUQuantity(value)
+			case strings.HasPrefix(column, "MEMORY") && strings.Contains(column, "%"):
+				metric["memory_percent"] = parsePercent(value)
+			case strings.HasPrefix(column, "MEMORY"):
+				metric["memory"] = parseMemoryQuantity(value)
+			}
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
+
+// parseCPUQuantity converts a CPU quantity such as 250m or 2 to millicores, or nil if unknown
+func parseCPUQuantity(value string) interface{} {
+	scale := 1000.0
+	switch {
+	case strings.HasSuffix(value, "n"):
+		scale, value = 1e-6, strings.TrimSuffix(value, "n")
+	case strings.HasSuffix(value, "u"):
+		scale, value = 1e-3, strings.TrimSuffix(value, "u")
+	case strings.HasSuffix(value, "m"):
+		scale, value = 1, strings.TrimSuffix(value, "m")
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return int64(n * scale)
+}
+
+var memorySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+// parseMemoryQuantity converts a memory quantity such as 128Mi or 1G to bytes, or nil if unknown
+func parseMemoryQuantity(value string) interface{} {
+	multiplier := 1.0
+	for _, s := range memorySuffixes {
+		if strings.HasSuffix(value, s.suffix) {
+			multiplier, value = s.multiplier, strings.TrimSuffix(value, s.suffix)
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return int64(n * multiplier)
+}
+
+func parsePercent(value string) interface{} {
+	n, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// setMetadata runs kubectl label or annotate. Null or empty values become "key-", which removes the key.
+func (p *KubernetesPlugin) setMetadata(params map[string]interface{}, command string) (map[string]interface{}, error) {
+	resource, ok := params["resource"].(string)
+	if !ok || resource == "" {
+		return map[string]interface{}{"error": "resource is required"}, nil
+	}
+
+	values, ok := params["values"].(map[string]interface{})
+	if !ok || len(values) == 0 {
+		return map[string]interface{}{"error": "values is required"}, nil
+	}
+
+	name, _ := params["name"].(string)
+	selector, _ := params["selector"].(string)
+	namespace, _ := params["namespace"].(string)
+
+	args := []string{command, resource}
+
+	if name != "" {
+		args = append(args, name)
+	} else if selector != "" {
+		args = append(args, "-l", selector)
+	} else {
+		return map[string]interface{}{"error": "name or selector parameter is required"}, nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := values[key]
+		if value == nil || value == "" {
+			args = append(args, key+"-")
+		} else {
+			args = append(args, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	if getBoolParam(params, "overwrite", false) {
+		args = append(args, "--overwrite")
+	}
+
+	stdout, stderr, err := p.runKubectlCommand(args, "")
+
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   stderr,
+		}, nil
+	}
+
+	// kubectl prints "<kind>/<name> labeled" (or "not labeled" when nothing changed) per resource
+	verb := "annotated"
+	if command == "label" {
+		verb = "labeled"
+	}
+	resources := []string{}
+	count := 0
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		resources = append(resources, line)
+		if strings.HasSuffix(line, " "+verb) && !strings.HasSuffix(line, " not "+verb) {
+			count++
+		}
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"count":     count,
+		"resources": resources,
+	}, nil
+}
+
+// eventKinds maps common resource names to the kind used in involvedObject field selectors
+var eventKinds = map[string]string{
+	"pod": "Pod", "pods": "Pod", "po": "Pod",
+	"deployment": "Deployment", "deployments": "Deployment", "deploy": "Deployment",
+	"replicaset": "ReplicaSet", "replicasets": "ReplicaSet", "rs": "ReplicaSet",
+	"statefulset": "StatefulSet", "statefulsets": "StatefulSet", "sts": "StatefulSet",
+	"daemonset": "DaemonSet", "daemonsets": "DaemonSet", "ds": "DaemonSet",
+	"job": "Job", "jobs": "Job",
+	"cronjob": "CronJob", "cronjobs": "CronJob", "cj": "CronJob",
+	"service": "Service", "services": "Service", "svc": "Service",
+	"node": "Node", "nodes": "Node", "no": "Node",
+	"persistentvolumeclaim": "PersistentVolumeClaim", "persistentvolumeclaims": "PersistentVolumeClaim", "pvc": "PersistentVolumeClaim",
+	"persistentvolume": "PersistentVolume", "persistentvolumes": "PersistentVolume", "pv": "PersistentVolume",
+	"horizontalpodautoscaler": "HorizontalPodAutoscaler", "horizontalpodautoscalers": "HorizontalPodAutoscaler", "hpa": "HorizontalPodAutoscaler",
+	"ingress": "Ingress", "ingresses": "Ingress", "ing": "Ingress",
+}
+
+func (p *KubernetesPlugin) getEvents(params map[string]interface{}) (map[string]interface{}, error) {
+	namespace, _ := params["namespace"].(string)
+	allNamespaces := getBoolParam(params, "all_namespaces", false)
+	resource, _ := params["resource"].(string)
+	name, _ := params["name"].(string)
+
+	types := map[string]bool{}
+	if list, ok := params["types"].([]interface{}); ok {
+		for _, t := range list {
+			if s, ok := t.(string); ok && s != "" {
+				types[strings.ToLower(s)] = true
+			}
+		}
+	}
+
+	args := []string{"get", "events"}
+
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	} else if allNamespaces {
+		args = append(args, "--all-namespaces")
+	}
+
+	selectors := []string{}
+	if name != "" {
+		selectors = append(selectors, "involvedObject.name="+name)
+	}
+	if resource != "" {
+		kind, ok := eventKinds[strings.ToLower(resource)]
+		if !ok {
+			// Assume an unknown resource is already a kind, e.g. 'Certificate'
+			kind = strings.ToUpper(resource[:1]) + resource[1:]
+		}
+		selectors = append(selectors, "involvedObject.kind="+kind)
+	}
+	// Field selectors cannot OR values, so several types are filtered after fetching
+	if len(types) == 1 {
+		for t := range types {
+			selectors = append(selectors, "type="+strings.ToUpper(t[:1])+t[1:])
+		}
+	}
+	if len(selectors) > 0 {
+		args = append(args, "--field-selector", strings.Join(selectors, ","))
+	}
+
+	args = append(args, "-o", "json")
+
+	stdout, stderr, err := p.runKubectlCommand(args, "")
+
+	if err != nil {
+		return map[string]interface{}{"error": stderr}, nil
+	}
+
+	var data struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &data); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to parse events: %v", err)}, nil
+	}
+
+	events := []map[string]interface{}{}
+	for _, item := range data.Items {
+		eventType, _ := item["type"].(string)
+		if len(types) > 0 && !types[strings.ToLower(eventType)] {
+			continue
+		}
+
+		involved, _ := item["involvedObject"].(map[string]interface{})
+		kind, _ := involved["kind"].(string)
+		objectName, _ := involved["name"].(string)
+
+		event := map[string]interface{}{
+			"type":           eventType,
+			"reason":         item["reason"],
+			"message":        item["message"],
+			"count":          eventCount(item),
+			"last_timestamp": eventTimestamp(item),
+			"object":         strings.ToLower(kind) + "/" + objectName,
+			"namespace":      involved["namespace"],
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return parseEventTime(events[i]).After(parseEventTime(events[j]))
+	})
+
+	return map[string]interface{}{"events": events}, nil
+}
+
+// parseEventTime returns the zero time for events without a timestamp, sorting them last
+func parseEventTime(event map[string]interface{}) time.Time {
+	timestamp, _ := event["last_timestamp"].(string)
+	t, _ := time.Parse(time.RFC3339Nano, timestamp)
+	return t
+}
+
+// eventTimestamp returns when an event last occurred. Events recorded through the
+// events.k8s.io API leave lastTimestamp empty and set eventTime or series instead.
+func eventTimestamp(item map[string]interface{}) string {
+	if series, ok := item["series"].(map[string]interface{}); ok {
+		if t, ok := series["lastObservedTime"].(string); ok && t != "" {
+			return t
+		}
+	}
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		if t, ok := item[field].(string); ok && t != "" {
+			return t
+		}
+	}
+	if metadata, ok := item["metadata"].(map[string]interface{}); ok {
+		if t, ok := metadata["creationTimestamp"].(string); ok {
+			return t
+		}
+	}
+	return ""
+}
+
+func eventCount(item map[string]interface{}) int {
+	if count, ok := item["count"].(float64); ok && count > 0 {
+		return int(count)
+	}
+	if series, ok := item["series"].(map[string]interface{}); ok {
+		if count, ok := series["count"].(float64); ok && count > 0 {
+			return int(count)
+		}
+	}
+	return 1
+}
+
 // Helper functions
 func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
 	if val, ok := params[key].(bool); ok {
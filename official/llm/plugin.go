@@ -1,13 +1,16 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -45,32 +48,177 @@ type Action struct {
 
 // OpenAIRequest represents an OpenAI API request
 type OpenAIRequest struct {
-	Model       string              `json:"model"`
-	Messages    []map[string]string `json:"messages"`
-	MaxTokens   int                 `json:"max_tokens,omitempty"`
-	Temperature float64             `json:"temperature,omitempty"`
+	Model       string                   `json:"model"`
+	Messages    []map[string]interface{} `json:"messages"`
+	MaxTokens   int                      `json:"max_tokens,omitempty"`
+	Temperature float64                  `json:"temperature,omitempty"`
+	Stream      bool                     `json:"stream,omitempty"`
+	Tools       []interface{}            `json:"tools,omitempty"`
+	ToolChoice  interface{}              `json:"tool_choice,omitempty"`
+	SafePrompt  bool                     `json:"safe_prompt,omitempty"`
+	RandomSeed  *int                     `json:"random_seed,omitempty"`
+	TopP        *float64                 `json:"top_p,omitempty"`
+	Stop        []string                 `json:"stop,omitempty"`
+	// ResponseFormat is {"type": "json_object"} in JSON mode
+	ResponseFormat map[string]interface{} `json:"response_format,omitempty"`
+}
+
+// OpenAIToolCall represents a function call requested by the model
+type OpenAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
 }
 
 // OpenAIResponse represents an OpenAI API response
 type OpenAIResponse struct {
 	Choices []struct {
 		Message struct {
-			Content string `json:"content"`
+			Content   string           `json:"content"`
+			ToolCalls []OpenAIToolCall `json:"tool_calls,omitempty"`
 		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
 	Usage map[string]interface{} `json:"usage,omitempty"`
 }
 
+// OpenAIStreamChunk represents one server-sent event from a streaming OpenAI response
+type OpenAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// OpenAIEmbeddingRequest represents an OpenAI embeddings request
+type OpenAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// OpenAIEmbeddingResponse represents an OpenAI embeddings response
+type OpenAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Model string `json:"model"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// OllamaEmbeddingResponse represents an Ollama embeddings response
+type OllamaEmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// AnthropicRequest represents an Anthropic Messages API request
+type AnthropicRequest struct {
+	Model         string        `json:"model"`
+	Messages      []interface{} `json:"messages"`
+	MaxTokens     int           `json:"max_tokens"`
+	Temperature   *float64      `json:"temperature,omitempty"`
+	TopP          *float64      `json:"top_p,omitempty"`
+	StopSequences []string      `json:"stop_sequences,omitempty"`
+	System        string        `json:"system,omitempty"`
+	Stream        bool          `json:"stream,omitempty"`
+}
+
+// AnthropicUsage represents token usage reported by the Anthropic API
+type AnthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// AnthropicResponse represents an Anthropic Messages API response
+type AnthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      AnthropicUsage `json:"usage"`
+}
+
+// AnthropicStreamEvent represents one server-sent event from a streaming Anthropic response
+type AnthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage AnthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage AnthropicUsage `json:"usage"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GeminiRequest represents a Gemini generateContent request
+type GeminiRequest struct {
+	Contents         []interface{}          `json:"contents"`
+	GenerationConfig map[string]interface{} `json:"generationConfig,omitempty"`
+	SafetySettings   []interface{}          `json:"safetySettings,omitempty"`
+}
+
+// GeminiResponse represents a Gemini generateContent response (or one streamed chunk of it)
+type GeminiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+		FinishReason  string        `json:"finishReason"`
+		SafetyRatings []interface{} `json:"safetyRatings"`
+	} `json:"candidates"`
+	UsageMetadata map[string]interface{} `json:"usageMetadata"`
+}
+
 // OllamaRequest represents an Ollama API request
 type OllamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model   string                 `json:"model"`
+	Prompt  string                 `json:"prompt"`
+	System  string                 `json:"system,omitempty"`
+	Stream  bool                   `json:"stream"`
+	Options map[string]interface{} `json:"options,omitempty"`
 }
 
 // OllamaResponse represents an Ollama API response
 type OllamaResponse struct {
-	Response string `json:"response"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+}
+
+// OllamaChatRequest represents an Ollama /api/chat request
+type OllamaChatRequest struct {
+	Model    string                   `json:"model"`
+	Messages []map[string]interface{} `json:"messages"`
+	Stream   bool                     `json:"stream"`
+	Format   string                   `json:"format,omitempty"`
+}
+
+// OllamaChatResponse represents an Ollama /api/chat response
+type OllamaChatResponse struct {
+	Message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"message"`
+	Done            bool   `json:"done"`
+	DoneReason      string `json:"done_reason"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
 // GetMetadata returns plugin metadata
@@ -113,10 +261,34 @@ func (p *LLMPlugin) GetActions() map[string]Action {
 					Default:     0.7,
 					Description: "Temperature",
 				},
+				"system": {
+					Type:        "string",
+					Required:    false,
+					Description: "System prompt prepended as a system-role message",
+				},
+				"stop": {
+					Type:        "array",
+					Required:    false,
+					Description: "Stop sequences (a single string is also accepted)",
+				},
+				"top_p": {
+					Type:        "number",
+					Required:    false,
+					Description: "Nucleus sampling probability mass",
+				},
+				"response_format": {
+					Type:        "string",
+					Required:    false,
+					Default:     "text",
+					Description: "text or json; json requests a JSON object and parses it into the json output",
+				},
 			},
 			Outputs: map[string]ActionOutput{
-				"text":  {Type: "string"},
-				"usage": {Type: "object"},
+				"text":       {Type: "string"},
+				"usage":      {Type: "object"},
+				"json":       {Type: "object"},
+				"valid_json": {Type: "boolean"},
+				"json_error": {Type: "string"},
 			},
 		},
 		"chat": {
@@ -125,7 +297,78 @@ func (p *LLMPlugin) GetActions() map[string]Action {
 				"messages": {
 					Type:        "array",
 					Required:    true,
-					Description: "Message history",
+					Description: "Message history (only the new messages when using a session)",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "gpt-3.5-turbo",
+					Description: "Model name (llama2 by default for Ollama)",
+				},
+				"provider": {
+					Type:        "string",
+					Required:    false,
+					Default:     "openai",
+					Description: "openai or ollama",
+				},
+				"system": {
+					Type:        "string",
+					Required:    false,
+					Description: "System prompt prepended as a system-role message",
+				},
+				"session": {
+					Type:        "string",
+					Required:    false,
+					Description: "Session ID; history is persisted in the OS temp dir and prepended to each call",
+				},
+				"response_format": {
+					Type:        "string",
+					Required:    false,
+					Default:     "text",
+					Description: "text or json; json requests a JSON object and parses it into the json output",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"response":      {Type: "string"},
+				"usage":         {Type: "object"},
+				"session_id":    {Type: "string"},
+				"message_count": {Type: "number"},
+				"json":          {Type: "object"},
+				"valid_json":    {Type: "boolean"},
+				"json_error":    {Type: "string"},
+			},
+		},
+		"clear_session": {
+			Description: "Delete the persisted history of a chat session",
+			Inputs: map[string]ActionInput{
+				"session": {
+					Type:        "string",
+					Required:    true,
+					Description: "Session ID",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"session_id": {Type: "string"},
+				"cleared":    {Type: "boolean"},
+			},
+		},
+		"generate_with_tools": {
+			Description: "Generate a response that may call predefined tools (OpenAI function calling)",
+			Inputs: map[string]ActionInput{
+				"prompt": {
+					Type:        "string",
+					Required:    true,
+					Description: "Input prompt",
+				},
+				"tools": {
+					Type:        "array",
+					Required:    true,
+					Description: "OpenAI function schemas (bare or wrapped in {type: function, function: ...})",
+				},
+				"tool_choice": {
+					Type:        "string",
+					Required:    false,
+					Description: "auto, required, none, or the name of a function the model must call",
 				},
 				"model": {
 					Type:        "string",
@@ -133,116 +376,1502 @@ func (p *LLMPlugin) GetActions() map[string]Action {
 					Default:     "gpt-3.5-turbo",
 					Description: "Model name",
 				},
+				"system": {
+					Type:        "string",
+					Required:    false,
+					Description: "System prompt prepended as a system-role message",
+				},
+				"session_id": {
+					Type:        "string",
+					Required:    false,
+					Description: "Session to continue (a new one is created when omitted)",
+				},
 			},
 			Outputs: map[string]ActionOutput{
-				"response": {Type: "string"},
-				"usage":    {Type: "object"},
+				"text":          {Type: "string"},
+				"tool_calls":    {Type: "array"},
+				"finish_reason": {Type: "string"},
+				"usage":         {Type: "object"},
+				"session_id":    {Type: "string"},
+				"message_count": {Type: "number"},
 			},
 		},
-		"ollama": {
-			Description: "Use local Ollama model",
+		"submit_tool_result": {
+			Description: "Return a tool call result to the model and continue the session",
+			Inputs: map[string]ActionInput{
+				"session_id": {
+					Type:        "string",
+					Required:    true,
+					Description: "Session returned by generate_with_tools",
+				},
+				"tool_call_id": {
+					Type:        "string",
+					Required:    true,
+					Description: "ID of the tool call being answered",
+				},
+				"result": {
+					Type:        "object",
+					Required:    true,
+					Description: "Tool result, any JSON value (non-string values are sent as JSON)",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"text":               {Type: "string"},
+				"tool_calls":         {Type: "array"},
+				"pending_tool_calls": {Type: "array"},
+				"finish_reason":      {Type: "string"},
+				"usage":              {Type: "object"},
+				"session_id":         {Type: "string"},
+				"message_count":      {Type: "number"},
+			},
+		},
+		"generate_stream": {
+			Description: "Generate text with a streaming response, writing partial output to a file as it arrives",
 			Inputs: map[string]ActionInput{
 				"prompt": {
 					Type:        "string",
 					Required:    true,
 					Description: "Input prompt",
 				},
+				"backend": {
+					Type:        "string",
+					Required:    false,
+					Default:     "openai",
+					Description: "Backend to stream from (openai or ollama)",
+				},
 				"model": {
 					Type:        "string",
 					Required:    false,
-					Default:     "llama2",
-					Description: "Ollama model name",
+					Default:     "gpt-3.5-turbo",
+					Description: "Model name (defaults to llama2 for ollama)",
+				},
+				"max_tokens": {
+					Type:        "number",
+					Required:    false,
+					Default:     150,
+					Description: "Max tokens",
+				},
+				"temperature": {
+					Type:        "number",
+					Required:    false,
+					Default:     0.7,
+					Description: "Temperature",
+				},
+				"stream_file": {
+					Type:        "string",
+					Required:    false,
+					Description: "File to write partial output to (defaults to a new temporary file)",
 				},
 			},
 			Outputs: map[string]ActionOutput{
-				"response": {Type: "string"},
+				"text":        {Type: "string"},
+				"chunks":      {Type: "number"},
+				"stream_file": {Type: "string"},
 			},
 		},
+		"claude": {
+			Description: "Generate a response with the Anthropic Claude Messages API",
+			Inputs: map[string]ActionInput{
+				"messages": {
+					Type:        "array",
+					Required:    true,
+					Description: "Conversation messages with role/content (a prompt string may be given instead)",
+				},
+				"prompt": {
+					Type:        "string",
+					Required:    false,
+					Description: "Single user prompt, used when messages is not provided",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "claude-3-haiku-20240307",
+					Description: "Claude model name",
+				},
+				"max_tokens": {
+					Type:        "number",
+					Required:    false,
+					Default:     1024,
+					Description: "Max tokens",
+				},
+				"temperature": {
+					Type:        "number",
+					Required:    false,
+					Description: "Temperature",
+				},
+				"system": {
+					Type:        "string",
+					Required:    false,
+					Description: "System prompt",
+				},
+				"stop": {
+					Type:        "array",
+					Required:    false,
+					Description: "Stop sequences (a single string is also accepted)",
+				},
+				"top_p": {
+					Type:        "number",
+					Required:    false,
+					Description: "Nucleus sampling probability mass",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"text":        {Type: "string"},
+				"stop_reason": {Type: "string"},
+				"usage":       {Type: "object"},
+			},
+		},
+		"claude_stream": {
+			Description: "Stream a Claude response, writing partial output to a file as it arrives",
+			Inputs: map[string]ActionInput{
+				"messages": {
+					Type:        "array",
+					Required:    true,
+					Description: "Conversation messages with role/content (a prompt string may be given instead)",
+				},
+				"prompt": {
+					Type:        "string",
+					Required:    false,
+					Description: "Single user prompt, used when messages is not provided",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "claude-3-haiku-20240307",
+					Description: "Claude model name",
+				},
+				"max_tokens": {
+					Type:        "number",
+					Required:    false,
+					Default:     1024,
+					Description: "Max tokens",
+				},
+				"temperature": {
+					Type:        "number",
+					Required:    false,
+					Description: "Temperature",
+				},
+				"system": {
+					Type:        "string",
+					Required:    false,
+					Description: "System prompt",
+				},
+				"stop": {
+					Type:        "array",
+					Required:    false,
+					Description: "Stop sequences (a single string is also accepted)",
+				},
+				"top_p": {
+					Type:        "number",
+					Required:    false,
+					Description: "Nucleus sampling probability mass",
+				},
+				"stream_file": {
+					Type:        "string",
+					Required:    false,
+					Description: "File to write partial output to (defaults to a new temporary file)",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"text":        {Type: "string"},
+				"chunks":      {Type: "number"},
+				"stream_file": {Type: "string"},
+				"stop_reason": {Type: "string"},
+				"usage":       {Type: "object"},
+			},
+		},
+		"gemini": {
+			Description: "Generate a response with the Google Gemini API",
+			Inputs: map[string]ActionInput{
+				"contents": {
+					Type:        "array",
+					Required:    true,
+					Description: "Gemini contents (role + parts); a prompt string may be given instead",
+				},
+				"prompt": {
+					Type:        "string",
+					Required:    false,
+					Description: "Single user prompt, used when contents is not provided",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "gemini-1.5-flash",
+					Description: "Gemini model name",
+				},
+				"temperature": {
+					Type:        "number",
+					Required:    false,
+					Description: "Temperature",
+				},
+				"max_output_tokens": {
+					Type:        "number",
+					Required:    false,
+					Description: "Max output tokens",
+				},
+				"safety_settings": {
+					Type:        "array",
+					Required:    false,
+					Description: "Safety settings (category/threshold objects)",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"text":           {Type: "string"},
+				"finish_reason":  {Type: "string"},
+				"usage_metadata": {Type: "object"},
+				"safety_ratings": {Type: "array"},
+			},
+		},
+		"gemini_stream": {
+			Description: "Stream a Gemini response, writing partial output to a file as it arrives",
+			Inputs: map[string]ActionInput{
+				"contents": {
+					Type:        "array",
+					Required:    true,
+					Description: "Gemini contents (role + parts); a prompt string may be given instead",
+				},
+				"prompt": {
+					Type:        "string",
+					Required:    false,
+					Description: "Single user prompt, used when contents is not provided",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "gemini-1.5-flash",
+					Description: "Gemini model name",
+				},
+				"temperature": {
+					Type:        "number",
+					Required:    false,
+					Description: "Temperature",
+				},
+				"max_output_tokens": {
+					Type:        "number",
+					Required:    false,
+					Description: "Max output tokens",
+				},
+				"safety_settings": {
+					Type:        "array",
+					Required:    false,
+					Description: "Safety settings (category/threshold objects)",
+				},
+				"stream_file": {
+					Type:        "string",
+					Required:    false,
+					Description: "File to write partial output to (defaults to a new temporary file)",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"text":           {Type: "string"},
+				"chunks":         {Type: "number"},
+				"stream_file":    {Type: "string"},
+				"finish_reason":  {Type: "string"},
+				"usage_metadata": {Type: "object"},
+				"safety_ratings": {Type: "array"},
+			},
+		},
+		"mistral": {
+			Description: "Chat completion with the Mistral AI API",
+			Inputs: map[string]ActionInput{
+				"messages": {
+					Type:        "array",
+					Required:    true,
+					Description: "Message history (a prompt string may be given instead)",
+				},
+				"prompt": {
+					Type:        "string",
+					Required:    false,
+					Description: "Single user prompt, used when messages is not provided",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "mistral-small-latest",
+					Description: "Mistral model name",
+				},
+				"temperature": {
+					Type:        "number",
+					Required:    false,
+					Description: "Temperature",
+				},
+				"max_tokens": {
+					Type:        "number",
+					Required:    false,
+					Description: "Max tokens",
+				},
+				"safe_prompt": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Prepend Mistral's safety system prompt",
+				},
+				"random_seed": {
+					Type:        "number",
+					Required:    false,
+					Description: "Seed for deterministic sampling",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"text":          {Type: "string"},
+				"finish_reason": {Type: "string"},
+				"usage":         {Type: "object"},
+			},
+		},
+		"embed": {
+			Description: "Generate vector embeddings for text",
+			Inputs: map[string]ActionInput{
+				"input": {
+					Type:        "string",
+					Required:    true,
+					Description: "Text to embed, or an array of texts (one embedding each)",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "text-embedding-3-small",
+					Description: "Embedding model (Ollama defaults to nomic-embed-text)",
+				},
+				"provider": {
+					Type:        "string",
+					Required:    false,
+					Default:     "openai",
+					Description: "Embedding provider: openai or ollama",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"embeddings": {Type: "array"},
+				"model":      {Type: "string"},
+				"usage":      {Type: "object"},
+			},
+		},
+		"ollama": {
+			Description: "Use local Ollama model",
+			Inputs: map[string]ActionInput{
+				"prompt": {
+					Type:        "string",
+					Required:    true,
+					Description: "Input prompt",
+				},
+				"model": {
+					Type:        "string",
+					Required:    false,
+					Default:     "llama2",
+					Description: "Ollama model name",
+				},
+				"system": {
+					Type:        "string",
+					Required:    false,
+					Description: "System prompt, overriding the one in the model's Modelfile",
+				},
+				"stop": {
+					Type:        "array",
+					Required:    false,
+					Description: "Stop sequences (a single string is also accepted)",
+				},
+				"top_p": {
+					Type:        "number",
+					Required:    false,
+					Description: "Nucleus sampling probability mass",
+				},
+			},
+			Outputs: map[string]ActionOutput{
+				"response": {Type: "string"},
+				"usage":    {Type: "object"},
+			},
+		},
+	}
+}
+
+// Execute performs the specified action
+func (p *LLMPlugin) Execute(action string, params map[string]interface{}) map[string]interface{} {
+	switch action {
+	case "generate":
+		return p.openaiGenerate(params)
+	case "chat":
+		return p.chat(params)
+	case "clear_session":
+		return p.clearSession(params)
+	case "generate_with_tools":
+		return p.generateWithTools(params)
+	case "submit_tool_result":
+		return p.submitToolResult(params)
+	case "generate_stream":
+		return p.generateStream(params)
+	case "claude":
+		return p.claudeGenerate(params)
+	case "claude_stream":
+		return p.claudeStream(params)
+	case "gemini":
+		return p.geminiGenerate(params)
+	case "gemini_stream":
+		return p.geminiStream(params)
+	case "mistral":
+		return p.mistralChat(params)
+	case "embed":
+		return p.embed(params)
+	case "ollama":
+		return p.ollamaGenerate(params)
+	default:
+		return map[string]interface{}{"error": fmt.Sprintf("Unknown action: %s", action)}
+	}
+}
+
+// openaiGenerate generates text using OpenAI API
+func (p *LLMPlugin) openaiGenerate(params map[string]interface{}) map[string]interface{} {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return map[string]interface{}{"error": "OPENAI_API_KEY not configured"}
+	}
+
+	prompt, ok := params["prompt"].(string)
+	if !ok {
+		return map[string]interface{}{"error": "prompt is required"}
+	}
+
+	model := "gpt-3.5-turbo"
+	if m, ok := params["model"].(string); ok {
+		model = m
+	}
+
+	maxTokens := 150
+	if mt, ok := params["max_tokens"]; ok {
+		switch v := mt.(type) {
+		case float64:
+			maxTokens = int(v)
+		case int:
+			maxTokens = v
+		case string:
+			if parsed, err := strconv.Atoi(v); err == nil {
+				maxTokens = parsed
+			}
+		}
+	}
+
+	temperature := 0.7
+	if temp, ok := params["temperature"]; ok {
+		switch v := temp.(type) {
+		case float64:
+			temperature = v
+		case int:
+			temperature = float64(v)
+		case string:
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				temperature = parsed
+			}
+		}
+	}
+
+	messages := []map[string]interface{}{
+		{"role": "user", "content": prompt},
+	}
+	if system, ok := params["system"].(string); ok && system != "" {
+		messages = withSystemPrompt(messages, system)
+	}
+
+	jsonMode, err := jsonModeParam(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	request := OpenAIRequest{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+		TopP:        getTopPParam(params),
+		Stop:        getStopParam(params),
+	}
+	if jsonMode {
+		request.Messages = withJSONInstruction(messages)
+		request.ResponseFormat = map[string]interface{}{"type": "json_object"}
+	}
+
+	result := callOpenAICompatibleAPI("https://api.openai.com/v1", apiKey, request)
+	if _, failed := result["error"]; !failed && jsonMode {
+		addParsedJSON(result, result["text"].(string))
+	}
+	return result
+}
+
+// chat handles chat conversations using OpenAI or Ollama
+func (p *LLMPlugin) chat(params map[string]interface{}) map[string]interface{} {
+	provider := "openai"
+	if pr, ok := params["provider"].(string); ok && pr != "" {
+		provider = pr
+	}
+	if provider != "openai" && provider != "ollama" {
+		return map[string]interface{}{"error": fmt.Sprintf("Unsupported provider: %s (use openai or ollama)", provider)}
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if provider == "openai" && apiKey == "" {
+		return map[string]interface{}{"error": "OPENAI_API_KEY not configured"}
+	}
+
+	messagesParam, ok := params["messages"]
+	if !ok {
+		return map[string]interface{}{"error": "messages are required"}
+	}
+
+	// Convert messages to the correct format
+	var messages []map[string]interface{}
+	if msgSlice, ok := messagesParam.([]interface{}); ok {
+		for _, msg := range msgSlice {
+			if msgMap, ok := msg.(map[string]interface{}); ok {
+				messages = append(messages, msgMap)
+			}
+		}
+	} else {
+		return map[string]interface{}{"error": "messages must be an array"}
+	}
+
+	sessionID, _ := params["session"].(string)
+	var session chatSession
+	if sessionID != "" {
+		var err error
+		session, err = loadSession(sessionID)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		messages = append(session.Messages, messages...)
+	}
+	if system, ok := params["system"].(string); ok && system != "" {
+		messages = withSystemPrompt(messages, system)
+	}
+
+	jsonMode, err := jsonModeParam(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	// The JSON instruction is only sent, never stored in the session history
+	requestMessages := messages
+	if jsonMode {
+		requestMessages = withJSONInstruction(messages)
+	}
+
+	var result map[string]interface{}
+	if provider == "ollama" {
+		model := "llama2"
+		if m, ok := params["model"].(string); ok && m != "" {
+			model = m
+		}
+		format := ""
+		if jsonMode {
+			format = "json"
+		}
+		result = ollamaChat(model, requestMessages, format)
+	} else {
+		model := "gpt-3.5-turbo"
+		if m, ok := params["model"].(string); ok {
+			model = m
+		}
+		request := OpenAIRequest{
+			Model:    model,
+			Messages: requestMessages,
+		}
+		if jsonMode {
+			request.ResponseFormat = map[string]interface{}{"type": "json_object"}
+		}
+		result = callOpenAICompatibleAPI("https://api.openai.com/v1", apiKey, request)
+	}
+	if _, failed := result["error"]; failed {
+		return result
+	}
+
+	// chat has always reported the reply as response
+	reply, _ := result["text"].(string)
+	delete(result, "text")
+	result["response"] = reply
+	if jsonMode {
+		addParsedJSON(result, reply)
+	}
+
+	messages = append(messages, map[string]interface{}{"role": "assistant", "content": reply})
+	result["message_count"] = len(messages)
+	if sessionID != "" {
+		session.Messages = messages
+		if err := saveSession(sessionID, session); err != nil {
+			result["error"] = err.Error()
+		}
+		result["session_id"] = sessionID
+	}
+	return result
+}
+
+// clearSession deletes the persisted history of a chat session
+func (p *LLMPlugin) clearSession(params map[string]interface{}) map[string]interface{} {
+	// Tool-calling sessions are addressed as session_id, chat sessions as session
+	sessionID, _ := params["session"].(string)
+	if sessionID == "" {
+		sessionID, _ = params["session_id"].(string)
+	}
+	if sessionID == "" {
+		return map[string]interface{}{"error": "session is required"}
+	}
+
+	path, err := sessionPath(sessionID)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return map[string]interface{}{"error": fmt.Sprintf("Failed to clear session: %v", err)}
+	}
+
+	return map[string]interface{}{
+		"session_id": sessionID,
+		"cleared":    err == nil,
+	}
+}
+
+// sessionPath returns the history file for a session, rejecting IDs that could escape the temp dir
+func sessionPath(sessionID string) (string, error) {
+	for _, r := range sessionID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return "", fmt.Errorf("Invalid session ID: %q", sessionID)
+		}
+	}
+	return filepath.Join(os.TempDir(), "corynth-llm-session-"+sessionID+".json"), nil
+}
+
+// chatSession is the persisted state of a chat or tool-calling session
+type chatSession struct {
+	Messages []map[string]interface{} `json:"messages"`
+	Model    string                   `json:"model,omitempty"`
+	Tools    []interface{}            `json:"tools,omitempty"`
+}
+
+// loadSession reads a session's state; an unknown session has no history
+func loadSession(sessionID string) (chatSession, error) {
+	var session chatSession
+	path, err := sessionPath(sessionID)
+	if err != nil {
+		return session, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return session, nil
+	}
+	if err != nil {
+		return session, fmt.Errorf("Failed to read session: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, fmt.Errorf("Failed to parse session: %v", err)
+	}
+	return session, nil
+}
+
+// saveSession writes a session's state
+func saveSession(sessionID string, session chatSession) error {
+	path, err := sessionPath(sessionID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("Failed to encode session: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("Failed to save session: %v", err)
+	}
+	return nil
+}
+
+// withSystemPrompt puts system at the start of messages, replacing any existing leading system message
+func withSystemPrompt(messages []map[string]interface{}, system string) []map[string]interface{} {
+	systemMsg := map[string]interface{}{"role": "system", "content": system}
+	if len(messages) > 0 && messages[0]["role"] == "system" {
+		return append([]map[string]interface{}{systemMsg}, messages[1:]...)
+	}
+	return append([]map[string]interface{}{systemMsg}, messages...)
+}
+
+// jsonInstruction is added to the system prompt in JSON mode. OpenAI rejects json_object
+// requests whose messages never mention JSON, and Ollama's format option works best when
+// the model is also told to answer in JSON.
+const jsonInstruction = "Respond only with a single valid JSON object, without any surrounding text or code fences."
+
+// jsonModeParam reports whether response_format asks for JSON output
+func jsonModeParam(params map[string]interface{}) (bool, error) {
+	format, _ := params["response_format"].(string)
+	switch format {
+	case "", "text":
+		return false, nil
+	case "json":
+		return true, nil
+	default:
+		return false, fmt.Errorf("Unsupported response_format: %s (use text or json)", format)
+	}
+}
+
+// withJSONInstruction returns a copy of messages with jsonInstruction appended to the system prompt
+func withJSONInstruction(messages []map[string]interface{}) []map[string]interface{} {
+	if len(messages) > 0 && messages[0]["role"] == "system" {
+		system, _ := messages[0]["content"].(string)
+		return withSystemPrompt(messages, strings.TrimSpace(system+"\n\n"+jsonInstruction))
+	}
+	return withSystemPrompt(messages, jsonInstruction)
+}
+
+// addParsedJSON sets json and valid_json from a model reply, plus json_error when no JSON could be parsed
+func addParsedJSON(result map[string]interface{}, text string) {
+	parsed, err := parseJSONReply(text)
+	if err != nil {
+		result["valid_json"] = false
+		result["json_error"] = err.Error()
+		return
+	}
+	result["json"] = parsed
+	result["valid_json"] = true
+}
+
+// parseJSONReply parses a reply that should be JSON. Models without native JSON mode often wrap
+// the object in prose or code fences, so when the whole reply is not JSON the first decodable
+// object in it is used instead.
+func parseJSONReply(text string) (interface{}, error) {
+	var parsed interface{}
+	firstErr := json.Unmarshal([]byte(strings.TrimSpace(text)), &parsed)
+	if firstErr == nil {
+		return parsed, nil
+	}
+
+	for i := strings.Index(text, "{"); i >= 0; {
+		var object map[string]interface{}
+		if err := json.NewDecoder(strings.NewReader(text[i:])).Decode(&object); err == nil {
+			return object, nil
+		}
+		next := strings.Index(text[i+1:], "{")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return nil, fmt.Errorf("No JSON object found in response: %v", firstErr)
+}
+
+// generateWithTools asks an OpenAI model to answer a prompt, letting it call the supplied tools
+func (p *LLMPlugin) generateWithTools(params map[string]interface{}) map[string]interface{} {
+	prompt, ok := params["prompt"].(string)
+	if !ok || prompt == "" {
+		return map[string]interface{}{"error": "prompt is required"}
+	}
+
+	toolsParam, ok := params["tools"].([]interface{})
+	if !ok || len(toolsParam) == 0 {
+		return map[string]interface{}{"error": "tools are required"}
+	}
+
+	// Accept bare function schemas as well as full {type: function, function: ...} tools
+	var tools []interface{}
+	for _, tool := range toolsParam {
+		toolMap, ok := tool.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{"error": "tools must be an array of objects"}
+		}
+		if _, hasType := toolMap["type"]; !hasType {
+			toolMap = map[string]interface{}{"type": "function", "function": toolMap}
+		}
+		tools = append(tools, toolMap)
+	}
+
+	sessionID, _ := params["session_id"].(string)
+	if sessionID == "" {
+		sessionID = fmt.Sprintf("tools-%d", time.Now().UnixNano())
+	}
+	session, err := loadSession(sessionID)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	session.Model = "gpt-3.5-turbo"
+	if m, ok := params["model"].(string); ok && m != "" {
+		session.Model = m
+	}
+	session.Tools = tools
+
+	session.Messages = append(session.Messages, map[string]interface{}{"role": "user", "content": prompt})
+	if system, ok := params["system"].(string); ok && system != "" {
+		session.Messages = withSystemPrompt(session.Messages, system)
+	}
+
+	request := OpenAIRequest{
+		Model:    session.Model,
+		Messages: session.Messages,
+		Tools:    tools,
+	}
+	switch choice, _ := params["tool_choice"].(string); choice {
+	case "":
+	case "auto", "required", "none":
+		request.ToolChoice = choice
+	default:
+		request.ToolChoice = map[string]interface{}{
+			"type":     "function",
+			"function": map[string]interface{}{"name": choice},
+		}
+	}
+
+	return p.completeToolTurn(sessionID, session, request)
+}
+
+// submitToolResult records the result of one tool call and, once every pending call
+// has a result, sends the conversation back to the model
+func (p *LLMPlugin) submitToolResult(params map[string]interface{}) map[string]interface{} {
+	sessionID, ok := params["session_id"].(string)
+	if !ok || sessionID == "" {
+		return map[string]interface{}{"error": "session_id is required"}
+	}
+
+	toolCallID, ok := params["tool_call_id"].(string)
+	if !ok || toolCallID == "" {
+		return map[string]interface{}{"error": "tool_call_id is required"}
+	}
+
+	session, err := loadSession(sessionID)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	pending := pendingToolCalls(session.Messages)
+	found := false
+	for _, id := range pending {
+		if id == toolCallID {
+			found = true
+		}
+	}
+	if !found {
+		return map[string]interface{}{"error": fmt.Sprintf("No pending tool call %s in session %s", toolCallID, sessionID)}
+	}
+
+	// The API expects tool results as strings, so structured results are sent as JSON
+	content, isString := params["result"].(string)
+	if !isString {
+		encoded, err := json.Marshal(params["result"])
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("Failed to encode result: %v", err)}
+		}
+		content = string(encoded)
+	}
+
+	session.Messages = append(session.Messages, map[string]interface{}{
+		"role":         "tool",
+		"tool_call_id": toolCallID,
+		"content":      content,
+	})
+
+	// Wait for the remaining results before calling the model again
+	if remaining := pendingToolCalls(session.Messages); len(remaining) > 0 {
+		if err := saveSession(sessionID, session); err != nil {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		return map[string]interface{}{
+			"session_id":         sessionID,
+			"pending_tool_calls": remaining,
+			"message_count":      len(session.Messages),
+		}
+	}
+
+	request := OpenAIRequest{
+		Model:    session.Model,
+		Messages: session.Messages,
+		Tools:    session.Tools,
+	}
+	return p.completeToolTurn(sessionID, session, request)
+}
+
+// completeToolTurn sends request, records the assistant reply in the session, and reports any tool calls
+func (p *LLMPlugin) completeToolTurn(sessionID string, session chatSession, request OpenAIRequest) map[string]interface{} {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return map[string]interface{}{"error": "OPENAI_API_KEY not configured"}
+	}
+
+	openaiResp, err := postChatCompletion("https://api.openai.com/v1", apiKey, request)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	choice := openaiResp.Choices[0]
+	assistant := map[string]interface{}{"role": "assistant", "content": choice.Message.Content}
+	toolCalls := make([]map[string]interface{}, 0, len(choice.Message.ToolCalls))
+	if len(choice.Message.ToolCalls) > 0 {
+		assistant["tool_calls"] = choice.Message.ToolCalls
+		if choice.Message.Content == "" {
+			assistant["content"] = nil
+		}
+
+		for _, call := range choice.Message.ToolCalls {
+			// Arguments arrive as a JSON string; decode them when they are valid JSON
+			var arguments interface{} = call.Function.Arguments
+			var decoded interface{}
+			if json.Unmarshal([]byte(call.Function.Arguments), &decoded) == nil {
+				arguments = decoded
+			}
+			toolCalls = append(toolCalls, map[string]interface{}{
+				"id":        call.ID,
+				"name":      call.Function.Name,
+				"arguments": arguments,
+			})
+		}
+	}
+	session.Messages = append(session.Messages, assistant)
+
+	result := map[string]interface{}{
+		"text":          choice.Message.Content,
+		"tool_calls":    toolCalls,
+		"finish_reason": choice.FinishReason,
+		"usage":         openaiResp.Usage,
+		"session_id":    sessionID,
+		"message_count": len(session.Messages),
+	}
+	if err := saveSession(sessionID, session); err != nil {
+		result["error"] = err.Error()
+	}
+	return result
+}
+
+// pendingToolCalls returns the IDs of tool calls in the last assistant message that have no result yet
+func pendingToolCalls(messages []map[string]interface{}) []string {
+	var pending []string
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i]["role"] != "assistant" {
+			continue
+		}
+
+		answered := make(map[string]bool)
+		for _, msg := range messages[i+1:] {
+			if id, ok := msg["tool_call_id"].(string); ok {
+				answered[id] = true
+			}
+		}
+
+		calls, _ := messages[i]["tool_calls"].([]interface{})
+		for _, call := range calls {
+			if callMap, ok := call.(map[string]interface{}); ok {
+				if id, ok := callMap["id"].(string); ok && !answered[id] {
+					pending = append(pending, id)
+				}
+			}
+		}
+		break
+	}
+	return pending
+}
+
+// mistralChat handles chat completions using the Mistral API
+func (p *LLMPlugin) mistralChat(params map[string]interface{}) map[string]interface{} {
+	apiKey := os.Getenv("MISTRAL_API_KEY")
+	if apiKey == "" {
+		return map[string]interface{}{"error": "MISTRAL_API_KEY not configured"}
+	}
+
+	var messages []map[string]interface{}
+	if msgSlice, ok := params["messages"].([]interface{}); ok && len(msgSlice) > 0 {
+		for _, msg := range msgSlice {
+			if msgMap, ok := msg.(map[string]interface{}); ok {
+				messages = append(messages, msgMap)
+			}
+		}
+	} else if prompt, ok := params["prompt"].(string); ok && prompt != "" {
+		messages = []map[string]interface{}{{"role": "user", "content": prompt}}
+	} else {
+		return map[string]interface{}{"error": "messages are required"}
+	}
+
+	model := "mistral-small-latest"
+	if m, ok := params["model"].(string); ok && m != "" {
+		model = m
+	}
+
+	request := OpenAIRequest{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   getIntParam(params, "max_tokens", 0),
+		Temperature: getFloatParam(params, "temperature", 0),
+	}
+	if safePrompt, ok := params["safe_prompt"].(bool); ok {
+		request.SafePrompt = safePrompt
+	}
+	if _, ok := params["random_seed"]; ok {
+		seed := getIntParam(params, "random_seed", 0)
+		request.RandomSeed = &seed
+	}
+
+	return callOpenAICompatibleAPI("https://api.mistral.ai/v1", apiKey, request)
+}
+
+// callOpenAICompatibleAPI sends a chat completion to any OpenAI-compatible API and returns text, finish_reason, and usage
+func callOpenAICompatibleAPI(baseURL, apiKey string, req OpenAIRequest) map[string]interface{} {
+	resp, err := postChatCompletion(baseURL, apiKey, req)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	return map[string]interface{}{
+		"text":          resp.Choices[0].Message.Content,
+		"finish_reason": resp.Choices[0].FinishReason,
+		"usage":         resp.Usage,
+	}
+}
+
+// postChatCompletion posts to baseURL's /chat/completions endpoint and decodes the response
+func postChatCompletion(baseURL, apiKey string, request OpenAIRequest) (*OpenAIResponse, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	req, err := http.NewRequest("POST", baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var openaiResp OpenAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
+		return nil, fmt.Errorf("Failed to decode response: %v", err)
+	}
+
+	if len(openaiResp.Choices) == 0 {
+		return nil, fmt.Errorf("No response choices returned")
+	}
+	return &openaiResp, nil
+}
+
+// ollamaGenerate generates text using Ollama API
+func (p *LLMPlugin) ollamaGenerate(params map[string]interface{}) map[string]interface{} {
+	prompt, ok := params["prompt"].(string)
+	if !ok {
+		return map[string]interface{}{"error": "prompt is required"}
+	}
+
+	model := "llama2"
+	if m, ok := params["model"].(string); ok {
+		model = m
+	}
+
+	request := OllamaRequest{Model: model, Prompt: prompt}
+	if system, ok := params["system"].(string); ok {
+		request.System = system
+	}
+	// Ollama takes sampling settings in options rather than at the top level
+	options := map[string]interface{}{}
+	if topP := getTopPParam(params); topP != nil {
+		options["top_p"] = *topP
+	}
+	if stop := getStopParam(params); len(stop) > 0 {
+		options["stop"] = stop
+	}
+	if len(options) > 0 {
+		request.Options = options
+	}
+
+	var ollamaResp OllamaResponse
+	if err := postOllama("/api/generate", request, &ollamaResp); err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	return map[string]interface{}{
+		"response": ollamaResp.Response,
+		"usage":    ollamaUsage(ollamaResp.PromptEvalCount, ollamaResp.EvalCount),
+	}
+}
+
+// ollamaChat sends a multi-turn conversation to Ollama's /api/chat endpoint; format "json" constrains the reply to JSON
+func ollamaChat(model string, messages []map[string]interface{}, format string) map[string]interface{} {
+	var chatResp OllamaChatResponse
+	if err := postOllama("/api/chat", OllamaChatRequest{Model: model, Messages: messages, Format: format}, &chatResp); err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	return map[string]interface{}{
+		"text":          chatResp.Message.Content,
+		"finish_reason": chatResp.DoneReason,
+		"usage":         ollamaUsage(chatResp.PromptEvalCount, chatResp.EvalCount),
+	}
+}
+
+// postOllama posts a non-streaming request to the Ollama API and decodes the response into out
+func postOllama(path string, request interface{}, out interface{}) error {
+	ollamaURL := os.Getenv("OLLAMA_URL")
+	if ollamaURL == "" {
+		ollamaURL = "http://localhost:11434"
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal request: %v", err)
+	}
+
+	client := &http.Client{Timeout: 120 * time.Second} // Longer timeout for local models
+	req, err := http.NewRequest("POST", ollamaURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Ollama API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("Failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// ollamaUsage reports Ollama's token counts with the same keys as OpenAI usage
+func ollamaUsage(promptEvalCount, evalCount int) map[string]interface{} {
+	return map[string]interface{}{
+		"prompt_tokens":     promptEvalCount,
+		"completion_tokens": evalCount,
+		"total_tokens":      promptEvalCount + evalCount,
+	}
+}
+
+// embed generates one embedding per input text using OpenAI or Ollama
+func (p *LLMPlugin) embed(params map[string]interface{}) map[string]interface{} {
+	var inputs []string
+	switch v := params["input"].(type) {
+	case string:
+		inputs = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return map[string]interface{}{"error": "input array must contain only strings"}
+			}
+			inputs = append(inputs, text)
+		}
+	}
+	if len(inputs) == 0 {
+		return map[string]interface{}{"error": "input is required"}
+	}
+
+	provider := "openai"
+	if pr, ok := params["provider"].(string); ok && pr != "" {
+		provider = pr
+	}
+
+	switch provider {
+	case "openai":
+		return p.openaiEmbed(inputs, params)
+	case "ollama":
+		return p.ollamaEmbed(inputs, params)
+	default:
+		return map[string]interface{}{"error": fmt.Sprintf("Unknown provider: %s", provider)}
+	}
+}
+
+// openaiEmbed embeds all inputs in a single /v1/embeddings request
+func (p *LLMPlugin) openaiEmbed(inputs []string, params map[string]interface{}) map[string]interface{} {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return map[string]interface{}{"error": "OPENAI_API_KEY not configured"}
+	}
+
+	model := "text-embedding-3-small"
+	if m, ok := params["model"].(string); ok && m != "" {
+		model = m
+	}
+
+	jsonData, err := json.Marshal(OpenAIEmbeddingRequest{Model: model, Input: inputs})
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("Failed to marshal request: %v", err)}
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	req, err := http.NewRequest("POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("Failed to create request: %v", err)}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return map[string]interface{}{"error": fmt.Sprintf("OpenAI API error (%d): %s", resp.StatusCode, string(body))}
+	}
+
+	var embedResp OpenAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("Failed to decode response: %v", err)}
+	}
+
+	// Order by index so embeddings line up with the inputs
+	embeddings := make([][]float64, len(inputs))
+	for _, item := range embedResp.Data {
+		if item.Index >= 0 && item.Index < len(embeddings) {
+			embeddings[item.Index] = item.Embedding
+		}
+	}
+
+	return map[string]interface{}{
+		"embeddings": embeddings,
+		"model":      embedResp.Model,
+		"usage":      map[string]interface{}{"total_tokens": embedResp.Usage.TotalTokens},
+	}
+}
+
+// ollamaEmbed embeds each input with /api/embeddings, which accepts one prompt per request
+func (p *LLMPlugin) ollamaEmbed(inputs []string, params map[string]interface{}) map[string]interface{} {
+	ollamaURL := os.Getenv("OLLAMA_URL")
+	if ollamaURL == "" {
+		ollamaURL = "http://localhost:11434"
+	}
+
+	model := "nomic-embed-text"
+	if m, ok := params["model"].(string); ok && m != "" {
+		model = m
+	}
+
+	client := &http.Client{Timeout: 120 * time.Second} // Longer timeout for local models
+	embeddings := make([][]float64, 0, len(inputs))
+	for _, input := range inputs {
+		jsonData, err := json.Marshal(map[string]string{"model": model, "prompt": input})
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("Failed to marshal request: %v", err)}
+		}
+
+		resp, err := client.Post(fmt.Sprintf("%s/api/embeddings", ollamaURL), "application/json", bytes.NewBuffer(jsonData))
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return map[string]interface{}{"error": fmt.Sprintf("Ollama API error (%d): %s", resp.StatusCode, string(body))}
+		}
+
+		var embedResp OllamaEmbeddingResponse
+		err = json.NewDecoder(resp.Body).Decode(&embedResp)
+		resp.Body.Close()
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("Failed to decode response: %v", err)}
+		}
+		embeddings = append(embeddings, embedResp.Embedding)
+	}
+
+	// Ollama does not report token usage for embeddings
+	return map[string]interface{}{
+		"embeddings": embeddings,
+		"model":      model,
+		"usage":      map[string]interface{}{"total_tokens": 0},
 	}
 }
 
-// Execute performs the specified action
-func (p *LLMPlugin) Execute(action string, params map[string]interface{}) map[string]interface{} {
-	switch action {
-	case "generate":
-		return p.openaiGenerate(params)
-	case "chat":
-		return p.openaiChat(params)
+// generateStream streams a completion from OpenAI or Ollama, appending each token to stream_file
+func (p *LLMPlugin) generateStream(params map[string]interface{}) map[string]interface{} {
+	prompt, ok := params["prompt"].(string)
+	if !ok {
+		return map[string]interface{}{"error": "prompt is required"}
+	}
+
+	backend := "openai"
+	if b, ok := params["backend"].(string); ok && b != "" {
+		backend = b
+	}
+
+	var req *http.Request
+	var err error
+	switch backend {
+	case "openai":
+		req, err = p.newOpenAIStreamRequest(prompt, params)
 	case "ollama":
-		return p.ollamaGenerate(params)
+		req, err = p.newOllamaStreamRequest(prompt, params)
 	default:
-		return map[string]interface{}{"error": fmt.Sprintf("Unknown action: %s", action)}
+		return map[string]interface{}{"error": fmt.Sprintf("Unsupported backend: %s (use openai or ollama)", backend)}
+	}
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
 	}
-}
 
-// openaiGenerate generates text using OpenAI API
-func (p *LLMPlugin) openaiGenerate(params map[string]interface{}) map[string]interface{} {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return map[string]interface{}{"error": "OPENAI_API_KEY not configured"}
+	streamFile, err := openStreamFile(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
 	}
+	defer streamFile.Close()
 
-	prompt, ok := params["prompt"].(string)
-	if !ok {
-		return map[string]interface{}{"error": "prompt is required"}
+	// Streams can run far longer than a single buffered response
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
 	}
+	defer resp.Body.Close()
 
-	model := "gpt-3.5-turbo"
-	if m, ok := params["model"].(string); ok {
-		model = m
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return map[string]interface{}{"error": fmt.Sprintf("API error (%d): %s", resp.StatusCode, string(body))}
 	}
 
-	maxTokens := 150
-	if mt, ok := params["max_tokens"]; ok {
-		switch v := mt.(type) {
-		case float64:
-			maxTokens = int(v)
-		case int:
-			maxTokens = v
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				maxTokens = parsed
-			}
+	var text strings.Builder
+	chunks := 0
+	emit := func(token string) error {
+		if token == "" {
+			return nil
 		}
+		chunks++
+		text.WriteString(token)
+		_, err := streamFile.WriteString(token)
+		return err
 	}
 
-	temperature := 0.7
-	if temp, ok := params["temperature"]; ok {
-		switch v := temp.(type) {
-		case float64:
-			temperature = v
-		case int:
-			temperature = float64(v)
-		case string:
-			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
-				temperature = parsed
+	if backend == "ollama" {
+		// Ollama streams newline-delimited JSON objects rather than SSE
+		err = readLines(resp.Body, func(line string) (bool, error) {
+			if strings.TrimSpace(line) == "" {
+				return false, nil
+			}
+			var chunk OllamaResponse
+			if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+				return false, fmt.Errorf("Failed to decode stream chunk: %v", err)
+			}
+			return chunk.Done, emit(chunk.Response)
+		})
+	} else {
+		err = readSSE(resp.Body, func(data string) (bool, error) {
+			if data == "[DONE]" {
+				return true, nil
+			}
+			var chunk OpenAIStreamChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return false, fmt.Errorf("Failed to decode stream chunk: %v", err)
+			}
+			for _, choice := range chunk.Choices {
+				if err := emit(choice.Delta.Content); err != nil {
+					return false, err
+				}
 			}
+			return false, nil
+		})
+	}
+	if err != nil {
+		return map[string]interface{}{
+			"error":       err.Error(),
+			"text":        text.String(),
+			"chunks":      chunks,
+			"stream_file": streamFile.Name(),
 		}
 	}
 
+	return map[string]interface{}{
+		"text":        text.String(),
+		"chunks":      chunks,
+		"stream_file": streamFile.Name(),
+	}
+}
+
+// newOpenAIStreamRequest builds a chat completion request with streaming enabled
+func (p *LLMPlugin) newOpenAIStreamRequest(prompt string, params map[string]interface{}) (*http.Request, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY not configured")
+	}
+
+	model := "gpt-3.5-turbo"
+	if m, ok := params["model"].(string); ok && m != "" {
+		model = m
+	}
+
 	request := OpenAIRequest{
 		Model: model,
-		Messages: []map[string]string{
+		Messages: []map[string]interface{}{
 			{"role": "user", "content": prompt},
 		},
-		MaxTokens:   maxTokens,
-		Temperature: temperature,
+		MaxTokens:   getIntParam(params, "max_tokens", 150),
+		Temperature: getFloatParam(params, "temperature", 0.7),
+		Stream:      true,
 	}
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to marshal request: %v", err)}
+		return nil, fmt.Errorf("Failed to marshal request: %v", err)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
 	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to create request: %v", err)}
+		return nil, fmt.Errorf("Failed to create request: %v", err)
 	}
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	return req, nil
+}
+
+// newOllamaStreamRequest builds an Ollama generate request with streaming enabled
+func (p *LLMPlugin) newOllamaStreamRequest(prompt string, params map[string]interface{}) (*http.Request, error) {
+	ollamaURL := os.Getenv("OLLAMA_URL")
+	if ollamaURL == "" {
+		ollamaURL = "http://localhost:11434"
+	}
+
+	model := "llama2"
+	if m, ok := params["model"].(string); ok && m != "" {
+		model = m
+	}
+
+	jsonData, err := json.Marshal(OllamaRequest{
+		Model:  model,
+		Prompt: prompt,
+		Stream: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/generate", ollamaURL), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// claudeGenerate sends a Messages API request to Anthropic
+func (p *LLMPlugin) claudeGenerate(params map[string]interface{}) map[string]interface{} {
+	req, err := p.newAnthropicRequest(params, false)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
 
+	client := &http.Client{Timeout: 120 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
@@ -251,78 +1880,159 @@ func (p *LLMPlugin) openaiGenerate(params map[string]interface{}) map[string]int
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return map[string]interface{}{"error": fmt.Sprintf("API error (%d): %s", resp.StatusCode, string(body))}
+		return map[string]interface{}{"error": fmt.Sprintf("Anthropic API error (%d): %s", resp.StatusCode, string(body))}
 	}
 
-	var openaiResp OpenAIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
+	var anthropicResp AnthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&anthropicResp); err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("Failed to decode response: %v", err)}
 	}
 
-	if len(openaiResp.Choices) == 0 {
-		return map[string]interface{}{"error": "No response choices returned"}
+	if len(anthropicResp.Content) == 0 {
+		return map[string]interface{}{"error": "No content returned"}
 	}
 
 	return map[string]interface{}{
-		"text":  openaiResp.Choices[0].Message.Content,
-		"usage": openaiResp.Usage,
+		"text":        anthropicResp.Content[0].Text,
+		"stop_reason": anthropicResp.StopReason,
+		"usage":       anthropicResp.Usage,
 	}
 }
 
-// openaiChat handles chat conversations using OpenAI API
-func (p *LLMPlugin) openaiChat(params map[string]interface{}) map[string]interface{} {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return map[string]interface{}{"error": "OPENAI_API_KEY not configured"}
+// claudeStream streams a Messages API response, appending each text delta to stream_file
+func (p *LLMPlugin) claudeStream(params map[string]interface{}) map[string]interface{} {
+	req, err := p.newAnthropicRequest(params, true)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
 	}
 
-	messagesParam, ok := params["messages"]
-	if !ok {
-		return map[string]interface{}{"error": "messages are required"}
+	streamFile, err := openStreamFile(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
 	}
+	defer streamFile.Close()
 
-	// Convert messages to the correct format
-	var messages []map[string]string
-	if msgSlice, ok := messagesParam.([]interface{}); ok {
-		for _, msg := range msgSlice {
-			if msgMap, ok := msg.(map[string]interface{}); ok {
-				convertedMsg := make(map[string]string)
-				for k, v := range msgMap {
-					if str, ok := v.(string); ok {
-						convertedMsg[k] = str
-					}
-				}
-				messages = append(messages, convertedMsg)
+	// Streams can run far longer than a single buffered response
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return map[string]interface{}{"error": fmt.Sprintf("Anthropic API error (%d): %s", resp.StatusCode, string(body))}
+	}
+
+	var text strings.Builder
+	var usage AnthropicUsage
+	stopReason := ""
+	chunks := 0
+
+	err = readSSE(resp.Body, func(data string) (bool, error) {
+		var event AnthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return false, fmt.Errorf("Failed to decode stream event: %v", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			usage.InputTokens = event.Message.Usage.InputTokens
+		case "content_block_delta":
+			if event.Delta.Text == "" {
+				return false, nil
 			}
+			chunks++
+			text.WriteString(event.Delta.Text)
+			if _, err := streamFile.WriteString(event.Delta.Text); err != nil {
+				return false, err
+			}
+		case "message_delta":
+			stopReason = event.Delta.StopReason
+			usage.OutputTokens = event.Usage.OutputTokens
+		case "message_stop":
+			return true, nil
+		case "error":
+			return false, fmt.Errorf("Anthropic stream error: %s", event.Error.Message)
 		}
+		return false, nil
+	})
+
+	result := map[string]interface{}{
+		"text":        text.String(),
+		"chunks":      chunks,
+		"stream_file": streamFile.Name(),
+		"stop_reason": stopReason,
+		"usage":       usage,
+	}
+	if err != nil {
+		result["error"] = err.Error()
+	}
+	return result
+}
+
+// newAnthropicRequest builds a Messages API request from the action parameters
+func (p *LLMPlugin) newAnthropicRequest(params map[string]interface{}, stream bool) (*http.Request, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY not configured")
+	}
+
+	var messages []interface{}
+	if msgSlice, ok := params["messages"].([]interface{}); ok && len(msgSlice) > 0 {
+		messages = msgSlice
+	} else if prompt, ok := params["prompt"].(string); ok && prompt != "" {
+		messages = []interface{}{map[string]interface{}{"role": "user", "content": prompt}}
 	} else {
-		return map[string]interface{}{"error": "messages must be an array"}
+		return nil, fmt.Errorf("messages are required")
 	}
 
-	model := "gpt-3.5-turbo"
-	if m, ok := params["model"].(string); ok {
+	model := "claude-3-haiku-20240307"
+	if m, ok := params["model"].(string); ok && m != "" {
 		model = m
 	}
 
-	request := OpenAIRequest{
-		Model:    model,
-		Messages: messages,
+	request := AnthropicRequest{
+		Model:     model,
+		Messages:  messages,
+		MaxTokens: getIntParam(params, "max_tokens", 1024),
+		Stream:    stream,
 	}
+	if _, ok := params["temperature"]; ok {
+		temperature := getFloatParam(params, "temperature", 1.0)
+		request.Temperature = &temperature
+	}
+	if system, ok := params["system"].(string); ok {
+		request.System = system
+	}
+	request.TopP = getTopPParam(params)
+	request.StopSequences = getStopParam(params)
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to marshal request: %v", err)}
+		return nil, fmt.Errorf("Failed to marshal request: %v", err)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to create request: %v", err)}
+		return nil, fmt.Errorf("Failed to create request: %v", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
 	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// geminiGenerate sends a generateContent request to the Gemini API
+func (p *LLMPlugin) geminiGenerate(params map[string]interface{}) map[string]interface{} {
+	req, err := p.newGeminiRequest(params, false)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
 
+	client := &http.Client{Timeout: 120 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
@@ -331,79 +2041,272 @@ func (p *LLMPlugin) openaiChat(params map[string]interface{}) map[string]interfa
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return map[string]interface{}{"error": fmt.Sprintf("API error (%d): %s", resp.StatusCode, string(body))}
+		return map[string]interface{}{"error": fmt.Sprintf("Gemini API error (%d): %s", resp.StatusCode, string(body))}
 	}
 
-	var openaiResp OpenAIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
+	var geminiResp GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
 		return map[string]interface{}{"error": fmt.Sprintf("Failed to decode response: %v", err)}
 	}
 
-	if len(openaiResp.Choices) == 0 {
-		return map[string]interface{}{"error": "No response choices returned"}
+	if len(geminiResp.Candidates) == 0 {
+		return map[string]interface{}{"error": "No candidates returned", "usage_metadata": geminiResp.UsageMetadata}
+	}
+
+	candidate := geminiResp.Candidates[0]
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		text.WriteString(part.Text)
 	}
 
 	return map[string]interface{}{
-		"response": openaiResp.Choices[0].Message.Content,
-		"usage":    openaiResp.Usage,
+		"text":           text.String(),
+		"finish_reason":  candidate.FinishReason,
+		"usage_metadata": geminiResp.UsageMetadata,
+		"safety_ratings": candidate.SafetyRatings,
 	}
 }
 
-// ollamaGenerate generates text using Ollama API
-func (p *LLMPlugin) ollamaGenerate(params map[string]interface{}) map[string]interface{} {
-	ollamaURL := os.Getenv("OLLAMA_URL")
-	if ollamaURL == "" {
-		ollamaURL = "http://localhost:11434"
+// geminiStream streams a streamGenerateContent response, appending each chunk's text to stream_file
+func (p *LLMPlugin) geminiStream(params map[string]interface{}) map[string]interface{} {
+	req, err := p.newGeminiRequest(params, true)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
 	}
 
-	prompt, ok := params["prompt"].(string)
-	if !ok {
-		return map[string]interface{}{"error": "prompt is required"}
+	streamFile, err := openStreamFile(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
 	}
+	defer streamFile.Close()
 
-	model := "llama2"
-	if m, ok := params["model"].(string); ok {
+	// Streams can run far longer than a single buffered response
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return map[string]interface{}{"error": fmt.Sprintf("Gemini API error (%d): %s", resp.StatusCode, string(body))}
+	}
+
+	var text strings.Builder
+	var finishReason string
+	var safetyRatings []interface{}
+	var usageMetadata map[string]interface{}
+	chunks := 0
+
+	err = readSSE(resp.Body, func(data string) (bool, error) {
+		var chunk GeminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, fmt.Errorf("Failed to decode stream chunk: %v", err)
+		}
+		// Usage is cumulative, so the last chunk carries the totals
+		if chunk.UsageMetadata != nil {
+			usageMetadata = chunk.UsageMetadata
+		}
+		if len(chunk.Candidates) == 0 {
+			return false, nil
+		}
+
+		candidate := chunk.Candidates[0]
+		if candidate.FinishReason != "" {
+			finishReason = candidate.FinishReason
+		}
+		if candidate.SafetyRatings != nil {
+			safetyRatings = candidate.SafetyRatings
+		}
+		for _, part := range candidate.Content.Parts {
+			if part.Text == "" {
+				continue
+			}
+			chunks++
+			text.WriteString(part.Text)
+			if _, err := streamFile.WriteString(part.Text); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	})
+
+	result := map[string]interface{}{
+		"text":           text.String(),
+		"chunks":         chunks,
+		"stream_file":    streamFile.Name(),
+		"finish_reason":  finishReason,
+		"usage_metadata": usageMetadata,
+		"safety_ratings": safetyRatings,
+	}
+	if err != nil {
+		result["error"] = err.Error()
+	}
+	return result
+}
+
+// newGeminiRequest builds a generateContent (or SSE streamGenerateContent) request
+func (p *LLMPlugin) newGeminiRequest(params map[string]interface{}, stream bool) (*http.Request, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY not configured")
+	}
+
+	var contents []interface{}
+	if contentSlice, ok := params["contents"].([]interface{}); ok && len(contentSlice) > 0 {
+		contents = contentSlice
+	} else if prompt, ok := params["prompt"].(string); ok && prompt != "" {
+		contents = []interface{}{map[string]interface{}{
+			"role":  "user",
+			"parts": []interface{}{map[string]interface{}{"text": prompt}},
+		}}
+	} else {
+		return nil, fmt.Errorf("contents are required")
+	}
+
+	model := "gemini-1.5-flash"
+	if m, ok := params["model"].(string); ok && m != "" {
 		model = m
 	}
 
-	request := OllamaRequest{
-		Model:  model,
-		Prompt: prompt,
-		Stream: false,
+	request := GeminiRequest{Contents: contents}
+	generationConfig := map[string]interface{}{}
+	if _, ok := params["temperature"]; ok {
+		generationConfig["temperature"] = getFloatParam(params, "temperature", 1.0)
+	}
+	if _, ok := params["max_output_tokens"]; ok {
+		generationConfig["maxOutputTokens"] = getIntParam(params, "max_output_tokens", 0)
+	}
+	if len(generationConfig) > 0 {
+		request.GenerationConfig = generationConfig
+	}
+	if safety, ok := params["safety_settings"].([]interface{}); ok {
+		request.SafetySettings = safety
 	}
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to marshal request: %v", err)}
+		return nil, fmt.Errorf("Failed to marshal request: %v", err)
 	}
 
-	client := &http.Client{Timeout: 120 * time.Second} // Longer timeout for local models
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/generate", ollamaURL), bytes.NewBuffer(jsonData))
+	endpoint := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent", model)
+	if stream {
+		endpoint = fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:streamGenerateContent?alt=sse", model)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to create request: %v", err)}
+		return nil, fmt.Errorf("Failed to create request: %v", err)
 	}
 
+	req.Header.Set("x-goog-api-key", apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
 
-	resp, err := client.Do(req)
+// openStreamFile opens the caller-provided stream_file or creates a temporary one
+func openStreamFile(params map[string]interface{}) (*os.File, error) {
+	if path, ok := params["stream_file"].(string); ok && path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create stream file: %v", err)
+		}
+		return file, nil
+	}
+
+	file, err := os.CreateTemp("", "corynth-llm-stream-*.txt")
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("HTTP request failed: %v", err)}
+		return nil, fmt.Errorf("Failed to create stream file: %v", err)
 	}
-	defer resp.Body.Close()
+	return file, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return map[string]interface{}{"error": fmt.Sprintf("Ollama API error (%d): %s", resp.StatusCode, string(body))}
+// readSSE calls handle with the payload of each "data:" line until handle reports completion
+func readSSE(body io.Reader, handle func(data string) (bool, error)) error {
+	return readLines(body, func(line string) (bool, error) {
+		if !strings.HasPrefix(line, "data:") {
+			return false, nil
+		}
+		return handle(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+	})
+}
+
+// readLines calls handle for each line of body until it reports completion or the body ends
+func readLines(body io.Reader, handle func(line string) (bool, error)) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		done, err := handle(scanner.Text())
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Failed to read stream: %v", err)
+	}
+	return nil
+}
+
+// getIntParam reads a numeric parameter that may arrive as a number or string
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	switch v := params[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		if parsed, err := strconv.Atoi(v); err == nil {
+			return parsed
+		}
 	}
+	return defaultValue
+}
 
-	var ollamaResp OllamaResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("Failed to decode response: %v", err)}
+// getTopPParam returns top_p when it was given, so the API default applies otherwise
+func getTopPParam(params map[string]interface{}) *float64 {
+	if _, ok := params["top_p"]; !ok {
+		return nil
 	}
+	topP := getFloatParam(params, "top_p", 1.0)
+	return &topP
+}
 
-	return map[string]interface{}{
-		"response": ollamaResp.Response,
+// getStopParam reads stop sequences given as an array of strings or a single string
+func getStopParam(params map[string]interface{}) []string {
+	switch v := params["stop"].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []interface{}:
+		var stop []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				stop = append(stop, s)
+			}
+		}
+		return stop
+	}
+	return nil
+}
+
+// getFloatParam reads a floating point parameter that may arrive as a number or string
+func getFloatParam(params map[string]interface{}, key string, defaultValue float64) float64 {
+	switch v := params[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			return parsed
+		}
 	}
+	return defaultValue
 }
 
 func main() {
@@ -0,0 +1,602 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type MongoDBPlugin struct{}
+
+func NewMongoDBPlugin() *MongoDBPlugin {
+	return &MongoDBPlugin{}
+}
+
+func (p *MongoDBPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "mongodb",
+		Version:     "1.0.0",
+		Description: "MongoDB document queries, updates, aggregation and index management",
+		Author:      "Corynth Team",
+		Tags:        []string{"mongodb", "database", "nosql", "document", "aggregation"},
+	}
+}
+
+func (p *MongoDBPlugin) GetActions() map[string]ActionSpec {
+	withConnection := func(inputs map[string]IOSpec) map[string]IOSpec {
+		inputs["connection_string"] = IOSpec{Type: "string", Required: false, Description: "MongoDB URI (defaults to MONGODB_URI)"}
+		inputs["database"] = IOSpec{Type: "string", Required: false, Description: "Database name (defaults to the database in the URI)"}
+		inputs["collection"] = IOSpec{Type: "string", Required: true, Description: "Collection name"}
+		inputs["timeout"] = IOSpec{Type: "number", Required: false, Default: 30, Description: "Operation timeout in seconds"}
+		return inputs
+	}
+
+	return map[string]ActionSpec{
+		"find": {
+			Description: "Find documents matching a filter",
+			Inputs: withConnection(map[string]IOSpec{
+				"filter":     {Type: "object", Required: false, Description: "Query filter (extended JSON, e.g. {\"_id\": {\"$oid\": \"...\"}})"},
+				"projection": {Type: "object", Required: false, Description: "Fields to include or exclude"},
+				"sort":       {Type: "object", Required: false, Description: "Sort specification, e.g. {\"created\": -1}; use an array such as [{\"a\": 1}, {\"b\": -1}] to sort on several fields in order"},
+				"limit":      {Type: "number", Required: false, Description: "Maximum number of documents"},
+				"skip":       {Type: "number", Required: false, Description: "Number of documents to skip"},
+			}),
+			Outputs: map[string]IOSpec{
+				"documents": {Type: "array", Description: "Matching documents as relaxed extended JSON"},
+				"count":     {Type: "number", Description: "Number of documents returned"},
+			},
+		},
+		"insert_one": {
+			Description: "Insert a document",
+			Inputs: withConnection(map[string]IOSpec{
+				"document": {Type: "object", Required: true, Description: "Document to insert"},
+			}),
+			Outputs: map[string]IOSpec{
+				"inserted_id": {Type: "object", Description: "ID of the inserted document"},
+			},
+		},
+		"insert_many": {
+			Description: "Insert several documents",
+			Inputs: withConnection(map[string]IOSpec{
+				"documents": {Type: "array", Required: true, Description: "Documents to insert"},
+				"ordered":   {Type: "boolean", Required: false, Default: true, Description: "Stop at the first failed insert"},
+			}),
+			Outputs: map[string]IOSpec{
+				"inserted_ids":   {Type: "array", Description: "IDs of the inserted documents"},
+				"inserted_count": {Type: "number", Description: "Number of documents inserted"},
+			},
+		},
+		"update_one": {
+			Description: "Update the first document matching a filter",
+			Inputs: withConnection(map[string]IOSpec{
+				"filter": {Type: "object", Required: true, Description: "Query filter"},
+				"update": {Type: "object", Required: true, Description: "Update operators, e.g. {\"$set\": {\"status\": \"done\"}}"},
+				"upsert": {Type: "boolean", Required: false, Default: false, Description: "Insert a document when none matches"},
+			}),
+			Outputs: map[string]IOSpec{
+				"matched_count":  {Type: "number", Description: "Number of documents matched"},
+				"modified_count": {Type: "number", Description: "Number of documents modified"},
+				"upserted_id":    {Type: "object", Description: "ID of the upserted document, if any"},
+			},
+		},
+		"delete_one": {
+			Description: "Delete the first document matching a filter",
+			Inputs: withConnection(map[string]IOSpec{
+				"filter": {Type: "object", Required: true, Description: "Query filter"},
+			}),
+			Outputs: map[string]IOSpec{
+				"deleted_count": {Type: "number", Description: "Number of documents deleted"},
+			},
+		},
+		"delete_many": {
+			Description: "Delete all documents matching a filter",
+			Inputs: withConnection(map[string]IOSpec{
+				"filter": {Type: "object", Required: true, Description: "Query filter; {} deletes every document"},
+			}),
+			Outputs: map[string]IOSpec{
+				"deleted_count": {Type: "number", Description: "Number of documents deleted"},
+			},
+		},
+		"aggregate": {
+			Description: "Run an aggregation pipeline",
+			Inputs: withConnection(map[string]IOSpec{
+				"pipeline": {Type: "array", Required: true, Description: "Pipeline stages, e.g. [{\"$match\": {...}}, {\"$group\": {...}}]"},
+			}),
+			Outputs: map[string]IOSpec{
+				"documents": {Type: "array", Description: "Pipeline results"},
+				"count":     {Type: "number", Description: "Number of documents returned"},
+			},
+		},
+		"create_index": {
+			Description: "Create an index on a collection",
+			Inputs: withConnection(map[string]IOSpec{
+				"keys":   {Type: "object", Required: true, Description: "Index keys, e.g. {\"email\": 1}; use an array such as [{\"a\": 1}, {\"b\": -1}] for compound indexes"},
+				"unique": {Type: "boolean", Required: false, Default: false, Description: "Enforce unique values"},
+				"name":   {Type: "string", Required: false, Description: "Index name (generated from the keys by default)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"index_name": {Type: "string", Description: "Name of the created index"},
+			},
+		},
+		"count": {
+			Description: "Count documents matching a filter",
+			Inputs: withConnection(map[string]IOSpec{
+				"filter": {Type: "object", Required: false, Description: "Query filter"},
+			}),
+			Outputs: map[string]IOSpec{
+				"count": {Type: "number", Description: "Number of matching documents"},
+			},
+		},
+	}
+}
+
+func (p *MongoDBPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(context.Context, *mongo.Collection, map[string]interface{}) (map[string]interface{}, error)
+
+	switch action {
+	case "find":
+		run = p.find
+	case "insert_one":
+		run = p.insertOne
+	case "insert_many":
+		run = p.insertMany
+	case "update_one":
+		run = p.updateOne
+	case "delete_one":
+		run = p.deleteOne
+	case "delete_many":
+		run = p.deleteMany
+	case "aggregate":
+		run = p.aggregate
+	case "create_index":
+		run = p.createIndex
+	case "count":
+		run = p.count
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	uri := getStringParam(params, "connection_string", os.Getenv("MONGODB_URI"))
+	if uri == "" {
+		return map[string]interface{}{"error": "connection_string or MONGODB_URI is required"}, nil
+	}
+
+	database := getStringParam(params, "database", "")
+	if database == "" {
+		cs, err := connstring.ParseAndValidate(uri)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid connection string: %v", err)}, nil
+		}
+		database = cs.Database
+	}
+	if database == "" {
+		return map[string]interface{}{"error": "database is required when the connection string does not name one"}, nil
+	}
+
+	collection := getStringParam(params, "collection", "")
+	if collection == "" {
+		return map[string]interface{}{"error": "collection is required"}, nil
+	}
+
+	timeout := time.Duration(getIntParam(params, "timeout", 30)) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetServerSelectionTimeout(timeout))
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to connect: %v", err)}, nil
+	}
+	defer client.Disconnect(context.Background())
+
+	result, err := run(ctx, client.Database(database).Collection(collection), params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func (p *MongoDBPlugin) find(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	filter, err := toDocument(params, "filter", false)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find()
+	if projection, err := toDocument(params, "projection", false); err != nil {
+		return nil, err
+	} else if len(projection) > 0 {
+		opts.SetProjection(projection)
+	}
+	if sort, err := toOrderedDocument(params, "sort", false); err != nil {
+		return nil, err
+	} else if len(sort) > 0 {
+		opts.SetSort(sort)
+	}
+	if limit := getIntParam(params, "limit", 0); limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	if skip := getIntParam(params, "skip", 0); skip > 0 {
+		opts.SetSkip(int64(skip))
+	}
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %v", err)
+	}
+
+	documents, err := readCursor(ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"documents": documents,
+		"count":     len(documents),
+	}, nil
+}
+
+func (p *MongoDBPlugin) insertOne(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	document, err := toDocument(params, "document", true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := coll.InsertOne(ctx, document)
+	if err != nil {
+		return nil, fmt.Errorf("insert failed: %v", err)
+	}
+
+	insertedID, err := toJSONValue(res.InsertedID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"inserted_id": insertedID,
+	}, nil
+}
+
+func (p *MongoDBPlugin) insertMany(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	items, ok := params["documents"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("documents is required")
+	}
+
+	documents := make([]interface{}, len(items))
+	for i, item := range items {
+		document, err := toBSON(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid document %d: %v", i, err)
+		}
+		documents[i] = document
+	}
+
+	res, err := coll.InsertMany(ctx, documents, options.InsertMany().SetOrdered(getBoolParam(params, "ordered", true)))
+	if err != nil {
+		return nil, fmt.Errorf("insert failed: %v", err)
+	}
+
+	insertedIDs := make([]interface{}, len(res.InsertedIDs))
+	for i, id := range res.InsertedIDs {
+		if insertedIDs[i], err = toJSONValue(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"inserted_ids":   insertedIDs,
+		"inserted_count": len(insertedIDs),
+	}, nil
+}
+
+func (p *MongoDBPlugin) updateOne(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	filter, err := toDocument(params, "filter", true)
+	if err != nil {
+		return nil, err
+	}
+	update, err := toDocument(params, "update", true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(getBoolParam(params, "upsert", false)))
+	if err != nil {
+		return nil, fmt.Errorf("update failed: %v", err)
+	}
+
+	upsertedID, err := toJSONValue(res.UpsertedID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"matched_count":  res.MatchedCount,
+		"modified_count": res.ModifiedCount,
+		"upserted_id":    upsertedID,
+	}, nil
+}
+
+func (p *MongoDBPlugin) deleteOne(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	filter, err := toDocument(params, "filter", true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := coll.DeleteOne(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("delete failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"deleted_count": res.DeletedCount,
+	}, nil
+}
+
+func (p *MongoDBPlugin) deleteMany(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	// The filter is required so that deleting every document takes an explicit {}
+	filter, err := toDocument(params, "filter", true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := coll.DeleteMany(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("delete failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"deleted_count": res.DeletedCount,
+	}, nil
+}
+
+func (p *MongoDBPlugin) aggregate(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	stages, ok := params["pipeline"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("pipeline is required")
+	}
+
+	pipeline := make(mongo.Pipeline, len(stages))
+	for i, stage := range stages {
+		document, err := toBSON(stage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline stage %d: %v", i, err)
+		}
+		pipeline[i] = document
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate failed: %v", err)
+	}
+
+	documents, err := readCursor(ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"documents": documents,
+		"count":     len(documents),
+	}, nil
+}
+
+func (p *MongoDBPlugin) createIndex(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	keys, err := toOrderedDocument(params, "keys", true)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Index().SetUnique(getBoolParam(params, "unique", false))
+	if name := getStringParam(params, "name", ""); name != "" {
+		opts.SetName(name)
+	}
+
+	name, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: opts})
+	if err != nil {
+		return nil, fmt.Errorf("create index failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"index_name": name,
+	}, nil
+}
+
+func (p *MongoDBPlugin) count(ctx context.Context, coll *mongo.Collection, params map[string]interface{}) (map[string]interface{}, error) {
+	filter, err := toDocument(params, "filter", false)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("count failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"count": n,
+	}, nil
+}
+
+// toDocument converts the object param key to BSON. A missing optional param is an empty document.
+func toDocument(params map[string]interface{}, key string, required bool) (bson.D, error) {
+	value, ok := params[key]
+	if !ok || value == nil {
+		if required {
+			return nil, fmt.Errorf("%s is required", key)
+		}
+		return bson.D{}, nil
+	}
+
+	document, err := toBSON(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return document, nil
+}
+
+// toOrderedDocument is toDocument for params where field order matters. Decoded params
+// lose key order, so an array of single-field objects is accepted and concatenated.
+func toOrderedDocument(params map[string]interface{}, key string, required bool) (bson.D, error) {
+	items, ok := params[key].([]interface{})
+	if !ok {
+		return toDocument(params, key, required)
+	}
+	if len(items) == 0 && required {
+		return nil, fmt.Errorf("%s is required", key)
+	}
+
+	document := bson.D{}
+	for _, item := range items {
+		part, err := toBSON(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+		document = append(document, part...)
+	}
+	return document, nil
+}
+
+// toBSON converts a JSON object to a BSON document. Values are read as extended JSON,
+// so {"$oid": ...} and {"$date": ...} become ObjectIDs and dates.
+func toBSON(value interface{}) (bson.D, error) {
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("expected an object")
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bson.D
+	if err := bson.UnmarshalExtJSON(data, false, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// toJSONValue converts a BSON value to plain JSON using relaxed extended JSON
+func toJSONValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := bson.MarshalExtJSON(bson.M{"v": value}, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %v", err)
+	}
+
+	var wrapper map[string]interface{}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to encode result: %v", err)
+	}
+	return wrapper["v"], nil
+}
+
+func readCursor(ctx context.Context, cursor *mongo.Cursor) ([]interface{}, error) {
+	defer cursor.Close(ctx)
+
+	documents := []interface{}{}
+	for cursor.Next(ctx) {
+		document, err := toJSONValue(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %v", err)
+	}
+	return documents, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewMongoDBPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
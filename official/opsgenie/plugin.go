@@ -0,0 +1,571 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// defaultAPIURL is used unless OPSGENIE_API_URL overrides it (e.g. https://api.eu.opsgenie.com)
+const defaultAPIURL = "https://api.opsgenie.com"
+
+// requestWait bounds how long mutating actions wait for OpsGenie to process their request
+const requestWait = 15 * time.Second
+
+type OpsGeniePlugin struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewOpsGeniePlugin() *OpsGeniePlugin {
+	baseURL := os.Getenv("OPSGENIE_API_URL")
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	return &OpsGeniePlugin{
+		apiKey:  os.Getenv("OPSGENIE_API_KEY"),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *OpsGeniePlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "opsgenie",
+		Version:     "1.0.0",
+		Description: "OpsGenie alert lifecycle management and on-call lookups",
+		Author:      "Corynth Team",
+		Tags:        []string{"opsgenie", "alerts", "incidents", "on-call", "alerting"},
+	}
+}
+
+func (p *OpsGeniePlugin) GetActions() map[string]ActionSpec {
+	identifierInputs := func(extra map[string]IOSpec) map[string]IOSpec {
+		inputs := map[string]IOSpec{
+			"alert_id": {Type: "string", Required: false, Description: "Alert ID (set alert_id or alias)"},
+			"alias":    {Type: "string", Required: false, Description: "Alert alias, used when alert_id is not given"},
+		}
+		for name, spec := range extra {
+			inputs[name] = spec
+		}
+		return inputs
+	}
+	mutationOutputs := map[string]IOSpec{
+		"alert_id":   {Type: "string", Description: "Alert ID"},
+		"request_id": {Type: "string", Description: "OpsGenie request ID"},
+		"is_success": {Type: "boolean", Description: "Whether OpsGenie processed the request successfully"},
+		"status":     {Type: "string", Description: "Processing status reported by OpsGenie"},
+	}
+	noteInput := IOSpec{Type: "string", Required: false, Description: "Note to add to the alert"}
+
+	return map[string]ActionSpec{
+		"create_alert": {
+			Description: "Create an alert",
+			Inputs: map[string]IOSpec{
+				"message":     {Type: "string", Required: true, Description: "Alert message (up to 130 characters)"},
+				"description": {Type: "string", Required: false, Description: "Alert details (up to 15000 characters)"},
+				"priority":    {Type: "string", Required: false, Default: "P3", Description: "Priority from P1 (critical) to P5 (informational)"},
+				"tags":        {Type: "array", Required: false, Description: "Tags"},
+				"details":     {Type: "object", Required: false, Description: "Custom properties as key/value pairs"},
+				"responders":  {Type: "array", Required: false, Description: "Responders as {type: team|user|escalation|schedule, id} (name or username may replace id)"},
+				"alias":       {Type: "string", Required: false, Description: "De-duplication key"},
+				"source":      {Type: "string", Required: false, Description: "Source of the alert"},
+			},
+			Outputs: mutationOutputs,
+		},
+		"close_alert": {
+			Description: "Close an alert",
+			Inputs:      identifierInputs(map[string]IOSpec{"note": noteInput}),
+			Outputs:     mutationOutputs,
+		},
+		"acknowledge_alert": {
+			Description: "Acknowledge an alert",
+			Inputs:      identifierInputs(map[string]IOSpec{"note": noteInput}),
+			Outputs:     mutationOutputs,
+		},
+		"add_note": {
+			Description: "Add a note to an alert",
+			Inputs: identifierInputs(map[string]IOSpec{
+				"note": {Type: "string", Required: true, Description: "Note text"},
+			}),
+			Outputs: mutationOutputs,
+		},
+		"get_alert": {
+			Description: "Get an alert",
+			Inputs:      identifierInputs(nil),
+			Outputs: map[string]IOSpec{
+				"alert": {Type: "object", Description: "Alert with alert_id, tiny_id, message, status, acknowledged, priority, tags, owner, created_at, updated_at, description and details"},
+			},
+		},
+		"list_alerts": {
+			Description: "Search alerts",
+			Inputs: map[string]IOSpec{
+				"query": {Type: "string", Required: false, Description: "Search query, e.g. status:open AND priority:P1"},
+				"limit": {Type: "number", Required: false, Default: 20, Description: "Maximum number of alerts (up to 100)"},
+				"sort":  {Type: "string", Required: false, Default: "createdAt", Description: "Field to sort by, e.g. createdAt, updatedAt or priority"},
+				"order": {Type: "string", Required: false, Default: "desc", Description: "asc or desc"},
+			},
+			Outputs: map[string]IOSpec{
+				"alerts": {Type: "array", Description: "Alerts with alert_id, tiny_id, message, status, acknowledged, priority, tags, owner, created_at and updated_at"},
+				"count":  {Type: "number", Description: "Number of alerts returned"},
+			},
+		},
+		"get_on_call": {
+			Description: "Get who is on call for a schedule",
+			Inputs: map[string]IOSpec{
+				"schedule_id": {Type: "string", Required: true, Description: "Schedule ID"},
+				"date":        {Type: "string", Required: false, Description: "RFC 3339 time to look up (defaults to now)"},
+			},
+			Outputs: map[string]IOSpec{
+				"on_call":       {Type: "array", Description: "Usernames of the on-call users"},
+				"schedule_name": {Type: "string", Description: "Schedule name"},
+			},
+		},
+	}
+}
+
+func (p *OpsGeniePlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(map[string]interface{}) (map[string]interface{}, error)
+	switch action {
+	case "create_alert":
+		run = p.createAlert
+	case "close_alert", "acknowledge_alert":
+		operation := strings.TrimSuffix(action, "_alert")
+		run = func(params map[string]interface{}) (map[string]interface{}, error) {
+			return p.alertAction(params, operation)
+		}
+	case "add_note":
+		run = p.addNote
+	case "get_alert":
+		run = p.getAlert
+	case "list_alerts":
+		run = p.listAlerts
+	case "get_on_call":
+		run = p.getOnCall
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	if p.apiKey == "" {
+		return map[string]interface{}{"error": "OPSGENIE_API_KEY not configured"}, nil
+	}
+
+	result, err := run(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func (p *OpsGeniePlugin) createAlert(params map[string]interface{}) (map[string]interface{}, error) {
+	message := getStringParam(params, "message", "")
+	if message == "" {
+		return nil, errors.New("message is required")
+	}
+
+	priority := strings.ToUpper(getStringParam(params, "priority", "P3"))
+	switch priority {
+	case "P1", "P2", "P3", "P4", "P5":
+	default:
+		return nil, fmt.Errorf("unsupported priority: %s (use P1 to P5)", priority)
+	}
+
+	alert := map[string]interface{}{
+		"message":  message,
+		"priority": priority,
+	}
+	for _, key := range []string{"description", "alias", "source"} {
+		if value := getStringParam(params, key, ""); value != "" {
+			alert[key] = value
+		}
+	}
+	if tags := getStringSlice(params, "tags"); len(tags) > 0 {
+		alert["tags"] = tags
+	}
+	if details, ok := params["details"].(map[string]interface{}); ok && len(details) > 0 {
+		// OpsGenie only accepts string values for custom properties
+		stringDetails := make(map[string]string, len(details))
+		for key, value := range details {
+			stringDetails[key] = fmt.Sprint(value)
+		}
+		alert["details"] = stringDetails
+	}
+	if items, ok := params["responders"].([]interface{}); ok && len(items) > 0 {
+		responders, err := parseResponders(items)
+		if err != nil {
+			return nil, err
+		}
+		alert["responders"] = responders
+	}
+
+	return p.mutate("POST", "/v2/alerts", alert)
+}
+
+// parseResponders checks each responder has a type and something identifying it
+func parseResponders(items []interface{}) ([]map[string]interface{}, error) {
+	responders := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		responder, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("responders[%d] must be an object", i)
+		}
+		switch responder["type"] {
+		case "team", "user", "escalation", "schedule":
+		default:
+			return nil, fmt.Errorf("responders[%d].type must be team, user, escalation or schedule", i)
+		}
+		if responder["id"] == nil && responder["name"] == nil && responder["username"] == nil {
+			return nil, fmt.Errorf("responders[%d] needs an id, name or username", i)
+		}
+		responders = append(responders, responder)
+	}
+	return responders, nil
+}
+
+func (p *OpsGeniePlugin) alertAction(params map[string]interface{}, operation string) (map[string]interface{}, error) {
+	path, err := alertPath(params, "/"+operation)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{}
+	if note := getStringParam(params, "note", ""); note != "" {
+		body["note"] = note
+	}
+	return p.mutate("POST", path, body)
+}
+
+func (p *OpsGeniePlugin) addNote(params map[string]interface{}) (map[string]interface{}, error) {
+	note := getStringParam(params, "note", "")
+	if note == "" {
+		return nil, errors.New("note is required")
+	}
+	path, err := alertPath(params, "/notes")
+	if err != nil {
+		return nil, err
+	}
+	return p.mutate("POST", path, map[string]interface{}{"note": note})
+}
+
+func (p *OpsGeniePlugin) getAlert(params map[string]interface{}) (map[string]interface{}, error) {
+	path, err := alertPath(params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.callAPI("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	alert := alertResult(data)
+	alert["description"] = data["description"]
+	alert["details"] = data["details"]
+	return map[string]interface{}{"alert": alert}, nil
+}
+
+func (p *OpsGeniePlugin) listAlerts(params map[string]interface{}) (map[string]interface{}, error) {
+	limit := getIntParam(params, "limit", 20)
+	if limit < 1 || limit > 100 {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+	order := getStringParam(params, "order", "desc")
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("unsupported order: %s (use asc or desc)", order)
+	}
+
+	query := url.Values{}
+	if q := getStringParam(params, "query", ""); q != "" {
+		query.Set("query", q)
+	}
+	query.Set("limit", fmt.Sprint(limit))
+	query.Set("sort", getStringParam(params, "sort", "createdAt"))
+	query.Set("order", order)
+
+	result, err := p.callAPI("GET", "/v2/alerts?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []interface{}{}
+	items, _ := result["data"].([]interface{})
+	for _, item := range items {
+		if alert, ok := item.(map[string]interface{}); ok {
+			alerts = append(alerts, alertResult(alert))
+		}
+	}
+
+	return map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+	}, nil
+}
+
+func (p *OpsGeniePlugin) getOnCall(params map[string]interface{}) (map[string]interface{}, error) {
+	scheduleID := getStringParam(params, "schedule_id", "")
+	if scheduleID == "" {
+		return nil, errors.New("schedule_id is required")
+	}
+
+	// flat=true returns the on-call users' usernames rather than a tree of participants
+	query := url.Values{}
+	query.Set("flat", "true")
+	if date := getStringParam(params, "date", ""); date != "" {
+		if _, err := time.Parse(time.RFC3339, date); err != nil {
+			return nil, fmt.Errorf("invalid date: %v", err)
+		}
+		query.Set("date", date)
+	}
+
+	result, err := p.callAPI("GET", "/v2/schedules/"+url.PathEscape(scheduleID)+"/on-calls?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	onCall := getStringSlice(data, "onCallRecipients")
+	parent, _ := data["_parent"].(map[string]interface{})
+	return map[string]interface{}{
+		"on_call":       onCall,
+		"schedule_name": parent["name"],
+	}, nil
+}
+
+// alertPath builds /v2/alerts/{identifier}{suffix}, addressing the alert by ID or by alias
+func alertPath(params map[string]interface{}, suffix string) (string, error) {
+	if id := getStringParam(params, "alert_id", ""); id != "" {
+		return "/v2/alerts/" + url.PathEscape(id) + suffix + "?identifierType=id", nil
+	}
+	if alias := getStringParam(params, "alias", ""); alias != "" {
+		return "/v2/alerts/" + url.PathEscape(alias) + suffix + "?identifierType=alias", nil
+	}
+	return "", errors.New("alert_id or alias is required")
+}
+
+// mutate sends a write request. OpsGenie only queues these, so the request status
+// is polled to learn the alert ID and whether processing succeeded.
+func (p *OpsGeniePlugin) mutate(method, path string, body interface{}) (map[string]interface{}, error) {
+	result, err := p.callAPI(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	requestID, _ := result["requestId"].(string)
+	if requestID == "" {
+		return nil, errors.New("OpsGenie did not return a request ID")
+	}
+
+	deadline := time.Now().Add(requestWait)
+	for {
+		status, err := p.callAPI("GET", "/v2/alerts/requests/"+url.PathEscape(requestID), nil)
+		if err == nil {
+			data, _ := status["data"].(map[string]interface{})
+			isSuccess, _ := data["isSuccess"].(bool)
+			return map[string]interface{}{
+				"alert_id":   data["alertId"],
+				"request_id": requestID,
+				"is_success": isSuccess,
+				"status":     data["status"],
+			}, nil
+		}
+		// The status endpoint answers 404 until the request has been processed
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || apiErr.status != http.StatusNotFound || time.Now().After(deadline) {
+			return map[string]interface{}{
+				"alert_id":   nil,
+				"request_id": requestID,
+				"is_success": false,
+				"status":     fmt.Sprintf("request status unavailable: %v", err),
+			}, nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// alertResult extracts the fields get_alert and list_alerts return
+func alertResult(alert map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"alert_id":     alert["id"],
+		"tiny_id":      alert["tinyId"],
+		"alias":        alert["alias"],
+		"message":      alert["message"],
+		"status":       alert["status"],
+		"acknowledged": alert["acknowledged"],
+		"priority":     alert["priority"],
+		"tags":         alert["tags"],
+		"owner":        alert["owner"],
+		"created_at":   alert["createdAt"],
+		"updated_at":   alert["updatedAt"],
+	}
+}
+
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("OpsGenie API error (%d): %s", e.status, e.message)
+}
+
+// callAPI sends a request to the REST API v2 authenticated with a GenieKey
+func (p *OpsGeniePlugin) callAPI(method, path string, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "GenieKey "+p.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, newAPIError(resp.StatusCode, result, data)
+	}
+	return result, nil
+}
+
+// newAPIError formats an OpsGenie error, including any per-field validation errors
+func newAPIError(status int, result map[string]interface{}, raw []byte) *apiError {
+	message, _ := result["message"].(string)
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+
+	if fields, ok := result["errors"].(map[string]interface{}); ok && len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		details := make([]string, 0, len(keys))
+		for _, key := range keys {
+			details = append(details, fmt.Sprintf("%s: %v", key, fields[key]))
+		}
+		message += " (" + strings.Join(details, "; ") + ")"
+	}
+	return &apiError{status: status, message: message}
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewOpsGeniePlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
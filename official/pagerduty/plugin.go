@@ -0,0 +1,516 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+// defaultAPIURL is used unless PAGERDUTY_API_URL overrides it (e.g. for the EU service region)
+const defaultAPIURL = "https://api.pagerduty.com"
+
+type PagerDutyPlugin struct {
+	apiKey  string
+	from    string
+	baseURL string
+	client  *http.Client
+}
+
+func NewPagerDutyPlugin() *PagerDutyPlugin {
+	baseURL := os.Getenv("PAGERDUTY_API_URL")
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	return &PagerDutyPlugin{
+		apiKey:  os.Getenv("PAGERDUTY_API_KEY"),
+		from:    os.Getenv("PAGERDUTY_FROM"),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *PagerDutyPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "pagerduty",
+		Version:     "1.0.0",
+		Description: "PagerDuty incident management and on-call lookups",
+		Author:      "Corynth Team",
+		Tags:        []string{"pagerduty", "incidents", "on-call", "alerting"},
+	}
+}
+
+func (p *PagerDutyPlugin) GetActions() map[string]ActionSpec {
+	fromInput := IOSpec{Type: "string", Required: false, Description: "Email of the PagerDuty user making the change (defaults to PAGERDUTY_FROM)"}
+	incidentOutputs := map[string]IOSpec{
+		"incident_id": {Type: "string", Description: "Incident ID"},
+		"html_url":    {Type: "string", Description: "Incident web URL"},
+		"status":      {Type: "string", Description: "Incident status"},
+		"assigned_to": {Type: "array", Description: "Names of the assigned users"},
+	}
+
+	return map[string]ActionSpec{
+		"create_incident": {
+			Description: "Create an incident",
+			Inputs: map[string]IOSpec{
+				"title":                {Type: "string", Required: true, Description: "Incident title"},
+				"service_id":           {Type: "string", Required: true, Description: "Service ID"},
+				"urgency":              {Type: "string", Required: false, Default: "high", Description: "Urgency: high or low"},
+				"body":                 {Type: "string", Required: false, Description: "Incident details"},
+				"escalation_policy_id": {Type: "string", Required: false, Description: "Escalation policy ID (defaults to the service's policy)"},
+				"incident_key":         {Type: "string", Required: false, Description: "De-duplication key"},
+				"from_email":           fromInput,
+			},
+			Outputs: incidentOutputs,
+		},
+		"resolve_incident": {
+			Description: "Resolve an incident",
+			Inputs: map[string]IOSpec{
+				"incident_id": {Type: "string", Required: true, Description: "Incident ID"},
+				"from_email":  fromInput,
+			},
+			Outputs: incidentOutputs,
+		},
+		"acknowledge_incident": {
+			Description: "Acknowledge an incident",
+			Inputs: map[string]IOSpec{
+				"incident_id": {Type: "string", Required: true, Description: "Incident ID"},
+				"from_email":  fromInput,
+			},
+			Outputs: incidentOutputs,
+		},
+		"get_oncall": {
+			Description: "Get who is on call for a schedule",
+			Inputs: map[string]IOSpec{
+				"schedule_id": {Type: "string", Required: true, Description: "Schedule ID"},
+				"time":        {Type: "string", Required: false, Description: "RFC 3339 time to look up (defaults to now)"},
+			},
+			Outputs: map[string]IOSpec{
+				"oncall": {Type: "array", Description: "On-call entries with user, user_id, escalation_level, start and end"},
+				"users":  {Type: "array", Description: "Names of the on-call users"},
+			},
+		},
+		"create_note": {
+			Description: "Add a note to an incident",
+			Inputs: map[string]IOSpec{
+				"incident_id":  {Type: "string", Required: true, Description: "Incident ID"},
+				"note_content": {Type: "string", Required: true, Description: "Note text"},
+				"from_email":   fromInput,
+			},
+			Outputs: map[string]IOSpec{
+				"note_id":    {Type: "string", Description: "Note ID"},
+				"created_at": {Type: "string", Description: "Creation time"},
+			},
+		},
+		"list_incidents": {
+			Description: "List incidents",
+			Inputs: map[string]IOSpec{
+				"statuses":    {Type: "array", Required: false, Description: "Statuses to include: triggered, acknowledged, resolved"},
+				"service_ids": {Type: "array", Required: false, Description: "Only incidents on these services"},
+				"since":       {Type: "string", Required: false, Description: "Start of the date range (RFC 3339)"},
+				"until":       {Type: "string", Required: false, Description: "End of the date range (RFC 3339)"},
+				"limit":       {Type: "number", Required: false, Default: 25, Description: "Maximum number of incidents"},
+			},
+			Outputs: map[string]IOSpec{
+				"incidents": {Type: "array", Description: "Incidents with incident_id, title, html_url, status, urgency and assigned_to"},
+				"count":     {Type: "number", Description: "Number of incidents returned"},
+				"more":      {Type: "boolean", Description: "Whether more incidents match"},
+			},
+		},
+	}
+}
+
+func (p *PagerDutyPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	if p.apiKey == "" {
+		return map[string]interface{}{"error": "PAGERDUTY_API_KEY not configured"}, nil
+	}
+
+	switch action {
+	case "create_incident":
+		return p.createIncident(params)
+	case "resolve_incident":
+		return p.updateIncidentStatus(params, "resolved")
+	case "acknowledge_incident":
+		return p.updateIncidentStatus(params, "acknowledged")
+	case "get_oncall":
+		return p.getOncall(params)
+	case "create_note":
+		return p.createNote(params)
+	case "list_incidents":
+		return p.listIncidents(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *PagerDutyPlugin) createIncident(params map[string]interface{}) (map[string]interface{}, error) {
+	title := getStringParam(params, "title", "")
+	serviceID := getStringParam(params, "service_id", "")
+	if title == "" || serviceID == "" {
+		return map[string]interface{}{"error": "title and service_id are required"}, nil
+	}
+
+	urgency := getStringParam(params, "urgency", "high")
+	if urgency != "high" && urgency != "low" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported urgency: %s", urgency)}, nil
+	}
+
+	incident := map[string]interface{}{
+		"type":    "incident",
+		"title":   title,
+		"urgency": urgency,
+		"service": map[string]interface{}{"id": serviceID, "type": "service_reference"},
+	}
+	if body := getStringParam(params, "body", ""); body != "" {
+		incident["body"] = map[string]interface{}{"type": "incident_body", "details": body}
+	}
+	if policyID := getStringParam(params, "escalation_policy_id", ""); policyID != "" {
+		incident["escalation_policy"] = map[string]interface{}{"id": policyID, "type": "escalation_policy_reference"}
+	}
+	if key := getStringParam(params, "incident_key", ""); key != "" {
+		incident["incident_key"] = key
+	}
+
+	result, err := p.callAPI("POST", "/incidents", p.fromEmail(params), map[string]interface{}{"incident": incident})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	created, _ := result["incident"].(map[string]interface{})
+	return incidentResult(created), nil
+}
+
+func (p *PagerDutyPlugin) updateIncidentStatus(params map[string]interface{}, status string) (map[string]interface{}, error) {
+	incidentID := getStringParam(params, "incident_id", "")
+	if incidentID == "" {
+		return map[string]interface{}{"error": "incident_id is required"}, nil
+	}
+
+	result, err := p.callAPI("PUT", "/incidents/"+url.PathEscape(incidentID), p.fromEmail(params), map[string]interface{}{
+		"incident": map[string]interface{}{"type": "incident_reference", "status": status},
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	updated, _ := result["incident"].(map[string]interface{})
+	return incidentResult(updated), nil
+}
+
+func (p *PagerDutyPlugin) getOncall(params map[string]interface{}) (map[string]interface{}, error) {
+	scheduleID := getStringParam(params, "schedule_id", "")
+	if scheduleID == "" {
+		return map[string]interface{}{"error": "schedule_id is required"}, nil
+	}
+
+	query := url.Values{}
+	query.Add("schedule_ids[]", scheduleID)
+	query.Set("include[]", "users")
+	// A zero-length window returns whoever is on call at that instant
+	if at := getStringParam(params, "time", ""); at != "" {
+		if _, err := time.Parse(time.RFC3339, at); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid time: %v", err)}, nil
+		}
+		query.Set("since", at)
+		query.Set("until", at)
+	}
+
+	result, err := p.callAPI("GET", "/oncalls?"+query.Encode(), "", nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	oncall := []interface{}{}
+	users := []string{}
+	entries, _ := result["oncalls"].([]interface{})
+	for _, item := range entries {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		user, _ := entry["user"].(map[string]interface{})
+		name := referenceName(user)
+
+		oncall = append(oncall, map[string]interface{}{
+			"user":             name,
+			"user_id":          user["id"],
+			"email":            user["email"],
+			"escalation_level": entry["escalation_level"],
+			"start":            entry["start"],
+			"end":              entry["end"],
+		})
+		if name != "" && !containsString(users, name) {
+			users = append(users, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"oncall": oncall,
+		"users":  users,
+	}, nil
+}
+
+func (p *PagerDutyPlugin) createNote(params map[string]interface{}) (map[string]interface{}, error) {
+	incidentID := getStringParam(params, "incident_id", "")
+	content := getStringParam(params, "note_content", "")
+	if incidentID == "" || content == "" {
+		return map[string]interface{}{"error": "incident_id and note_content are required"}, nil
+	}
+
+	result, err := p.callAPI("POST", "/incidents/"+url.PathEscape(incidentID)+"/notes", p.fromEmail(params), map[string]interface{}{
+		"note": map[string]interface{}{"content": content},
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	note, _ := result["note"].(map[string]interface{})
+	return map[string]interface{}{
+		"note_id":    note["id"],
+		"created_at": note["created_at"],
+	}, nil
+}
+
+func (p *PagerDutyPlugin) listIncidents(params map[string]interface{}) (map[string]interface{}, error) {
+	query := url.Values{}
+	for _, status := range getStringSlice(params, "statuses") {
+		query.Add("statuses[]", status)
+	}
+	for _, serviceID := range getStringSlice(params, "service_ids") {
+		query.Add("service_ids[]", serviceID)
+	}
+	if since := getStringParam(params, "since", ""); since != "" {
+		query.Set("since", since)
+	}
+	if until := getStringParam(params, "until", ""); until != "" {
+		query.Set("until", until)
+	}
+	query.Set("limit", fmt.Sprintf("%d", getIntParam(params, "limit", 25)))
+
+	result, err := p.callAPI("GET", "/incidents?"+query.Encode(), "", nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	incidents := []interface{}{}
+	items, _ := result["incidents"].([]interface{})
+	for _, item := range items {
+		if incident, ok := item.(map[string]interface{}); ok {
+			entry := incidentResult(incident)
+			entry["title"] = incident["title"]
+			entry["urgency"] = incident["urgency"]
+			entry["created_at"] = incident["created_at"]
+			incidents = append(incidents, entry)
+		}
+	}
+
+	more, _ := result["more"].(bool)
+	return map[string]interface{}{
+		"incidents": incidents,
+		"count":     len(incidents),
+		"more":      more,
+	}, nil
+}
+
+func (p *PagerDutyPlugin) fromEmail(params map[string]interface{}) string {
+	return getStringParam(params, "from_email", p.from)
+}
+
+// incidentResult extracts the fields every incident action returns
+func incidentResult(incident map[string]interface{}) map[string]interface{} {
+	assignedTo := []string{}
+	assignments, _ := incident["assignments"].([]interface{})
+	for _, item := range assignments {
+		assignment, _ := item.(map[string]interface{})
+		assignee, _ := assignment["assignee"].(map[string]interface{})
+		if name := referenceName(assignee); name != "" {
+			assignedTo = append(assignedTo, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"incident_id": incident["id"],
+		"html_url":    incident["html_url"],
+		"status":      incident["status"],
+		"assigned_to": assignedTo,
+	}
+}
+
+// referenceName returns the display name of a full object or the summary of a reference
+func referenceName(ref map[string]interface{}) string {
+	if name, ok := ref["name"].(string); ok && name != "" {
+		return name
+	}
+	summary, _ := ref["summary"].(string)
+	return summary
+}
+
+// callAPI sends a request to the REST API v2. Write operations need a From header
+// naming a valid PagerDuty user.
+func (p *PagerDutyPlugin) callAPI(method, path, from string, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Token token="+p.apiKey)
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if from != "" {
+		req.Header.Set("From", from)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, apiError(resp.StatusCode, result, data)
+	}
+	return result, nil
+}
+
+// apiError formats a PagerDuty error object, including its list of detailed errors
+func apiError(status int, result map[string]interface{}, raw []byte) error {
+	errObj, _ := result["error"].(map[string]interface{})
+	message, _ := errObj["message"].(string)
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+
+	var details []string
+	if errs, ok := errObj["errors"].([]interface{}); ok {
+		for _, detail := range errs {
+			details = append(details, fmt.Sprintf("%v", detail))
+		}
+	}
+	if len(details) > 0 {
+		message += " (" + strings.Join(details, "; ") + ")"
+	}
+	return fmt.Errorf("PagerDuty API error (%d): %s", status, message)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func getStringSlice(params map[string]interface{}, key string) []string {
+	items, _ := params[key].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewPagerDutyPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -0,0 +1,693 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+var (
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+type PrometheusPlugin struct {
+	client *http.Client
+}
+
+func NewPrometheusPlugin() *PrometheusPlugin {
+	return &PrometheusPlugin{
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *PrometheusPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "prometheus",
+		Version:     "1.0.0",
+		Description: "Prometheus PromQL queries, alerts and rules, and Pushgateway metric push",
+		Author:      "Corynth Team",
+		Tags:        []string{"prometheus", "monitoring", "metrics", "promql", "alerting"},
+	}
+}
+
+func (p *PrometheusPlugin) GetActions() map[string]ActionSpec {
+	serverInput := IOSpec{Type: "string", Required: false, Description: "Prometheus server URL (defaults to PROMETHEUS_URL)"}
+	queryOutputs := map[string]IOSpec{
+		"result_type": {Type: "string", Description: "vector, matrix, scalar or string"},
+		"result":      {Type: "array", Description: "Query result as returned by the Prometheus API"},
+		"warnings":    {Type: "array", Description: "Warnings returned with the result"},
+	}
+
+	return map[string]ActionSpec{
+		"query": {
+			Description: "Evaluate an instant PromQL query",
+			Inputs: map[string]IOSpec{
+				"query": {Type: "string", Required: true, Description: "PromQL expression"},
+				"time":  {Type: "string", Required: false, Description: "Evaluation time as RFC 3339 or Unix timestamp (defaults to now)"},
+				"url":   serverInput,
+			},
+			Outputs: queryOutputs,
+		},
+		"query_range": {
+			Description: "Evaluate a PromQL query over a time range",
+			Inputs: map[string]IOSpec{
+				"query": {Type: "string", Required: true, Description: "PromQL expression"},
+				"start": {Type: "string", Required: false, Description: "Range start as RFC 3339 or Unix timestamp (defaults to one hour before end)"},
+				"end":   {Type: "string", Required: false, Description: "Range end as RFC 3339 or Unix timestamp (defaults to now)"},
+				"step":  {Type: "string", Required: false, Default: "60s", Description: "Resolution step as a duration (e.g. 15s) or seconds"},
+				"url":   serverInput,
+			},
+			Outputs: queryOutputs,
+		},
+		"push_metric": {
+			Description: "Push metrics to a Pushgateway",
+			Inputs: map[string]IOSpec{
+				"job":             {Type: "string", Required: true, Description: "Job label for the pushed group"},
+				"instance":        {Type: "string", Required: false, Description: "Instance label for the pushed group"},
+				"metrics":         {Type: "object", Required: true, Description: "Metrics as {name: value}, {name: {value, labels, type, help}}, or a list of {name, value, labels, type, help}"},
+				"replace":         {Type: "boolean", Required: false, Default: false, Description: "Replace all metrics in the group (PUT) instead of only those with the same name (POST)"},
+				"pushgateway_url": {Type: "string", Required: false, Description: "Pushgateway URL (defaults to PUSHGATEWAY_URL)"},
+			},
+			Outputs: map[string]IOSpec{
+				"success":      {Type: "boolean", Description: "Whether the push was accepted"},
+				"metric_count": {Type: "number", Description: "Number of samples pushed"},
+			},
+		},
+		"list_alerts": {
+			Description: "List active alerts",
+			Inputs: map[string]IOSpec{
+				"state": {Type: "string", Required: false, Description: "Only alerts in this state: firing or pending"},
+				"url":   serverInput,
+			},
+			Outputs: map[string]IOSpec{
+				"alerts": {Type: "array", Description: "Alerts with name, state, labels, annotations, active_at and value"},
+				"count":  {Type: "number", Description: "Number of alerts returned"},
+			},
+		},
+		"get_alert_rules": {
+			Description: "Get alerting rules",
+			Inputs: map[string]IOSpec{
+				"group_name": {Type: "string", Required: false, Description: "Only rules in this group"},
+				"url":        serverInput,
+			},
+			Outputs: map[string]IOSpec{
+				"groups": {Type: "array", Description: "Rule groups with name, file and rules"},
+				"count":  {Type: "number", Description: "Number of rules returned"},
+			},
+		},
+		"reload_config": {
+			Description: "Reload the Prometheus configuration (requires --web.enable-lifecycle)",
+			Inputs: map[string]IOSpec{
+				"url": serverInput,
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the reload succeeded"},
+			},
+		},
+	}
+}
+
+func (p *PrometheusPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	switch action {
+	case "query":
+		return p.query(params)
+	case "query_range":
+		return p.queryRange(params)
+	case "push_metric":
+		return p.pushMetric(params)
+	case "list_alerts":
+		return p.listAlerts(params)
+	case "get_alert_rules":
+		return p.getAlertRules(params)
+	case "reload_config":
+		return p.reloadConfig(params)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (p *PrometheusPlugin) query(params map[string]interface{}) (map[string]interface{}, error) {
+	query := getStringParam(params, "query", "")
+	if query == "" {
+		return map[string]interface{}{"error": "query is required"}, nil
+	}
+
+	values := url.Values{}
+	values.Set("query", query)
+	if t := getTimeParam(params, "time"); t != "" {
+		values.Set("time", t)
+	}
+
+	return p.runQuery(params, "/api/v1/query", values)
+}
+
+func (p *PrometheusPlugin) queryRange(params map[string]interface{}) (map[string]interface{}, error) {
+	query := getStringParam(params, "query", "")
+	if query == "" {
+		return map[string]interface{}{"error": "query is required"}, nil
+	}
+
+	end := getTimeParam(params, "end")
+	if end == "" {
+		end = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	start := getTimeParam(params, "start")
+	if start == "" {
+		endTime, err := parseTimestamp(end)
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("invalid end: %v", err)}, nil
+		}
+		start = strconv.FormatInt(endTime.Add(-time.Hour).Unix(), 10)
+	}
+
+	step := getStringParam(params, "step", "60s")
+	if n, ok := params["step"].(float64); ok {
+		step = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	values := url.Values{}
+	values.Set("query", query)
+	values.Set("start", start)
+	values.Set("end", end)
+	values.Set("step", step)
+
+	return p.runQuery(params, "/api/v1/query_range", values)
+}
+
+func (p *PrometheusPlugin) runQuery(params map[string]interface{}, path string, values url.Values) (map[string]interface{}, error) {
+	baseURL, err := serverURL(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	// POST keeps long PromQL expressions out of URL length limits
+	resp, err := p.callAPI("POST", baseURL+path, values)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	var data struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to parse query result: %v", err)}, nil
+	}
+
+	// Scalar and string results are a single [time, value] pair; wrap them so result is always an array
+	var result []interface{}
+	var raw interface{}
+	json.Unmarshal(data.Result, &raw)
+	switch r := raw.(type) {
+	case []interface{}:
+		if data.ResultType == "scalar" || data.ResultType == "string" {
+			result = []interface{}{r}
+		} else {
+			result = r
+		}
+	default:
+		result = []interface{}{}
+	}
+
+	warnings := resp.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return map[string]interface{}{
+		"result_type": data.ResultType,
+		"result":      result,
+		"warnings":    warnings,
+	}, nil
+}
+
+func (p *PrometheusPlugin) pushMetric(params map[string]interface{}) (map[string]interface{}, error) {
+	job := getStringParam(params, "job", "")
+	if job == "" {
+		return map[string]interface{}{"error": "job is required"}, nil
+	}
+
+	gatewayURL := strings.TrimRight(getStringParam(params, "pushgateway_url", os.Getenv("PUSHGATEWAY_URL")), "/")
+	if gatewayURL == "" {
+		return map[string]interface{}{"error": "pushgateway_url or PUSHGATEWAY_URL is required"}, nil
+	}
+
+	samples, err := parseSamples(params["metrics"])
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	path := "/metrics/" + groupingKey("job", job)
+	if instance := getStringParam(params, "instance", ""); instance != "" {
+		path += "/" + groupingKey("instance", instance)
+	}
+
+	method := "POST"
+	if getBoolParam(params, "replace", false) {
+		method = "PUT"
+	}
+
+	req, err := http.NewRequest(method, gatewayURL+path, strings.NewReader(formatSamples(samples)))
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create request: %v", err)}, nil
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": fmt.Sprintf("request failed: %v", err)}, nil
+	}
+	defer httpResp.Body.Close()
+	body, _ := io.ReadAll(httpResp.Body)
+
+	if httpResp.StatusCode >= 300 {
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Pushgateway error (%d): %s", httpResp.StatusCode, strings.TrimSpace(string(body))),
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"success":      true,
+		"metric_count": len(samples),
+	}, nil
+}
+
+type sample struct {
+	name       string
+	labels     map[string]string
+	value      float64
+	metricType string
+	help       string
+}
+
+// parseSamples accepts {name: value}, {name: {value, labels, type, help}} or a list of {name, value, labels, type, help}
+func parseSamples(raw interface{}) ([]sample, error) {
+	var entries []map[string]interface{}
+	switch m := raw.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entry := map[string]interface{}{"name": name}
+			if detail, ok := m[name].(map[string]interface{}); ok {
+				for key, value := range detail {
+					entry[key] = value
+				}
+			} else {
+				entry["value"] = m[name]
+			}
+			entries = append(entries, entry)
+		}
+	case []interface{}:
+		for i, item := range m {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("metrics[%d] must be an object", i)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("metrics is required")
+	}
+
+	samples := make([]sample, 0, len(entries))
+	for _, entry := range entries {
+		name, _ := entry["name"].(string)
+		if !metricNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid metric name: %q", name)
+		}
+
+		value, ok := entry["value"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("metric %s: value must be a number", name)
+		}
+
+		s := sample{
+			name:       name,
+			labels:     map[string]string{},
+			value:      value,
+			metricType: getStringParam(entry, "type", "untyped"),
+			help:       getStringParam(entry, "help", ""),
+		}
+		if labels, ok := entry["labels"].(map[string]interface{}); ok {
+			for key, val := range labels {
+				if !labelNamePattern.MatchString(key) {
+					return nil, fmt.Errorf("metric %s: invalid label name %q", name, key)
+				}
+				s.labels[key] = fmt.Sprintf("%v", val)
+			}
+		}
+		samples = append(samples, s)
+	}
+	return samples, nil
+}
+
+// formatSamples renders samples in the Prometheus text exposition format, with one
+// TYPE/HELP header per metric name
+func formatSamples(samples []sample) string {
+	var b strings.Builder
+	described := map[string]bool{}
+	for _, s := range samples {
+		if !described[s.name] {
+			described[s.name] = true
+			if s.help != "" {
+				fmt.Fprintf(&b, "# HELP %s %s\n", s.name, helpEscaper.Replace(s.help))
+			}
+			fmt.Fprintf(&b, "# TYPE %s %s\n", s.name, s.metricType)
+		}
+
+		b.WriteString(s.name)
+		if len(s.labels) > 0 {
+			keys := make([]string, 0, len(s.labels))
+			for key := range s.labels {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			parts := make([]string, 0, len(keys))
+			for _, key := range keys {
+				parts = append(parts, fmt.Sprintf(`%s="%s"`, key, labelValueEscaper.Replace(s.labels[key])))
+			}
+			b.WriteString("{" + strings.Join(parts, ",") + "}")
+		}
+		b.WriteString(" " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+	}
+	return b.String()
+}
+
+// groupingKey builds a Pushgateway path segment, switching to the base64 form for
+// values the plain form cannot carry (slashes or empty strings)
+func groupingKey(label, value string) string {
+	if value == "" {
+		return label + "@base64/="
+	}
+	if strings.Contains(value, "/") {
+		return label + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return label + "/" + url.PathEscape(value)
+}
+
+func (p *PrometheusPlugin) listAlerts(params map[string]interface{}) (map[string]interface{}, error) {
+	baseURL, err := serverURL(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	state := getStringParam(params, "state", "")
+	if state != "" && state != "firing" && state != "pending" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported state: %s (use firing or pending)", state)}, nil
+	}
+
+	resp, err := p.callAPI("GET", baseURL+"/api/v1/alerts", nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	var data struct {
+		Alerts []struct {
+			Labels      map[string]string `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+			State       string            `json:"state"`
+			ActiveAt    string            `json:"activeAt"`
+			Value       string            `json:"value"`
+		} `json:"alerts"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to parse alerts: %v", err)}, nil
+	}
+
+	alerts := []interface{}{}
+	for _, alert := range data.Alerts {
+		if state != "" && alert.State != state {
+			continue
+		}
+		alerts = append(alerts, map[string]interface{}{
+			"name":        alert.Labels["alertname"],
+			"state":       alert.State,
+			"labels":      alert.Labels,
+			"annotations": alert.Annotations,
+			"active_at":   alert.ActiveAt,
+			"value":       alert.Value,
+		})
+	}
+
+	return map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+	}, nil
+}
+
+func (p *PrometheusPlugin) getAlertRules(params map[string]interface{}) (map[string]interface{}, error) {
+	baseURL, err := serverURL(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	resp, err := p.callAPI("GET", baseURL+"/api/v1/rules?type=alert", nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	var data struct {
+		Groups []struct {
+			Name  string                   `json:"name"`
+			File  string                   `json:"file"`
+			Rules []map[string]interface{} `json:"rules"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to parse rules: %v", err)}, nil
+	}
+
+	groupName := getStringParam(params, "group_name", "")
+	groups := []interface{}{}
+	count := 0
+	for _, group := range data.Groups {
+		if groupName != "" && group.Name != groupName {
+			continue
+		}
+
+		rules := []interface{}{}
+		for _, rule := range group.Rules {
+			rules = append(rules, map[string]interface{}{
+				"name":        rule["name"],
+				"query":       rule["query"],
+				"duration":    rule["duration"],
+				"labels":      rule["labels"],
+				"annotations": rule["annotations"],
+				"state":       rule["state"],
+				"health":      rule["health"],
+			})
+		}
+		count += len(rules)
+
+		groups = append(groups, map[string]interface{}{
+			"name":  group.Name,
+			"file":  group.File,
+			"rules": rules,
+		})
+	}
+	if groupName != "" && len(groups) == 0 {
+		return map[string]interface{}{"error": fmt.Sprintf("rule group not found: %s", groupName)}, nil
+	}
+
+	return map[string]interface{}{
+		"groups": groups,
+		"count":  count,
+	}, nil
+}
+
+func (p *PrometheusPlugin) reloadConfig(params map[string]interface{}) (map[string]interface{}, error) {
+	baseURL, err := serverURL(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	resp, err := p.client.Post(baseURL+"/-/reload", "", nil)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": fmt.Sprintf("request failed: %v", err)}, nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(body))
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound {
+			message += " (is Prometheus running with --web.enable-lifecycle?)"
+		}
+		return map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("reload failed (%d): %s", resp.StatusCode, message),
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+type apiResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+	Warnings  []string        `json:"warnings"`
+}
+
+// callAPI sends a request to the HTTP API and unwraps its status/data envelope.
+// Form values are sent URL-encoded in the body.
+func (p *PrometheusPlugin) callAPI(method, target string, form url.Values) (*apiResponse, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	var result apiResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("Prometheus API error (%d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("Prometheus API error (%d): %s: %s", resp.StatusCode, result.ErrorType, result.Error)
+	}
+	return &result, nil
+}
+
+func serverURL(params map[string]interface{}) (string, error) {
+	baseURL := strings.TrimRight(getStringParam(params, "url", os.Getenv("PROMETHEUS_URL")), "/")
+	if baseURL == "" {
+		return "", fmt.Errorf("url or PROMETHEUS_URL is required")
+	}
+	return baseURL, nil
+}
+
+// getTimeParam returns a time input in a form the API accepts: numbers become Unix
+// seconds and strings (RFC 3339 or Unix) pass through
+func getTimeParam(params map[string]interface{}, key string) string {
+	switch v := params[key].(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(0, int64(secs*float64(time.Second))), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewPrometheusPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
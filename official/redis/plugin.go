@@ -0,0 +1,806 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type RedisPlugin struct{}
+
+func NewRedisPlugin() *RedisPlugin {
+	return &RedisPlugin{}
+}
+
+func (p *RedisPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "redis",
+		Version:     "1.0.0",
+		Description: "Redis strings, hashes, lists, sets and pub/sub",
+		Author:      "Corynth Team",
+		Tags:        []string{"redis", "cache", "queue", "pubsub", "key-value"},
+	}
+}
+
+func (p *RedisPlugin) GetActions() map[string]ActionSpec {
+	withConnection := func(inputs map[string]IOSpec) map[string]IOSpec {
+		inputs["address"] = IOSpec{Type: "string", Required: false, Description: "Server address host:port (defaults to REDIS_URL, then localhost:6379)"}
+		inputs["password"] = IOSpec{Type: "string", Required: false, Description: "Server password"}
+		inputs["db"] = IOSpec{Type: "number", Required: false, Description: "Database number"}
+		return inputs
+	}
+	key := IOSpec{Type: "string", Required: true, Description: "Key name"}
+	keys := IOSpec{Type: "array", Required: true, Description: "Key names"}
+
+	return map[string]ActionSpec{
+		"get": {
+			Description: "Get the value of a key",
+			Inputs:      withConnection(map[string]IOSpec{"key": key}),
+			Outputs: map[string]IOSpec{
+				"value":  {Type: "string", Description: "Value, or null when the key does not exist"},
+				"exists": {Type: "boolean", Description: "Whether the key exists"},
+			},
+		},
+		"set": {
+			Description: "Set the value of a key",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":         key,
+				"value":       {Type: "string", Required: true, Description: "Value; non-string values are stored as JSON"},
+				"ttl_seconds": {Type: "number", Required: false, Description: "Expire the key after this many seconds"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the value was set"},
+			},
+		},
+		"del": {
+			Description: "Delete keys",
+			Inputs:      withConnection(map[string]IOSpec{"keys": keys}),
+			Outputs: map[string]IOSpec{
+				"deleted": {Type: "number", Description: "Number of keys deleted"},
+			},
+		},
+		"exists": {
+			Description: "Count how many of the given keys exist",
+			Inputs:      withConnection(map[string]IOSpec{"keys": keys}),
+			Outputs: map[string]IOSpec{
+				"count":  {Type: "number", Description: "Number of keys that exist"},
+				"exists": {Type: "boolean", Description: "Whether every key exists"},
+			},
+		},
+		"expire": {
+			Description: "Set a timeout on a key",
+			Inputs: withConnection(map[string]IOSpec{
+				"key": key,
+				"ttl": {Type: "number", Required: true, Description: "Timeout in seconds"},
+			}),
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "False when the key does not exist"},
+			},
+		},
+		"incr": {
+			Description: "Increment an integer value",
+			Inputs: withConnection(map[string]IOSpec{
+				"key": key,
+				"by":  {Type: "number", Required: false, Default: 1, Description: "Amount to increment by"},
+			}),
+			Outputs: map[string]IOSpec{
+				"value": {Type: "number", Description: "Value after the increment"},
+			},
+		},
+		"decr": {
+			Description: "Decrement an integer value",
+			Inputs: withConnection(map[string]IOSpec{
+				"key": key,
+				"by":  {Type: "number", Required: false, Default: 1, Description: "Amount to decrement by"},
+			}),
+			Outputs: map[string]IOSpec{
+				"value": {Type: "number", Description: "Value after the decrement"},
+			},
+		},
+		"keys": {
+			Description: "Find keys matching a pattern",
+			Inputs: withConnection(map[string]IOSpec{
+				"pattern": {Type: "string", Required: false, Default: "*", Description: "Glob-style pattern"},
+				"limit":   {Type: "number", Required: false, Description: "Maximum number of keys to return"},
+			}),
+			Outputs: map[string]IOSpec{
+				"keys":  {Type: "array", Description: "Matching keys"},
+				"count": {Type: "number", Description: "Number of keys returned"},
+			},
+		},
+		"hget": {
+			Description: "Get a hash field",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":   key,
+				"field": {Type: "string", Required: true, Description: "Field name"},
+			}),
+			Outputs: map[string]IOSpec{
+				"value":  {Type: "string", Description: "Field value, or null when missing"},
+				"exists": {Type: "boolean", Description: "Whether the field exists"},
+			},
+		},
+		"hset": {
+			Description: "Set hash fields",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":    key,
+				"fields": {Type: "object", Required: true, Description: "Field/value pairs"},
+			}),
+			Outputs: map[string]IOSpec{
+				"added": {Type: "number", Description: "Number of new fields"},
+			},
+		},
+		"hgetall": {
+			Description: "Get all fields of a hash",
+			Inputs:      withConnection(map[string]IOSpec{"key": key}),
+			Outputs: map[string]IOSpec{
+				"fields": {Type: "object", Description: "Field/value pairs"},
+			},
+		},
+		"lpush": {
+			Description: "Prepend values to a list",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":    key,
+				"values": {Type: "array", Required: true, Description: "Values to push"},
+			}),
+			Outputs: map[string]IOSpec{
+				"length": {Type: "number", Description: "List length after the push"},
+			},
+		},
+		"rpop": {
+			Description: "Remove and return the last element of a list",
+			Inputs:      withConnection(map[string]IOSpec{"key": key}),
+			Outputs: map[string]IOSpec{
+				"value":  {Type: "string", Description: "Popped value, or null when the list is empty"},
+				"exists": {Type: "boolean", Description: "Whether a value was popped"},
+			},
+		},
+		"lrange": {
+			Description: "Get a range of list elements",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":   key,
+				"start": {Type: "number", Required: false, Default: 0, Description: "Start index"},
+				"stop":  {Type: "number", Required: false, Default: -1, Description: "Stop index (inclusive, -1 for the end)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"values": {Type: "array", Description: "List elements"},
+			},
+		},
+		"sadd": {
+			Description: "Add members to a set",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":     key,
+				"members": {Type: "array", Required: true, Description: "Members to add"},
+			}),
+			Outputs: map[string]IOSpec{
+				"added": {Type: "number", Description: "Number of new members"},
+			},
+		},
+		"smembers": {
+			Description: "Get all members of a set",
+			Inputs:      withConnection(map[string]IOSpec{"key": key}),
+			Outputs: map[string]IOSpec{
+				"members": {Type: "array", Description: "Set members"},
+			},
+		},
+		"sismember": {
+			Description: "Check set membership",
+			Inputs: withConnection(map[string]IOSpec{
+				"key":    key,
+				"member": {Type: "string", Required: true, Description: "Member to check"},
+			}),
+			Outputs: map[string]IOSpec{
+				"is_member": {Type: "boolean", Description: "Whether the member is in the set"},
+			},
+		},
+		"publish": {
+			Description: "Publish a message to a channel",
+			Inputs: withConnection(map[string]IOSpec{
+				"channel": {Type: "string", Required: true, Description: "Channel name"},
+				"message": {Type: "string", Required: true, Description: "Message; non-string values are sent as JSON"},
+			}),
+			Outputs: map[string]IOSpec{
+				"receivers": {Type: "number", Description: "Number of subscribers that received the message"},
+			},
+		},
+		"subscribe": {
+			Description: "Collect messages published to channels for a while",
+			Inputs: withConnection(map[string]IOSpec{
+				"channels":        {Type: "array", Required: true, Description: "Channel names"},
+				"timeout_seconds": {Type: "number", Required: false, Default: 10, Description: "How long to listen"},
+				"max_messages":    {Type: "number", Required: false, Description: "Stop after this many messages"},
+			}),
+			Outputs: map[string]IOSpec{
+				"messages": {Type: "array", Description: "Received messages with channel and payload"},
+				"count":    {Type: "number", Description: "Number of messages received"},
+			},
+		},
+	}
+}
+
+func (p *RedisPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(context.Context, *redis.Client, map[string]interface{}) (map[string]interface{}, error)
+
+	switch action {
+	case "get":
+		run = p.get
+	case "set":
+		run = p.set
+	case "del":
+		run = p.del
+	case "exists":
+		run = p.exists
+	case "expire":
+		run = p.expire
+	case "incr":
+		run = p.incr
+	case "decr":
+		run = p.decr
+	case "keys":
+		run = p.keys
+	case "hget":
+		run = p.hget
+	case "hset":
+		run = p.hset
+	case "hgetall":
+		run = p.hgetall
+	case "lpush":
+		run = p.lpush
+	case "rpop":
+		run = p.rpop
+	case "lrange":
+		run = p.lrange
+	case "sadd":
+		run = p.sadd
+	case "smembers":
+		run = p.smembers
+	case "sismember":
+		run = p.sismember
+	case "publish":
+		run = p.publish
+	case "subscribe":
+		run = p.subscribe
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	opts, err := clientOptions(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	result, err := run(context.Background(), client, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+// clientOptions builds connection options from REDIS_URL, with address, password and db
+// params taking precedence
+func clientOptions(params map[string]interface{}) (*redis.Options, error) {
+	opts := &redis.Options{Addr: "localhost:6379"}
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		parsed, err := redis.ParseURL(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %v", err)
+		}
+		opts = parsed
+	}
+
+	if address := getStringParam(params, "address", ""); address != "" {
+		opts.Addr = address
+	}
+	if password := getStringParam(params, "password", ""); password != "" {
+		opts.Password = password
+	}
+	if db, ok := params["db"].(float64); ok {
+		opts.DB = int(db)
+	}
+	return opts, nil
+}
+
+func (p *RedisPlugin) get(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	return optionalValue(client.Get(ctx, key).Result())
+}
+
+func (p *RedisPlugin) set(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	value, ok := params["value"]
+	if !ok {
+		return nil, fmt.Errorf("value is required")
+	}
+
+	ttl := time.Duration(getIntParam(params, "ttl_seconds", 0)) * time.Second
+	if err := client.Set(ctx, key, toRedisValue(value), ttl).Err(); err != nil {
+		return nil, fmt.Errorf("set failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+func (p *RedisPlugin) del(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	keys, err := requireStrings(params, "keys")
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := client.Del(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("del failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"deleted": n,
+	}, nil
+}
+
+func (p *RedisPlugin) exists(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	keys, err := requireStrings(params, "keys")
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := client.Exists(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("exists failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"count":  n,
+		"exists": n == int64(len(keys)),
+	}, nil
+}
+
+func (p *RedisPlugin) expire(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	ttl, ok := params["ttl"].(float64)
+	if !ok || ttl <= 0 {
+		return nil, fmt.Errorf("ttl must be a positive number of seconds")
+	}
+
+	ok, err = client.Expire(ctx, key, time.Duration(ttl)*time.Second).Result()
+	if err != nil {
+		return nil, fmt.Errorf("expire failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"success": ok,
+	}, nil
+}
+
+func (p *RedisPlugin) incr(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := client.IncrBy(ctx, key, int64(getIntParam(params, "by", 1))).Result()
+	if err != nil {
+		return nil, fmt.Errorf("incr failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"value": value,
+	}, nil
+}
+
+func (p *RedisPlugin) decr(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := client.DecrBy(ctx, key, int64(getIntParam(params, "by", 1))).Result()
+	if err != nil {
+		return nil, fmt.Errorf("decr failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"value": value,
+	}, nil
+}
+
+// keys iterates with SCAN rather than KEYS so large databases are not blocked
+func (p *RedisPlugin) keys(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	pattern := getStringParam(params, "pattern", "*")
+	limit := getIntParam(params, "limit", 0)
+
+	keys := []string{}
+	iter := client.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if limit > 0 && len(keys) >= limit {
+			break
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("scan failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"keys":  keys,
+		"count": len(keys),
+	}, nil
+}
+
+func (p *RedisPlugin) hget(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	field, err := requireString(params, "field")
+	if err != nil {
+		return nil, err
+	}
+	return optionalValue(client.HGet(ctx, key, field).Result())
+}
+
+func (p *RedisPlugin) hset(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := params["fields"].(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		return nil, fmt.Errorf("fields is required")
+	}
+
+	values := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		values[field] = toRedisValue(value)
+	}
+
+	n, err := client.HSet(ctx, key, values).Result()
+	if err != nil {
+		return nil, fmt.Errorf("hset failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"added": n,
+	}, nil
+}
+
+func (p *RedisPlugin) hgetall(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("hgetall failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"fields": fields,
+	}, nil
+}
+
+func (p *RedisPlugin) lpush(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	values, err := requireValues(params, "values")
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := client.LPush(ctx, key, values...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("lpush failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"length": n,
+	}, nil
+}
+
+func (p *RedisPlugin) rpop(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	return optionalValue(client.RPop(ctx, key).Result())
+}
+
+func (p *RedisPlugin) lrange(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := client.LRange(ctx, key, int64(getIntParam(params, "start", 0)), int64(getIntParam(params, "stop", -1))).Result()
+	if err != nil {
+		return nil, fmt.Errorf("lrange failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"values": values,
+	}, nil
+}
+
+func (p *RedisPlugin) sadd(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	members, err := requireValues(params, "members")
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := client.SAdd(ctx, key, members...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("sadd failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"added": n,
+	}, nil
+}
+
+func (p *RedisPlugin) smembers(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("smembers failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"members": members,
+	}, nil
+}
+
+func (p *RedisPlugin) sismember(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	key, err := requireString(params, "key")
+	if err != nil {
+		return nil, err
+	}
+	member, ok := params["member"]
+	if !ok {
+		return nil, fmt.Errorf("member is required")
+	}
+
+	isMember, err := client.SIsMember(ctx, key, toRedisValue(member)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("sismember failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"is_member": isMember,
+	}, nil
+}
+
+func (p *RedisPlugin) publish(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	channel, err := requireString(params, "channel")
+	if err != nil {
+		return nil, err
+	}
+	message, ok := params["message"]
+	if !ok {
+		return nil, fmt.Errorf("message is required")
+	}
+
+	n, err := client.Publish(ctx, channel, toRedisValue(message)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("publish failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"receivers": n,
+	}, nil
+}
+
+// subscribe listens until timeout_seconds elapse or max_messages arrive. Running out of
+// time is the normal way for it to finish, so it is not reported as an error.
+func (p *RedisPlugin) subscribe(ctx context.Context, client *redis.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	channels, err := requireStrings(params, "channels")
+	if err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(getIntParam(params, "timeout_seconds", 10)) * time.Second
+	maxMessages := getIntParam(params, "max_messages", 0)
+
+	pubsub := client.Subscribe(ctx, channels...)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed so connection errors are reported
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return nil, fmt.Errorf("subscribe failed: %v", err)
+	}
+
+	// ReceiveTimeout is used because pub/sub reads do not observe context deadlines
+	deadline := time.Now().Add(timeout)
+	messages := []map[string]interface{}{}
+	for maxMessages <= 0 || len(messages) < maxMessages {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+
+		received, err := pubsub.ReceiveTimeout(ctx, remaining)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, fmt.Errorf("receive failed: %v", err)
+		}
+
+		if msg, ok := received.(*redis.Message); ok {
+			messages = append(messages, map[string]interface{}{
+				"channel": msg.Channel,
+				"payload": msg.Payload,
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"messages": messages,
+		"count":    len(messages),
+	}, nil
+}
+
+// optionalValue turns the result of a command that may find nothing into value/exists outputs
+func optionalValue(value string, err error) (map[string]interface{}, error) {
+	if errors.Is(err, redis.Nil) {
+		return map[string]interface{}{"value": nil, "exists": false}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("command failed: %v", err)
+	}
+	return map[string]interface{}{"value": value, "exists": true}, nil
+}
+
+// toRedisValue stores strings as-is and anything else as JSON
+func toRedisValue(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func requireString(params map[string]interface{}, key string) (string, error) {
+	value := getStringParam(params, key, "")
+	if value == "" {
+		return "", fmt.Errorf("%s is required", key)
+	}
+	return value, nil
+}
+
+func requireStrings(params map[string]interface{}, key string) ([]string, error) {
+	items, ok := params[key].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%s is required", key)
+	}
+
+	values := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%s must contain only non-empty strings", key)
+		}
+		values[i] = s
+	}
+	return values, nil
+}
+
+func requireValues(params map[string]interface{}, key string) ([]interface{}, error) {
+	items, ok := params[key].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%s is required", key)
+	}
+
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		values[i] = toRedisValue(item)
+	}
+	return values, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewRedisPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	htmltemplate "html/template"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +15,8 @@ import (
 	"strings"
 	texttemplate "text/template"
 	"time"
+
+	"github.com/xuri/excelize/v2"
 )
 
 type Metadata struct {
@@ -57,39 +61,67 @@ func (p *ReportingPlugin) GetActions() map[string]ActionSpec {
 		"create_report": {
 			Description: "Create formatted report",
 			Inputs: map[string]IOSpec{
-				"title":       {Type: "string", Required: true, Description: "Report title"},
-				"content":     {Type: "string", Required: true, Description: "Report content"},
-				"format":      {Type: "string", Required: false, Default: "markdown", Description: "Output format: markdown, html, text"},
+				"title":         {Type: "string", Required: true, Description: "Report title"},
+				"content":       {Type: "string", Required: true, Description: "Report content"},
+				"format":        {Type: "string", Required: false, Default: "markdown", Description: "Output format: markdown, html, text, csv, xlsx (xlsx requires output_path)"},
+				"output_path":   {Type: "string", Required: false, Description: "Output file path"},
+				"metadata":      {Type: "object", Required: false, Description: "Report metadata"},
+				"raw_html":      {Type: "boolean", Required: false, Default: false, Description: "Insert content into HTML reports without escaping; only for trusted content"},
+				"template_file": {Type: "string", Required: false, Description: "Custom Go template file used instead of the built-in layout (html/template for html, text/template otherwise); receives .Title, .Content, .Metadata and .Timestamp"},
+			},
+			Outputs: map[string]IOSpec{
+				"report":      {Type: "string", Description: "Generated report"},
+				"file_path":   {Type: "string", Description: "Output file path"},
+				"sheet_count": {Type: "number", Description: "Worksheets written (xlsx only)"},
+				"row_count":   {Type: "number", Description: "Rows written (xlsx only)"},
+			},
+		},
+		"create_csv": {
+			Description: "Create RFC 4180 CSV data",
+			Inputs: map[string]IOSpec{
+				"headers":     {Type: "array", Required: false, Description: "Column headers"},
+				"rows":        {Type: "array", Required: true, Description: "Rows as arrays of values"},
 				"output_path": {Type: "string", Required: false, Description: "Output file path"},
-				"metadata":    {Type: "object", Required: false, Description: "Report metadata"},
 			},
 			Outputs: map[string]IOSpec{
-				"report":    {Type: "string", Description: "Generated report"},
+				"csv":       {Type: "string", Description: "Generated CSV"},
+				"row_count": {Type: "number", Description: "Number of data rows"},
 				"file_path": {Type: "string", Description: "Output file path"},
 			},
 		},
 		"create_table": {
 			Description: "Create formatted table",
 			Inputs: map[string]IOSpec{
-				"data":    {Type: "array", Required: true, Description: "Table data"},
-				"headers": {Type: "array", Required: false, Description: "Column headers"},
-				"format":  {Type: "string", Required: false, Default: "markdown", Description: "Table format"},
-				"title":   {Type: "string", Required: false, Description: "Table title"},
+				"data":        {Type: "array", Required: true, Description: "Table data"},
+				"headers":     {Type: "array", Required: false, Description: "Column headers"},
+				"format":      {Type: "string", Required: false, Default: "markdown", Description: "Table format: markdown, text, xlsx"},
+				"title":       {Type: "string", Required: false, Description: "Table title"},
+				"output_path": {Type: "string", Required: false, Description: "Output file path (required for xlsx)"},
 			},
 			Outputs: map[string]IOSpec{
-				"table": {Type: "string", Description: "Formatted table"},
+				"table":       {Type: "string", Description: "Formatted table"},
+				"file_path":   {Type: "string", Description: "Output file path (xlsx only)"},
+				"sheet_count": {Type: "number", Description: "Worksheets written (xlsx only)"},
+				"row_count":   {Type: "number", Description: "Rows written (xlsx only)"},
 			},
 		},
 		"create_chart": {
 			Description: "Create ASCII chart",
 			Inputs: map[string]IOSpec{
-				"data":  {Type: "object", Required: true, Description: "Chart data"},
-				"type":  {Type: "string", Required: false, Default: "bar", Description: "Chart type: bar, line"},
-				"title": {Type: "string", Required: false, Description: "Chart title"},
-				"width": {Type: "number", Required: false, Default: 60, Description: "Chart width"},
+				"data":        {Type: "object", Required: false, Description: "Chart data mapping label to numeric value (required unless series is given)"},
+				"series":      {Type: "array", Required: false, Description: "Ordered line chart points: numbers, [label, value] pairs or {label, value} objects"},
+				"type":        {Type: "string", Required: false, Default: "bar", Description: "Chart type: bar, line, pie, donut"},
+				"title":       {Type: "string", Required: false, Description: "Chart title"},
+				"width":       {Type: "number", Required: false, Default: 60, Description: "Chart width"},
+				"height":      {Type: "number", Required: false, Default: 10, Description: "Line chart height in rows"},
+				"format":      {Type: "string", Required: false, Default: "text", Description: "Pie and donut output format: text, html, svg"},
+				"colors":      {Type: "array", Required: false, Description: "Hex colors for pie and donut slices in SVG output"},
+				"show_legend": {Type: "boolean", Required: false, Default: true, Description: "Show a legend with percentages for pie and donut charts"},
+				"output_path": {Type: "string", Required: false, Description: "Write the chart to this file"},
 			},
 			Outputs: map[string]IOSpec{
-				"chart": {Type: "string", Description: "ASCII chart"},
+				"chart":     {Type: "string", Description: "Rendered chart"},
+				"file_path": {Type: "string", Description: "Output file path"},
 			},
 		},
 	}
@@ -99,6 +131,8 @@ func (p *ReportingPlugin) Execute(action string, params map[string]interface{})
 	switch action {
 	case "create_report":
 		return p.createReport(params)
+	case "create_csv":
+		return p.createCSV(params)
 	case "create_table":
 		return p.createTable(params)
 	case "create_chart":
@@ -125,14 +159,42 @@ func (p *ReportingPlugin) createReport(params map[string]interface{}) (map[strin
 
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 
+	templateFile := getStringParam(params, "template_file", "")
+	rawHTML := false
+	if val, ok := params["raw_html"].(bool); ok {
+		rawHTML = val
+	}
+
+	if format == "xlsx" {
+		if templateFile != "" {
+			return map[string]interface{}{"error": "template_file is not supported for xlsx format"}, nil
+		}
+		if outputPath == "" {
+			return map[string]interface{}{"error": "output_path is required for xlsx format"}, nil
+		}
+		rowCount, err := p.generateXLSXReport(outputPath, title, content, metadata, timestamp)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		return map[string]interface{}{
+			"file_path":   outputPath,
+			"sheet_count": 1,
+			"row_count":   rowCount,
+		}, nil
+	}
+
 	var report string
 	var err error
 
-	switch format {
-	case "markdown":
+	switch {
+	case templateFile != "":
+		report, err = p.generateTemplateReport(templateFile, format, title, content, metadata, timestamp, rawHTML)
+	case format == "markdown":
 		report, err = p.generateMarkdownReport(title, content, metadata, timestamp)
-	case "html":
-		report, err = p.generateHTMLReport(title, content, metadata, timestamp)
+	case format == "html":
+		report, err = p.generateHTMLReport(title, content, metadata, timestamp, rawHTML)
+	case format == "csv":
+		report, err = p.generateCSVReport(title, content, metadata, timestamp)
 	default: // text
 		report, err = p.generateTextReport(title, content, metadata, timestamp)
 	}
@@ -158,6 +220,47 @@ func (p *ReportingPlugin) createReport(params map[string]interface{}) (map[strin
 	}, nil
 }
 
+func (p *ReportingPlugin) createCSV(params map[string]interface{}) (map[string]interface{}, error) {
+	rowsRaw, ok := params["rows"].([]interface{})
+	if !ok {
+		return map[string]interface{}{"error": "rows must be an array"}, nil
+	}
+
+	var records [][]string
+	if headersRaw, ok := params["headers"].([]interface{}); ok && len(headersRaw) > 0 {
+		records = append(records, csvRecord(headersRaw))
+	}
+	for i, rowRaw := range rowsRaw {
+		row, ok := rowRaw.([]interface{})
+		if !ok {
+			return map[string]interface{}{"error": fmt.Sprintf("row %d must be an array", i)}, nil
+		}
+		records = append(records, csvRecord(row))
+	}
+
+	report, err := writeCSV(records)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	outputPath := getStringParam(params, "output_path", "")
+	if outputPath != "" {
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to create directory: %v", err)}, nil
+		}
+
+		if err := os.WriteFile(outputPath, []byte(report), 0644); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to write file: %v", err)}, nil
+		}
+	}
+
+	return map[string]interface{}{
+		"csv":       report,
+		"row_count": len(rowsRaw),
+		"file_path": outputPath,
+	}, nil
+}
+
 func (p *ReportingPlugin) createTable(params map[string]interface{}) (map[string]interface{}, error) {
 	dataRaw, ok := params["data"]
 	if !ok {
@@ -206,6 +309,22 @@ func (p *ReportingPlugin) createTable(params map[string]interface{}) (map[string
 	format := getStringParam(params, "format", "markdown")
 	title := getStringParam(params, "title", "")
 
+	if format == "xlsx" {
+		outputPath := getStringParam(params, "output_path", "")
+		if outputPath == "" {
+			return map[string]interface{}{"error": "output_path is required for xlsx format"}, nil
+		}
+		rowCount, err := p.generateXLSXTable(outputPath, data, headers)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		return map[string]interface{}{
+			"file_path":   outputPath,
+			"sheet_count": 1,
+			"row_count":   rowCount,
+		}, nil
+	}
+
 	var table string
 	if format == "markdown" {
 		table = p.generateMarkdownTable(data, headers, title)
@@ -219,37 +338,137 @@ func (p *ReportingPlugin) createTable(params map[string]interface{}) (map[string
 }
 
 func (p *ReportingPlugin) createChart(params map[string]interface{}) (map[string]interface{}, error) {
-	dataRaw, ok := params["data"]
-	if !ok {
-		return map[string]interface{}{"error": "data is required"}, nil
-	}
-
-	data, ok := dataRaw.(map[string]interface{})
-	if !ok {
-		return map[string]interface{}{"error": "data must be an object"}, nil
-	}
-
-	if len(data) == 0 {
-		return map[string]interface{}{"chart": "No data provided"}, nil
-	}
-
 	chartType := getStringParam(params, "type", "bar")
 	title := getStringParam(params, "title", "")
 	width := int(getFloatParam(params, "width", 60))
+	height := int(getFloatParam(params, "height", 10))
+
+	// Line charts may take ordered series data instead of the label/value map
+	series, hasSeries := params["series"].([]interface{})
+	hasSeries = hasSeries && chartType == "line"
+
+	var data map[string]interface{}
+	if !hasSeries {
+		dataRaw, ok := params["data"]
+		if !ok {
+			return map[string]interface{}{"error": "data is required"}, nil
+		}
+
+		data, ok = dataRaw.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{"error": "data must be an object"}, nil
+		}
+
+		if len(data) == 0 {
+			return map[string]interface{}{"chart": "No data provided"}, nil
+		}
+	}
 
 	var chart string
 	switch chartType {
-	case "bar", "line": // Both use bar chart for simplicity
+	case "bar":
 		chart = p.generateBarChart(data, title, width)
+	case "line":
+		var points []chartPoint
+		if hasSeries {
+			var err error
+			if points, err = seriesPoints(series); err != nil {
+				return map[string]interface{}{"error": err.Error()}, nil
+			}
+		} else {
+			points = mapPoints(data)
+		}
+		if len(points) == 0 {
+			return map[string]interface{}{"chart": "No data provided"}, nil
+		}
+		chart = p.generateLineChart(points, title, width, height)
+	case "pie", "donut":
+		slices, err := pieSlices(data)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}, nil
+		}
+		donut := chartType == "donut"
+		showLegend := true
+		if val, ok := params["show_legend"].(bool); ok {
+			showLegend = val
+		}
+
+		switch format := getStringParam(params, "format", "text"); format {
+		case "text":
+			chart = p.generateASCIIPieChart(slices, title, donut, showLegend)
+		case "html", "svg":
+			colors, err := chartColors(params["colors"])
+			if err != nil {
+				return map[string]interface{}{"error": err.Error()}, nil
+			}
+			chart = p.generateSVGPieChart(slices, title, donut, showLegend, colors)
+			if format == "html" {
+				chart = fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head><title>%s</title></head>\n<body>\n%s\n</body>\n</html>", htmltemplate.HTMLEscapeString(title), chart)
+			}
+		default:
+			return map[string]interface{}{"error": fmt.Sprintf("unsupported chart format: %s", format)}, nil
+		}
 	default:
 		chart = p.generateBarChart(data, title, width)
 	}
 
+	outputPath := getStringParam(params, "output_path", "")
+	if outputPath != "" {
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to create directory: %v", err)}, nil
+		}
+
+		if err := os.WriteFile(outputPath, []byte(chart), 0644); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to write file: %v", err)}, nil
+		}
+	}
+
 	return map[string]interface{}{
-		"chart": chart,
+		"chart":     chart,
+		"file_path": outputPath,
 	}, nil
 }
 
+// generateTemplateReport renders a user-supplied template file with the same data
+// as the built-in layouts. HTML reports use html/template so values are escaped.
+func (p *ReportingPlugin) generateTemplateReport(templateFile, format, title, content string, metadata map[string]interface{}, timestamp string, rawHTML bool) (string, error) {
+	tmplBytes, err := os.ReadFile(templateFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template file: %v", err)
+	}
+
+	data := map[string]interface{}{
+		"Title":     title,
+		"Content":   content,
+		"Metadata":  metadata,
+		"Timestamp": timestamp,
+	}
+
+	var buf bytes.Buffer
+	name := filepath.Base(templateFile)
+	if format == "html" {
+		if rawHTML {
+			data["Content"] = htmltemplate.HTML(content)
+		}
+		tmpl, parseErr := htmltemplate.New(name).Parse(string(tmplBytes))
+		if parseErr != nil {
+			return "", fmt.Errorf("failed to parse template %s: %v", templateFile, parseErr)
+		}
+		err = tmpl.Execute(&buf, data)
+	} else {
+		tmpl, parseErr := texttemplate.New(name).Parse(string(tmplBytes))
+		if parseErr != nil {
+			return "", fmt.Errorf("failed to parse template %s: %v", templateFile, parseErr)
+		}
+		err = tmpl.Execute(&buf, data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to render template %s: %v", templateFile, err)
+	}
+
+	return buf.String(), nil
+}
+
 func (p *ReportingPlugin) generateMarkdownReport(title, content string, metadata map[string]interface{}, timestamp string) (string, error) {
 	tmplStr := `# {{.Title}}
 
@@ -281,7 +500,9 @@ func (p *ReportingPlugin) generateMarkdownReport(title, content string, metadata
 	return buf.String(), nil
 }
 
-func (p *ReportingPlugin) generateHTMLReport(title, content string, metadata map[string]interface{}, timestamp string) (string, error) {
+// generateHTMLReport relies on html/template to escape title, content and metadata;
+// rawHTML marks content as trusted markup instead
+func (p *ReportingPlugin) generateHTMLReport(title, content string, metadata map[string]interface{}, timestamp string, rawHTML bool) (string, error) {
 	tmplStr := `<!DOCTYPE html>
 <html>
 <head>
@@ -317,6 +538,9 @@ func (p *ReportingPlugin) generateHTMLReport(title, content string, metadata map
 		"Metadata":  metadata,
 		"Timestamp": timestamp,
 	}
+	if rawHTML {
+		data["Content"] = htmltemplate.HTML(content)
+	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
@@ -355,6 +579,98 @@ func (p *ReportingPlugin) generateTextReport(title, content string, metadata map
 	return strings.Join(lines, "\n"), nil
 }
 
+// generateCSVReport writes title, timestamp and metadata as key,value rows,
+// followed by a blank line and the content as a raw data block
+func (p *ReportingPlugin) generateCSVReport(title, content string, metadata map[string]interface{}, timestamp string) (string, error) {
+	records := [][]string{
+		{"key", "value"},
+		{"title", title},
+		{"generated", timestamp},
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		records = append(records, []string{key, csvValue(metadata[key])})
+	}
+
+	header, err := writeCSV(records)
+	if err != nil {
+		return "", err
+	}
+
+	return header + "\r\n" + content, nil
+}
+
+// generateXLSXReport writes a single "Report" sheet with the title, timestamp,
+// metadata key/value rows and one row per content line
+func (p *ReportingPlugin) generateXLSXReport(path, title, content string, metadata map[string]interface{}, timestamp string) (int, error) {
+	rows := [][]xlsxCell{
+		{{Value: title, Style: xlsxStyleTitle}},
+		{{Value: "Generated", Style: xlsxStyleBold}, {Value: timestamp}},
+		{},
+	}
+
+	if len(metadata) > 0 {
+		keys := make([]string, 0, len(metadata))
+		for key := range metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		rows = append(rows, []xlsxCell{{Value: "Metadata", Style: xlsxStyleBold}})
+		for _, key := range keys {
+			rows = append(rows, []xlsxCell{{Value: key}, {Value: metadata[key]}})
+		}
+		rows = append(rows, []xlsxCell{})
+	}
+
+	rows = append(rows, []xlsxCell{{Value: "Content", Style: xlsxStyleBold}})
+	for _, line := range strings.Split(content, "\n") {
+		rows = append(rows, []xlsxCell{{Value: line}})
+	}
+
+	if err := writeXLSX(path, "Report", rows); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// generateXLSXTable writes headers in bold on row 1 with data rows below
+func (p *ReportingPlugin) generateXLSXTable(path string, data []interface{}, headers []string) (int, error) {
+	headerRow := make([]xlsxCell, len(headers))
+	for i, header := range headers {
+		headerRow[i] = xlsxCell{Value: header, Style: xlsxStyleBold}
+	}
+	rows := [][]xlsxCell{headerRow}
+
+	for _, rowRaw := range data {
+		var row []xlsxCell
+		if rowMap, ok := rowRaw.(map[string]interface{}); ok {
+			row = make([]xlsxCell, len(headers))
+			for i, header := range headers {
+				row[i] = xlsxCell{Value: rowMap[header]}
+			}
+		} else if rowSlice, ok := rowRaw.([]interface{}); ok {
+			row = make([]xlsxCell, len(rowSlice))
+			for i, val := range rowSlice {
+				row[i] = xlsxCell{Value: val}
+			}
+		} else {
+			row = []xlsxCell{{Value: rowRaw}}
+		}
+		rows = append(rows, row)
+	}
+
+	if err := writeXLSX(path, "Sheet1", rows); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
 func (p *ReportingPlugin) generateMarkdownTable(data []interface{}, headers []string, title string) string {
 	var lines []string
 
@@ -520,6 +836,345 @@ func (p *ReportingPlugin) generateBarChart(data map[string]interface{}, title st
 	return strings.Join(lines, "\n")
 }
 
+type chartPoint struct {
+	Label string
+	Value float64
+}
+
+// seriesPoints converts ordered series entries (numbers, [label, value] pairs or
+// {label, value} objects) into chart points, keeping their order
+func seriesPoints(series []interface{}) ([]chartPoint, error) {
+	points := make([]chartPoint, 0, len(series))
+	for i, item := range series {
+		label := strconv.Itoa(i + 1)
+		var raw interface{} = item
+		switch v := item.(type) {
+		case []interface{}:
+			if len(v) != 2 {
+				return nil, fmt.Errorf("series entry %d must be a [label, value] pair", i)
+			}
+			label, raw = fmt.Sprintf("%v", v[0]), v[1]
+		case map[string]interface{}:
+			if l, ok := v["label"]; ok {
+				label = fmt.Sprintf("%v", l)
+			}
+			raw = v["value"]
+		}
+		value, err := convertToFloat(raw)
+		if err != nil {
+			return nil, fmt.Errorf("series entry %d: %v", i, err)
+		}
+		points = append(points, chartPoint{Label: label, Value: value})
+	}
+	return points, nil
+}
+
+// mapPoints orders label/value map data by label
+func mapPoints(data map[string]interface{}) []chartPoint {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	points := make([]chartPoint, 0, len(keys))
+	for _, label := range keys {
+		if val, err := convertToFloat(data[label]); err == nil {
+			points = append(points, chartPoint{Label: label, Value: val})
+		}
+	}
+	return points
+}
+
+// generateLineChart plots points evenly across width columns, marking data
+// points with ● and linearly interpolated values between them with ·
+func (p *ReportingPlugin) generateLineChart(points []chartPoint, title string, width, height int) string {
+	var lines []string
+
+	if title != "" {
+		lines = append(lines, title)
+		lines = append(lines, strings.Repeat("=", len(title)))
+		lines = append(lines, "")
+	}
+
+	if width < 2 {
+		width = 2
+	}
+	if height < 2 {
+		height = 2
+	}
+
+	minVal, maxVal := points[0].Value, points[0].Value
+	for _, point := range points {
+		minVal = math.Min(minVal, point.Value)
+		maxVal = math.Max(maxVal, point.Value)
+	}
+	valueRange := maxVal - minVal
+	if valueRange == 0 {
+		valueRange = 1
+	}
+
+	grid := make([][]string, height)
+	for i := range grid {
+		grid[i] = make([]string, width)
+		for j := range grid[i] {
+			grid[i][j] = " "
+		}
+	}
+	rowFor := func(value float64) int {
+		return height - 1 - int(math.Round((value-minVal)/valueRange*float64(height-1)))
+	}
+	columnFor := func(index int) int {
+		if len(points) == 1 {
+			return 0
+		}
+		return int(math.Round(float64(index) * float64(width-1) / float64(len(points)-1)))
+	}
+
+	// Interpolate between neighbouring points, then overlay the points themselves
+	for col := 0; col < width && len(points) > 1; col++ {
+		position := float64(col) * float64(len(points)-1) / float64(width-1)
+		left := int(math.Floor(position))
+		if left >= len(points)-1 {
+			left = len(points) - 2
+		}
+		fraction := position - float64(left)
+		value := points[left].Value + (points[left+1].Value-points[left].Value)*fraction
+		grid[rowFor(value)][col] = "·"
+	}
+	for i, point := range points {
+		grid[rowFor(point.Value)][columnFor(i)] = "●"
+	}
+
+	maxLabel := strconv.FormatFloat(maxVal, 'f', 2, 64)
+	minLabel := strconv.FormatFloat(minVal, 'f', 2, 64)
+	axisWidth := len(maxLabel)
+	if len(minLabel) > axisWidth {
+		axisWidth = len(minLabel)
+	}
+
+	for i, row := range grid {
+		label := ""
+		switch i {
+		case 0:
+			label = maxLabel
+		case height - 1:
+			label = minLabel
+		}
+		lines = append(lines, fmt.Sprintf("%*s ┤%s", axisWidth, label, strings.TrimRight(strings.Join(row, ""), " ")))
+	}
+	lines = append(lines, fmt.Sprintf("%*s └%s", axisWidth, "", strings.Repeat("─", width)))
+
+	// X axis labels, skipping any that would overlap the previous one
+	labelRow := []rune(strings.Repeat(" ", width+len(points[len(points)-1].Label)))
+	next := 0
+	for i, point := range points {
+		col := columnFor(i)
+		label := []rune(point.Label)
+		if col < next || col+len(label) > len(labelRow) {
+			continue
+		}
+		copy(labelRow[col:], label)
+		next = col + len(label) + 1
+	}
+	lines = append(lines, fmt.Sprintf("%*s  %s", axisWidth, "", strings.TrimRight(string(labelRow), " ")))
+
+	return strings.Join(lines, "\n")
+}
+
+type pieSlice struct {
+	Label    string
+	Value    float64
+	Fraction float64
+}
+
+// pieSymbols fill the slices of ASCII pie charts, in slice order
+var pieSymbols = []string{"█", "▓", "▒", "░", "#", "*", "+", "o", "=", "@"}
+
+// defaultChartColors are used for SVG slices when no colors are given
+var defaultChartColors = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// pieSlices converts chart data to slices sorted by label, dropping non-numeric and non-positive values
+func pieSlices(data map[string]interface{}) ([]pieSlice, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var slices []pieSlice
+	total := 0.0
+	for _, label := range keys {
+		val, err := convertToFloat(data[label])
+		if err != nil || val <= 0 {
+			continue
+		}
+		slices = append(slices, pieSlice{Label: label, Value: val})
+		total += val
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("pie charts require at least one positive value")
+	}
+	for i := range slices {
+		slices[i].Fraction = slices[i].Value / total
+	}
+	return slices, nil
+}
+
+func chartColors(raw interface{}) ([]string, error) {
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return defaultChartColors, nil
+	}
+
+	colors := make([]string, len(list))
+	for i, item := range list {
+		color, _ := item.(string)
+		color = strings.TrimPrefix(color, "#")
+		if len(color) != 3 && len(color) != 6 {
+			return nil, fmt.Errorf("invalid color %v: expected hex like #ff0000", item)
+		}
+		if _, err := strconv.ParseUint(color, 16, 32); err != nil {
+			return nil, fmt.Errorf("invalid color %v: expected hex like #ff0000", item)
+		}
+		colors[i] = "#" + color
+	}
+	return colors, nil
+}
+
+// generateASCIIPieChart rasterises the pie into characters, doubling horizontal
+// resolution because terminal cells are roughly twice as tall as they are wide
+func (p *ReportingPlugin) generateASCIIPieChart(slices []pieSlice, title string, donut, showLegend bool) string {
+	var lines []string
+
+	if title != "" {
+		lines = append(lines, title)
+		lines = append(lines, strings.Repeat("=", len(title)))
+		lines = append(lines, "")
+	}
+
+	const radius = 8
+	for y := -radius; y <= radius; y++ {
+		var row strings.Builder
+		for x := -2 * radius; x <= 2*radius; x++ {
+			dx := float64(x) / 2
+			dist := math.Hypot(dx, float64(y))
+			if dist > radius+0.25 || (donut && dist < radius*0.5) {
+				row.WriteString(" ")
+				continue
+			}
+			// Angle clockwise from twelve o'clock, as a fraction of the full circle
+			angle := math.Atan2(dx, float64(-y)) / (2 * math.Pi)
+			if angle < 0 {
+				angle++
+			}
+			row.WriteString(pieSymbols[pieSliceAt(slices, angle)%len(pieSymbols)])
+		}
+		lines = append(lines, strings.TrimRight(row.String(), " "))
+	}
+
+	if showLegend {
+		lines = append(lines, "")
+		for i, slice := range slices {
+			lines = append(lines, fmt.Sprintf("%s %-15s %10.2f %6.1f%%", pieSymbols[i%len(pieSymbols)], slice.Label, slice.Value, slice.Fraction*100))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// pieSliceAt returns the index of the slice covering position (0..1) around the circle
+func pieSliceAt(slices []pieSlice, position float64) int {
+	cumulative := 0.0
+	for i, slice := range slices {
+		cumulative += slice.Fraction
+		if position < cumulative {
+			return i
+		}
+	}
+	return len(slices) - 1
+}
+
+// generateSVGPieChart draws each slice as an SVG path built from arc commands
+func (p *ReportingPlugin) generateSVGPieChart(slices []pieSlice, title string, donut, showLegend bool, colors []string) string {
+	const size, radius = 300.0, 120.0
+	cx, cy := size/2, size/2
+	if title != "" {
+		cy += 30
+	}
+	inner := 0.0
+	if donut {
+		inner = radius * 0.55
+	}
+
+	width := size
+	if showLegend {
+		width += 220
+	}
+	height := cy + size/2
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Arial, sans-serif">`, width, height, width, height)
+	if title != "" {
+		fmt.Fprintf(&b, "\n  <text x=\"%.0f\" y=\"24\" font-size=\"18\" font-weight=\"bold\" text-anchor=\"middle\">%s</text>", width/2, htmltemplate.HTMLEscapeString(title))
+	}
+
+	start := 0.0
+	for i, slice := range slices {
+		end := start + slice.Fraction*2*math.Pi
+		fmt.Fprintf(&b, "\n  <path d=\"%s\" fill=\"%s\" fill-rule=\"evenodd\" stroke=\"#ffffff\" stroke-width=\"1\"><title>%s: %.1f%%</title></path>",
+			pieSlicePath(cx, cy, radius, inner, start, end), colors[i%len(colors)], htmltemplate.HTMLEscapeString(slice.Label), slice.Fraction*100)
+		start = end
+	}
+
+	if showLegend {
+		for i, slice := range slices {
+			y := cy - radius + float64(i)*22
+			fmt.Fprintf(&b, "\n  <rect x=\"%.0f\" y=\"%.0f\" width=\"14\" height=\"14\" fill=\"%s\"/>", size+10, y, colors[i%len(colors)])
+			fmt.Fprintf(&b, "\n  <text x=\"%.0f\" y=\"%.0f\" font-size=\"13\">%s (%.1f%%)</text>", size+30, y+12, htmltemplate.HTMLEscapeString(slice.Label), slice.Fraction*100)
+		}
+	}
+
+	b.WriteString("\n</svg>")
+	return b.String()
+}
+
+// pieSlicePath returns path data for the slice between two angles (radians,
+// clockwise from twelve o'clock). A non-zero inner radius produces a ring segment.
+func pieSlicePath(cx, cy, outer, inner, start, end float64) string {
+	point := func(r, angle float64) (float64, float64) {
+		return cx + r*math.Sin(angle), cy - r*math.Cos(angle)
+	}
+
+	// A single arc cannot describe a full circle, so draw it as two half circles
+	if end-start >= 2*math.Pi-1e-9 {
+		ox0, oy0 := point(outer, 0)
+		ox1, oy1 := point(outer, math.Pi)
+		d := fmt.Sprintf("M %.2f %.2f A %.2f %.2f 0 0 1 %.2f %.2f A %.2f %.2f 0 0 1 %.2f %.2f Z", ox0, oy0, outer, outer, ox1, oy1, outer, outer, ox0, oy0)
+		if inner > 0 {
+			ix0, iy0 := point(inner, 0)
+			ix1, iy1 := point(inner, math.Pi)
+			d += fmt.Sprintf(" M %.2f %.2f A %.2f %.2f 0 0 0 %.2f %.2f A %.2f %.2f 0 0 0 %.2f %.2f Z", ix0, iy0, inner, inner, ix1, iy1, inner, inner, ix0, iy0)
+		}
+		return d
+	}
+
+	large := 0
+	if end-start > math.Pi {
+		large = 1
+	}
+	ox0, oy0 := point(outer, start)
+	ox1, oy1 := point(outer, end)
+	if inner == 0 {
+		return fmt.Sprintf("M %.2f %.2f L %.2f %.2f A %.2f %.2f 0 %d 1 %.2f %.2f Z", cx, cy, ox0, oy0, outer, outer, large, ox1, oy1)
+	}
+	ix0, iy0 := point(inner, start)
+	ix1, iy1 := point(inner, end)
+	return fmt.Sprintf("M %.2f %.2f A %.2f %.2f 0 %d 1 %.2f %.2f L %.2f %.2f A %.2f %.2f 0 %d 0 %.2f %.2f Z",
+		ox0, oy0, outer, outer, large, ox1, oy1, ix1, iy1, inner, inner, large, ix0, iy0)
+}
+
 // Helper functions
 func getStringParam(params map[string]interface{}, key, defaultValue string) string {
 	if val, ok := params[key].(string); ok {
@@ -542,6 +1197,110 @@ func getMapParam(params map[string]interface{}, key string, defaultValue map[str
 	return defaultValue
 }
 
+// Cell styles applied by writeXLSX
+const (
+	xlsxStyleNormal = 0
+	xlsxStyleBold   = 1
+	xlsxStyleTitle  = 2
+)
+
+type xlsxCell struct {
+	Value interface{}
+	Style int
+}
+
+// writeXLSX writes a single-sheet workbook with excelize. Numbers and booleans
+// keep their cell types; everything else is stored as text.
+func writeXLSX(path, sheetName string, rows [][]xlsxCell) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// New workbooks start with a single Sheet1, which is renamed rather than added to
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		return fmt.Errorf("failed to name worksheet: %v", err)
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+	title, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+	styles := map[int]int{xlsxStyleBold: bold, xlsxStyleTitle: title}
+
+	for r, row := range rows {
+		for c, cell := range row {
+			ref, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return fmt.Errorf("failed to write workbook: %v", err)
+			}
+
+			switch v := cell.Value.(type) {
+			case nil:
+			case float64, bool:
+				err = f.SetCellValue(sheetName, ref, v)
+			default:
+				err = f.SetCellStr(sheetName, ref, csvValue(v))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write cell %s: %v", ref, err)
+			}
+
+			if style, ok := styles[cell.Style]; ok {
+				if err := f.SetCellStyle(sheetName, ref, ref, style); err != nil {
+					return fmt.Errorf("failed to style cell %s: %v", ref, err)
+				}
+			}
+		}
+	}
+
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+// writeCSV encodes records as RFC 4180 CSV with CRLF line endings
+func writeCSV(records [][]string) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.UseCRLF = true
+	if err := writer.WriteAll(records); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %v", err)
+	}
+	return buf.String(), nil
+}
+
+func csvRecord(values []interface{}) []string {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = csvValue(value)
+	}
+	return record
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 func convertToFloat(value interface{}) (float64, error) {
 	switch v := value.(type) {
 	case float64:
@@ -596,4 +1355,4 @@ func main() {
 	}
 
 	json.NewEncoder(os.Stdout).Encode(result)
-}
\ No newline at end of file
+}
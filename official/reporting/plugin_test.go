@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLReportEscapesContent(t *testing.T) {
+	const script = `<script>alert("x")</script>`
+
+	tests := []struct {
+		name       string
+		rawHTML    bool
+		wantScript bool
+	}{
+		{name: "escaped by default", rawHTML: false, wantScript: false},
+		{name: "raw_html passes through", rawHTML: true, wantScript: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewReportingPlugin().Execute("create_report", map[string]interface{}{
+				"title":    "Run <1>",
+				"content":  script,
+				"format":   "html",
+				"raw_html": tt.rawHTML,
+			})
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			report, ok := result["report"].(string)
+			if !ok {
+				t.Fatalf("expected a report string, got %v", result)
+			}
+
+			if got := strings.Contains(report, script); got != tt.wantScript {
+				t.Errorf("report contains raw <script>: got %v, want %v\n%s", got, tt.wantScript, report)
+			}
+			if !tt.wantScript && !strings.Contains(report, "&lt;script&gt;") {
+				t.Errorf("expected escaped &lt;script&gt; in report:\n%s", report)
+			}
+			// raw_html only applies to content; the title is always escaped
+			if !strings.Contains(report, "<h1>Run &lt;1&gt;</h1>") {
+				t.Errorf("expected escaped title in report:\n%s", report)
+			}
+		})
+	}
+}
@@ -0,0 +1,580 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type SFTPPlugin struct{}
+
+const connectTimeout = 30 * time.Second
+
+func NewSFTPPlugin() *SFTPPlugin {
+	return &SFTPPlugin{}
+}
+
+func (p *SFTPPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "sftp",
+		Version:     "1.0.0",
+		Description: "SFTP file upload, download, listing and remote file management",
+		Author:      "Corynth Team",
+		Tags:        []string{"sftp", "ssh", "file-transfer", "remote", "files"},
+	}
+}
+
+func (p *SFTPPlugin) GetActions() map[string]ActionSpec {
+	withHost := func(inputs map[string]IOSpec) map[string]IOSpec {
+		inputs["host"] = IOSpec{Type: "string", Required: true, Description: "SFTP server host"}
+		inputs["port"] = IOSpec{Type: "number", Required: false, Default: 22, Description: "SSH port"}
+		inputs["username"] = IOSpec{Type: "string", Required: true, Description: "Login user"}
+		inputs["private_key_path"] = IOSpec{Type: "string", Required: false, Description: "Path to a private key file"}
+		inputs["passphrase"] = IOSpec{Type: "string", Required: false, Description: "Passphrase of the private key"}
+		inputs["password"] = IOSpec{Type: "string", Required: false, Description: "Login password"}
+		inputs["known_hosts_file"] = IOSpec{Type: "string", Required: false, Default: "~/.ssh/known_hosts", Description: "known_hosts file used to verify host keys"}
+		inputs["strict_host_checking"] = IOSpec{Type: "boolean", Required: false, Default: true, Description: "Verify host keys; set to false to accept any key"}
+		return inputs
+	}
+
+	fileInfo := "{name, path, size, modified, permissions, is_dir}"
+
+	return map[string]ActionSpec{
+		"upload": {
+			Description: "Upload a local file",
+			Inputs: withHost(map[string]IOSpec{
+				"local_path":  {Type: "string", Required: true, Description: "Local file to upload"},
+				"remote_path": {Type: "string", Required: true, Description: "Remote file, or existing directory to upload into"},
+			}),
+			Outputs: map[string]IOSpec{
+				"bytes":       {Type: "number", Description: "Bytes uploaded"},
+				"remote_path": {Type: "string", Description: "Remote file written"},
+			},
+		},
+		"download": {
+			Description: "Download a remote file",
+			Inputs: withHost(map[string]IOSpec{
+				"remote_path": {Type: "string", Required: true, Description: "Remote file to download"},
+				"local_path":  {Type: "string", Required: true, Description: "Local file, or existing directory to download into"},
+			}),
+			Outputs: map[string]IOSpec{
+				"bytes":      {Type: "number", Description: "Bytes downloaded"},
+				"local_path": {Type: "string", Description: "Local file written"},
+			},
+		},
+		"list": {
+			Description: "List a remote directory",
+			Inputs: withHost(map[string]IOSpec{
+				"remote_path": {Type: "string", Required: false, Default: ".", Description: "Remote directory"},
+				"pattern":     {Type: "string", Required: false, Description: "Glob matched against file names, e.g. *.csv"},
+			}),
+			Outputs: map[string]IOSpec{
+				"files": {Type: "array", Description: "Entries as " + fileInfo + ", sorted by name"},
+				"count": {Type: "number", Description: "Number of entries returned"},
+			},
+		},
+		"delete": {
+			Description: "Delete a remote file or directory",
+			Inputs: withHost(map[string]IOSpec{
+				"remote_path": {Type: "string", Required: true, Description: "Remote file or directory"},
+				"recursive":   {Type: "boolean", Required: false, Default: false, Description: "Delete a directory and everything in it"},
+			}),
+			Outputs: map[string]IOSpec{
+				"deleted": {Type: "boolean", Description: "Whether the path was deleted"},
+			},
+		},
+		"mkdir": {
+			Description: "Create a remote directory",
+			Inputs: withHost(map[string]IOSpec{
+				"remote_path": {Type: "string", Required: true, Description: "Directory to create"},
+				"parents":     {Type: "boolean", Required: false, Default: true, Description: "Create missing parent directories and succeed if it already exists"},
+			}),
+			Outputs: map[string]IOSpec{
+				"created": {Type: "boolean", Description: "Whether the directory exists afterwards"},
+			},
+		},
+		"stat": {
+			Description: "Get metadata of a remote file or directory",
+			Inputs: withHost(map[string]IOSpec{
+				"remote_path": {Type: "string", Required: true, Description: "Remote path"},
+			}),
+			Outputs: map[string]IOSpec{
+				"exists":      {Type: "boolean", Description: "Whether the path exists"},
+				"name":        {Type: "string", Description: "File name"},
+				"path":        {Type: "string", Description: "Remote path"},
+				"size":        {Type: "number", Description: "Size in bytes"},
+				"modified":    {Type: "string", Description: "Modification time (RFC 3339)"},
+				"permissions": {Type: "string", Description: "Octal permissions, e.g. 0644"},
+				"is_dir":      {Type: "boolean", Description: "Whether the path is a directory"},
+			},
+		},
+		"rename": {
+			Description: "Rename or move a remote file",
+			Inputs: withHost(map[string]IOSpec{
+				"old_path":  {Type: "string", Required: true, Description: "Current remote path"},
+				"new_path":  {Type: "string", Required: true, Description: "New remote path"},
+				"overwrite": {Type: "boolean", Required: false, Default: false, Description: "Replace new_path if it exists (needs the posix-rename server extension)"},
+			}),
+			Outputs: map[string]IOSpec{
+				"renamed": {Type: "boolean", Description: "Whether the rename succeeded"},
+			},
+		},
+	}
+}
+
+func (p *SFTPPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	var run func(*sftp.Client, map[string]interface{}) (map[string]interface{}, error)
+
+	switch action {
+	case "upload":
+		run = p.upload
+	case "download":
+		run = p.download
+	case "list":
+		run = p.list
+	case "delete":
+		run = p.delete
+	case "mkdir":
+		run = p.mkdir
+	case "stat":
+		run = p.stat
+	case "rename":
+		run = p.rename
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	host := getStringParam(params, "host", "")
+	if host == "" {
+		return map[string]interface{}{"error": "host is required"}, nil
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(getIntParam(params, "port", 22)))
+
+	clientConfig, err := sshClientConfig(params, address)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	conn, err := ssh.Dial("tcp", address, clientConfig)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to connect to %s: %v", address, err)}, nil
+	}
+	defer conn.Close()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to start sftp session: %v", err)}, nil
+	}
+	defer client.Close()
+
+	result, err := run(client, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	return result, nil
+}
+
+func (p *SFTPPlugin) upload(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	localPath := getStringParam(params, "local_path", "")
+	remotePath := getStringParam(params, "remote_path", "")
+	if localPath == "" || remotePath == "" {
+		return nil, fmt.Errorf("local_path and remote_path are required")
+	}
+
+	local, err := os.Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local file: %v", err)
+	}
+	defer local.Close()
+
+	if info, err := client.Stat(remotePath); err == nil && info.IsDir() {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	remote, err := client.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote file %s: %v", remotePath, err)
+	}
+	defer remote.Close()
+
+	// io.Copy streams through sftp.File.ReadFrom, which pipelines writes without buffering the whole file
+	written, err := io.Copy(remote, local)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %v", err)
+	}
+	if err := remote.Close(); err != nil {
+		return nil, fmt.Errorf("upload failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"bytes":       written,
+		"remote_path": remotePath,
+	}, nil
+}
+
+func (p *SFTPPlugin) download(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	remotePath := getStringParam(params, "remote_path", "")
+	localPath := getStringParam(params, "local_path", "")
+	if remotePath == "" || localPath == "" {
+		return nil, fmt.Errorf("remote_path and local_path are required")
+	}
+
+	remote, err := client.Open(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open remote file %s: %v", remotePath, err)
+	}
+	defer remote.Close()
+
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remotePath))
+	}
+
+	local, err := os.Create(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer local.Close()
+
+	written, err := io.Copy(local, remote)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+	if err := local.Close(); err != nil {
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+
+	return map[string]interface{}{
+		"bytes":      written,
+		"local_path": localPath,
+	}, nil
+}
+
+func (p *SFTPPlugin) list(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	remotePath := getStringParam(params, "remote_path", ".")
+	pattern := getStringParam(params, "pattern", "")
+	if pattern != "" {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+
+	entries, err := client.ReadDir(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", remotePath, err)
+	}
+
+	files := []map[string]interface{}{}
+	for _, entry := range entries {
+		if pattern != "" {
+			if matched, _ := path.Match(pattern, entry.Name()); !matched {
+				continue
+			}
+		}
+		files = append(files, fileMetadata(path.Join(remotePath, entry.Name()), entry))
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i]["name"].(string) < files[j]["name"].(string)
+	})
+
+	return map[string]interface{}{
+		"files": files,
+		"count": len(files),
+	}, nil
+}
+
+func (p *SFTPPlugin) delete(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	remotePath := getStringParam(params, "remote_path", "")
+	if remotePath == "" {
+		return nil, fmt.Errorf("remote_path is required")
+	}
+
+	var err error
+	if getBoolParam(params, "recursive", false) {
+		err = client.RemoveAll(remotePath)
+	} else {
+		err = client.Remove(remotePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete %s: %v", remotePath, err)
+	}
+
+	return map[string]interface{}{
+		"deleted": true,
+	}, nil
+}
+
+func (p *SFTPPlugin) mkdir(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	remotePath := getStringParam(params, "remote_path", "")
+	if remotePath == "" {
+		return nil, fmt.Errorf("remote_path is required")
+	}
+
+	var err error
+	if getBoolParam(params, "parents", true) {
+		err = client.MkdirAll(remotePath)
+	} else {
+		err = client.Mkdir(remotePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", remotePath, err)
+	}
+
+	return map[string]interface{}{
+		"created": true,
+	}, nil
+}
+
+func (p *SFTPPlugin) stat(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	remotePath := getStringParam(params, "remote_path", "")
+	if remotePath == "" {
+		return nil, fmt.Errorf("remote_path is required")
+	}
+
+	info, err := client.Stat(remotePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]interface{}{"exists": false, "path": remotePath}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %v", remotePath, err)
+	}
+
+	result := fileMetadata(remotePath, info)
+	result["exists"] = true
+	return result, nil
+}
+
+func (p *SFTPPlugin) rename(client *sftp.Client, params map[string]interface{}) (map[string]interface{}, error) {
+	oldPath := getStringParam(params, "old_path", "")
+	newPath := getStringParam(params, "new_path", "")
+	if oldPath == "" || newPath == "" {
+		return nil, fmt.Errorf("old_path and new_path are required")
+	}
+
+	// Plain SFTP rename fails when the target exists; posix-rename replaces it atomically
+	var err error
+	if getBoolParam(params, "overwrite", false) {
+		err = client.PosixRename(oldPath, newPath)
+	} else {
+		err = client.Rename(oldPath, newPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename %s to %s: %v", oldPath, newPath, err)
+	}
+
+	return map[string]interface{}{
+		"renamed": true,
+	}, nil
+}
+
+func fileMetadata(remotePath string, info os.FileInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        info.Name(),
+		"path":        remotePath,
+		"size":        info.Size(),
+		"modified":    info.ModTime().UTC().Format(time.RFC3339),
+		"permissions": fmt.Sprintf("%04o", info.Mode().Perm()),
+		"is_dir":      info.IsDir(),
+	}
+}
+
+func sshClientConfig(params map[string]interface{}, address string) (*ssh.ClientConfig, error) {
+	username := getStringParam(params, "username", "")
+	if username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	clientConfig := &ssh.ClientConfig{
+		User:    username,
+		Timeout: connectTimeout,
+	}
+
+	if keyPath := getStringParam(params, "private_key_path", ""); keyPath != "" {
+		key, err := os.ReadFile(expandHome(keyPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %v", err)
+		}
+		var signer ssh.Signer
+		if passphrase := getStringParam(params, "passphrase", ""); passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %v", err)
+		}
+		clientConfig.Auth = append(clientConfig.Auth, ssh.PublicKeys(signer))
+	}
+
+	if password := getStringParam(params, "password", ""); password != "" {
+		clientConfig.Auth = append(clientConfig.Auth,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	if len(clientConfig.Auth) == 0 {
+		return nil, fmt.Errorf("private_key_path or password is required")
+	}
+
+	if !getBoolParam(params, "strict_host_checking", true) {
+		clientConfig.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+		return clientConfig, nil
+	}
+
+	knownHostsFile := getStringParam(params, "known_hosts_file", "~/.ssh/known_hosts")
+	callback, err := knownhosts.New(expandHome(knownHostsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts from %s: %v (set strict_host_checking to false to skip verification)", knownHostsFile, err)
+	}
+	clientConfig.HostKeyCallback = callback
+	clientConfig.HostKeyAlgorithms = knownHostKeyAlgorithms(callback, address)
+
+	return clientConfig, nil
+}
+
+// knownHostKeyAlgorithms returns the host key algorithms recorded for address in known_hosts. Servers
+// offer several host keys, and without this the handshake may pick a type that isn't recorded and fail
+// verification even though the host is known.
+func knownHostKeyAlgorithms(callback ssh.HostKeyCallback, address string) []string {
+	_, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return nil
+	}
+
+	// Checking a throwaway key makes the callback report the keys it knows for the host
+	var keyErr *knownhosts.KeyError
+	if !errors.As(callback(address, &net.TCPAddr{IP: net.IPv4zero}, signer.PublicKey()), &keyErr) {
+		return nil
+	}
+
+	var algorithms []string
+	seen := map[string]bool{}
+	for _, known := range keyErr.Want {
+		keyType := known.Key.Type()
+		if seen[keyType] {
+			continue
+		}
+		seen[keyType] = true
+		if keyType == ssh.KeyAlgoRSA {
+			algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256)
+		}
+		algorithms = append(algorithms, keyType)
+	}
+	return algorithms
+}
+
+func expandHome(name string) string {
+	if name == "~" || strings.HasPrefix(name, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, name[1:])
+		}
+	}
+	return name
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewSFTPPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}
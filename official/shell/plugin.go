@@ -8,7 +8,12 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
@@ -81,13 +86,128 @@ func (p *ShellPlugin) GetActions() map[string]ActionSpec {
 					Required:    false,
 					Description: "Environment variables as key-value pairs",
 				},
+				"isolated_env": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Pass only the env variables instead of inheriting the plugin's environment",
+				},
+				"path": {
+					Type:        "string",
+					Required:    false,
+					Description: "Value for PATH, overriding the inherited or env-provided one",
+				},
+				"stdin": {
+					Type:        "string",
+					Required:    false,
+					Description: "Data written to the command's standard input",
+				},
 			},
 			Outputs: map[string]IOSpec{
-				"output":    {Type: "string", Description: "Combined stdout and stderr output"},
-				"stdout":    {Type: "string", Description: "Standard output"},
-				"stderr":    {Type: "string", Description: "Standard error"},
-				"exit_code": {Type: "number", Description: "Process exit code"},
-				"success":   {Type: "boolean", Description: "Whether command succeeded (exit code 0)"},
+				"output":        {Type: "string", Description: "Combined stdout and stderr output"},
+				"stdout":        {Type: "string", Description: "Standard output"},
+				"stderr":        {Type: "string", Description: "Standard error"},
+				"exit_code":     {Type: "number", Description: "Process exit code"},
+				"success":       {Type: "boolean", Description: "Whether command succeeded (exit code 0)"},
+				"effective_env": {Type: "array", Description: "Names (not values) of the environment variables the command received"},
+			},
+		},
+		"parallel_exec": {
+			Description: "Execute independent shell commands concurrently",
+			Inputs: map[string]IOSpec{
+				"commands": {
+					Type:        "array",
+					Required:    true,
+					Description: "Shell commands to execute",
+				},
+				"max_concurrency": {
+					Type:        "number",
+					Required:    false,
+					Description: "Maximum commands running at once (defaults to the number of commands)",
+				},
+				"working_dir": {
+					Type:        "string",
+					Required:    false,
+					Description: "Working directory for every command",
+				},
+				"timeout": {
+					Type:        "number",
+					Required:    false,
+					Default:     300,
+					Description: "Timeout in seconds shared by all commands",
+				},
+				"env": {
+					Type:        "object",
+					Required:    false,
+					Description: "Environment variables as key-value pairs",
+				},
+				"isolated_env": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Pass only the env variables instead of inheriting the plugin's environment",
+				},
+				"path": {
+					Type:        "string",
+					Required:    false,
+					Description: "Value for PATH, overriding the inherited or env-provided one",
+				},
+			},
+			Outputs: map[string]IOSpec{
+				"results":       {Type: "array", Description: "Per-command command, stdout, stderr, exit_code, and success, in input order"},
+				"success":       {Type: "boolean", Description: "Whether every command succeeded"},
+				"failed":        {Type: "number", Description: "Number of commands that failed"},
+				"effective_env": {Type: "array", Description: "Names (not values) of the environment variables the commands received"},
+			},
+		},
+		"pipe": {
+			Description: "Run commands as a pipeline, feeding each command's stdout to the next without a shell",
+			Inputs: map[string]IOSpec{
+				"commands": {
+					Type:        "array",
+					Required:    true,
+					Description: "Commands to chain, each an argv array such as [\"grep\", \"a b\"] or a string split on whitespace (quotes are not interpreted)",
+				},
+				"stdin": {
+					Type:        "string",
+					Required:    false,
+					Description: "Data written to the first command's standard input",
+				},
+				"working_dir": {
+					Type:        "string",
+					Required:    false,
+					Description: "Working directory for every command",
+				},
+				"timeout": {
+					Type:        "number",
+					Required:    false,
+					Default:     300,
+					Description: "Timeout in seconds for the whole pipeline",
+				},
+				"env": {
+					Type:        "object",
+					Required:    false,
+					Description: "Environment variables as key-value pairs",
+				},
+				"isolated_env": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Pass only the env variables instead of inheriting the plugin's environment",
+				},
+				"path": {
+					Type:        "string",
+					Required:    false,
+					Description: "Value for PATH, overriding the inherited or env-provided one",
+				},
+			},
+			Outputs: map[string]IOSpec{
+				"output":        {Type: "string", Description: "Standard output of the last command"},
+				"stderr":        {Type: "string", Description: "Standard error of all commands"},
+				"stages":        {Type: "array", Description: "Per-command command, stdout, stderr, and exit_code"},
+				"exit_code":     {Type: "number", Description: "First non-zero exit code in the pipeline, or 0"},
+				"success":       {Type: "boolean", Description: "Whether every command succeeded"},
+				"effective_env": {Type: "array", Description: "Names (not values) of the environment variables the commands received"},
 			},
 		},
 		"script": {
@@ -113,20 +233,38 @@ func (p *ShellPlugin) GetActions() map[string]ActionSpec {
 					Type:        "string",
 					Required:    false,
 					Default:     "bash",
-					Description: "Shell/interpreter type (bash, sh, python, python3, node, etc.)",
+					Description: "Shell/interpreter type (bash, sh, python, python3, node, powershell, etc.)",
+				},
+				"execution_policy": {
+					Type:        "string",
+					Required:    false,
+					Default:     "Bypass",
+					Description: "PowerShell execution policy for powershell scripts (applies on Windows)",
 				},
 				"env": {
 					Type:        "object",
 					Required:    false,
 					Description: "Environment variables as key-value pairs",
 				},
+				"isolated_env": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     false,
+					Description: "Pass only the env variables instead of inheriting the plugin's environment",
+				},
+				"path": {
+					Type:        "string",
+					Required:    false,
+					Description: "Value for PATH, overriding the inherited or env-provided one",
+				},
 			},
 			Outputs: map[string]IOSpec{
-				"output":    {Type: "string", Description: "Combined stdout and stderr output"},
-				"stdout":    {Type: "string", Description: "Standard output"},
-				"stderr":    {Type: "string", Description: "Standard error"},
-				"exit_code": {Type: "number", Description: "Process exit code"},
-				"success":   {Type: "boolean", Description: "Whether script succeeded (exit code 0)"},
+				"output":        {Type: "string", Description: "Combined stdout and stderr output"},
+				"stdout":        {Type: "string", Description: "Standard output"},
+				"stderr":        {Type: "string", Description: "Standard error (for PowerShell, the error stream: Write-Error and uncaught errors, not Write-Host)"},
+				"exit_code":     {Type: "number", Description: "Process exit code (for PowerShell, set with exit; non-terminating errors leave it 0)"},
+				"success":       {Type: "boolean", Description: "Whether script succeeded (exit code 0)"},
+				"effective_env": {Type: "array", Description: "Names (not values) of the environment variables the script received"},
 			},
 		},
 	}
@@ -138,6 +276,10 @@ func (p *ShellPlugin) Execute(action string, params map[string]interface{}) (map
 		return p.executeCommand(params)
 	case "script":
 		return p.executeScript(params)
+	case "parallel_exec":
+		return p.executeParallel(params)
+	case "pipe":
+		return p.executePipe(params)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
@@ -153,7 +295,6 @@ func (p *ShellPlugin) executeCommand(params map[string]interface{}) (map[string]
 	workingDir := p.getStringParam(params, "working_dir", "")
 	timeout := p.getFloatParam(params, "timeout", 300)
 	useShell := p.getBoolParam(params, "shell", true)
-	envVars := p.getMapParam(params, "env")
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
@@ -178,23 +319,23 @@ func (p *ShellPlugin) executeCommand(params map[string]interface{}) (map[string]
 	}
 
 	// Set environment variables
-	if len(envVars) > 0 {
-		env := os.Environ()
-		for key, value := range envVars {
-			env = append(env, fmt.Sprintf("%s=%s", key, value))
-		}
-		cmd.Env = env
+	env, envKeys := p.buildEnv(params)
+	cmd.Env = env
+
+	if stdin, ok := params["stdin"].(string); ok {
+		cmd.Stdin = strings.NewReader(stdin)
 	}
 
 	// Execute command and capture output
 	stdout, stderr, exitCode := p.runCommand(cmd)
 
 	return map[string]interface{}{
-		"output":    stdout + stderr,
-		"stdout":    stdout,
-		"stderr":    stderr,
-		"exit_code": exitCode,
-		"success":   exitCode == 0,
+		"output":        stdout + stderr,
+		"stdout":        stdout,
+		"stderr":        stderr,
+		"exit_code":     exitCode,
+		"success":       exitCode == 0,
+		"effective_env": envKeys,
 	}, nil
 }
 
@@ -208,7 +349,6 @@ func (p *ShellPlugin) executeScript(params map[string]interface{}) (map[string]i
 	workingDir := p.getStringParam(params, "working_dir", "")
 	timeout := p.getFloatParam(params, "timeout", 300)
 	shellType := p.getStringParam(params, "shell_type", "bash")
-	envVars := p.getMapParam(params, "env")
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
@@ -228,13 +368,13 @@ func (p *ShellPlugin) executeScript(params map[string]interface{}) (map[string]i
 			return map[string]interface{}{"error": fmt.Sprintf("failed to create temp file: %v", err)}, nil
 		}
 		defer os.Remove(tmpFile.Name())
-		
+
 		if _, err := tmpFile.WriteString(script); err != nil {
 			tmpFile.Close()
 			return map[string]interface{}{"error": fmt.Sprintf("failed to write script: %v", err)}, nil
 		}
 		tmpFile.Close()
-		
+
 		cmd = exec.CommandContext(ctx, shellType, tmpFile.Name())
 	case "node", "nodejs":
 		// Create temporary file for Node.js script
@@ -243,14 +383,44 @@ func (p *ShellPlugin) executeScript(params map[string]interface{}) (map[string]i
 			return map[string]interface{}{"error": fmt.Sprintf("failed to create temp file: %v", err)}, nil
 		}
 		defer os.Remove(tmpFile.Name())
-		
+
 		if _, err := tmpFile.WriteString(script); err != nil {
 			tmpFile.Close()
 			return map[string]interface{}{"error": fmt.Sprintf("failed to write script: %v", err)}, nil
 		}
 		tmpFile.Close()
-		
+
 		cmd = exec.CommandContext(ctx, "node", tmpFile.Name())
+	case "powershell", "pwsh":
+		// Windows ships powershell.exe; elsewhere PowerShell Core is installed as pwsh
+		executable := "powershell.exe"
+		if runtime.GOOS != "windows" {
+			executable = "pwsh"
+		}
+		if _, err := exec.LookPath(executable); err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("PowerShell is not available: %s not found in PATH", executable)}, nil
+		}
+
+		// Create temporary file for PowerShell script
+		tmpFile, err := ioutil.TempFile("", "corynth_script_*.ps1")
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to create temp file: %v", err)}, nil
+		}
+		defer os.Remove(tmpFile.Name())
+
+		if _, err := tmpFile.WriteString(script); err != nil {
+			tmpFile.Close()
+			return map[string]interface{}{"error": fmt.Sprintf("failed to write script: %v", err)}, nil
+		}
+		tmpFile.Close()
+
+		args := []string{"-NoProfile", "-NonInteractive"}
+		// Execution policies only exist on Windows
+		if runtime.GOOS == "windows" {
+			args = append(args, "-ExecutionPolicy", p.getStringParam(params, "execution_policy", "Bypass"))
+		}
+		args = append(args, "-File", tmpFile.Name())
+		cmd = exec.CommandContext(ctx, executable, args...)
 	default:
 		// For other interpreters, try to execute directly with -c flag
 		cmd = exec.CommandContext(ctx, shellType, "-c", script)
@@ -262,26 +432,263 @@ func (p *ShellPlugin) executeScript(params map[string]interface{}) (map[string]i
 	}
 
 	// Set environment variables
-	if len(envVars) > 0 {
-		env := os.Environ()
-		for key, value := range envVars {
-			env = append(env, fmt.Sprintf("%s=%s", key, value))
+	env, envKeys := p.buildEnv(params)
+	cmd.Env = env
+
+	// Execute script and capture output
+	stdout, stderr, exitCode := p.runCommand(cmd)
+
+	return map[string]interface{}{
+		"output":        stdout + stderr,
+		"stdout":        stdout,
+		"stderr":        stderr,
+		"exit_code":     exitCode,
+		"success":       exitCode == 0,
+		"effective_env": envKeys,
+	}, nil
+}
+
+func (p *ShellPlugin) executeParallel(params map[string]interface{}) (map[string]interface{}, error) {
+	commandList, ok := params["commands"].([]interface{})
+	if !ok || len(commandList) == 0 {
+		return map[string]interface{}{"error": "commands parameter is required"}, nil
+	}
+
+	commands := make([]string, len(commandList))
+	for i, item := range commandList {
+		command, _ := item.(string)
+		if strings.TrimSpace(command) == "" {
+			return map[string]interface{}{"error": fmt.Sprintf("command %d is empty", i)}, nil
+		}
+		commands[i] = command
+	}
+
+	// Extract parameters with defaults
+	workingDir := p.getStringParam(params, "working_dir", "")
+	timeout := p.getFloatParam(params, "timeout", 300)
+	maxConcurrency := int(p.getFloatParam(params, "max_concurrency", float64(len(commands))))
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	env, envKeys := p.buildEnv(params)
+
+	// One deadline covers every command, including those still waiting for a slot
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	results := make([]map[string]interface{}, len(commands))
+	slots := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, command := range commands {
+		wg.Add(1)
+		go func(i int, command string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+			if workingDir != "" {
+				cmd.Dir = workingDir
+			}
+			cmd.Env = env
+
+			stdout, stderr, exitCode := p.runCommand(cmd)
+			results[i] = map[string]interface{}{
+				"command":   command,
+				"stdout":    stdout,
+				"stderr":    stderr,
+				"exit_code": exitCode,
+				"success":   exitCode == 0,
+			}
+		}(i, command)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result["success"] != true {
+			failed++
+		}
+	}
+
+	return map[string]interface{}{
+		"results":       results,
+		"success":       failed == 0,
+		"failed":        failed,
+		"effective_env": envKeys,
+	}, nil
+}
+
+func (p *ShellPlugin) executePipe(params map[string]interface{}) (map[string]interface{}, error) {
+	commandList, ok := params["commands"].([]interface{})
+	if !ok || len(commandList) == 0 {
+		return map[string]interface{}{"error": "commands parameter is required"}, nil
+	}
+
+	// Extract parameters with defaults
+	workingDir := p.getStringParam(params, "working_dir", "")
+	timeout := p.getFloatParam(params, "timeout", 300)
+	env, envKeys := p.buildEnv(params)
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	// Commands are run directly rather than through a shell, so their text is never interpreted
+	commands := make([]string, len(commandList))
+	cmds := make([]*exec.Cmd, len(commandList))
+	for i, item := range commandList {
+		// An argv array keeps arguments with spaces intact; a string is only split on whitespace
+		var parts []string
+		switch command := item.(type) {
+		case string:
+			parts = strings.Fields(command)
+			commands[i] = command
+		case []interface{}:
+			display := make([]string, 0, len(command))
+			for _, arg := range command {
+				argStr, ok := arg.(string)
+				if !ok {
+					return map[string]interface{}{"error": fmt.Sprintf("command %d: arguments must be strings", i)}, nil
+				}
+				parts = append(parts, argStr)
+				if argStr == "" || strings.ContainsAny(argStr, " \t\n\"") {
+					argStr = strconv.Quote(argStr)
+				}
+				display = append(display, argStr)
+			}
+			commands[i] = strings.Join(display, " ")
+		}
+		if len(parts) == 0 || parts[0] == "" {
+			return map[string]interface{}{"error": fmt.Sprintf("command %d is empty", i)}, nil
+		}
+
+		cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+		if workingDir != "" {
+			cmd.Dir = workingDir
 		}
 		cmd.Env = env
+		cmds[i] = cmd
 	}
 
-	// Execute script and capture output
-	stdout, stderr, exitCode := p.runCommand(cmd)
+	stdouts := make([]strings.Builder, len(cmds))
+	stderrs := make([]strings.Builder, len(cmds))
+	if stdin, ok := params["stdin"].(string); ok {
+		cmds[0].Stdin = strings.NewReader(stdin)
+	}
+
+	// Connect the stages with OS pipes, teeing each stdout so intermediate output can be reported
+	pipeWriters := make([]*os.File, len(cmds))
+	for i := 0; i < len(cmds)-1; i++ {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			return map[string]interface{}{"error": fmt.Sprintf("failed to create pipe: %v", err)}, nil
+		}
+		cmds[i].Stdout = io.MultiWriter(&stdouts[i], writer)
+		cmds[i+1].Stdin = reader
+		pipeWriters[i] = writer
+	}
+	cmds[len(cmds)-1].Stdout = &stdouts[len(cmds)-1]
+
+	startErrs := make([]error, len(cmds))
+	for i, cmd := range cmds {
+		cmd.Stderr = &stderrs[i]
+		startErrs[i] = cmd.Start()
+		// The child now owns its end of the pipe; keeping ours open would stop the upstream
+		// command from seeing a closed reader
+		if reader, ok := cmd.Stdin.(*os.File); ok {
+			reader.Close()
+		}
+	}
+
+	stages := make([]map[string]interface{}, len(cmds))
+	var allStderr strings.Builder
+	pipelineExit := 0
+	for i, cmd := range cmds {
+		exitCode := 0
+		err := startErrs[i]
+		if err == nil {
+			err = cmd.Wait()
+		}
+		if pipeWriters[i] != nil {
+			// Signal EOF to the next command
+			pipeWriters[i].Close()
+		}
+
+		if err != nil {
+			if exitError, ok := err.(*exec.ExitError); ok {
+				exitCode = exitError.ExitCode()
+				// A stage killed by SIGPIPE only means a later command stopped reading early (as head does)
+				if status, ok := exitError.Sys().(syscall.WaitStatus); ok && status.Signaled() && status.Signal() == syscall.SIGPIPE && i < len(cmds)-1 {
+					exitCode = 0
+				}
+			} else {
+				// Command failed to start or other error
+				exitCode = -1
+				if stderrs[i].Len() == 0 {
+					stderrs[i].WriteString(err.Error())
+				}
+			}
+		}
+		if exitCode != 0 && pipelineExit == 0 {
+			pipelineExit = exitCode
+		}
+
+		allStderr.WriteString(stderrs[i].String())
+		stages[i] = map[string]interface{}{
+			"command":   commands[i],
+			"stdout":    stdouts[i].String(),
+			"stderr":    stderrs[i].String(),
+			"exit_code": exitCode,
+		}
+	}
 
 	return map[string]interface{}{
-		"output":    stdout + stderr,
-		"stdout":    stdout,
-		"stderr":    stderr,
-		"exit_code": exitCode,
-		"success":   exitCode == 0,
+		"output":        stdouts[len(cmds)-1].String(),
+		"stderr":        allStderr.String(),
+		"stages":        stages,
+		"exit_code":     pipelineExit,
+		"success":       pipelineExit == 0,
+		"effective_env": envKeys,
 	}, nil
 }
 
+// buildEnv returns the environment for a command and the sorted names of its variables.
+// The env input is merged over the plugin's own environment unless isolated_env is set,
+// in which case only env (and path) are passed; path always overrides PATH.
+func (p *ShellPlugin) buildEnv(params map[string]interface{}) ([]string, []string) {
+	values := make(map[string]string)
+	order := []string{}
+	set := func(key, value string) {
+		if _, exists := values[key]; !exists {
+			order = append(order, key)
+		}
+		values[key] = value
+	}
+
+	if !p.getBoolParam(params, "isolated_env", false) {
+		for _, entry := range os.Environ() {
+			if key, value, ok := strings.Cut(entry, "="); ok {
+				set(key, value)
+			}
+		}
+	}
+	for key, value := range p.getMapParam(params, "env") {
+		set(key, value)
+	}
+	if path := p.getStringParam(params, "path", ""); path != "" {
+		set("PATH", path)
+	}
+
+	env := make([]string, 0, len(order))
+	for _, key := range order {
+		env = append(env, key+"="+values[key])
+	}
+	sort.Strings(order)
+	return env, order
+}
+
 func (p *ShellPlugin) runCommand(cmd *exec.Cmd) (stdout, stderr string, exitCode int) {
 	var outBuf, errBuf strings.Builder
 	cmd.Stdout = &outBuf
@@ -381,4 +788,4 @@ func main() {
 	}
 
 	json.NewEncoder(os.Stdout).Encode(result)
-}
\ No newline at end of file
+}
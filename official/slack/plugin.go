@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -38,12 +43,26 @@ type ActionSpec struct {
 	Outputs     map[string]OutputSpec `json:"outputs"`
 }
 
+// slackAPIBase is the Slack Web API endpoint prefix; method names are appended to it
+const slackAPIBase = "https://slack.com/api/"
+
+// maxUploadSize is Slack's file size limit (1 GB)
+const maxUploadSize = 1 << 30
+
+// defaultMaxRetries is how many times a rate-limited Web API call is retried
+const defaultMaxRetries = 3
+
+// defaultRetryAfter is used when a rate-limited response has no usable Retry-After header
+const defaultRetryAfter = time.Second
+
 // SlackPlugin represents the Slack plugin
 type SlackPlugin struct {
 	metadata   Metadata
 	token      string
 	webhookURL string
 	client     *http.Client
+	maxRetries int
+	attempts   int
 }
 
 // NewSlackPlugin creates a new Slack plugin instance
@@ -83,7 +102,17 @@ func (s *SlackPlugin) GetActions() map[string]ActionSpec {
 				"text": {
 					Type:        "string",
 					Required:    true,
-					Description: "Message text",
+					Description: "Message text (the notification fallback when blocks are given)",
+				},
+				"blocks": {
+					Type:        "array",
+					Required:    false,
+					Description: "Block Kit blocks (array, or a JSON string of one)",
+				},
+				"attachments": {
+					Type:        "array",
+					Required:    false,
+					Description: "Legacy attachments, e.g. with a color bar (array, or a JSON string of one)",
 				},
 				"username": {
 					Type:        "string",
@@ -95,10 +124,168 @@ func (s *SlackPlugin) GetActions() map[string]ActionSpec {
 					Required:    false,
 					Description: "Bot emoji icon",
 				},
+				"thread_ts": {
+					Type:        "string",
+					Required:    false,
+					Description: "Timestamp of the parent message to reply in its thread",
+				},
+				"max_retries": {
+					Type:        "number",
+					Required:    false,
+					Description: "Retries when Slack rate limits the request (default 3)",
+				},
 			},
 			Outputs: map[string]OutputSpec{
-				"success": {Type: "boolean"},
+				"success":   {Type: "boolean"},
 				"timestamp": {Type: "string"},
+				"ts":        {Type: "string"},
+				"channel":   {Type: "string"},
+				"attempts":  {Type: "number"},
+			},
+		},
+		"update_message": {
+			Description: "Edit a previously sent message",
+			Inputs: map[string]InputSpec{
+				"channel": {
+					Type:        "string",
+					Required:    true,
+					Description: "Channel ID containing the message",
+				},
+				"ts": {
+					Type:        "string",
+					Required:    true,
+					Description: "Timestamp of the message to update",
+				},
+				"text": {
+					Type:        "string",
+					Required:    true,
+					Description: "New message text",
+				},
+				"max_retries": {
+					Type:        "number",
+					Required:    false,
+					Description: "Retries when Slack rate limits the request (default 3)",
+				},
+			},
+			Outputs: map[string]OutputSpec{
+				"success":   {Type: "boolean"},
+				"ts":        {Type: "string"},
+				"channel":   {Type: "string"},
+				"not_found": {Type: "boolean"},
+				"attempts":  {Type: "number"},
+			},
+		},
+		"add_reaction": {
+			Description: "Add an emoji reaction to a message",
+			Inputs: map[string]InputSpec{
+				"channel": {
+					Type:        "string",
+					Required:    true,
+					Description: "Channel ID containing the message",
+				},
+				"timestamp": {
+					Type:        "string",
+					Required:    true,
+					Description: "Timestamp of the message to react to",
+				},
+				"name": {
+					Type:        "string",
+					Required:    true,
+					Description: "Emoji name without colons (e.g. thumbsup)",
+				},
+				"max_retries": {
+					Type:        "number",
+					Required:    false,
+					Description: "Retries when Slack rate limits the request (default 3)",
+				},
+			},
+			Outputs: map[string]OutputSpec{
+				"success":  {Type: "boolean"},
+				"attempts": {Type: "number"},
+			},
+		},
+		"get_history": {
+			Description: "Retrieve messages from a channel",
+			Inputs: map[string]InputSpec{
+				"channel": {
+					Type:        "string",
+					Required:    true,
+					Description: "Channel ID to read",
+				},
+				"limit": {
+					Type:        "number",
+					Required:    false,
+					Description: "Maximum number of messages to return (default 100)",
+				},
+				"oldest": {
+					Type:        "string",
+					Required:    false,
+					Description: "Only messages after this timestamp",
+				},
+				"latest": {
+					Type:        "string",
+					Required:    false,
+					Description: "Only messages before this timestamp",
+				},
+				"max_retries": {
+					Type:        "number",
+					Required:    false,
+					Description: "Retries when Slack rate limits the request (default 3)",
+				},
+			},
+			Outputs: map[string]OutputSpec{
+				"success":  {Type: "boolean"},
+				"messages": {Type: "array"},
+				"count":    {Type: "number"},
+				"has_more": {Type: "boolean"},
+				"attempts": {Type: "number"},
+			},
+		},
+		"upload_file": {
+			Description: "Upload a file and share it to channels",
+			Inputs: map[string]InputSpec{
+				"file_path": {
+					Type:        "string",
+					Required:    true,
+					Description: "Path of the file to upload",
+				},
+				"channels": {
+					Type:        "string",
+					Required:    false,
+					Description: "Channel IDs to share to (comma-separated string or array)",
+				},
+				"initial_comment": {
+					Type:        "string",
+					Required:    false,
+					Description: "Message posted with the file",
+				},
+				"title": {
+					Type:        "string",
+					Required:    false,
+					Description: "File title (defaults to the file name)",
+				},
+				"filename": {
+					Type:        "string",
+					Required:    false,
+					Description: "File name shown in Slack (defaults to the base name of file_path)",
+				},
+				"thread_ts": {
+					Type:        "string",
+					Required:    false,
+					Description: "Share the file as a reply in this thread",
+				},
+				"max_retries": {
+					Type:        "number",
+					Required:    false,
+					Description: "Retries when Slack rate limits the request (default 3)",
+				},
+			},
+			Outputs: map[string]OutputSpec{
+				"success":   {Type: "boolean"},
+				"file_id":   {Type: "string"},
+				"permalink": {Type: "string"},
+				"size":      {Type: "number"},
+				"attempts":  {Type: "number"},
 			},
 		},
 		"webhook": {
@@ -129,9 +316,32 @@ func (s *SlackPlugin) GetActions() map[string]ActionSpec {
 
 // Execute executes the specified action
 func (s *SlackPlugin) Execute(action string, params map[string]interface{}) map[string]interface{} {
+	s.maxRetries = defaultMaxRetries
+	if retries, ok := params["max_retries"].(float64); ok && retries >= 0 {
+		s.maxRetries = int(retries)
+	}
+	s.attempts = 0
+
+	result := s.dispatch(action, params)
+	if s.attempts > 0 {
+		result["attempts"] = s.attempts
+	}
+	return result
+}
+
+// dispatch routes an action to its handler
+func (s *SlackPlugin) dispatch(action string, params map[string]interface{}) map[string]interface{} {
 	switch action {
 	case "message":
 		return s.sendMessage(params)
+	case "update_message":
+		return s.updateMessage(params)
+	case "add_reaction":
+		return s.addReaction(params)
+	case "get_history":
+		return s.getHistory(params)
+	case "upload_file":
+		return s.uploadFile(params)
 	case "webhook":
 		return s.sendWebhook(params)
 	default:
@@ -168,58 +378,534 @@ func (s *SlackPlugin) sendMessage(params map[string]interface{}) map[string]inte
 		"username":   username,
 		"icon_emoji": iconEmoji,
 	}
+	if threadTS, ok := params["thread_ts"].(string); ok && threadTS != "" {
+		data["thread_ts"] = threadTS
+	}
+	for _, key := range []string{"blocks", "attachments"} {
+		items, err := objectArrayParam(params, key)
+		if err != nil {
+			return map[string]interface{}{
+				"error": err.Error(),
+			}
+		}
+		if items != nil {
+			data[key] = items
+		}
+	}
 
-	jsonData, err := json.Marshal(data)
+	result, err := s.callAPI("chat.postMessage", data)
 	if err != nil {
 		return map[string]interface{}{
-			"error": fmt.Sprintf("Failed to marshal request data: %v", err),
+			"error": err.Error(),
 		}
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", "https://slack.com/api/chat.postMessage", bytes.NewBuffer(jsonData))
+	// Extract success and timestamp
+	success, _ := result["ok"].(bool)
+	timestamp, _ := result["ts"].(string)
+	postedChannel, _ := result["channel"].(string)
+
+	output := map[string]interface{}{
+		"success":   success,
+		"timestamp": timestamp,
+		"ts":        timestamp,
+		"channel":   postedChannel,
+	}
+	if !success {
+		output["error"] = apiError(result)
+	}
+	return output
+}
+
+// objectArrayParam reads an array of objects given either as JSON array or as a JSON-encoded string.
+// Block Kit blocks must also carry a type.
+func objectArrayParam(params map[string]interface{}, key string) ([]interface{}, error) {
+	value, ok := params[key]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	if encoded, isString := value.(string); isString {
+		if err := json.Unmarshal([]byte(encoded), &value); err != nil {
+			return nil, fmt.Errorf("%s is not valid JSON: %v", key, err)
+		}
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array", key)
+	}
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be an object", key, i)
+		}
+		if _, hasType := obj["type"]; key == "blocks" && !hasType {
+			return nil, fmt.Errorf("blocks[%d] is missing a type", i)
+		}
+	}
+	return items, nil
+}
+
+// apiError formats a failed Web API response, including any detail messages Slack returned
+func apiError(result map[string]interface{}) string {
+	message := fmt.Sprintf("Slack API error: %v", result["error"])
+	if metadata, ok := result["response_metadata"].(map[string]interface{}); ok {
+		if details, ok := metadata["messages"].([]interface{}); ok && len(details) > 0 {
+			parts := make([]string, len(details))
+			for i, detail := range details {
+				parts[i] = fmt.Sprint(detail)
+			}
+			message += " (" + strings.Join(parts, "; ") + ")"
+		}
+	}
+	return message
+}
+
+// updateMessage edits an existing message using chat.update
+func (s *SlackPlugin) updateMessage(params map[string]interface{}) map[string]interface{} {
+	if s.token == "" {
+		return map[string]interface{}{
+			"error": "SLACK_BOT_TOKEN not configured",
+		}
+	}
+
+	channel, _ := params["channel"].(string)
+	ts, _ := params["ts"].(string)
+	text, _ := params["text"].(string)
+	if channel == "" || ts == "" {
+		return map[string]interface{}{
+			"error": "channel and ts are required",
+		}
+	}
+
+	result, err := s.callAPI("chat.update", map[string]interface{}{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+	})
 	if err != nil {
 		return map[string]interface{}{
-			"error": fmt.Sprintf("Failed to create request: %v", err),
+			"error": err.Error(),
+		}
+	}
+
+	if success, _ := result["ok"].(bool); !success {
+		// A deleted or mistyped message is an expected outcome for incident updates, so flag it explicitly
+		if result["error"] == "message_not_found" {
+			return map[string]interface{}{
+				"success":   false,
+				"not_found": true,
+				"error":     fmt.Sprintf("Message %s not found in channel %s", ts, channel),
+			}
+		}
+		return map[string]interface{}{
+			"success": false,
+			"error":   apiError(result),
+		}
+	}
+
+	updatedTS, _ := result["ts"].(string)
+	updatedChannel, _ := result["channel"].(string)
+	return map[string]interface{}{
+		"success":   true,
+		"ts":        updatedTS,
+		"channel":   updatedChannel,
+		"not_found": false,
+	}
+}
+
+// addReaction adds an emoji reaction to a message using reactions.add
+func (s *SlackPlugin) addReaction(params map[string]interface{}) map[string]interface{} {
+	if s.token == "" {
+		return map[string]interface{}{
+			"error": "SLACK_BOT_TOKEN not configured",
 		}
 	}
 
+	channel, _ := params["channel"].(string)
+	timestamp, _ := params["timestamp"].(string)
+	name, _ := params["name"].(string)
+	name = strings.Trim(name, ":")
+	if channel == "" || timestamp == "" || name == "" {
+		return map[string]interface{}{
+			"error": "channel, timestamp and name are required",
+		}
+	}
+
+	result, err := s.callAPI("reactions.add", map[string]interface{}{
+		"channel":   channel,
+		"timestamp": timestamp,
+		"name":      name,
+	})
+	if err != nil {
+		return map[string]interface{}{
+			"error": err.Error(),
+		}
+	}
+
+	if success, _ := result["ok"].(bool); !success {
+		return map[string]interface{}{
+			"success": false,
+			"error":   apiError(result),
+		}
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}
+}
+
+// historyPageSize is the largest page conversations.history reliably returns
+const historyPageSize = 100
+
+// getHistory reads channel messages using conversations.history, following
+// response_metadata.next_cursor until limit messages have been collected
+func (s *SlackPlugin) getHistory(params map[string]interface{}) map[string]interface{} {
+	if s.token == "" {
+		return map[string]interface{}{
+			"error": "SLACK_BOT_TOKEN not configured",
+		}
+	}
+
+	channel, _ := params["channel"].(string)
+	if channel == "" {
+		return map[string]interface{}{
+			"error": "channel is required",
+		}
+	}
+
+	limit := historyPageSize
+	if l, ok := params["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	messages := []map[string]interface{}{}
+	cursor := ""
+	hasMore := false
+	for len(messages) < limit {
+		form := url.Values{}
+		form.Set("channel", channel)
+		form.Set("limit", strconv.Itoa(min(limit-len(messages), historyPageSize)))
+		if oldest, ok := params["oldest"].(string); ok && oldest != "" {
+			form.Set("oldest", oldest)
+		}
+		if latest, ok := params["latest"].(string); ok && latest != "" {
+			form.Set("latest", latest)
+		}
+		if cursor != "" {
+			form.Set("cursor", cursor)
+		}
+
+		result, err := s.callAPIForm("conversations.history", form)
+		if err != nil {
+			return map[string]interface{}{
+				"error": err.Error(),
+			}
+		}
+		if success, _ := result["ok"].(bool); !success {
+			return map[string]interface{}{
+				"success": false,
+				"error":   apiError(result),
+			}
+		}
+
+		page, _ := result["messages"].([]interface{})
+		for _, item := range page {
+			msg, ok := item.(map[string]interface{})
+			if !ok || len(messages) >= limit {
+				continue
+			}
+			text, _ := msg["text"].(string)
+			ts, _ := msg["ts"].(string)
+			user, _ := msg["user"].(string)
+			messages = append(messages, map[string]interface{}{
+				"text": text,
+				"ts":   ts,
+				"user": user,
+			})
+		}
+
+		hasMore, _ = result["has_more"].(bool)
+		cursor = ""
+		if metadata, ok := result["response_metadata"].(map[string]interface{}); ok {
+			cursor, _ = metadata["next_cursor"].(string)
+		}
+		if cursor == "" || len(page) == 0 {
+			break
+		}
+	}
+
+	return map[string]interface{}{
+		"success":  true,
+		"messages": messages,
+		"count":    len(messages),
+		"has_more": hasMore || cursor != "",
+	}
+}
+
+// uploadFile uploads a file with the external upload flow: reserve an upload URL,
+// stream the file to it, then complete the upload to share it
+func (s *SlackPlugin) uploadFile(params map[string]interface{}) map[string]interface{} {
+	if s.token == "" {
+		return map[string]interface{}{
+			"error": "SLACK_BOT_TOKEN not configured",
+		}
+	}
+
+	filePath, _ := params["file_path"].(string)
+	if filePath == "" {
+		return map[string]interface{}{
+			"error": "file_path is required",
+		}
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return map[string]interface{}{
+			"error": fmt.Sprintf("Failed to access file: %v", err),
+		}
+	}
+	if info.IsDir() {
+		return map[string]interface{}{
+			"error": fmt.Sprintf("%s is a directory", filePath),
+		}
+	}
+	if info.Size() == 0 || info.Size() > maxUploadSize {
+		return map[string]interface{}{
+			"error": fmt.Sprintf("File size %d is outside Slack's limits (1 byte to 1 GB)", info.Size()),
+		}
+	}
+
+	filename, _ := params["filename"].(string)
+	if filename == "" {
+		filename = filepath.Base(filePath)
+	}
+	title, _ := params["title"].(string)
+	if title == "" {
+		title = filename
+	}
+
+	// Step 1: reserve an upload URL (this method only accepts form-encoded arguments)
+	reserved, err := s.callAPIForm("files.getUploadURLExternal", url.Values{
+		"filename": {filename},
+		"length":   {strconv.FormatInt(info.Size(), 10)},
+	})
+	if err != nil {
+		return map[string]interface{}{
+			"error": err.Error(),
+		}
+	}
+	if ok, _ := reserved["ok"].(bool); !ok {
+		return map[string]interface{}{
+			"success": false,
+			"error":   apiError(reserved),
+		}
+	}
+	uploadURL, _ := reserved["upload_url"].(string)
+	fileID, _ := reserved["file_id"].(string)
+
+	// Step 2: stream the file to the upload URL
+	if err := s.streamUpload(uploadURL, filePath, filename); err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"file_id": fileID,
+			"error":   err.Error(),
+		}
+	}
+
+	// Step 3: complete the upload, sharing it if channels were given
+	complete := map[string]interface{}{
+		"files": []map[string]string{{"id": fileID, "title": title}},
+	}
+	if channels := channelList(params["channels"]); channels != "" {
+		complete["channels"] = channels
+	}
+	if comment, ok := params["initial_comment"].(string); ok && comment != "" {
+		complete["initial_comment"] = comment
+	}
+	if threadTS, ok := params["thread_ts"].(string); ok && threadTS != "" {
+		complete["thread_ts"] = threadTS
+	}
+
+	completed, err := s.callAPI("files.completeUploadExternal", complete)
+	if err != nil {
+		return map[string]interface{}{
+			"error": err.Error(),
+		}
+	}
+	if ok, _ := completed["ok"].(bool); !ok {
+		return map[string]interface{}{
+			"success": false,
+			"file_id": fileID,
+			"error":   apiError(completed),
+		}
+	}
+
+	permalink := ""
+	if files, ok := completed["files"].([]interface{}); ok && len(files) > 0 {
+		if file, ok := files[0].(map[string]interface{}); ok {
+			permalink, _ = file["permalink"].(string)
+		}
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"file_id":   fileID,
+		"permalink": permalink,
+		"size":      info.Size(),
+	}
+}
+
+// streamUpload sends the file to a reserved upload URL as multipart/form-data without buffering it
+func (s *SlackPlugin) streamUpload(uploadURL, filePath, filename string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+	go func() {
+		part, err := writer.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest("POST", uploadURL, pipeReader)
+	if err != nil {
+		pipeReader.Close()
+		return fmt.Errorf("Failed to create upload request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	// Large files can take far longer than a regular API call
+	uploadClient := &http.Client{Timeout: 30 * time.Minute}
+	resp, err := uploadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to upload file: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("File upload failed (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// channelList joins a channels input given as a comma-separated string or an array
+func channelList(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		var channels []string
+		for _, item := range v {
+			if channel, ok := item.(string); ok && channel != "" {
+				channels = append(channels, channel)
+			}
+		}
+		return strings.Join(channels, ",")
+	}
+	return ""
+}
+
+// callAPIForm posts form-encoded arguments to a Slack Web API method and returns the decoded response
+func (s *SlackPlugin) callAPIForm(method string, form url.Values) (map[string]interface{}, error) {
+	req, err := http.NewRequest("POST", slackAPIBase+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request: %v", err)
+	}
+
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return s.doAPIRequest(req)
+}
+
+// callAPI posts a JSON payload to a Slack Web API method and returns the decoded response
+func (s *SlackPlugin) callAPI(method string, data map[string]interface{}) (map[string]interface{}, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal request data: %v", err)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequest("POST", slackAPIBase+method, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	return s.doAPIRequest(req)
+}
+
+// doAPIRequest sends a Web API request and decodes its JSON response, waiting
+// and retrying up to maxRetries times when Slack rate limits the call
+func (s *SlackPlugin) doAPIRequest(req *http.Request) (map[string]interface{}, error) {
+	for retries := 0; ; retries++ {
+		if retries > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to rewind request body: %v", err)
+			}
+			req.Body = body
+		}
 
+		s.attempts++
+		result, retryAfter, err := s.sendAPIRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		if retryAfter == 0 || retries >= s.maxRetries {
+			return result, nil
+		}
+		time.Sleep(retryAfter)
+	}
+}
+
+// sendAPIRequest performs a single Web API call. A non-zero duration is returned
+// when the call was rate limited, either by HTTP 429 or a "ratelimited" error body.
+func (s *SlackPlugin) sendAPIRequest(req *http.Request) (map[string]interface{}, time.Duration, error) {
 	// Send request
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return map[string]interface{}{
-			"error": fmt.Sprintf("Failed to send request: %v", err),
-		}
+		return nil, 0, fmt.Errorf("Failed to send request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return map[string]interface{}{
-			"error": fmt.Sprintf("Failed to read response: %v", err),
-		}
+		return nil, 0, fmt.Errorf("Failed to read response: %v", err)
 	}
 
 	// Parse response
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return map[string]interface{}{
-			"error": fmt.Sprintf("Failed to parse response: %v", err),
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return nil, 0, fmt.Errorf("Failed to parse response: %v", err)
 		}
+		result = map[string]interface{}{"ok": false, "error": "ratelimited"}
 	}
 
-	// Extract success and timestamp
-	success, _ := result["ok"].(bool)
-	timestamp, _ := result["ts"].(string)
-
-	return map[string]interface{}{
-		"success":   success,
-		"timestamp": timestamp,
+	if resp.StatusCode != http.StatusTooManyRequests && result["error"] != "ratelimited" {
+		return result, 0, nil
 	}
+	retryAfter := defaultRetryAfter
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return result, retryAfter, nil
 }
 
 // sendWebhook sends a message using Slack webhook
@@ -310,4 +996,4 @@ func main() {
 	}
 
 	json.NewEncoder(os.Stdout).Encode(result)
-}
\ No newline at end of file
+}
@@ -1,15 +1,23 @@
 package main
 
 import (
+	"bufio"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -35,10 +43,40 @@ type ActionSpec struct {
 	Outputs     map[string]IOSpec `json:"outputs"`
 }
 
-type SQLPlugin struct{}
+type SQLPlugin struct {
+	connections *connectionRegistry
+}
+
+// connectionRegistry caches *sql.DB handles by connection string so that actions run by the same
+// plugin process share a connection pool instead of connecting and pinging every time
+type connectionRegistry struct {
+	mu      sync.Mutex
+	entries map[string]*registryEntry
+}
+
+type registryEntry struct {
+	db          *sql.DB
+	inUse       int
+	lastUsed    time.Time
+	idleTimeout time.Duration
+}
+
+type poolSettings struct {
+	maxOpenConns int
+	maxIdleConns int
+	idleTimeout  time.Duration
+	maxLifetime  time.Duration
+}
 
 func NewSQLPlugin() *SQLPlugin {
-	return &SQLPlugin{}
+	return &SQLPlugin{
+		connections: &connectionRegistry{entries: make(map[string]*registryEntry)},
+	}
+}
+
+// Close closes all cached database handles
+func (p *SQLPlugin) Close() {
+	p.connections.closeAll()
 }
 
 func (p *SQLPlugin) GetMetadata() Metadata {
@@ -55,7 +93,7 @@ func (p *SQLPlugin) GetActions() map[string]ActionSpec {
 	return map[string]ActionSpec{
 		"query": {
 			Description: "Execute SELECT query and return results",
-			Inputs: map[string]IOSpec{
+			Inputs: withPoolInputs(map[string]IOSpec{
 				"connection_string": {
 					Type:        "string",
 					Required:    true,
@@ -71,7 +109,12 @@ func (p *SQLPlugin) GetActions() map[string]ActionSpec {
 					Required:    false,
 					Description: "Query parameters for prepared statements",
 				},
-			},
+				"named_params": {
+					Type:        "object",
+					Required:    false,
+					Description: "Values for :name placeholders, used instead of params",
+				},
+			}),
 			Outputs: map[string]IOSpec{
 				"rows":      {Type: "array", Description: "Query result rows as array of objects"},
 				"columns":   {Type: "array", Description: "Column names"},
@@ -80,7 +123,7 @@ func (p *SQLPlugin) GetActions() map[string]ActionSpec {
 		},
 		"execute": {
 			Description: "Execute INSERT/UPDATE/DELETE statement",
-			Inputs: map[string]IOSpec{
+			Inputs: withPoolInputs(map[string]IOSpec{
 				"connection_string": {
 					Type:        "string",
 					Required:    true,
@@ -96,7 +139,12 @@ func (p *SQLPlugin) GetActions() map[string]ActionSpec {
 					Required:    false,
 					Description: "Statement parameters for prepared statements",
 				},
-			},
+				"named_params": {
+					Type:        "object",
+					Required:    false,
+					Description: "Values for :name placeholders, used instead of params",
+				},
+			}),
 			Outputs: map[string]IOSpec{
 				"affected_rows": {Type: "number", Description: "Number of rows affected"},
 				"last_insert_id": {Type: "number", Description: "Last inserted ID (if applicable)"},
@@ -105,7 +153,7 @@ func (p *SQLPlugin) GetActions() map[string]ActionSpec {
 		},
 		"schema": {
 			Description: "Get database schema information",
-			Inputs: map[string]IOSpec{
+			Inputs: withPoolInputs(map[string]IOSpec{
 				"connection_string": {
 					Type:        "string",
 					Required:    true,
@@ -116,13 +164,175 @@ func (p *SQLPlugin) GetActions() map[string]ActionSpec {
 					Required:    false,
 					Description: "Specific table name to get schema for",
 				},
-			},
+			}),
 			Outputs: map[string]IOSpec{
 				"tables":  {Type: "array", Description: "List of table names"},
 				"columns": {Type: "object", Description: "Column information by table name"},
 			},
 		},
+		"batch_insert": {
+			Description: "Insert many rows using multi-row parameterized INSERT statements",
+			Inputs: withPoolInputs(map[string]IOSpec{
+				"connection_string": {
+					Type:        "string",
+					Required:    true,
+					Description: "Database connection string",
+				},
+				"table": {
+					Type:        "string",
+					Required:    true,
+					Description: "Target table name, optionally schema-qualified (schema.table)",
+				},
+				"rows": {
+					Type:        "array",
+					Required:    true,
+					Description: "Rows to insert as array of objects keyed by column name",
+				},
+				"on_conflict": {
+					Type:        "string",
+					Required:    false,
+					Description: "Conflict handling: ignore, replace, or update",
+				},
+				"update_columns": {
+					Type:        "array",
+					Required:    false,
+					Description: "Columns to overwrite when on_conflict is update (defaults to all inserted columns)",
+				},
+				"conflict_columns": {
+					Type:        "array",
+					Required:    false,
+					Description: "Unique/primary key columns for the conflict target (required for PostgreSQL replace/update)",
+				},
+				"batch_size": {
+					Type:        "number",
+					Required:    false,
+					Default:     500,
+					Description: "Maximum rows per INSERT statement",
+				},
+			}),
+			Outputs: map[string]IOSpec{
+				"affected_rows": {Type: "number", Description: "Total number of rows affected"},
+				"batches":       {Type: "number", Description: "Number of INSERT statements executed"},
+				"success":       {Type: "boolean", Description: "Operation success status"},
+			},
+		},
+		"export": {
+			Description: "Stream SELECT query results to a CSV or JSON Lines file",
+			Inputs: withPoolInputs(map[string]IOSpec{
+				"connection_string": {
+					Type:        "string",
+					Required:    true,
+					Description: "Database connection string",
+				},
+				"query": {
+					Type:        "string",
+					Required:    true,
+					Description: "SQL SELECT query to execute",
+				},
+				"params": {
+					Type:        "array",
+					Required:    false,
+					Description: "Query parameters for prepared statements",
+				},
+				"named_params": {
+					Type:        "object",
+					Required:    false,
+					Description: "Values for :name placeholders, used instead of params",
+				},
+				"output_file": {
+					Type:        "string",
+					Required:    true,
+					Description: "File to write, replaced if it exists",
+				},
+				"output_format": {
+					Type:        "string",
+					Required:    false,
+					Default:     "csv",
+					Description: "csv or jsonl (one JSON object per row)",
+				},
+				"delimiter": {
+					Type:        "string",
+					Required:    false,
+					Default:     ",",
+					Description: "CSV field delimiter, a single character",
+				},
+				"header": {
+					Type:        "boolean",
+					Required:    false,
+					Default:     true,
+					Description: "Write a CSV header row with the column names",
+				},
+			}),
+			Outputs: map[string]IOSpec{
+				"file_path": {Type: "string", Description: "Absolute path of the written file"},
+				"row_count": {Type: "number", Description: "Number of rows written"},
+				"columns":   {Type: "array", Description: "Column names"},
+			},
+		},
+		"migrate": {
+			Description: "Apply or roll back ordered schema migrations, tracked in a schema_migrations table",
+			Inputs: withPoolInputs(map[string]IOSpec{
+				"connection_string": {
+					Type:        "string",
+					Required:    true,
+					Description: "Database connection string",
+				},
+				"migrations_dir": {
+					Type:        "string",
+					Required:    false,
+					Description: "Directory of <version>_<name>.up.sql and .down.sql files (plain .sql files are up-only)",
+				},
+				"migrations": {
+					Type:        "array",
+					Required:    false,
+					Description: "Inline migrations as objects with version, up and optional down SQL (instead of migrations_dir)",
+				},
+				"direction": {
+					Type:        "string",
+					Required:    false,
+					Default:     "up",
+					Description: "up to apply pending migrations, down to roll back applied ones",
+				},
+				"steps": {
+					Type:        "number",
+					Required:    false,
+					Description: "Maximum migrations to apply or roll back (default: all pending for up, 1 for down)",
+				},
+			}),
+			Outputs: map[string]IOSpec{
+				"applied":         {Type: "array", Description: "Versions applied, or rolled back for down, in this run"},
+				"current_version": {Type: "string", Description: "Highest applied version afterwards (empty when none)"},
+				"success":         {Type: "boolean", Description: "Operation success status"},
+			},
+		},
+	}
+}
+
+// withPoolInputs adds the connection pool settings shared by every action
+func withPoolInputs(inputs map[string]IOSpec) map[string]IOSpec {
+	inputs["max_open_conns"] = IOSpec{
+		Type:        "number",
+		Required:    false,
+		Description: "Maximum open connections to the database (unlimited by default)",
+	}
+	inputs["max_idle_conns"] = IOSpec{
+		Type:        "number",
+		Required:    false,
+		Description: "Maximum idle connections kept in the pool (default 2)",
+	}
+	inputs["idle_timeout"] = IOSpec{
+		Type:        "number",
+		Required:    false,
+		Default:     300,
+		Description: "Seconds an unused connection, or cached database handle, is kept before closing",
+	}
+	inputs["max_lifetime"] = IOSpec{
+		Type:        "number",
+		Required:    false,
+		Default:     1800,
+		Description: "Maximum seconds a connection is reused before being replaced",
 	}
+	return inputs
 }
 
 func (p *SQLPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
@@ -133,11 +343,147 @@ func (p *SQLPlugin) Execute(action string, params map[string]interface{}) (map[s
 		return p.executeStatement(params)
 	case "schema":
 		return p.getSchema(params)
+	case "batch_insert":
+		return p.batchInsert(params)
+	case "export":
+		return p.exportQuery(params)
+	case "migrate":
+		return p.migrate(params)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", action)
 	}
 }
 
+// statementArgs returns the statement and its arguments, rewriting :name placeholders when named_params is given
+func (p *SQLPlugin) statementArgs(driverName, statement string, params map[string]interface{}) (string, []interface{}, error) {
+	positional, _ := params["params"].([]interface{})
+	named, ok := params["named_params"].(map[string]interface{})
+	if !ok {
+		return statement, positional, nil
+	}
+	if len(positional) > 0 {
+		return "", nil, fmt.Errorf("use either params or named_params, not both")
+	}
+	return bindNamedParams(driverName, statement, named)
+}
+
+// bindNamedParams replaces :name placeholders with the driver's positional markers and returns the matching
+// arguments. PostgreSQL reuses $N for a repeated name; ? drivers get the value once per occurrence. Quoted
+// strings and identifiers (including MySQL backslash escapes), comments, PostgreSQL :: casts and dollar-quoted
+// bodies are left untouched.
+func bindNamedParams(driverName, statement string, named map[string]interface{}) (string, []interface{}, error) {
+	var out strings.Builder
+	var args []interface{}
+	positions := make(map[string]int)
+
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(statement) {
+				// MySQL also escapes characters in string literals with a backslash
+				if driverName == "mysql" && c != '`' && statement[end] == '\\' {
+					end += 2
+					continue
+				}
+				if statement[end] == c {
+					// A doubled quote is an escaped quote inside the literal
+					if end+1 < len(statement) && statement[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end+1, len(statement))
+			out.WriteString(statement[i:end])
+			i = end
+
+		case c == '-' && strings.HasPrefix(statement[i:], "--"):
+			end := strings.IndexByte(statement[i:], '\n')
+			if end < 0 {
+				end = len(statement) - i
+			}
+			out.WriteString(statement[i : i+end])
+			i += end
+
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			end := strings.Index(statement[i+2:], "*/")
+			if end < 0 {
+				end = len(statement) - i
+			} else {
+				end += 4
+			}
+			out.WriteString(statement[i : i+end])
+			i += end
+
+		case c == '$' && driverName == "postgres" && dollarTag(statement[i:]) != "":
+			tag := dollarTag(statement[i:])
+			end := strings.Index(statement[i+len(tag):], tag)
+			if end < 0 {
+				end = len(statement) - i
+			} else {
+				end += 2 * len(tag)
+			}
+			out.WriteString(statement[i : i+end])
+			i += end
+
+		case c == ':' && i+1 < len(statement) && statement[i+1] == ':':
+			out.WriteString("::")
+			i += 2
+
+		case c == ':' && i+1 < len(statement) && isNameStart(statement[i+1]):
+			end := i + 2
+			for end < len(statement) && (isNameStart(statement[end]) || (statement[end] >= '0' && statement[end] <= '9')) {
+				end++
+			}
+			name := statement[i+1 : end]
+			value, ok := named[name]
+			if !ok {
+				return "", nil, fmt.Errorf("named parameter :%s has no value in named_params", name)
+			}
+			if driverName == "postgres" {
+				n, seen := positions[name]
+				if !seen {
+					args = append(args, value)
+					n = len(args)
+					positions[name] = n
+				}
+				fmt.Fprintf(&out, "$%d", n)
+			} else {
+				args = append(args, value)
+				out.WriteByte('?')
+			}
+			i = end
+
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+
+	return out.String(), args, nil
+}
+
+// dollarTag returns the opening $tag$ of a PostgreSQL dollar-quoted string at the start of s, or ""
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1]
+		}
+		if !isNameStart(s[i]) && (i == 1 || s[i] < '0' || s[i] > '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
 func (p *SQLPlugin) parseConnectionString(connStr string) (string, string, error) {
 	u, err := url.Parse(connStr)
 	if err != nil {
@@ -203,23 +549,15 @@ func (p *SQLPlugin) executeQuery(params map[string]interface{}) (map[string]inte
 		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
-	db, err := sql.Open(driverName, dataSource)
+	db, release, err := p.connections.get(connStr, driverName, dataSource, poolSettingsFromParams(params))
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to connect: %v", err)}, nil
-	}
-	defer db.Close()
-
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to ping database: %v", err)}, nil
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
+	defer release()
 
-	// Get parameters
-	var queryParams []interface{}
-	if paramsVal, ok := params["params"]; ok {
-		if paramsList, ok := paramsVal.([]interface{}); ok {
-			queryParams = paramsList
-		}
+	query, queryParams, err := p.statementArgs(driverName, query, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
 	rows, err := db.Query(query, queryParams...)
@@ -278,6 +616,191 @@ func (p *SQLPlugin) executeQuery(params map[string]interface{}) (map[string]inte
 	}, nil
 }
 
+// exportQuery writes each row to the output file as it is scanned, so the result set is never held in memory
+func (p *SQLPlugin) exportQuery(params map[string]interface{}) (map[string]interface{}, error) {
+	connStr, ok := params["connection_string"].(string)
+	if !ok || connStr == "" {
+		return map[string]interface{}{"error": "connection_string is required"}, nil
+	}
+
+	query, ok := params["query"].(string)
+	if !ok || query == "" {
+		return map[string]interface{}{"error": "query is required"}, nil
+	}
+
+	outputFile, ok := params["output_file"].(string)
+	if !ok || outputFile == "" {
+		return map[string]interface{}{"error": "output_file is required"}, nil
+	}
+
+	format, _ := params["output_format"].(string)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported output_format: %s (use csv or jsonl)", format)}, nil
+	}
+
+	delimiter := ','
+	if val, ok := params["delimiter"].(string); ok && val != "" {
+		r, size := utf8.DecodeRuneInString(val)
+		if size != len(val) || r == '"' || r == '\r' || r == '\n' {
+			return map[string]interface{}{"error": "delimiter must be a single character other than a quote or newline"}, nil
+		}
+		delimiter = r
+	}
+
+	header := true
+	if val, ok := params["header"].(bool); ok {
+		header = val
+	}
+
+	driverName, dataSource, err := p.parseConnectionString(connStr)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	db, release, err := p.connections.get(connStr, driverName, dataSource, poolSettingsFromParams(params))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer release()
+
+	query, queryParams, err := p.statementArgs(driverName, query, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	rows, err := db.Query(query, queryParams...)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("query failed: %v", err)}, nil
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to get columns: %v", err)}, nil
+	}
+
+	path, err := filepath.Abs(outputFile)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("invalid output_file: %v", err)}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create output directory: %v", err)}, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create output file: %v", err)}, nil
+	}
+
+	rowCount, err := writeRows(rows, columns, file, format, delimiter, header)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return map[string]interface{}{"error": fmt.Sprintf("export failed: %v", err)}, nil
+	}
+
+	return map[string]interface{}{
+		"file_path": path,
+		"row_count": rowCount,
+		"columns":   columns,
+	}, nil
+}
+
+func writeRows(rows *sql.Rows, columns []string, w io.Writer, format string, delimiter rune, header bool) (int, error) {
+	buf := bufio.NewWriter(w)
+
+	var csvWriter *csv.Writer
+	record := make([]string, len(columns))
+	keys := make([][]byte, len(columns))
+	if format == "csv" {
+		csvWriter = csv.NewWriter(buf)
+		csvWriter.Comma = delimiter
+		if header {
+			if err := csvWriter.Write(columns); err != nil {
+				return 0, err
+			}
+		}
+	} else {
+		// Keys are encoded once; rows are assembled by hand to keep the column order
+		for i, col := range columns {
+			keys[i], _ = json.Marshal(col)
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	rowCount := 0
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return rowCount, fmt.Errorf("scan failed: %v", err)
+		}
+
+		if csvWriter != nil {
+			for i, val := range values {
+				record[i] = csvValue(val)
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return rowCount, err
+			}
+		} else {
+			buf.WriteByte('{')
+			for i, val := range values {
+				if b, ok := val.([]byte); ok {
+					val = string(b)
+				}
+				encoded, err := json.Marshal(val)
+				if err != nil {
+					return rowCount, fmt.Errorf("failed to encode column %s: %v", columns[i], err)
+				}
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				buf.Write(keys[i])
+				buf.WriteByte(':')
+				buf.Write(encoded)
+			}
+			buf.WriteString("}\n")
+		}
+		rowCount++
+	}
+	if err := rows.Err(); err != nil {
+		return rowCount, fmt.Errorf("rows error: %v", err)
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return rowCount, err
+		}
+	}
+	return rowCount, buf.Flush()
+}
+
+func csvValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func (p *SQLPlugin) executeStatement(params map[string]interface{}) (map[string]interface{}, error) {
 	connStr, ok := params["connection_string"].(string)
 	if !ok || connStr == "" {
@@ -294,23 +817,15 @@ func (p *SQLPlugin) executeStatement(params map[string]interface{}) (map[string]
 		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
-	db, err := sql.Open(driverName, dataSource)
+	db, release, err := p.connections.get(connStr, driverName, dataSource, poolSettingsFromParams(params))
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to connect: %v", err)}, nil
-	}
-	defer db.Close()
-
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to ping database: %v", err)}, nil
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
+	defer release()
 
-	// Get parameters
-	var stmtParams []interface{}
-	if paramsVal, ok := params["params"]; ok {
-		if paramsList, ok := paramsVal.([]interface{}); ok {
-			stmtParams = paramsList
-		}
+	statement, stmtParams, err := p.statementArgs(driverName, statement, params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
 	result, err := db.Exec(statement, stmtParams...)
@@ -339,16 +854,11 @@ func (p *SQLPlugin) getSchema(params map[string]interface{}) (map[string]interfa
 		return map[string]interface{}{"error": err.Error()}, nil
 	}
 
-	db, err := sql.Open(driverName, dataSource)
+	db, release, err := p.connections.get(connStr, driverName, dataSource, poolSettingsFromParams(params))
 	if err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to connect: %v", err)}, nil
-	}
-	defer db.Close()
-
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return map[string]interface{}{"error": fmt.Sprintf("failed to ping database: %v", err)}, nil
+		return map[string]interface{}{"error": err.Error()}, nil
 	}
+	defer release()
 
 	tableName, _ := params["table_name"].(string)
 
@@ -364,6 +874,93 @@ func (p *SQLPlugin) getSchema(params map[string]interface{}) (map[string]interfa
 	}
 }
 
+func poolSettingsFromParams(params map[string]interface{}) poolSettings {
+	settings := poolSettings{
+		idleTimeout: 300 * time.Second,
+		maxLifetime: 1800 * time.Second,
+	}
+	if val, ok := params["max_open_conns"].(float64); ok && val > 0 {
+		settings.maxOpenConns = int(val)
+	}
+	if val, ok := params["max_idle_conns"].(float64); ok && val > 0 {
+		settings.maxIdleConns = int(val)
+	}
+	if val, ok := params["idle_timeout"].(float64); ok && val >= 0 {
+		settings.idleTimeout = time.Duration(val * float64(time.Second))
+	}
+	if val, ok := params["max_lifetime"].(float64); ok && val >= 0 {
+		settings.maxLifetime = time.Duration(val * float64(time.Second))
+	}
+	return settings
+}
+
+// get returns the cached handle for connStr, opening and pinging a new one on first use, together
+// with a release function the caller must call once it has finished with the handle. Handles that
+// no caller holds and that have been unused for longer than their idle timeout are closed first.
+func (r *connectionRegistry) get(connStr, driverName, dataSource string, settings poolSettings) (*sql.DB, func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range r.entries {
+		if entry.inUse == 0 && entry.idleTimeout > 0 && now.Sub(entry.lastUsed) > entry.idleTimeout {
+			entry.db.Close()
+			delete(r.entries, key)
+		}
+	}
+
+	entry, ok := r.entries[connStr]
+	if !ok {
+		db, err := sql.Open(driverName, dataSource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect: %v", err)
+		}
+
+		// Test connection
+		if err := db.Ping(); err != nil {
+			db.Close()
+			return nil, nil, fmt.Errorf("failed to ping database: %v", err)
+		}
+
+		entry = &registryEntry{db: db}
+		r.entries[connStr] = entry
+	}
+
+	// Zero leaves the database/sql defaults (or an earlier action's setting) in place
+	if settings.maxOpenConns > 0 {
+		entry.db.SetMaxOpenConns(settings.maxOpenConns)
+	}
+	if settings.maxIdleConns > 0 {
+		entry.db.SetMaxIdleConns(settings.maxIdleConns)
+	}
+	entry.db.SetConnMaxIdleTime(settings.idleTimeout)
+	entry.db.SetConnMaxLifetime(settings.maxLifetime)
+	entry.idleTimeout = settings.idleTimeout
+	entry.lastUsed = now
+	entry.inUse++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			entry.inUse--
+			entry.lastUsed = time.Now()
+		})
+	}
+	return entry.db, release, nil
+}
+
+func (r *connectionRegistry) closeAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, entry := range r.entries {
+		entry.db.Close()
+		delete(r.entries, key)
+	}
+}
+
 func (p *SQLPlugin) getSQLiteSchema(db *sql.DB, tableName string) (map[string]interface{}, error) {
 	if tableName != "" {
 		// Get specific table schema
@@ -566,6 +1163,569 @@ func (p *SQLPlugin) getMySQLSchema(db *sql.DB, tableName string) (map[string]int
 	}
 }
 
+func (p *SQLPlugin) batchInsert(params map[string]interface{}) (map[string]interface{}, error) {
+	connStr, ok := params["connection_string"].(string)
+	if !ok || connStr == "" {
+		return map[string]interface{}{"error": "connection_string is required"}, nil
+	}
+
+	table, ok := params["table"].(string)
+	if !ok || table == "" {
+		return map[string]interface{}{"error": "table is required"}, nil
+	}
+
+	rawRows, ok := params["rows"].([]interface{})
+	if !ok || len(rawRows) == 0 {
+		return map[string]interface{}{"error": "rows must be a non-empty array of objects"}, nil
+	}
+
+	onConflict, _ := params["on_conflict"].(string)
+	switch onConflict {
+	case "", "ignore", "replace", "update":
+	default:
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported on_conflict value: %s (use ignore, replace, or update)", onConflict)}, nil
+	}
+
+	batchSize := 500
+	if val, ok := params["batch_size"].(float64); ok && val > 0 {
+		batchSize = int(val)
+	}
+
+	// Collect the union of column names so rows with missing keys insert NULL
+	rows := make([]map[string]interface{}, 0, len(rawRows))
+	columnSet := make(map[string]bool)
+	for i, raw := range rawRows {
+		row, ok := raw.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{"error": fmt.Sprintf("rows[%d] must be an object", i)}, nil
+		}
+		for col := range row {
+			columnSet[col] = true
+		}
+		rows = append(rows, row)
+	}
+	columns := make([]string, 0, len(columnSet))
+	for col := range columnSet {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	if len(columns) == 0 {
+		return map[string]interface{}{"error": "rows must contain at least one column"}, nil
+	}
+
+	updateColumns := toStringSlice(params["update_columns"])
+	if len(updateColumns) == 0 {
+		updateColumns = columns
+	}
+	conflictColumns := toStringSlice(params["conflict_columns"])
+
+	driverName, dataSource, err := p.parseConnectionString(connStr)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	if driverName == "postgres" && (onConflict == "replace" || onConflict == "update") && len(conflictColumns) == 0 {
+		return map[string]interface{}{"error": "conflict_columns is required for PostgreSQL replace/update"}, nil
+	}
+
+	db, release, err := p.connections.get(connStr, driverName, dataSource, poolSettingsFromParams(params))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer release()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to begin transaction: %v", err)}, nil
+	}
+
+	var affectedRows int64
+	batches := 0
+	stmts := make(map[int]*sql.Stmt)
+	defer func() {
+		for _, stmt := range stmts {
+			stmt.Close()
+		}
+	}()
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[start:end]
+
+		// Only the final chunk can differ in size, so at most two statements are prepared
+		stmt, ok := stmts[len(chunk)]
+		if !ok {
+			query := p.buildBatchInsert(driverName, table, columns, len(chunk), onConflict, updateColumns, conflictColumns)
+			stmt, err = tx.Prepare(query)
+			if err != nil {
+				tx.Rollback()
+				return map[string]interface{}{"error": fmt.Sprintf("failed to prepare statement: %v", err)}, nil
+			}
+			stmts[len(chunk)] = stmt
+		}
+
+		args := make([]interface{}, 0, len(chunk)*len(columns))
+		for _, row := range chunk {
+			for _, col := range columns {
+				args = append(args, row[col])
+			}
+		}
+
+		result, err := stmt.Exec(args...)
+		if err != nil {
+			tx.Rollback()
+			return map[string]interface{}{"error": fmt.Sprintf("batch %d failed: %v", batches+1, err)}, nil
+		}
+		affected, _ := result.RowsAffected()
+		affectedRows += affected
+		batches++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to commit: %v", err)}, nil
+	}
+
+	return map[string]interface{}{
+		"affected_rows": affectedRows,
+		"batches":       batches,
+		"success":       true,
+	}, nil
+}
+
+// buildBatchInsert renders a multi-row INSERT with driver-specific placeholders and conflict clause.
+func (p *SQLPlugin) buildBatchInsert(driverName, table string, columns []string, rowCount int, onConflict string, updateColumns, conflictColumns []string) string {
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = p.quoteIdentifier(driverName, col)
+	}
+
+	tuples := make([]string, rowCount)
+	placeholders := make([]string, len(columns))
+	n := 1
+	for r := 0; r < rowCount; r++ {
+		for c := range columns {
+			if driverName == "postgres" {
+				placeholders[c] = fmt.Sprintf("$%d", n)
+			} else {
+				placeholders[c] = "?"
+			}
+			n++
+		}
+		tuples[r] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	verb := "INSERT INTO"
+	switch {
+	case driverName == "sqlite3" && onConflict == "ignore":
+		verb = "INSERT OR IGNORE INTO"
+	case driverName == "sqlite3" && onConflict == "replace":
+		verb = "INSERT OR REPLACE INTO"
+	case driverName == "mysql" && onConflict == "ignore":
+		verb = "INSERT IGNORE INTO"
+	case driverName == "mysql" && onConflict == "replace":
+		verb = "REPLACE INTO"
+	}
+
+	query := fmt.Sprintf("%s %s (%s) VALUES %s", verb, p.quoteTableName(driverName, table),
+		strings.Join(quotedColumns, ", "), strings.Join(tuples, ", "))
+
+	// PostgreSQL has no REPLACE, so replace behaves like update across every inserted column
+	if driverName == "postgres" && onConflict == "replace" {
+		onConflict = "update"
+		updateColumns = columns
+	}
+
+	switch onConflict {
+	case "ignore":
+		if driverName == "postgres" {
+			query += " ON CONFLICT DO NOTHING"
+		}
+	case "update":
+		assignments := make([]string, len(updateColumns))
+		for i, col := range updateColumns {
+			quoted := p.quoteIdentifier(driverName, col)
+			if driverName == "mysql" {
+				assignments[i] = fmt.Sprintf("%s = VALUES(%s)", quoted, quoted)
+			} else {
+				assignments[i] = fmt.Sprintf("%s = excluded.%s", quoted, quoted)
+			}
+		}
+		if driverName == "mysql" {
+			query += " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+		} else {
+			target := ""
+			if len(conflictColumns) > 0 {
+				quotedTarget := make([]string, len(conflictColumns))
+				for i, col := range conflictColumns {
+					quotedTarget[i] = p.quoteIdentifier(driverName, col)
+				}
+				target = "(" + strings.Join(quotedTarget, ", ") + ") "
+			}
+			query += " ON CONFLICT " + target + "DO UPDATE SET " + strings.Join(assignments, ", ")
+		}
+	}
+
+	return query
+}
+
+// migration is one schema version with the SQL to apply and, optionally, revert it
+type migration struct {
+	version string
+	up      string
+	down    string
+}
+
+func (p *SQLPlugin) migrate(params map[string]interface{}) (map[string]interface{}, error) {
+	connStr, ok := params["connection_string"].(string)
+	if !ok || connStr == "" {
+		return map[string]interface{}{"error": "connection_string is required"}, nil
+	}
+
+	direction, _ := params["direction"].(string)
+	if direction == "" {
+		direction = "up"
+	}
+	if direction != "up" && direction != "down" {
+		return map[string]interface{}{"error": fmt.Sprintf("unsupported direction: %s (use up or down)", direction)}, nil
+	}
+
+	var migrations []migration
+	var err error
+	if dir, ok := params["migrations_dir"].(string); ok && dir != "" {
+		migrations, err = loadMigrationsDir(dir)
+	} else if list, ok := params["migrations"].([]interface{}); ok && len(list) > 0 {
+		migrations, err = parseInlineMigrations(list)
+	} else {
+		return map[string]interface{}{"error": "migrations_dir or migrations is required"}, nil
+	}
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	steps := 0
+	if val, ok := params["steps"].(float64); ok && val > 0 {
+		steps = int(val)
+	} else if direction == "down" {
+		steps = 1
+	}
+
+	driverName, dataSource, err := p.parseConnectionString(connStr)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	// The MySQL driver rejects several statements in one Exec unless the DSN enables them,
+	// so catch that before anything is applied rather than failing part way through
+	if driverName == "mysql" {
+		if cfg, err := mysql.ParseDSN(dataSource); err == nil && !cfg.MultiStatements {
+			for _, m := range migrations {
+				script := m.up
+				if direction == "down" {
+					script = m.down
+				}
+				if mysqlStatementCount(script) > 1 {
+					return map[string]interface{}{"error": fmt.Sprintf("migration %s contains more than one statement; add multiStatements=true to the MySQL connection string", m.version)}, nil
+				}
+			}
+		}
+	}
+
+	db, release, err := p.connections.get(connStr, driverName, dataSource, poolSettingsFromParams(params))
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	defer release()
+
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(255) NOT NULL PRIMARY KEY, applied_at TIMESTAMP NOT NULL)"); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to create schema_migrations table: %v", err)}, nil
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	insertVersion := "INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)"
+	deleteVersion := "DELETE FROM schema_migrations WHERE version = ?"
+	if driverName == "postgres" {
+		insertVersion = "INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)"
+		deleteVersion = "DELETE FROM schema_migrations WHERE version = $1"
+	}
+
+	done := []string{}
+	if direction == "up" {
+		for _, m := range migrations {
+			if applied[m.version] {
+				continue
+			}
+			if steps > 0 && len(done) == steps {
+				break
+			}
+			if err := runMigration(db, m.up, insertVersion, m.version, time.Now().UTC()); err != nil {
+				return map[string]interface{}{"error": fmt.Sprintf("migration %s failed: %v", m.version, err), "applied": done}, nil
+			}
+			applied[m.version] = true
+			done = append(done, m.version)
+		}
+	} else {
+		byVersion := make(map[string]migration, len(migrations))
+		for _, m := range migrations {
+			byVersion[m.version] = m
+		}
+		for _, version := range sortedVersions(applied, true) {
+			if len(done) == steps {
+				break
+			}
+			m, ok := byVersion[version]
+			if !ok {
+				return map[string]interface{}{"error": fmt.Sprintf("applied migration %s is not among the provided migrations", version), "applied": done}, nil
+			}
+			if m.down == "" {
+				return map[string]interface{}{"error": fmt.Sprintf("migration %s has no down script", version), "applied": done}, nil
+			}
+			if err := runMigration(db, m.down, deleteVersion, m.version); err != nil {
+				return map[string]interface{}{"error": fmt.Sprintf("rolling back migration %s failed: %v", m.version, err), "applied": done}, nil
+			}
+			delete(applied, version)
+			done = append(done, version)
+		}
+	}
+
+	currentVersion := ""
+	if versions := sortedVersions(applied, true); len(versions) > 0 {
+		currentVersion = versions[0]
+	}
+
+	return map[string]interface{}{
+		"applied":         done,
+		"current_version": currentVersion,
+		"success":         true,
+	}, nil
+}
+
+// runMigration executes script and the schema_migrations bookkeeping statement in one transaction.
+// MySQL commits DDL implicitly, so there a failing script can leave earlier statements applied.
+func runMigration(db *sql.DB, script, bookkeeping string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.Exec(script); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec(bookkeeping, args...); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update schema_migrations: %v", err)
+	}
+	return tx.Commit()
+}
+
+// mysqlStatementCount counts the ;-separated statements in script, ignoring semicolons
+// inside quoted strings, quoted identifiers and comments
+func mysqlStatementCount(script string) int {
+	count := 0
+	pending := false
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			pending = true
+			for i++; i < len(script) && script[i] != c; i++ {
+				if script[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+
+		case c == '#' || strings.HasPrefix(script[i:], "--"):
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+
+		case strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+
+		case c == ';':
+			if pending {
+				count++
+			}
+			pending = false
+
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			pending = true
+		}
+	}
+	if pending {
+		count++
+	}
+	return count
+}
+
+func appliedVersions(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func sortedVersions(versions map[string]bool, descending bool) []string {
+	sorted := make([]string, 0, len(versions))
+	for version := range versions {
+		sorted = append(sorted, version)
+	}
+	sort.Strings(sorted)
+	if descending {
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+	return sorted
+}
+
+// loadMigrationsDir reads <version>_<name>.up.sql / .down.sql pairs; a plain .sql file is an up-only
+// migration. The version is the part of the file name before the first underscore.
+func loadMigrationsDir(dir string) ([]migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations_dir: %v", err)
+	}
+
+	byVersion := make(map[string]*migration)
+	files := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		base, kind := strings.TrimSuffix(name, ".sql"), "up"
+		if strings.HasSuffix(base, ".down") {
+			base, kind = strings.TrimSuffix(base, ".down"), "down"
+		} else {
+			base = strings.TrimSuffix(base, ".up")
+		}
+		version, _, _ := strings.Cut(base, "_")
+
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", name, err)
+		}
+
+		key := version + "." + kind
+		if other, ok := files[key]; ok {
+			return nil, fmt.Errorf("duplicate migration version %s: %s and %s", version, other, name)
+		}
+		files[key] = name
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version}
+			byVersion[version] = m
+		}
+		if kind == "down" {
+			m.down = string(content)
+		} else {
+			m.up = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for version, m := range byVersion {
+		if _, ok := files[version+".up"]; !ok {
+			return nil, fmt.Errorf("migration %s has a down script but no up script", version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+func parseInlineMigrations(list []interface{}) ([]migration, error) {
+	seen := make(map[string]bool)
+	migrations := make([]migration, 0, len(list))
+	for i, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("migrations[%d] must be an object", i)
+		}
+		var m migration
+		switch v := obj["version"].(type) {
+		case string:
+			m.version = v
+		case float64:
+			m.version = fmt.Sprintf("%v", v)
+		}
+		if m.version == "" {
+			return nil, fmt.Errorf("migrations[%d] requires a version", i)
+		}
+		if seen[m.version] {
+			return nil, fmt.Errorf("duplicate migration version %s", m.version)
+		}
+		seen[m.version] = true
+		m.up, _ = obj["up"].(string)
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %s requires up SQL", m.version)
+		}
+		m.down, _ = obj["down"].(string)
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+func (p *SQLPlugin) quoteIdentifier(driverName, name string) string {
+	if driverName == "mysql" {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteTableName quotes each dot-separated part of a possibly schema-qualified table name
+// (schema.table), so PostgreSQL, MySQL and attached SQLite databases resolve the schema
+func (p *SQLPlugin) quoteTableName(driverName, name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = p.quoteIdentifier(driverName, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func toStringSlice(val interface{}) []string {
+	list, ok := val.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range list {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
@@ -604,5 +1764,6 @@ func main() {
 		}
 	}
 
+	plugin.Close()
 	json.NewEncoder(os.Stdout).Encode(result)
 }
\ No newline at end of file
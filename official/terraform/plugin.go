@@ -22,6 +22,8 @@ type Metadata struct {
 	Description string   `json:"description"`
 	Author      string   `json:"author"`
 	Tags        []string `json:"tags"`
+	// TerraformVersion is the version of the terraform binary on PATH, empty when it is missing
+	TerraformVersion string `json:"terraform_version,omitempty"`
 }
 
 type ActionSpec struct {
@@ -37,7 +39,29 @@ func (p *TerraformPlugin) GetMetadata() Metadata {
 		Description: "Terraform Infrastructure as Code operations",
 		Author:      "Corynth Team",
 		Tags:        []string{"terraform", "iac", "infrastructure", "cloud", "provisioning"},
+
+		TerraformVersion: terraformVersion(),
+	}
+}
+
+// terraformVersion asks the terraform binary for its version, returning "" when it cannot be run
+func terraformVersion() string {
+	if output, err := exec.Command("terraform", "version", "-json").Output(); err == nil {
+		var version struct {
+			TerraformVersion string `json:"terraform_version"`
+		}
+		if json.Unmarshal(output, &version) == nil && version.TerraformVersion != "" {
+			return version.TerraformVersion
+		}
+	}
+
+	// Releases before 0.13 have no -json flag and print "Terraform v0.12.31" first
+	output, err := exec.Command("terraform", "version").Output()
+	if err != nil {
+		return ""
 	}
+	firstLine := strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0]
+	return strings.TrimPrefix(strings.TrimSpace(firstLine), "Terraform v")
 }
 
 func (p *TerraformPlugin) GetActions() map[string]ActionSpec {
@@ -263,6 +287,10 @@ func (p *TerraformPlugin) Execute(action string, params map[string]interface{})
 		p.WorkingDir, _ = os.Getwd()
 	}
 
+	if err := p.Validate(action, params); err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
 	switch action {
 	case "init":
 		return p.terraformInit(params)
@@ -287,6 +315,42 @@ func (p *TerraformPlugin) Execute(action string, params map[string]interface{})
 	}
 }
 
+// Validate runs pre-flight checks before an action so that a missing binary or an empty
+// working directory produces a clear error instead of a failed terraform run
+func (p *TerraformPlugin) Validate(action string, params map[string]interface{}) error {
+	if _, known := p.GetActions()[action]; !known {
+		return nil
+	}
+
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return fmt.Errorf("terraform binary not found on PATH; install Terraform from https://developer.hashicorp.com/terraform/install or add it to PATH")
+	}
+
+	info, err := os.Stat(p.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("working directory %s does not exist; set working_dir to a Terraform configuration directory", p.WorkingDir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %s is not a directory", p.WorkingDir)
+	}
+
+	// A saved plan carries its own configuration, so applying one needs no .tf files
+	needsConfig := action == "plan"
+	if action == "apply" {
+		planFile, _ := params["plan_file"].(string)
+		needsConfig = planFile == ""
+	}
+	if needsConfig {
+		tfFiles, _ := filepath.Glob(filepath.Join(p.WorkingDir, "*.tf"))
+		jsonFiles, _ := filepath.Glob(filepath.Join(p.WorkingDir, "*.tf.json"))
+		if len(tfFiles)+len(jsonFiles) == 0 {
+			return fmt.Errorf("no .tf files found in %s; set working_dir to the directory containing your Terraform configuration", p.WorkingDir)
+		}
+	}
+
+	return nil
+}
+
 func (p *TerraformPlugin) runTerraformCommand(args []string, input string) (string, int, error) {
 	cmd := exec.Command("terraform", args...)
 	cmd.Dir = p.WorkingDir
@@ -646,12 +710,16 @@ func main() {
 	var result map[string]interface{}
 	switch action {
 	case "metadata":
+		metadata := plugin.GetMetadata()
 		result = map[string]interface{}{
-			"name":        plugin.GetMetadata().Name,
-			"version":     plugin.GetMetadata().Version,
-			"description": plugin.GetMetadata().Description,
-			"author":      plugin.GetMetadata().Author,
-			"tags":        plugin.GetMetadata().Tags,
+			"name":        metadata.Name,
+			"version":     metadata.Version,
+			"description": metadata.Description,
+			"author":      metadata.Author,
+			"tags":        metadata.Tags,
+		}
+		if metadata.TerraformVersion != "" {
+			result["terraform_version"] = metadata.TerraformVersion
 		}
 	case "actions":
 		result = make(map[string]interface{})
@@ -0,0 +1,619 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Metadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+type IOSpec struct {
+	Type        string      `json:"type"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description"`
+}
+
+type ActionSpec struct {
+	Description string            `json:"description"`
+	Inputs      map[string]IOSpec `json:"inputs"`
+	Outputs     map[string]IOSpec `json:"outputs"`
+}
+
+type VaultPlugin struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func NewVaultPlugin() *VaultPlugin {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = "http://127.0.0.1:8200"
+	}
+	return &VaultPlugin{
+		addr:      strings.TrimRight(addr, "/"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *VaultPlugin) GetMetadata() Metadata {
+	return Metadata{
+		Name:        "vault",
+		Version:     "1.0.0",
+		Description: "HashiCorp Vault secrets, dynamic credentials and transit encryption",
+		Author:      "Corynth Team",
+		Tags:        []string{"vault", "hashicorp", "secrets", "security", "encryption"},
+	}
+}
+
+func (p *VaultPlugin) GetActions() map[string]ActionSpec {
+	kvVersion := IOSpec{Type: "number", Required: false, Description: "KV engine version (1 or 2); detected from the mount when omitted"}
+	transitMount := IOSpec{Type: "string", Required: false, Default: "transit", Description: "Transit engine mount path"}
+
+	return map[string]ActionSpec{
+		"kv_get": {
+			Description: "Read a secret from a KV engine",
+			Inputs: map[string]IOSpec{
+				"path":       {Type: "string", Required: true, Description: "Secret path including the mount, e.g. secret/app/db"},
+				"version":    {Type: "number", Required: false, Description: "Secret version to read (KV v2 only)"},
+				"kv_version": kvVersion,
+			},
+			Outputs: map[string]IOSpec{
+				"data":       {Type: "object", Description: "Secret key/value pairs"},
+				"metadata":   {Type: "object", Description: "Version metadata (KV v2 only)"},
+				"kv_version": {Type: "number", Description: "KV engine version of the mount"},
+			},
+		},
+		"kv_put": {
+			Description: "Write a secret to a KV engine",
+			Inputs: map[string]IOSpec{
+				"path":       {Type: "string", Required: true, Description: "Secret path including the mount"},
+				"data":       {Type: "object", Required: true, Description: "Secret key/value pairs"},
+				"kv_version": kvVersion,
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the write succeeded"},
+				"version": {Type: "number", Description: "New secret version (KV v2 only)"},
+			},
+		},
+		"kv_delete": {
+			Description: "Delete a secret from a KV engine",
+			Inputs: map[string]IOSpec{
+				"path":       {Type: "string", Required: true, Description: "Secret path including the mount"},
+				"permanent":  {Type: "boolean", Required: false, Default: false, Description: "KV v2: remove all versions and metadata instead of soft-deleting the latest version"},
+				"kv_version": kvVersion,
+			},
+			Outputs: map[string]IOSpec{
+				"success": {Type: "boolean", Description: "Whether the delete succeeded"},
+			},
+		},
+		"kv_list": {
+			Description: "List secret keys under a path",
+			Inputs: map[string]IOSpec{
+				"path":       {Type: "string", Required: true, Description: "Directory path including the mount"},
+				"kv_version": kvVersion,
+			},
+			Outputs: map[string]IOSpec{
+				"keys": {Type: "array", Description: "Key names; entries ending in / are directories"},
+			},
+		},
+		"dynamic_creds": {
+			Description: "Generate dynamic credentials from a secrets engine",
+			Inputs: map[string]IOSpec{
+				"path": {Type: "string", Required: true, Description: "Credentials path, e.g. database/creds/myrole or aws/creds/deploy"},
+			},
+			Outputs: map[string]IOSpec{
+				"data":           {Type: "object", Description: "Generated credentials"},
+				"lease_id":       {Type: "string", Description: "Lease ID for renewal or revocation"},
+				"lease_duration": {Type: "number", Description: "Lease duration in seconds"},
+				"renewable":      {Type: "boolean", Description: "Whether the lease can be renewed"},
+			},
+		},
+		"transit_encrypt": {
+			Description: "Encrypt data with a transit key",
+			Inputs: map[string]IOSpec{
+				"key_name":  {Type: "string", Required: true, Description: "Transit key name"},
+				"plaintext": {Type: "string", Required: true, Description: "Base64-encoded plaintext"},
+				"mount":     transitMount,
+			},
+			Outputs: map[string]IOSpec{
+				"ciphertext":  {Type: "string", Description: "Ciphertext (vault:v1:...)"},
+				"key_version": {Type: "number", Description: "Key version used"},
+			},
+		},
+		"transit_decrypt": {
+			Description: "Decrypt data with a transit key",
+			Inputs: map[string]IOSpec{
+				"key_name":   {Type: "string", Required: true, Description: "Transit key name"},
+				"ciphertext": {Type: "string", Required: true, Description: "Ciphertext from transit_encrypt"},
+				"mount":      transitMount,
+			},
+			Outputs: map[string]IOSpec{
+				"plaintext": {Type: "string", Description: "Base64-encoded plaintext"},
+				"text":      {Type: "string", Description: "Decoded plaintext when it is valid UTF-8"},
+			},
+		},
+		"token_renew": {
+			Description: "Renew the current token",
+			Inputs: map[string]IOSpec{
+				"increment": {Type: "string", Required: false, Description: "Requested extension, e.g. 1h (defaults to the token's TTL)"},
+			},
+			Outputs: map[string]IOSpec{
+				"lease_duration": {Type: "number", Description: "New token TTL in seconds"},
+				"renewable":      {Type: "boolean", Description: "Whether the token can be renewed again"},
+				"policies":       {Type: "array", Description: "Token policies"},
+			},
+		},
+	}
+}
+
+func (p *VaultPlugin) Execute(action string, params map[string]interface{}) (map[string]interface{}, error) {
+	switch action {
+	case "kv_get", "kv_put", "kv_delete", "kv_list", "dynamic_creds", "transit_encrypt", "transit_decrypt", "token_renew":
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+
+	if err := p.authenticate(); err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	switch action {
+	case "kv_get":
+		return p.kvGet(params)
+	case "kv_put":
+		return p.kvPut(params)
+	case "kv_delete":
+		return p.kvDelete(params)
+	case "kv_list":
+		return p.kvList(params)
+	case "dynamic_creds":
+		return p.dynamicCreds(params)
+	case "transit_encrypt":
+		return p.transitEncrypt(params)
+	case "transit_decrypt":
+		return p.transitDecrypt(params)
+	default:
+		return p.tokenRenew(params)
+	}
+}
+
+// authenticate uses VAULT_TOKEN when set, otherwise logs in with AppRole credentials
+func (p *VaultPlugin) authenticate() error {
+	if p.token != "" {
+		return nil
+	}
+
+	roleID := os.Getenv("VAULT_ROLE_ID")
+	secretID := os.Getenv("VAULT_SECRET_ID")
+	if roleID == "" || secretID == "" {
+		return fmt.Errorf("VAULT_TOKEN or VAULT_ROLE_ID and VAULT_SECRET_ID must be set")
+	}
+
+	mount := os.Getenv("VAULT_APPROLE_MOUNT")
+	if mount == "" {
+		mount = "approle"
+	}
+
+	resp, err := p.callAPI("POST", "auth/"+strings.Trim(mount, "/")+"/login", map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil {
+		return fmt.Errorf("AppRole login failed: %v", err)
+	}
+
+	auth, _ := resp["auth"].(map[string]interface{})
+	token, _ := auth["client_token"].(string)
+	if token == "" {
+		return fmt.Errorf("AppRole login returned no token")
+	}
+	p.token = token
+	return nil
+}
+
+// kvPath is a secret path split into its KV mount and the path below it
+type kvPath struct {
+	version  int
+	mount    string // e.g. "secret/"
+	relative string // path below the mount
+}
+
+func (k kvPath) data() string {
+	if k.version == 2 {
+		return k.mount + "data/" + k.relative
+	}
+	return k.mount + k.relative
+}
+
+func (k kvPath) metadata() string {
+	if k.version == 2 {
+		return k.mount + "metadata/" + k.relative
+	}
+	return k.mount + k.relative
+}
+
+// resolveKV detects the mount and engine version of path from sys/internal/ui/mounts,
+// which any token with access to the path may read. kv_version skips version detection.
+func (p *VaultPlugin) resolveKV(params map[string]interface{}) (kvPath, error) {
+	path := strings.Trim(getStringParam(params, "path", ""), "/")
+	if path == "" {
+		return kvPath{}, fmt.Errorf("path is required")
+	}
+
+	resp, err := p.callAPI("GET", "sys/internal/ui/mounts/"+path, nil)
+	if err != nil {
+		if version := getIntParam(params, "kv_version", 0); version == 1 || version == 2 {
+			// Without mount information assume the first path segment is the mount
+			mount, rest, _ := strings.Cut(path, "/")
+			return kvPath{version: version, mount: mount + "/", relative: rest}, nil
+		}
+		return kvPath{}, fmt.Errorf("failed to detect KV mount for %s (set kv_version to skip detection): %v", path, err)
+	}
+
+	data, _ := resp["data"].(map[string]interface{})
+	mount, _ := data["path"].(string)
+	if mount == "" || !strings.HasPrefix(path+"/", mount) {
+		return kvPath{}, fmt.Errorf("no secrets engine mounted at %s", path)
+	}
+	if engineType, _ := data["type"].(string); engineType != "" && engineType != "kv" && engineType != "generic" {
+		return kvPath{}, fmt.Errorf("%s is a %s engine, not kv", mount, engineType)
+	}
+
+	version := 1
+	if options, ok := data["options"].(map[string]interface{}); ok && options["version"] == "2" {
+		version = 2
+	}
+	if override := getIntParam(params, "kv_version", 0); override == 1 || override == 2 {
+		version = override
+	}
+
+	return kvPath{
+		version:  version,
+		mount:    mount,
+		relative: strings.Trim(strings.TrimPrefix(path+"/", mount), "/"),
+	}, nil
+}
+
+func (p *VaultPlugin) kvGet(params map[string]interface{}) (map[string]interface{}, error) {
+	kv, err := p.resolveKV(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	path := kv.data()
+	if version := getIntParam(params, "version", 0); version > 0 && kv.version == 2 {
+		path += fmt.Sprintf("?version=%d", version)
+	}
+
+	resp, err := p.callAPI("GET", path, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	data, _ := resp["data"].(map[string]interface{})
+	if kv.version == 1 {
+		return map[string]interface{}{
+			"data":       data,
+			"kv_version": 1,
+		}, nil
+	}
+
+	return map[string]interface{}{
+		"data":       data["data"],
+		"metadata":   data["metadata"],
+		"kv_version": 2,
+	}, nil
+}
+
+func (p *VaultPlugin) kvPut(params map[string]interface{}) (map[string]interface{}, error) {
+	secret, ok := params["data"].(map[string]interface{})
+	if !ok || len(secret) == 0 {
+		return map[string]interface{}{"error": "data is required"}, nil
+	}
+
+	kv, err := p.resolveKV(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	var body interface{} = secret
+	if kv.version == 2 {
+		body = map[string]interface{}{"data": secret}
+	}
+
+	resp, err := p.callAPI("POST", kv.data(), body)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	result := map[string]interface{}{"success": true}
+	if data, ok := resp["data"].(map[string]interface{}); ok {
+		result["version"] = data["version"]
+	}
+	return result, nil
+}
+
+func (p *VaultPlugin) kvDelete(params map[string]interface{}) (map[string]interface{}, error) {
+	kv, err := p.resolveKV(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	path := kv.data()
+	if getBoolParam(params, "permanent", false) {
+		path = kv.metadata()
+	}
+
+	if _, err := p.callAPI("DELETE", path, nil); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+func (p *VaultPlugin) kvList(params map[string]interface{}) (map[string]interface{}, error) {
+	kv, err := p.resolveKV(params)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	resp, err := p.callAPI("LIST", kv.metadata(), nil)
+	if err != nil {
+		// An empty directory is reported as not found
+		if strings.Contains(err.Error(), "(404)") {
+			return map[string]interface{}{"keys": []interface{}{}}, nil
+		}
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	data, _ := resp["data"].(map[string]interface{})
+	keys, _ := data["keys"].([]interface{})
+	if keys == nil {
+		keys = []interface{}{}
+	}
+
+	return map[string]interface{}{
+		"keys": keys,
+	}, nil
+}
+
+func (p *VaultPlugin) dynamicCreds(params map[string]interface{}) (map[string]interface{}, error) {
+	path := strings.Trim(getStringParam(params, "path", ""), "/")
+	if path == "" {
+		return map[string]interface{}{"error": "path is required"}, nil
+	}
+
+	resp, err := p.callAPI("GET", path, nil)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	return map[string]interface{}{
+		"data":           resp["data"],
+		"lease_id":       resp["lease_id"],
+		"lease_duration": resp["lease_duration"],
+		"renewable":      resp["renewable"],
+	}, nil
+}
+
+func (p *VaultPlugin) transitEncrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	keyName := getStringParam(params, "key_name", "")
+	plaintext := getStringParam(params, "plaintext", "")
+	if keyName == "" || plaintext == "" {
+		return map[string]interface{}{"error": "key_name and plaintext are required"}, nil
+	}
+	if _, err := base64.StdEncoding.DecodeString(plaintext); err != nil {
+		return map[string]interface{}{"error": "plaintext must be base64-encoded"}, nil
+	}
+
+	mount := strings.Trim(getStringParam(params, "mount", "transit"), "/")
+	resp, err := p.callAPI("POST", mount+"/encrypt/"+url.PathEscape(keyName), map[string]interface{}{
+		"plaintext": plaintext,
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	data, _ := resp["data"].(map[string]interface{})
+	return map[string]interface{}{
+		"ciphertext":  data["ciphertext"],
+		"key_version": data["key_version"],
+	}, nil
+}
+
+func (p *VaultPlugin) transitDecrypt(params map[string]interface{}) (map[string]interface{}, error) {
+	keyName := getStringParam(params, "key_name", "")
+	ciphertext := getStringParam(params, "ciphertext", "")
+	if keyName == "" || ciphertext == "" {
+		return map[string]interface{}{"error": "key_name and ciphertext are required"}, nil
+	}
+
+	mount := strings.Trim(getStringParam(params, "mount", "transit"), "/")
+	resp, err := p.callAPI("POST", mount+"/decrypt/"+url.PathEscape(keyName), map[string]interface{}{
+		"ciphertext": ciphertext,
+	})
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	data, _ := resp["data"].(map[string]interface{})
+	plaintext, _ := data["plaintext"].(string)
+	result := map[string]interface{}{"plaintext": plaintext}
+	if decoded, err := base64.StdEncoding.DecodeString(plaintext); err == nil && utf8.Valid(decoded) {
+		result["text"] = string(decoded)
+	}
+	return result, nil
+}
+
+func (p *VaultPlugin) tokenRenew(params map[string]interface{}) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if increment := getStringParam(params, "increment", ""); increment != "" {
+		body["increment"] = increment
+	}
+
+	resp, err := p.callAPI("POST", "auth/token/renew-self", body)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+
+	auth, _ := resp["auth"].(map[string]interface{})
+	return map[string]interface{}{
+		"lease_duration": auth["lease_duration"],
+		"renewable":      auth["renewable"],
+		"policies":       auth["policies"],
+	}, nil
+}
+
+// callAPI sends a request to /v1/<path> and decodes the response. LIST is sent as
+// GET with list=true, which Vault treats the same.
+func (p *VaultPlugin) callAPI(method, path string, body interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := p.addr + "/v1/" + path
+	if method == "LIST" {
+		method = "GET"
+		if strings.Contains(target, "?") {
+			target += "&list=true"
+		} else {
+			target += "?list=true"
+		}
+	}
+
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if p.token != "" {
+		req.Header.Set("X-Vault-Token", p.token)
+	}
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode < 300 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+
+	if resp.StatusCode >= 300 {
+		var messages []string
+		if errs, ok := result["errors"].([]interface{}); ok {
+			for _, e := range errs {
+				messages = append(messages, fmt.Sprintf("%v", e))
+			}
+		}
+		message := strings.Join(messages, "; ")
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("Vault API error (%d): %s", resp.StatusCode, message)
+	}
+	return result, nil
+}
+
+func getStringParam(params map[string]interface{}, key string, defaultValue string) string {
+	if val, ok := params[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := params[key].(bool); ok {
+		return val
+	}
+	return defaultValue
+}
+
+func getIntParam(params map[string]interface{}, key string, defaultValue int) int {
+	if val, ok := params[key].(float64); ok {
+		return int(val)
+	}
+	return defaultValue
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"error": "action required"})
+		os.Exit(1)
+	}
+
+	action := os.Args[1]
+	plugin := NewVaultPlugin()
+
+	var result interface{}
+
+	switch action {
+	case "metadata":
+		result = plugin.GetMetadata()
+	case "actions":
+		result = plugin.GetActions()
+	default:
+		var params map[string]interface{}
+		inputData, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			result = map[string]interface{}{"error": fmt.Sprintf("failed to read input: %v", err)}
+		} else if len(inputData) > 0 {
+			if err := json.Unmarshal(inputData, &params); err != nil {
+				result = map[string]interface{}{"error": fmt.Sprintf("failed to parse JSON: %v", err)}
+			} else {
+				result, err = plugin.Execute(action, params)
+				if err != nil {
+					result = map[string]interface{}{"error": err.Error()}
+				}
+			}
+		} else {
+			result, err = plugin.Execute(action, map[string]interface{}{})
+			if err != nil {
+				result = map[string]interface{}{"error": err.Error()}
+			}
+		}
+	}
+
+	json.NewEncoder(os.Stdout).Encode(result)
+}